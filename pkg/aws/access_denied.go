@@ -0,0 +1,107 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+)
+
+// accessDeniedCodes are the API error codes returned when the caller lacks a permission
+var accessDeniedCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"UnauthorizedOperation": true,
+}
+
+// iamServicePrefixes maps SDK service IDs to the service prefix used in IAM actions
+var iamServicePrefixes = map[string]string{
+	"CloudWatch":      "cloudwatch",
+	"CloudWatch Logs": "logs",
+	"EC2":             "ec2",
+	"ECR":             "ecr",
+	"EKS":             "eks",
+	"IAM":             "iam",
+	"SSM":             "ssm",
+	"STS":             "sts",
+}
+
+// AccessDeniedError is returned when the caller identity is not allowed to perform an action
+type AccessDeniedError struct {
+	Action   string // IAM action, e.g. eks:DescribeNodegroup
+	Identity string // caller identity ARN, empty if it could not be determined
+	Err      error
+}
+
+func (e *AccessDeniedError) Error() string {
+	identity := e.Identity
+	if identity == "" {
+		identity = "the current AWS identity"
+	}
+	return fmt.Sprintf("access denied: %s is not allowed to perform %s (run \"ekspeek debug permissions\" to check every required action)", identity, e.Action)
+}
+
+func (e *AccessDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// IsAccessDenied returns true if the error, or an error it wraps, is an access denied error
+func IsAccessDenied(err error) bool {
+	var accessErr *AccessDeniedError
+	return errors.As(err, &accessErr)
+}
+
+// explainAccessDenied converts an AWS access denied error into an *AccessDeniedError and
+// returns any other error unchanged
+func (c *Client) explainAccessDenied(ctx context.Context, err error) error {
+	var apiErr smithy.APIError
+	if err == nil || !errors.As(err, &apiErr) || !accessDeniedCodes[apiErr.ErrorCode()] {
+		return err
+	}
+
+	action := "the requested action"
+	var opErr *smithy.OperationError
+	if errors.As(err, &opErr) {
+		prefix, ok := iamServicePrefixes[opErr.Service()]
+		if !ok {
+			prefix = strings.ToLower(strings.ReplaceAll(opErr.Service(), " ", ""))
+		}
+		action = prefix + ":" + opErr.Operation()
+	}
+
+	identity, _ := c.CallerIdentity(ctx)
+	return &AccessDeniedError{Action: action, Identity: identity, Err: err}
+}
+
+// Identity is the AWS account and principal a client's credentials belong to
+type Identity struct {
+	Account string `json:"account"`
+	ARN     string `json:"arn"`
+}
+
+// Identity returns the account and ARN of the identity the client's credentials belong to.
+// The result is cached for the lifetime of the client.
+func (c *Client) Identity(ctx context.Context) (Identity, error) {
+	c.identityOnce.Do(func() {
+		// explainAccessDenied looks up the identity, so only expired credentials are handled here
+		result, err := refreshOnExpiry(ctx, c, func() (*sts.GetCallerIdentityOutput, error) {
+			return c.STSClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		})
+		if err != nil {
+			c.identityErr = fmt.Errorf("failed to get caller identity: %w", err)
+			return
+		}
+		c.identity = Identity{Account: aws.ToString(result.Account), ARN: aws.ToString(result.Arn)}
+	})
+	return c.identity, c.identityErr
+}
+
+// CallerIdentity returns the ARN of the identity the client's credentials belong to
+func (c *Client) CallerIdentity(ctx context.Context) (string, error) {
+	identity, err := c.Identity(ctx)
+	return identity.ARN, err
+}
@@ -0,0 +1,88 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+)
+
+// AccessEntryInfo describes an EKS access entry and the access policies associated with it
+type AccessEntryInfo struct {
+	PrincipalARN     string
+	Type             string
+	Username         string
+	KubernetesGroups []string
+	Policies         []AccessPolicyInfo
+}
+
+// AccessPolicyInfo describes an access policy associated with an access entry
+type AccessPolicyInfo struct {
+	PolicyARN  string
+	ScopeType  string   // "cluster" or "namespace"
+	Namespaces []string // only set for namespace scoped policies
+}
+
+// GetAccessEntries lists the cluster's access entries with their Kubernetes identity and
+// associated access policies
+func (c *Client) GetAccessEntries(ctx context.Context, clusterName string) ([]AccessEntryInfo, error) {
+	var principals []string
+	paginator := eks.NewListAccessEntriesPaginator(c.EKSClient, &eks.ListAccessEntriesInput{
+		ClusterName: aws.String(clusterName),
+	})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*eks.ListAccessEntriesOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list access entries: %w", err)
+		}
+		principals = append(principals, page.AccessEntries...)
+	}
+
+	var entries []AccessEntryInfo
+	for _, principal := range principals {
+		desc, err := withCredRefresh(ctx, c, func() (*eks.DescribeAccessEntryOutput, error) {
+			return c.EKSClient.DescribeAccessEntry(ctx, &eks.DescribeAccessEntryInput{
+				ClusterName:  aws.String(clusterName),
+				PrincipalArn: aws.String(principal),
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe access entry %s: %w", principal, err)
+		}
+
+		entry := AccessEntryInfo{
+			PrincipalARN:     principal,
+			Type:             aws.ToString(desc.AccessEntry.Type),
+			Username:         aws.ToString(desc.AccessEntry.Username),
+			KubernetesGroups: desc.AccessEntry.KubernetesGroups,
+		}
+
+		policies := eks.NewListAssociatedAccessPoliciesPaginator(c.EKSClient, &eks.ListAssociatedAccessPoliciesInput{
+			ClusterName:  aws.String(clusterName),
+			PrincipalArn: aws.String(principal),
+		})
+		for policies.HasMorePages() {
+			page, err := withCredRefresh(ctx, c, func() (*eks.ListAssociatedAccessPoliciesOutput, error) {
+				return policies.NextPage(ctx)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list access policies for %s: %w", principal, err)
+			}
+			for _, policy := range page.AssociatedAccessPolicies {
+				info := AccessPolicyInfo{PolicyARN: aws.ToString(policy.PolicyArn)}
+				if policy.AccessScope != nil {
+					info.ScopeType = string(policy.AccessScope.Type)
+					info.Namespaces = policy.AccessScope.Namespaces
+				}
+				entry.Policies = append(entry.Policies, info)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+)
+
+// GetDefaultAddonVersion returns the add-on version EKS installs by default on clusters of
+// the given Kubernetes version, falling back to the newest compatible version when none is
+// marked default
+func (c *Client) GetDefaultAddonVersion(ctx context.Context, addonName, kubernetesVersion string) (string, error) {
+	var latest string
+	paginator := eks.NewDescribeAddonVersionsPaginator(c.EKSClient, &eks.DescribeAddonVersionsInput{
+		AddonName:         aws.String(addonName),
+		KubernetesVersion: aws.String(kubernetesVersion),
+	})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*eks.DescribeAddonVersionsOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to describe %s addon versions: %w", addonName, err)
+		}
+		for _, addon := range page.Addons {
+			for _, version := range addon.AddonVersions {
+				for _, compat := range version.Compatibilities {
+					if aws.ToString(compat.ClusterVersion) != kubernetesVersion {
+						continue
+					}
+					if compat.DefaultVersion {
+						return aws.ToString(version.AddonVersion), nil
+					}
+					if latest == "" {
+						latest = aws.ToString(version.AddonVersion)
+					}
+				}
+			}
+		}
+	}
+
+	if latest == "" {
+		return "", fmt.Errorf("no %s addon version is compatible with Kubernetes %s", addonName, kubernetesVersion)
+	}
+	return latest, nil
+}
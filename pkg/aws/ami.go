@@ -0,0 +1,258 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// NodegroupAMIStatus compares a managed nodegroup's AMI release with the latest
+// EKS-optimized release for the cluster's Kubernetes version
+type NodegroupAMIStatus struct {
+	Nodegroup      string
+	AMIType        string
+	ReleaseVersion string
+	LatestVersion  string
+	// ReleasesBehind is the number of newer releases published since the nodegroup's
+	// release. When the nodegroup's release is older than the parameter history it is
+	// the size of the history, a lower bound.
+	ReleasesBehind int
+	Error          string
+}
+
+// Outdated returns true if the nodegroup is more than one release behind the latest
+func (s NodegroupAMIStatus) Outdated() bool {
+	return s.ReleasesBehind > 1
+}
+
+// SelfManagedNodeAMI describes the AMI of an EC2 instance that is not part of a managed nodegroup
+type SelfManagedNodeAMI struct {
+	InstanceID   string
+	ImageID      string
+	ImageName    string
+	CreationDate time.Time
+	Error        string
+}
+
+// Age returns how long ago the AMI was created
+func (n SelfManagedNodeAMI) Age() time.Duration {
+	return time.Since(n.CreationDate)
+}
+
+// releaseVersionParameter returns the public SSM parameter holding the recommended release
+// version of the EKS-optimized AMI for an AMI type, or "" if the type is not tracked
+func releaseVersionParameter(amiType ekstypes.AMITypes, k8sVersion string) string {
+	switch amiType {
+	case ekstypes.AMITypesAl2X8664:
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2/recommended/release_version", k8sVersion)
+	case ekstypes.AMITypesAl2X8664Gpu:
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2-gpu/recommended/release_version", k8sVersion)
+	case ekstypes.AMITypesAl2Arm64:
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2-arm64/recommended/release_version", k8sVersion)
+	case ekstypes.AMITypesAl2023X8664Standard:
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/release_version", k8sVersion)
+	case ekstypes.AMITypesAl2023Arm64Standard:
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/arm64/standard/recommended/release_version", k8sVersion)
+	case ekstypes.AMITypesAl2023X8664Nvidia:
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/nvidia/recommended/release_version", k8sVersion)
+	case ekstypes.AMITypesBottlerocketX8664:
+		return fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/x86_64/latest/image_version", k8sVersion)
+	case ekstypes.AMITypesBottlerocketArm64:
+		return fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/arm64/latest/image_version", k8sVersion)
+	}
+	return ""
+}
+
+// getReleaseHistory returns the distinct values of a release version parameter, oldest first
+func (c *Client) getReleaseHistory(ctx context.Context, name string) ([]string, error) {
+	var history []string
+	seen := make(map[string]bool)
+	paginator := ssm.NewGetParameterHistoryPaginator(c.SSMClient, &ssm.GetParameterHistoryInput{
+		Name: aws.String(name),
+	})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*ssm.GetParameterHistoryOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parameter history for %s: %w", name, err)
+		}
+		sort.Slice(page.Parameters, func(i, j int) bool {
+			return page.Parameters[i].Version < page.Parameters[j].Version
+		})
+		for _, p := range page.Parameters {
+			value := aws.ToString(p.Value)
+			if value != "" && !seen[value] {
+				seen[value] = true
+				history = append(history, value)
+			}
+		}
+	}
+	return history, nil
+}
+
+// releasesBehind returns the number of releases in history published after current
+func releasesBehind(history []string, current string) int {
+	for i, v := range history {
+		if v == current {
+			return len(history) - 1 - i
+		}
+	}
+	return len(history)
+}
+
+// GetNodegroupAMIStatus compares each managed nodegroup's AMI release version with the
+// release history of the EKS-optimized AMI for the cluster's Kubernetes version, read from
+// the public SSM parameters
+func (c *Client) GetNodegroupAMIStatus(ctx context.Context, clusterName string) ([]NodegroupAMIStatus, error) {
+	cluster, err := c.DescribeCluster(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+	k8sVersion := aws.ToString(cluster.Cluster.Version)
+
+	nodegroups, err := c.GetClusterNodegroups(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make(map[string][]string)
+	var statuses []NodegroupAMIStatus
+	for _, ng := range nodegroups {
+		status := NodegroupAMIStatus{
+			Nodegroup:      aws.ToString(ng.NodegroupName),
+			AMIType:        string(ng.AmiType),
+			ReleaseVersion: aws.ToString(ng.ReleaseVersion),
+		}
+
+		// Nodegroups on an older Kubernetes version are compared with that version's releases
+		version := k8sVersion
+		if ng.Version != nil {
+			version = aws.ToString(ng.Version)
+		}
+
+		param := releaseVersionParameter(ng.AmiType, version)
+		if param == "" {
+			status.Error = fmt.Sprintf("release tracking is not supported for AMI type %s", ng.AmiType)
+			statuses = append(statuses, status)
+			continue
+		}
+
+		history, ok := histories[param]
+		if !ok {
+			history, err = c.getReleaseHistory(ctx, param)
+			if err != nil {
+				status.Error = err.Error()
+				statuses = append(statuses, status)
+				continue
+			}
+			histories[param] = history
+		}
+
+		if len(history) > 0 {
+			status.LatestVersion = history[len(history)-1]
+			status.ReleasesBehind = releasesBehind(history, status.ReleaseVersion)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// GetSelfManagedNodeAMIs returns the AMI and AMI creation date of each running instance
+// tagged for the cluster that is not part of an EKS managed nodegroup
+func (c *Client) GetSelfManagedNodeAMIs(ctx context.Context, clusterName string) ([]SelfManagedNodeAMI, error) {
+	var instances []ec2types.Instance
+	paginator := ec2.NewDescribeInstancesPaginator(c.EC2Client, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: []string{"kubernetes.io/cluster/" + clusterName},
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{"running"},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*ec2.DescribeInstancesOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instances: %w", err)
+		}
+		for _, r := range page.Reservations {
+			for _, inst := range r.Instances {
+				if !hasTag(inst.Tags, "eks:nodegroup-name") {
+					instances = append(instances, inst)
+				}
+			}
+		}
+	}
+	if len(instances) == 0 {
+		return nil, nil
+	}
+
+	imageIDs := make(map[string]bool)
+	for _, inst := range instances {
+		imageIDs[aws.ToString(inst.ImageId)] = true
+	}
+	ids := make([]string, 0, len(imageIDs))
+	for id := range imageIDs {
+		ids = append(ids, id)
+	}
+
+	images, err := withCredRefresh(ctx, c, func() (*ec2.DescribeImagesOutput, error) {
+		return c.EC2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{ImageIds: ids})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe images: %w", err)
+	}
+	byID := make(map[string]ec2types.Image)
+	for _, img := range images.Images {
+		byID[aws.ToString(img.ImageId)] = img
+	}
+
+	var nodes []SelfManagedNodeAMI
+	for _, inst := range instances {
+		node := SelfManagedNodeAMI{
+			InstanceID: aws.ToString(inst.InstanceId),
+			ImageID:    aws.ToString(inst.ImageId),
+		}
+		img, ok := byID[node.ImageID]
+		if !ok {
+			node.Error = "AMI not found; it may have been deregistered"
+			nodes = append(nodes, node)
+			continue
+		}
+		node.ImageName = aws.ToString(img.Name)
+		created, err := time.Parse(time.RFC3339, aws.ToString(img.CreationDate))
+		if err != nil {
+			node.Error = fmt.Sprintf("invalid AMI creation date %q", aws.ToString(img.CreationDate))
+		}
+		node.CreationDate = created
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].InstanceID < nodes[j].InstanceID
+	})
+	return nodes, nil
+}
+
+func hasTag(tags []ec2types.Tag, key string) bool {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key {
+			return true
+		}
+	}
+	return false
+}
@@ -4,17 +4,25 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"ekspeek/pkg/findings"
 )
 
 var clusterName string
@@ -23,14 +31,33 @@ var clusterName string
 type ClientConfig struct {
 	Profile string
 	Region  string
+	// RoleARN, if set, is assumed with the credentials of the profile
+	RoleARN string
 }
 
 // Client is the struct that holds the AWS services clients
 type Client struct {
 	EKSClient        *eks.Client
 	EC2Client        *ec2.Client
+	ECRClient        *ecr.Client
 	CloudWatchClient *cloudwatch.Client
+	LogsClient       *cloudwatchlogs.Client
 	IAMClient        *iam.Client
+	SSMClient        *ssm.Client
+	STSClient        *sts.Client
+
+	// config and refresh are used to reload credentials that expire mid-run. The service
+	// clients above are set once and share credentials, which a refresh swaps.
+	config      ClientConfig
+	credentials *swappableCredentials
+	refresh     func(ctx context.Context) error
+	refreshMu   sync.Mutex
+
+	// identity caches the caller identity logged by commands and used in access denied
+	// errors
+	identityOnce sync.Once
+	identity     Identity
+	identityErr  error
 }
 
 // NATGatewayInfo contains information about a NAT gateway
@@ -42,6 +69,32 @@ type NATGatewayInfo struct {
 
 // NewClient creates a new AWS client
 func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
+	awsCfg, err := loadConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load SDK config: %w", err)
+	}
+
+	credentials := newSwappableCredentials(awsCfg.Credentials)
+	awsCfg.Credentials = credentials.cache
+
+	client := &Client{
+		EKSClient:        eks.NewFromConfig(awsCfg),
+		EC2Client:        ec2.NewFromConfig(awsCfg),
+		ECRClient:        ecr.NewFromConfig(awsCfg),
+		CloudWatchClient: cloudwatch.NewFromConfig(awsCfg),
+		LogsClient:       cloudwatchlogs.NewFromConfig(awsCfg),
+		IAMClient:        iam.NewFromConfig(awsCfg),
+		SSMClient:        ssm.NewFromConfig(awsCfg),
+		STSClient:        sts.NewFromConfig(awsCfg),
+		config:           cfg,
+		credentials:      credentials,
+	}
+	client.refresh = client.reloadCredentials
+	return client, nil
+}
+
+// loadConfig loads the SDK config for the profile and region, assuming the role if one is set
+func loadConfig(ctx context.Context, cfg ClientConfig) (aws.Config, error) {
 	opts := []func(*config.LoadOptions) error{
 		config.WithRegion(cfg.Region),
 	}
@@ -51,15 +104,15 @@ func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
 
 	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config: %w", err)
+		return aws.Config{}, err
 	}
-
-	return &Client{
-		EKSClient:        eks.NewFromConfig(awsCfg),
-		EC2Client:        ec2.NewFromConfig(awsCfg),
-		CloudWatchClient: cloudwatch.NewFromConfig(awsCfg),
-		IAMClient:        iam.NewFromConfig(awsCfg),
-	}, nil
+	if cfg.RoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsCfg), cfg.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "ekspeek"
+		})
+		awsCfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return awsCfg, nil
 }
 
 // ValidateNodeGroupsConfig validates the configuration of node groups
@@ -68,7 +121,9 @@ func (c *Client) ValidateNodeGroupsConfig(ctx context.Context, clusterName strin
 		ClusterName: aws.String(clusterName),
 	}
 
-	result, err := c.EKSClient.ListNodegroups(ctx, input)
+	result, err := withCredRefresh(ctx, c, func() (*eks.ListNodegroupsOutput, error) {
+		return c.EKSClient.ListNodegroups(ctx, input)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to list nodegroups: %w", err)
 	}
@@ -79,7 +134,9 @@ func (c *Client) ValidateNodeGroupsConfig(ctx context.Context, clusterName strin
 			NodegroupName: aws.String(ng),
 		}
 
-		desc, err := c.EKSClient.DescribeNodegroup(ctx, descInput)
+		desc, err := withCredRefresh(ctx, c, func() (*eks.DescribeNodegroupOutput, error) {
+			return c.EKSClient.DescribeNodegroup(ctx, descInput)
+		})
 		if err != nil {
 			return fmt.Errorf("failed to describe nodegroup %s: %w", ng, err)
 		}
@@ -99,13 +156,19 @@ func (c *Client) ValidateNodeGroupsConfig(ctx context.Context, clusterName strin
 
 // ListClusters lists all EKS clusters in the current region
 func (c *Client) ListClusters(ctx context.Context) ([]string, error) {
-	input := &eks.ListClustersInput{}
-	result, err := c.EKSClient.ListClusters(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	var clusters []string
+	paginator := eks.NewListClustersPaginator(c.EKSClient, &eks.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*eks.ListClustersOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list clusters: %w", err)
+		}
+		clusters = append(clusters, page.Clusters...)
 	}
 
-	return result.Clusters, nil
+	return clusters, nil
 }
 
 // DescribeCluster gets detailed information about an EKS cluster
@@ -114,7 +177,9 @@ func (c *Client) DescribeCluster(ctx context.Context, clusterName string) (*eks.
 		Name: aws.String(clusterName),
 	}
 
-	result, err := c.EKSClient.DescribeCluster(ctx, input)
+	result, err := withCredRefresh(ctx, c, func() (*eks.DescribeClusterOutput, error) {
+		return c.EKSClient.DescribeCluster(ctx, input)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe cluster %s: %w", clusterName, err)
 	}
@@ -191,7 +256,9 @@ func (c *Client) GetEKSThrottlingMetrics(ctx context.Context, startTime, endTime
 		EndTime:   aws.Time(endTime),
 	}
 
-	output, err := c.CloudWatchClient.GetMetricData(ctx, input)
+	output, err := withCredRefresh(ctx, c, func() (*cloudwatch.GetMetricDataOutput, error) {
+		return c.CloudWatchClient.GetMetricData(ctx, input)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get throttling metrics: %w", err)
 	}
@@ -258,7 +325,9 @@ func (c *Client) GetEKSPerformanceMetrics(ctx context.Context, clusterName strin
 		EndTime:   &endTime,
 	}
 
-	output, err := c.CloudWatchClient.GetMetricData(ctx, input)
+	output, err := withCredRefresh(ctx, c, func() (*cloudwatch.GetMetricDataOutput, error) {
+		return c.CloudWatchClient.GetMetricData(ctx, input)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get CloudWatch metrics: %w", err)
 	}
@@ -304,29 +373,57 @@ type ControlPlaneMetrics struct {
 	RequestThroughput  float64
 }
 
-// GetClusterNodegroups gets detailed information about all nodegroups in a cluster
+// describeNodegroupConcurrency is the number of DescribeNodegroup calls
+// GetClusterNodegroups makes at a time
+const describeNodegroupConcurrency = 8
+
+// GetClusterNodegroups gets detailed information about all nodegroups in a cluster. The
+// nodegroups are described concurrently, with at most describeNodegroupConcurrency calls
+// in flight, and returned in the order they were listed.
 func (c *Client) GetClusterNodegroups(ctx context.Context, clusterName string) ([]*ekstypes.Nodegroup, error) {
 	input := &eks.ListNodegroupsInput{
 		ClusterName: aws.String(clusterName),
 	}
 
-	result, err := c.EKSClient.ListNodegroups(ctx, input)
+	result, err := withCredRefresh(ctx, c, func() (*eks.ListNodegroupsOutput, error) {
+		return c.EKSClient.ListNodegroups(ctx, input)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list nodegroups: %w", err)
 	}
 
-	var nodegroups []*ekstypes.Nodegroup
-	for _, ng := range result.Nodegroups {
-		descInput := &eks.DescribeNodegroupInput{
-			ClusterName:   aws.String(clusterName),
-			NodegroupName: aws.String(ng),
-		}
-
-		desc, err := c.EKSClient.DescribeNodegroup(ctx, descInput)
+	nodegroups := make([]*ekstypes.Nodegroup, len(result.Nodegroups))
+	errs := make([]error, len(result.Nodegroups))
+	sem := make(chan struct{}, describeNodegroupConcurrency)
+	var wg sync.WaitGroup
+	for i, ng := range result.Nodegroups {
+		wg.Add(1)
+		go func(i int, ng string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			descInput := &eks.DescribeNodegroupInput{
+				ClusterName:   aws.String(clusterName),
+				NodegroupName: aws.String(ng),
+			}
+
+			desc, err := withCredRefresh(ctx, c, func() (*eks.DescribeNodegroupOutput, error) {
+				return c.EKSClient.DescribeNodegroup(ctx, descInput)
+			})
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to describe nodegroup %s: %w", ng, err)
+				return
+			}
+			nodegroups[i] = desc.Nodegroup
+		}(i, ng)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
 		if err != nil {
-			return nil, fmt.Errorf("failed to describe nodegroup %s: %w", ng, err)
+			return nil, err
 		}
-		nodegroups = append(nodegroups, desc.Nodegroup)
 	}
 
 	return nodegroups, nil
@@ -376,7 +473,9 @@ func (c *Client) GetClusterPerformanceMetrics(ctx context.Context, clusterName s
 			EndTime:   aws.Time(endTime),
 		}
 
-		output, err := c.CloudWatchClient.GetMetricData(ctx, input)
+		output, err := withCredRefresh(ctx, c, func() (*cloudwatch.GetMetricDataOutput, error) {
+			return c.CloudWatchClient.GetMetricData(ctx, input)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to get metric %s: %w", m.Name, err)
 		}
@@ -395,7 +494,9 @@ func (c *Client) GetAddons(ctx context.Context, clusterName string) ([]*ekstypes
 		ClusterName: aws.String(clusterName),
 	}
 
-	result, err := c.EKSClient.ListAddons(ctx, input)
+	result, err := withCredRefresh(ctx, c, func() (*eks.ListAddonsOutput, error) {
+		return c.EKSClient.ListAddons(ctx, input)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list addons: %w", err)
 	}
@@ -407,7 +508,9 @@ func (c *Client) GetAddons(ctx context.Context, clusterName string) ([]*ekstypes
 			AddonName:   aws.String(a),
 		}
 
-		desc, err := c.EKSClient.DescribeAddon(ctx, descInput)
+		desc, err := withCredRefresh(ctx, c, func() (*eks.DescribeAddonOutput, error) {
+			return c.EKSClient.DescribeAddon(ctx, descInput)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to describe addon %s: %w", a, err)
 		}
@@ -423,10 +526,12 @@ func (c *Client) ListAddons(ctx context.Context, clusterName string) ([]string,
         ClusterName: aws.String(clusterName),
     }
 
-    result, err := c.EKSClient.ListAddons(ctx, input)
-    if err != nil {
-        return nil, fmt.Errorf("failed to list addons: %w", err)
-    }
+	result, err := withCredRefresh(ctx, c, func() (*eks.ListAddonsOutput, error) {
+		return c.EKSClient.ListAddons(ctx, input)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addons: %w", err)
+	}
 
     return result.Addons, nil
 }
@@ -438,10 +543,12 @@ func (c *Client) DescribeAddon(ctx context.Context, clusterName, addonName strin
         ClusterName: aws.String(clusterName),
     }
 
-    result, err := c.EKSClient.DescribeAddon(ctx, input)
-    if err != nil {
-        return nil, fmt.Errorf("failed to describe addon %s: %w", addonName, err)
-    }
+	result, err := withCredRefresh(ctx, c, func() (*eks.DescribeAddonOutput, error) {
+		return c.EKSClient.DescribeAddon(ctx, input)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe addon %s: %w", addonName, err)
+	}
 
     return result, nil
 }
@@ -457,10 +564,12 @@ func (c *Client) GetNATGateways(ctx context.Context, vpcID string) ([]*NATGatewa
         },
     }
 
-    result, err := c.EC2Client.DescribeNatGateways(ctx, input)
-    if err != nil {
-        return nil, fmt.Errorf("failed to describe NAT gateways: %w", err)
-    }
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeNatGatewaysOutput, error) {
+		return c.EC2Client.DescribeNatGateways(ctx, input)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe NAT gateways: %w", err)
+	}
 
     var gateways []*NATGatewayInfo
     for _, ng := range result.NatGateways {
@@ -489,10 +598,12 @@ func (c *Client) GetSecurityGroupEgressRules(ctx context.Context, securityGroupI
         },
     }
 
-    result, err := c.EC2Client.DescribeSecurityGroupRules(ctx, input)
-    if err != nil {
-        return nil, fmt.Errorf("failed to describe security group rules: %w", err)
-    }
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeSecurityGroupRulesOutput, error) {
+		return c.EC2Client.DescribeSecurityGroupRules(ctx, input)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe security group rules: %w", err)
+	}
 
     return result.SecurityGroupRules, nil
 }
@@ -508,10 +619,12 @@ func (c *Client) GetRouteTables(ctx context.Context, vpcID string) ([]ec2types.R
         },
     }
 
-    result, err := c.EC2Client.DescribeRouteTables(ctx, input)
-    if err != nil {
-        return nil, fmt.Errorf("failed to describe route tables: %w", err)
-    }
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeRouteTablesOutput, error) {
+		return c.EC2Client.DescribeRouteTables(ctx, input)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe route tables: %w", err)
+	}
 
     return result.RouteTables, nil
 }
@@ -522,10 +635,12 @@ func (c *Client) ValidateSecurityGroupAccess(ctx context.Context, securityGroupI
         GroupIds: []string{securityGroupID},
     }
 
-    result, err := c.EC2Client.DescribeSecurityGroups(ctx, input)
-    if err != nil {
-        return fmt.Errorf("failed to describe security group: %w", err)
-    }
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeSecurityGroupsOutput, error) {
+		return c.EC2Client.DescribeSecurityGroups(ctx, input)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe security group: %w", err)
+	}
 
     if len(result.SecurityGroups) == 0 {
         return fmt.Errorf("security group %s not found", securityGroupID)
@@ -533,28 +648,30 @@ func (c *Client) ValidateSecurityGroupAccess(ctx context.Context, securityGroupI
 
     sg := result.SecurityGroups[0]
 
-    // Check for cross-account references in ingress rules
-    for _, rule := range sg.IpPermissions {
-        for _, group := range rule.UserIdGroupPairs {
-            if group.UserId != nil && *group.UserId != *sg.OwnerId {
-                // Found a cross-account reference, validate if the account has permission
-                iamInput := &iam.GetRoleInput{
-                    RoleName: aws.String(extractRoleNameFromARN(*group.UserId)),
-                }
-                _, err := c.IAMClient.GetRole(ctx, iamInput)
-                if err != nil {
-                    return fmt.Errorf("cross-account access issue: %w", err)
-                }
-            }
-        }
-    }
+	// Check for cross-account references in ingress rules
+	for _, rule := range sg.IpPermissions {
+		for _, group := range rule.UserIdGroupPairs {
+			if group.UserId != nil && *group.UserId != *sg.OwnerId {
+				// Found a cross-account reference, validate if the account has permission
+				iamInput := &iam.GetRoleInput{
+					RoleName: aws.String(extractRoleNameFromARN(*group.UserId)),
+				}
+				_, err := withCredRefresh(ctx, c, func() (*iam.GetRoleOutput, error) {
+					return c.IAMClient.GetRole(ctx, iamInput)
+				})
+				if err != nil {
+					return fmt.Errorf("cross-account access issue: %w", err)
+				}
+			}
+		}
+	}
 
     return nil
 }
 
 // GetSecurityAnalysis analyzes security settings of the cluster and nodegroups
-func (c *Client) GetSecurityAnalysis(ctx context.Context, clusterName string) (map[string]string, error) {
-	findings := make(map[string]string)
+func (c *Client) GetSecurityAnalysis(ctx context.Context, clusterName string) ([]findings.Finding, error) {
+	var results []findings.Finding
 
 	// Get cluster details
 	cluster, err := c.DescribeCluster(ctx, clusterName)
@@ -564,49 +681,178 @@ func (c *Client) GetSecurityAnalysis(ctx context.Context, clusterName string) (m
 
 	// Check cluster encryption
 	if cluster.Cluster.EncryptionConfig == nil {
-		findings["cluster_encryption"] = "WARNING: Cluster encryption is not enabled"
+		results = append(results, findings.Finding{
+			ID:          "cluster_encryption",
+			Severity:    findings.SeverityWarning,
+			Category:    "security",
+			Resource:    clusterName,
+			Message:     "Cluster encryption is not enabled",
+			Remediation: "Enable envelope encryption of Kubernetes secrets with a KMS key",
+		})
 	} else {
-		findings["cluster_encryption"] = "OK: Cluster encryption is enabled"
+		results = append(results, findings.Finding{
+			ID:       "cluster_encryption",
+			Severity: findings.SeverityPass,
+			Category: "security",
+			Resource: clusterName,
+			Message:  "Cluster encryption is enabled",
+		})
 	}
 
 	// Check endpoint access
 	if cluster.Cluster.ResourcesVpcConfig.EndpointPublicAccess {
-		findings["endpoint_access"] = "WARNING: Public endpoint access is enabled"
+		results = append(results, findings.Finding{
+			ID:          "endpoint_access",
+			Severity:    findings.SeverityWarning,
+			Category:    "security",
+			Resource:    clusterName,
+			Message:     "Public endpoint access is enabled",
+			Remediation: "Disable public endpoint access or restrict it with publicAccessCidrs",
+		})
 	} else {
-		findings["endpoint_access"] = "OK: Public endpoint access is disabled"
+		results = append(results, findings.Finding{
+			ID:       "endpoint_access",
+			Severity: findings.SeverityPass,
+			Category: "security",
+			Resource: clusterName,
+			Message:  "Public endpoint access is disabled",
+		})
 	}
 
 	// Check logging
 	if cluster.Cluster.Logging != nil && len(cluster.Cluster.Logging.ClusterLogging) > 0 {
-		findings["logging"] = "OK: Cluster logging is configured"
+		results = append(results, findings.Finding{
+			ID:       "logging",
+			Severity: findings.SeverityPass,
+			Category: "security",
+			Resource: clusterName,
+			Message:  "Cluster logging is configured",
+		})
 	} else {
-		findings["logging"] = "WARNING: Cluster logging is not configured"
+		results = append(results, findings.Finding{
+			ID:          "logging",
+			Severity:    findings.SeverityWarning,
+			Category:    "security",
+			Resource:    clusterName,
+			Message:     "Cluster logging is not configured",
+			Remediation: "Enable audit and authenticator control plane logs",
+		})
 	}
 
 	// Check nodegroups
 	nodegroups, err := c.GetClusterNodegroups(ctx, clusterName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nodegroups: %w", err)
+		if !IsAccessDenied(err) {
+			return nil, fmt.Errorf("failed to get nodegroups: %w", err)
+		}
+		// Report the missing permission and continue with the remaining checks
+		results = append(results, findings.Finding{
+			ID:       "nodegroups",
+			Severity: findings.SeverityWarning,
+			Category: "security",
+			Resource: clusterName,
+			Message:  fmt.Sprintf("Skipped nodegroup checks: %v", err),
+		})
+	}
+
+	// Route tables tell whether nodegroup subnets are public
+	var (
+		routeTables    []ec2types.RouteTable
+		routeTablesErr error
+	)
+	if len(nodegroups) > 0 {
+		routeTables, routeTablesErr = c.GetRouteTables(ctx, aws.ToString(cluster.Cluster.ResourcesVpcConfig.VpcId))
+		if routeTablesErr != nil {
+			results = append(results, findings.Finding{
+				ID:       "nodegroup_public_ip",
+				Severity: findings.SeverityWarning,
+				Category: "security",
+				Resource: clusterName,
+				Message:  fmt.Sprintf("Failed to check public subnets: %v", routeTablesErr),
+			})
+		}
 	}
 
 	for _, ng := range nodegroups {
 		ngName := *ng.NodegroupName
 
+		// Check public IPs
+		if routeTablesErr == nil {
+			results = append(results, c.nodegroupPublicIPFinding(ctx, ng, routeTables))
+		}
+
 		// Check remote access
 		if ng.RemoteAccess != nil && len(ng.RemoteAccess.SourceSecurityGroups) == 0 {
-			findings[fmt.Sprintf("nodegroup_%s_remote_access", ngName)] = 
-				"WARNING: Nodegroup remote access is not restricted by security groups"
+			results = append(results, findings.Finding{
+				ID:          "nodegroup_remote_access",
+				Severity:    findings.SeverityWarning,
+				Category:    "security",
+				Resource:    ngName,
+				Message:     "Nodegroup remote access is not restricted by security groups",
+				Remediation: "Set sourceSecurityGroups on the nodegroup's remote access configuration",
+			})
 		}
 
 		// Check IAM roles
 		if ng.NodeRole != nil {
-			findings[fmt.Sprintf("nodegroup_%s_iam", ngName)] = "OK: Nodegroup has IAM role configured"
+			results = append(results, findings.Finding{
+				ID:       "nodegroup_iam",
+				Severity: findings.SeverityPass,
+				Category: "security",
+				Resource: ngName,
+				Message:  "Nodegroup has IAM role configured",
+			})
 		} else {
-			findings[fmt.Sprintf("nodegroup_%s_iam", ngName)] = "WARNING: Nodegroup IAM role not found"
+			results = append(results, findings.Finding{
+				ID:          "nodegroup_iam",
+				Severity:    findings.SeverityWarning,
+				Category:    "security",
+				Resource:    ngName,
+				Message:     "Nodegroup IAM role not found",
+				Remediation: "Attach a node IAM role to the nodegroup",
+			})
+		}
+	}
+
+	// Check the instance metadata options of the nodes
+	metadataOptions, err := c.GetNodeMetadataOptions(ctx, clusterName)
+	if err != nil {
+		results = append(results, findings.Finding{
+			ID:       "node_imds",
+			Severity: findings.SeverityWarning,
+			Category: "security",
+			Resource: clusterName,
+			Message:  fmt.Sprintf("Failed to check instance metadata options: %v", err),
+		})
+	} else {
+		results = append(results, nodeMetadataFindings(metadataOptions)...)
+	}
+
+	// Audit security group rules exposed to the internet
+	sgFindings, err := c.AuditSecurityGroups(ctx, cluster.Cluster, nodegroups)
+	if err != nil {
+		results = append(results, findings.Finding{
+			ID:       "security_groups",
+			Severity: findings.SeverityWarning,
+			Category: "security",
+			Resource: clusterName,
+			Message:  fmt.Sprintf("Failed to audit security groups: %v", err),
+		})
+	} else {
+		if !hasOpenIngress(sgFindings) {
+			results = append(results, findings.Finding{
+				ID:       "security_groups",
+				Severity: findings.SeverityPass,
+				Category: "security",
+				Resource: clusterName,
+				Message:  "No security group rules open to the internet on sensitive ports",
+			})
 		}
+		results = append(results, sgFindings...)
 	}
 
-	return findings, nil
+	findings.Sort(results)
+	return results, nil
 }
 
 // Helper functions
@@ -642,10 +888,12 @@ func (c *Client) ListNodegroups(ctx context.Context, clusterName string) ([]stri
         ClusterName: aws.String(clusterName),
     }
 
-    result, err := c.EKSClient.ListNodegroups(ctx, input)
-    if err != nil {
-        return nil, fmt.Errorf("failed to list nodegroups: %w", err)
-    }
+	result, err := withCredRefresh(ctx, c, func() (*eks.ListNodegroupsOutput, error) {
+		return c.EKSClient.ListNodegroups(ctx, input)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodegroups: %w", err)
+	}
 
     return result.Nodegroups, nil
 }
@@ -657,10 +905,12 @@ func (c *Client) DescribeNodegroup(ctx context.Context, clusterName, nodegroupNa
         NodegroupName: aws.String(nodegroupName),
     }
 
-    result, err := c.EKSClient.DescribeNodegroup(ctx, input)
-    if err != nil {
-        return nil, fmt.Errorf("failed to describe nodegroup %s: %w", nodegroupName, err)
-    }
+	result, err := withCredRefresh(ctx, c, func() (*eks.DescribeNodegroupOutput, error) {
+		return c.EKSClient.DescribeNodegroup(ctx, input)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe nodegroup %s: %w", nodegroupName, err)
+	}
 
     return result, nil
 }
@@ -0,0 +1,115 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+)
+
+// expiredCredentialCodes are the API error codes returned when credentials have expired
+var expiredCredentialCodes = map[string]bool{
+	"ExpiredToken":          true,
+	"ExpiredTokenException": true,
+	"RequestExpired":        true,
+}
+
+// withCredRefresh runs an AWS call and, if it fails because the credentials have expired,
+// reloads the credentials once and retries the call. Access denied errors are returned as
+// an *AccessDeniedError naming the action and the caller identity.
+func withCredRefresh[T any](ctx context.Context, c *Client, call func() (T, error)) (T, error) {
+	result, err := refreshOnExpiry(ctx, c, call)
+	return result, c.explainAccessDenied(ctx, err)
+}
+
+// Call runs an AWS call made with the service clients outside the Client's own methods,
+// such as the EKS handler's, with the credential refresh and access denied explanation of
+// withCredRefresh
+func (c *Client) Call(ctx context.Context, call func() error) error {
+	_, err := withCredRefresh(ctx, c, func() (struct{}, error) {
+		return struct{}{}, call()
+	})
+	return err
+}
+
+// refreshOnExpiry runs an AWS call and, if it fails because the credentials have expired,
+// reloads the credentials once and retries the call. Paginators can be retried too: a
+// failed NextPage does not advance the paginator.
+func refreshOnExpiry[T any](ctx context.Context, c *Client, call func() (T, error)) (T, error) {
+	result, err := call()
+	if err == nil || !isExpiredCredentialsError(err) || c.refresh == nil {
+		return result, err
+	}
+
+	if refreshErr := c.refresh(ctx); refreshErr != nil {
+		return result, fmt.Errorf("AWS credentials expired and could not be refreshed (%v): %w", refreshErr, err)
+	}
+
+	return call()
+}
+
+// isExpiredCredentialsError returns true if the error is caused by expired credentials
+func isExpiredCredentialsError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && expiredCredentialCodes[apiErr.ErrorCode()] {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "credentials expired") ||
+		strings.Contains(message, "security token included in the request is expired")
+}
+
+// reloadCredentials loads a fresh SDK config, which re-resolves the credential chain and
+// re-runs any assume-role provider, and swaps its credentials into the provider shared by
+// the service clients. The service clients themselves are never replaced, so goroutines
+// using them, and paginators built before the refresh, pick up the new credentials.
+func (c *Client) reloadCredentials(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	awsCfg, err := loadConfig(ctx, c.config)
+	if err != nil {
+		return fmt.Errorf("unable to reload SDK config: %w", err)
+	}
+	c.credentials.swap(awsCfg.Credentials)
+	return nil
+}
+
+// swappableCredentials holds the credentials of a client's service clients. The cache is
+// what the service clients use; it holds the credentials of the current provider until
+// they expire or the provider is swapped.
+type swappableCredentials struct {
+	cache *aws.CredentialsCache
+
+	mu       sync.RWMutex
+	provider aws.CredentialsProvider
+}
+
+func newSwappableCredentials(provider aws.CredentialsProvider) *swappableCredentials {
+	s := &swappableCredentials{provider: provider}
+	s.cache = aws.NewCredentialsCache(aws.CredentialsProviderFunc(s.retrieve))
+	return s
+}
+
+func (s *swappableCredentials) retrieve(ctx context.Context) (aws.Credentials, error) {
+	s.mu.RLock()
+	provider := s.provider
+	s.mu.RUnlock()
+	if provider == nil {
+		return aws.Credentials{}, errors.New("no AWS credentials provider configured")
+	}
+	return provider.Retrieve(ctx)
+}
+
+// swap replaces the provider and drops the cached credentials
+func (s *swappableCredentials) swap(provider aws.CredentialsProvider) {
+	s.mu.Lock()
+	s.provider = provider
+	s.mu.Unlock()
+	s.cache.Invalidate()
+}
@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+)
+
+func TestWithCredRefresh(t *testing.T) {
+	expired := &smithy.GenericAPIError{Code: "ExpiredToken", Message: "The security token included in the request is expired"}
+
+	testCases := []struct {
+		name            string
+		errs            []error
+		expectedCalls   int
+		expectedRefresh int
+		expectError     bool
+	}{
+		{
+			name:            "Expired token then success",
+			errs:            []error{expired, nil},
+			expectedCalls:   2,
+			expectedRefresh: 1,
+			expectError:     false,
+		},
+		{
+			name:            "Expired token twice fails after one refresh",
+			errs:            []error{expired, expired},
+			expectedCalls:   2,
+			expectedRefresh: 1,
+			expectError:     true,
+		},
+		{
+			name:            "Other errors are not retried",
+			errs:            []error{errors.New("AccessDenied")},
+			expectedCalls:   1,
+			expectedRefresh: 0,
+			expectError:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			refreshes := 0
+			client := &Client{
+				refresh: func(ctx context.Context) error {
+					refreshes++
+					return nil
+				},
+			}
+
+			calls := 0
+			result, err := withCredRefresh(context.Background(), client, func() (string, error) {
+				err := tc.errs[calls]
+				calls++
+				if err != nil {
+					return "", err
+				}
+				return "ok", nil
+			})
+
+			if tc.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !tc.expectError && result != "ok" {
+				t.Errorf("Expected result ok, got %q", result)
+			}
+			if calls != tc.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+			if refreshes != tc.expectedRefresh {
+				t.Errorf("Expected %d refreshes, got %d", tc.expectedRefresh, refreshes)
+			}
+		})
+	}
+}
+
+func TestClientCall(t *testing.T) {
+	expired := &smithy.GenericAPIError{Code: "ExpiredToken", Message: "The security token included in the request is expired"}
+	refreshes := 0
+	client := &Client{
+		refresh: func(ctx context.Context) error {
+			refreshes++
+			return nil
+		},
+	}
+
+	calls := 0
+	err := client.Call(context.Background(), func() error {
+		calls++
+		if calls == 1 {
+			return expired
+		}
+		return nil
+	})
+	if err != nil || calls != 2 || refreshes != 1 {
+		t.Errorf("Call() = %v after %d calls and %d refreshes, want nil after 2 calls and 1 refresh", err, calls, refreshes)
+	}
+}
+
+func TestSwappableCredentials(t *testing.T) {
+	static := func(key string) aws.CredentialsProvider {
+		return aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: key, CanExpire: true, Expires: time.Now().Add(time.Hour)}, nil
+		})
+	}
+	credentials := newSwappableCredentials(static("old"))
+
+	creds, err := credentials.cache.Retrieve(context.Background())
+	if err != nil || creds.AccessKeyID != "old" {
+		t.Fatalf("Retrieve() = %q, %v, want old", creds.AccessKeyID, err)
+	}
+
+	// The old credentials are still cached and valid, as after a server-side expiry
+	credentials.swap(static("new"))
+	creds, err = credentials.cache.Retrieve(context.Background())
+	if err != nil || creds.AccessKeyID != "new" {
+		t.Errorf("Retrieve() after swap = %q, %v, want new", creds.AccessKeyID, err)
+	}
+}
@@ -0,0 +1,260 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// ecrImagePattern matches private ECR image references, e.g.
+// 123456789012.dkr.ecr.us-west-2.amazonaws.com/team/app:1.2
+var ecrImagePattern = regexp.MustCompile(`^(\d{12})\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?/([^:@]+)`)
+
+// ecrPullActions are the IAM actions a node role needs to pull from ECR
+var ecrPullActions = []string{
+	"ecr:GetAuthorizationToken",
+	"ecr:BatchGetImage",
+	"ecr:GetDownloadUrlForLayer",
+}
+
+// ECRImageRef is an image reference hosted in a private ECR registry
+type ECRImageRef struct {
+	Image      string
+	AccountID  string
+	Region     string
+	Repository string
+	Tag        string // Empty when the reference has no tag
+}
+
+// ARN returns the repository ARN
+func (r ECRImageRef) ARN() string {
+	partition := "aws"
+	if strings.HasPrefix(r.Region, "cn-") {
+		partition = "aws-cn"
+	} else if strings.HasPrefix(r.Region, "us-gov-") {
+		partition = "aws-us-gov"
+	}
+	return fmt.Sprintf("arn:%s:ecr:%s:%s:repository/%s", partition, r.Region, r.AccountID, r.Repository)
+}
+
+// ParseECRImage parses an image reference, returning false if it is not a private ECR image
+func ParseECRImage(image string) (ECRImageRef, bool) {
+	match := ecrImagePattern.FindStringSubmatch(image)
+	if match == nil {
+		return ECRImageRef{}, false
+	}
+	ref := ECRImageRef{
+		Image:      image,
+		AccountID:  match[1],
+		Region:     match[2],
+		Repository: match[3],
+	}
+	rest := strings.TrimPrefix(image, match[0])
+	if i := strings.Index(rest, "@"); i >= 0 {
+		rest = rest[:i]
+	}
+	ref.Tag = strings.TrimPrefix(rest, ":")
+	return ref, true
+}
+
+// ECRImageDetail is the size and push time of an image in ECR
+type ECRImageDetail struct {
+	SizeBytes int64
+	PushedAt  time.Time
+}
+
+// DescribeECRImage returns the size and push time of an ECR image, looked up by digest
+// or, when digest is empty, by the reference's tag
+func (c *Client) DescribeECRImage(ctx context.Context, ref ECRImageRef, digest string) (*ECRImageDetail, error) {
+	id := ecrtypes.ImageIdentifier{ImageDigest: aws.String(digest)}
+	if digest == "" {
+		tag := ref.Tag
+		if tag == "" {
+			tag = "latest"
+		}
+		id = ecrtypes.ImageIdentifier{ImageTag: aws.String(tag)}
+	}
+
+	result, err := withCredRefresh(ctx, c, func() (*ecr.DescribeImagesOutput, error) {
+		return c.ECRClient.DescribeImages(ctx, &ecr.DescribeImagesInput{
+			RegistryId:     aws.String(ref.AccountID),
+			RepositoryName: aws.String(ref.Repository),
+			ImageIds:       []ecrtypes.ImageIdentifier{id},
+		}, func(o *ecr.Options) { o.Region = ref.Region })
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe image %s: %w", ref.Image, err)
+	}
+	if len(result.ImageDetails) == 0 {
+		return nil, fmt.Errorf("image %s not found", ref.Image)
+	}
+
+	detail := result.ImageDetails[0]
+	return &ECRImageDetail{
+		SizeBytes: aws.ToInt64(detail.ImageSizeInBytes),
+		PushedAt:  aws.ToTime(detail.ImagePushedAt),
+	}, nil
+}
+
+// ECRPullCheck is the result of checking whether a node role can pull an ECR image
+type ECRPullCheck struct {
+	Ref              ECRImageRef
+	CrossAccount     bool
+	RepositoryExists bool
+	RepositoryError  string // set when the repository could not be described
+	PolicyAllows     *bool  // whether the repository policy grants the cluster account; nil if unknown or not needed
+	PolicyError      string
+	NodeRole         string
+	RoleCanPull      *bool // nil when the role's permissions could not be simulated
+	DeniedActions    []string
+	RoleError        string
+}
+
+// CheckECRPull checks that an ECR repository exists and that the node role can pull from it.
+// clusterAccount is the account the cluster runs in, used to detect cross-account pulls.
+func (c *Client) CheckECRPull(ctx context.Context, ref ECRImageRef, clusterAccount, nodeRoleARN string) *ECRPullCheck {
+	check := &ECRPullCheck{
+		Ref:          ref,
+		CrossAccount: clusterAccount != "" && ref.AccountID != clusterAccount,
+		NodeRole:     nodeRoleARN,
+	}
+	inRegion := func(o *ecr.Options) { o.Region = ref.Region }
+
+	_, err := withCredRefresh(ctx, c, func() (*ecr.DescribeRepositoriesOutput, error) {
+		return c.ECRClient.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
+			RegistryId:      aws.String(ref.AccountID),
+			RepositoryNames: []string{ref.Repository},
+		}, inRegion)
+	})
+	var notFound *ecrtypes.RepositoryNotFoundException
+	switch {
+	case err == nil:
+		check.RepositoryExists = true
+	case errors.As(err, &notFound):
+		check.RepositoryError = "repository does not exist"
+	default:
+		check.RepositoryError = fmt.Sprintf("failed to describe repository: %v", err)
+	}
+
+	if check.CrossAccount && check.RepositoryExists {
+		policy, err := withCredRefresh(ctx, c, func() (*ecr.GetRepositoryPolicyOutput, error) {
+			return c.ECRClient.GetRepositoryPolicy(ctx, &ecr.GetRepositoryPolicyInput{
+				RegistryId:     aws.String(ref.AccountID),
+				RepositoryName: aws.String(ref.Repository),
+			}, inRegion)
+		})
+		var noPolicy *ecrtypes.RepositoryPolicyNotFoundException
+		switch {
+		case err == nil:
+			allows := repositoryPolicyAllows(aws.ToString(policy.PolicyText), clusterAccount, nodeRoleARN)
+			check.PolicyAllows = &allows
+		case errors.As(err, &noPolicy):
+			allows := false
+			check.PolicyAllows = &allows
+		default:
+			check.PolicyError = fmt.Sprintf("failed to get repository policy: %v", err)
+		}
+	}
+
+	if nodeRoleARN != "" {
+		denied, err := c.simulatePull(ctx, nodeRoleARN, ref.ARN())
+		if err != nil {
+			check.RoleError = err.Error()
+		} else {
+			canPull := len(denied) == 0
+			check.RoleCanPull = &canPull
+			check.DeniedActions = denied
+		}
+	}
+
+	return check
+}
+
+// simulatePull returns the ECR pull actions the role is not allowed to perform on the repository
+func (c *Client) simulatePull(ctx context.Context, roleARN, repositoryARN string) ([]string, error) {
+	result, err := withCredRefresh(ctx, c, func() (*iam.SimulatePrincipalPolicyOutput, error) {
+		return c.IAMClient.SimulatePrincipalPolicy(ctx, &iam.SimulatePrincipalPolicyInput{
+			PolicySourceArn: aws.String(roleARN),
+			ActionNames:     ecrPullActions,
+			ResourceArns:    []string{repositoryARN},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate node role policy: %w", err)
+	}
+
+	var denied []string
+	for _, r := range result.EvaluationResults {
+		if r.EvalDecision != "allowed" {
+			denied = append(denied, aws.ToString(r.EvalActionName))
+		}
+	}
+	return denied, nil
+}
+
+// repositoryPolicyAllows returns true if an ECR repository policy has an Allow statement
+// granting image pulls to the account or role
+func repositoryPolicyAllows(policyText, account, roleARN string) bool {
+	var policy struct {
+		Statement []struct {
+			Effect    string
+			Principal interface{}
+			Action    interface{}
+		}
+	}
+	if err := json.Unmarshal([]byte(policyText), &policy); err != nil {
+		return false
+	}
+
+	for _, stmt := range policy.Statement {
+		if stmt.Effect != "Allow" || !grantsPull(stmt.Action) {
+			continue
+		}
+		for _, principal := range policyStrings(stmt.Principal) {
+			if principal == "*" || principal == account || principal == roleARN ||
+				strings.Contains(principal, ":"+account+":") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func grantsPull(action interface{}) bool {
+	for _, a := range policyStrings(action) {
+		if a == "*" || a == "ecr:*" || a == "ecr:BatchGetImage" {
+			return true
+		}
+	}
+	return false
+}
+
+// policyStrings flattens a policy element that may be a string, a list, or a map of either
+func policyStrings(v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []interface{}:
+		var result []string
+		for _, item := range val {
+			result = append(result, policyStrings(item)...)
+		}
+		return result
+	case map[string]interface{}:
+		var result []string
+		for _, item := range val {
+			result = append(result, policyStrings(item)...)
+		}
+		return result
+	}
+	return nil
+}
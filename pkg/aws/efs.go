@@ -0,0 +1,248 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// nfsPort is the port EFS mount targets serve NFS on
+const nfsPort = 2049
+
+// efsMountTargetDescription prefixes the description EFS gives mount target network
+// interfaces: "EFS mount target for fs-0123 (fsmt-0456)"
+const efsMountTargetDescription = "EFS mount target for "
+
+// EFSMountTarget is an EFS mount target, read from its network interface
+type EFSMountTarget struct {
+	ID               string
+	FileSystemID     string
+	SubnetID         string
+	AvailabilityZone string
+	IPAddress        string
+	SecurityGroups   []string
+}
+
+// EFSReachability is whether the nodes in one availability zone can mount a file system
+type EFSReachability struct {
+	FileSystemID     string
+	AvailabilityZone string
+	Nodes            []string
+	// MountTarget is the file system's mount target in the zone, nil if it has none
+	MountTarget *EFSMountTarget
+	// BlockedNodes are the nodes the mount target security groups do not allow on TCP 2049,
+	// neither by node security group nor by an address range holding the node IP
+	BlockedNodes []string
+}
+
+// Reachable returns true if the zone has a mount target that allows NFS from all its nodes
+func (r EFSReachability) Reachable() bool {
+	return r.MountTarget != nil && len(r.BlockedNodes) == 0
+}
+
+// efsNodeInstance is the placement and network settings of a node's instance
+type efsNodeInstance struct {
+	name             string
+	availabilityZone string
+	ips              []net.IP
+	securityGroups   map[string]bool
+}
+
+// GetEFSMountTargets returns the mount targets of a file system. They are read from the
+// mount target network interfaces, so only mount targets in VPCs of this account are found.
+func (c *Client) GetEFSMountTargets(ctx context.Context, fileSystemID string) ([]EFSMountTarget, error) {
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("description"), Values: []string{efsMountTargetDescription + fileSystemID + " (*"}},
+		},
+	}
+
+	var targets []EFSMountTarget
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(c.EC2Client, input)
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*ec2.DescribeNetworkInterfacesOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe mount target network interfaces: %w", err)
+		}
+		for _, eni := range page.NetworkInterfaces {
+			target := EFSMountTarget{
+				ID:               mountTargetID(aws.ToString(eni.Description)),
+				FileSystemID:     fileSystemID,
+				SubnetID:         aws.ToString(eni.SubnetId),
+				AvailabilityZone: aws.ToString(eni.AvailabilityZone),
+				IPAddress:        aws.ToString(eni.PrivateIpAddress),
+			}
+			for _, group := range eni.Groups {
+				target.SecurityGroups = append(target.SecurityGroups, aws.ToString(group.GroupId))
+			}
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// mountTargetID extracts the mount target ID from its network interface description
+func mountTargetID(description string) string {
+	start, end := strings.LastIndex(description, "("), strings.LastIndex(description, ")")
+	if start < 0 || end < start {
+		return ""
+	}
+	return description[start+1 : end]
+}
+
+// CheckEFSReachability checks, for each file system and each availability zone with
+// nodes, that the file system has a mount target in the zone and that the mount target
+// security groups allow NFS (TCP 2049) from the nodes, given as node names by instance ID
+func (c *Client) CheckEFSReachability(ctx context.Context, fileSystemIDs []string, nodes map[string]string) ([]EFSReachability, error) {
+	instances, err := c.describeEFSNodes(ctx, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make(map[string][]efsNodeInstance)
+	for _, inst := range instances {
+		zones[inst.availabilityZone] = append(zones[inst.availabilityZone], inst)
+	}
+	zoneNames := make([]string, 0, len(zones))
+	for zone := range zones {
+		zoneNames = append(zoneNames, zone)
+	}
+	sort.Strings(zoneNames)
+
+	var results []EFSReachability
+	for _, fsID := range fileSystemIDs {
+		targets, err := c.GetEFSMountTargets(ctx, fsID)
+		if err != nil {
+			return nil, err
+		}
+
+		var groupIDs []string
+		byZone := make(map[string]*EFSMountTarget)
+		for i := range targets {
+			byZone[targets[i].AvailabilityZone] = &targets[i]
+			groupIDs = append(groupIDs, targets[i].SecurityGroups...)
+		}
+		groups, err := c.describeSecurityGroups(ctx, uniqueStrings(groupIDs))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, zone := range zoneNames {
+			r := EFSReachability{
+				FileSystemID:     fsID,
+				AvailabilityZone: zone,
+				MountTarget:      byZone[zone],
+			}
+			for _, inst := range zones[zone] {
+				r.Nodes = append(r.Nodes, inst.name)
+				if r.MountTarget != nil && !allowsNFS(r.MountTarget.SecurityGroups, groups, inst) {
+					r.BlockedNodes = append(r.BlockedNodes, inst.name)
+				}
+			}
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+// describeEFSNodes looks up the availability zone, private IPs and security groups of the
+// nodes' instances
+func (c *Client) describeEFSNodes(ctx context.Context, nodes map[string]string) ([]efsNodeInstance, error) {
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	sort.Strings(ids)
+
+	var instances []efsNodeInstance
+	paginator := ec2.NewDescribeInstancesPaginator(c.EC2Client, &ec2.DescribeInstancesInput{InstanceIds: ids})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*ec2.DescribeInstancesOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe node instances: %w", err)
+		}
+		for _, r := range page.Reservations {
+			for _, inst := range r.Instances {
+				node := efsNodeInstance{
+					name:           nodes[aws.ToString(inst.InstanceId)],
+					securityGroups: make(map[string]bool),
+				}
+				if inst.Placement != nil {
+					node.availabilityZone = aws.ToString(inst.Placement.AvailabilityZone)
+				}
+				for _, eni := range inst.NetworkInterfaces {
+					for _, addr := range eni.PrivateIpAddresses {
+						if ip := net.ParseIP(aws.ToString(addr.PrivateIpAddress)); ip != nil {
+							node.ips = append(node.ips, ip)
+						}
+					}
+					for _, group := range eni.Groups {
+						node.securityGroups[aws.ToString(group.GroupId)] = true
+					}
+				}
+				instances = append(instances, node)
+			}
+		}
+	}
+	return instances, nil
+}
+
+// describeSecurityGroups returns the security groups by ID
+func (c *Client) describeSecurityGroups(ctx context.Context, groupIDs []string) (map[string]ec2types.SecurityGroup, error) {
+	groups := make(map[string]ec2types.SecurityGroup, len(groupIDs))
+	if len(groupIDs) == 0 {
+		return groups, nil
+	}
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeSecurityGroupsOutput, error) {
+		return c.EC2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{GroupIds: groupIDs})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe security groups: %w", err)
+	}
+	for _, sg := range result.SecurityGroups {
+		groups[aws.ToString(sg.GroupId)] = sg
+	}
+	return groups, nil
+}
+
+// allowsNFS returns true if an ingress rule of the mount target security groups allows
+// TCP 2049 from one of the node's security groups or from a range holding a node IP
+func allowsNFS(groupIDs []string, groups map[string]ec2types.SecurityGroup, node efsNodeInstance) bool {
+	for _, id := range groupIDs {
+		for _, rule := range groups[id].IpPermissions {
+			if !ruleCoversPorts(rule, nfsPort, nfsPort) {
+				continue
+			}
+			for _, pair := range rule.UserIdGroupPairs {
+				if node.securityGroups[aws.ToString(pair.GroupId)] {
+					return true
+				}
+			}
+			for _, r := range rule.IpRanges {
+				_, cidr, err := net.ParseCIDR(aws.ToString(r.CidrIp))
+				if err != nil {
+					continue
+				}
+				for _, ip := range node.ips {
+					if cidr.Contains(ip) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package aws
+
+import (
+	"net"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestAllowsNFS(t *testing.T) {
+	node := efsNodeInstance{
+		name:             "node-1",
+		availabilityZone: "us-east-1a",
+		ips:              []net.IP{net.ParseIP("10.0.1.15")},
+		securityGroups:   map[string]bool{"sg-node": true},
+	}
+	nfsRule := func(rule ec2types.IpPermission) ec2types.IpPermission {
+		rule.IpProtocol = aws.String("tcp")
+		rule.FromPort = aws.Int32(nfsPort)
+		rule.ToPort = aws.Int32(nfsPort)
+		return rule
+	}
+
+	testCases := []struct {
+		name     string
+		rules    []ec2types.IpPermission
+		expected bool
+	}{
+		{
+			name: "Node security group pair",
+			rules: []ec2types.IpPermission{nfsRule(ec2types.IpPermission{
+				UserIdGroupPairs: []ec2types.UserIdGroupPair{{GroupId: aws.String("sg-node")}},
+			})},
+			expected: true,
+		},
+		{
+			name: "Other security group pair",
+			rules: []ec2types.IpPermission{nfsRule(ec2types.IpPermission{
+				UserIdGroupPairs: []ec2types.UserIdGroupPair{{GroupId: aws.String("sg-other")}},
+			})},
+			expected: false,
+		},
+		{
+			name: "CIDR holding the node IP",
+			rules: []ec2types.IpPermission{nfsRule(ec2types.IpPermission{
+				IpRanges: []ec2types.IpRange{{CidrIp: aws.String("10.0.0.0/16")}},
+			})},
+			expected: true,
+		},
+		{
+			name: "CIDR without the node IP",
+			rules: []ec2types.IpPermission{nfsRule(ec2types.IpPermission{
+				IpRanges: []ec2types.IpRange{{CidrIp: aws.String("10.1.0.0/16")}},
+			})},
+			expected: false,
+		},
+		{
+			name: "All traffic from the node security group",
+			rules: []ec2types.IpPermission{{
+				IpProtocol:       aws.String("-1"),
+				UserIdGroupPairs: []ec2types.UserIdGroupPair{{GroupId: aws.String("sg-node")}},
+			}},
+			expected: true,
+		},
+		{
+			name: "Node security group on another port",
+			rules: []ec2types.IpPermission{{
+				IpProtocol:       aws.String("tcp"),
+				FromPort:         aws.Int32(443),
+				ToPort:           aws.Int32(443),
+				UserIdGroupPairs: []ec2types.UserIdGroupPair{{GroupId: aws.String("sg-node")}},
+			}},
+			expected: false,
+		},
+		{
+			name: "UDP from the node security group",
+			rules: []ec2types.IpPermission{{
+				IpProtocol:       aws.String("udp"),
+				FromPort:         aws.Int32(nfsPort),
+				ToPort:           aws.Int32(nfsPort),
+				UserIdGroupPairs: []ec2types.UserIdGroupPair{{GroupId: aws.String("sg-node")}},
+			}},
+			expected: false,
+		},
+		{
+			name:     "No rules",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			groups := map[string]ec2types.SecurityGroup{
+				"sg-efs": {GroupId: aws.String("sg-efs"), IpPermissions: tc.rules},
+			}
+			if got := allowsNFS([]string{"sg-efs"}, groups, node); got != tc.expected {
+				t.Errorf("allowsNFS() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMountTargetID(t *testing.T) {
+	testCases := []struct {
+		description string
+		expected    string
+	}{
+		{description: "EFS mount target for fs-0123 (fsmt-0456)", expected: "fsmt-0456"},
+		{description: "EFS mount target for fs-0123", expected: ""},
+		{description: "EFS mount target for fs-0123 )fsmt-0456(", expected: ""},
+		{description: "", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		if got := mountTargetID(tc.description); got != tc.expected {
+			t.Errorf("mountTargetID(%q) = %q, want %q", tc.description, got, tc.expected)
+		}
+	}
+}
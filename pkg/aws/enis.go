@@ -0,0 +1,92 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const (
+	// cniInstanceTag and cniCreatedAtTag are set by the VPC CNI (ipamd) on the ENIs it creates
+	cniInstanceTag  = "node.k8s.amazonaws.com/instance_id"
+	cniCreatedAtTag = "node.k8s.amazonaws.com/createdAt"
+	// orphanedENIGracePeriod skips ENIs ipamd has just created and not attached yet
+	orphanedENIGracePeriod = 5 * time.Minute
+)
+
+// OrphanedENI is an ENI created by the VPC CNI that is no longer attached to an instance.
+// Each one holds its IP addresses until it is deleted.
+type OrphanedENI struct {
+	ID               string
+	SubnetID         string
+	AvailabilityZone string
+	// InstanceID is the instance the ENI was created for
+	InstanceID string
+	IPs        int
+	Created    time.Time // Zero when the createdAt tag is missing
+}
+
+// GetOrphanedENIs lists the ENIs in the cluster VPC that the VPC CNI created for a node
+// (tagged node.k8s.amazonaws.com/instance_id) and that are in the available state, i.e.
+// attached to no instance. They are leaked when nodes are terminated before ipamd frees
+// them and slowly exhaust the subnet.
+func (c *Client) GetOrphanedENIs(ctx context.Context, clusterName string) ([]OrphanedENI, error) {
+	cluster, err := c.DescribeCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Cluster.ResourcesVpcConfig == nil {
+		return nil, fmt.Errorf("cluster has no VPC configured")
+	}
+	vpcID := aws.ToString(cluster.Cluster.ResourcesVpcConfig.VpcId)
+
+	var enis []OrphanedENI
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(c.EC2Client, &ec2.DescribeNetworkInterfacesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("vpc-id"), Values: []string{vpcID}},
+			{Name: aws.String("status"), Values: []string{string(ec2types.NetworkInterfaceStatusAvailable)}},
+			{Name: aws.String("tag-key"), Values: []string{cniInstanceTag}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*ec2.DescribeNetworkInterfacesOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe network interfaces: %w", err)
+		}
+		for _, ni := range page.NetworkInterfaces {
+			eni := OrphanedENI{
+				ID:               aws.ToString(ni.NetworkInterfaceId),
+				SubnetID:         aws.ToString(ni.SubnetId),
+				AvailabilityZone: aws.ToString(ni.AvailabilityZone),
+				IPs:              len(ni.PrivateIpAddresses) + len(ni.Ipv4Prefixes)*16,
+			}
+			for _, tag := range ni.TagSet {
+				switch aws.ToString(tag.Key) {
+				case cniInstanceTag:
+					eni.InstanceID = aws.ToString(tag.Value)
+				case cniCreatedAtTag:
+					eni.Created, _ = time.Parse(time.RFC3339, aws.ToString(tag.Value))
+				}
+			}
+			if !eni.Created.IsZero() && time.Since(eni.Created) < orphanedENIGracePeriod {
+				continue
+			}
+			enis = append(enis, eni)
+		}
+	}
+
+	sort.Slice(enis, func(i, j int) bool {
+		if enis[i].SubnetID != enis[j].SubnetID {
+			return enis[i].SubnetID < enis[j].SubnetID
+		}
+		return enis[i].ID < enis[j].ID
+	})
+	return enis, nil
+}
@@ -0,0 +1,204 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// RequiredClusterRolePolicies are the managed policies the EKS cluster role needs
+var RequiredClusterRolePolicies = []string{
+	"AmazonEKSClusterPolicy",
+}
+
+// RequiredNodeRolePolicies are the managed policies EKS worker node roles need
+var RequiredNodeRolePolicies = []string{
+	"AmazonEKSWorkerNodePolicy",
+	"AmazonEC2ContainerRegistryReadOnly",
+	"AmazonEKS_CNI_Policy",
+}
+
+// RolePolicyReport contains the policies attached to an IAM role and any problems found
+type RolePolicyReport struct {
+	RoleName           string
+	ManagedPolicies    []string
+	InlinePolicies     []string
+	MissingPolicies    []string
+	PermissivePolicies []string
+}
+
+// policyDocument is the subset of an IAM policy document needed for analysis
+type policyDocument struct {
+	Statement policyStatements `json:"Statement"`
+}
+
+type policyStatement struct {
+	Effect   string       `json:"Effect"`
+	Action   stringOrList `json:"Action"`
+	Resource stringOrList `json:"Resource"`
+}
+
+// policyStatements accepts either a single statement object or a list of statements
+type policyStatements []policyStatement
+
+func (p *policyStatements) UnmarshalJSON(data []byte) error {
+	var list []policyStatement
+	if err := json.Unmarshal(data, &list); err == nil {
+		*p = list
+		return nil
+	}
+	var single policyStatement
+	if err := json.Unmarshal(data, &single); err != nil {
+		return err
+	}
+	*p = []policyStatement{single}
+	return nil
+}
+
+// stringOrList accepts either a single string or a list of strings
+type stringOrList []string
+
+func (s *stringOrList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*s = list
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(data, &single); err != nil {
+		return err
+	}
+	*s = []string{single}
+	return nil
+}
+
+// AnalyzeRolePolicies lists the managed and inline policies attached to a role, reports
+// required policies that are missing, and flags policies granting "*" on "*"
+func (c *Client) AnalyzeRolePolicies(ctx context.Context, roleARN string, required []string) (*RolePolicyReport, error) {
+	roleName := extractRoleNameFromARN(roleARN)
+	if roleName == "" {
+		return nil, fmt.Errorf("invalid role ARN %s", roleARN)
+	}
+
+	report := &RolePolicyReport{RoleName: roleName}
+
+	attached, err := withCredRefresh(ctx, c, func() (*iam.ListAttachedRolePoliciesOutput, error) {
+		return c.IAMClient.ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{
+			RoleName: aws.String(roleName),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attached policies for role %s: %w", roleName, err)
+	}
+
+	attachedNames := make(map[string]bool)
+	for _, policy := range attached.AttachedPolicies {
+		name := aws.ToString(policy.PolicyName)
+		attachedNames[name] = true
+		report.ManagedPolicies = append(report.ManagedPolicies, name)
+
+		document, err := c.getManagedPolicyDocument(ctx, aws.ToString(policy.PolicyArn))
+		if err != nil {
+			continue
+		}
+		if isOverlyPermissive(document) {
+			report.PermissivePolicies = append(report.PermissivePolicies, name)
+		}
+	}
+
+	inline, err := withCredRefresh(ctx, c, func() (*iam.ListRolePoliciesOutput, error) {
+		return c.IAMClient.ListRolePolicies(ctx, &iam.ListRolePoliciesInput{
+			RoleName: aws.String(roleName),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inline policies for role %s: %w", roleName, err)
+	}
+
+	for _, name := range inline.PolicyNames {
+		report.InlinePolicies = append(report.InlinePolicies, name)
+
+		policy, err := withCredRefresh(ctx, c, func() (*iam.GetRolePolicyOutput, error) {
+			return c.IAMClient.GetRolePolicy(ctx, &iam.GetRolePolicyInput{
+				RoleName:   aws.String(roleName),
+				PolicyName: aws.String(name),
+			})
+		})
+		if err != nil {
+			continue
+		}
+		if isOverlyPermissive(aws.ToString(policy.PolicyDocument)) {
+			report.PermissivePolicies = append(report.PermissivePolicies, name)
+		}
+	}
+
+	for _, name := range required {
+		if !attachedNames[name] {
+			report.MissingPolicies = append(report.MissingPolicies, name)
+		}
+	}
+
+	return report, nil
+}
+
+// getManagedPolicyDocument returns the default version document of a managed policy
+func (c *Client) getManagedPolicyDocument(ctx context.Context, policyARN string) (string, error) {
+	policy, err := withCredRefresh(ctx, c, func() (*iam.GetPolicyOutput, error) {
+		return c.IAMClient.GetPolicy(ctx, &iam.GetPolicyInput{
+			PolicyArn: aws.String(policyARN),
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get policy %s: %w", policyARN, err)
+	}
+
+	version, err := withCredRefresh(ctx, c, func() (*iam.GetPolicyVersionOutput, error) {
+		return c.IAMClient.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
+			PolicyArn: aws.String(policyARN),
+			VersionId: policy.Policy.DefaultVersionId,
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get policy version for %s: %w", policyARN, err)
+	}
+
+	return aws.ToString(version.PolicyVersion.Document), nil
+}
+
+// isOverlyPermissive returns true if the policy document allows every action on every resource
+func isOverlyPermissive(document string) bool {
+	// IAM returns policy documents URL-encoded
+	decoded, err := url.QueryUnescape(document)
+	if err != nil {
+		decoded = document
+	}
+
+	var doc policyDocument
+	if err := json.Unmarshal([]byte(decoded), &doc); err != nil {
+		return false
+	}
+
+	for _, statement := range doc.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		if containsString(statement.Action, "*") && containsString(statement.Resource, "*") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
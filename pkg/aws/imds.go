@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ekspeek/pkg/findings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// maxListedInstances bounds the instance IDs named in a finding message
+const maxListedInstances = 5
+
+// NodeMetadataOptions are the instance metadata service settings of a node's EC2 instance
+type NodeMetadataOptions struct {
+	InstanceID string
+	// Group is the managed nodegroup or Karpenter NodePool of the instance, or
+	// "self-managed"
+	Group string
+	// Enabled is false when the metadata endpoint is turned off
+	Enabled bool
+	// IMDSv1 is true when HttpTokens is optional, so requests without a session token work
+	IMDSv1 bool
+	// HopLimit is the HttpPutResponseHopLimit. Above 1 the IMDSv2 token reaches pods that
+	// are not on the host network, and with it the node role credentials.
+	HopLimit int32
+}
+
+// GetNodeMetadataOptions returns the metadata options of the running instances tagged for
+// the cluster
+func (c *Client) GetNodeMetadataOptions(ctx context.Context, clusterName string) ([]NodeMetadataOptions, error) {
+	var nodes []NodeMetadataOptions
+	paginator := ec2.NewDescribeInstancesPaginator(c.EC2Client, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("tag-key"), Values: []string{"kubernetes.io/cluster/" + clusterName}},
+			{Name: aws.String("instance-state-name"), Values: []string{"running"}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*ec2.DescribeInstancesOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instances: %w", err)
+		}
+		for _, r := range page.Reservations {
+			for _, inst := range r.Instances {
+				node := NodeMetadataOptions{
+					InstanceID: aws.ToString(inst.InstanceId),
+					Group:      instanceGroup(inst.Tags),
+					Enabled:    true,
+				}
+				if opts := inst.MetadataOptions; opts != nil {
+					node.Enabled = opts.HttpEndpoint != ec2types.InstanceMetadataEndpointStateDisabled
+					node.IMDSv1 = opts.HttpTokens == ec2types.HttpTokensStateOptional
+					node.HopLimit = aws.ToInt32(opts.HttpPutResponseHopLimit)
+				}
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Group != nodes[j].Group {
+			return nodes[i].Group < nodes[j].Group
+		}
+		return nodes[i].InstanceID < nodes[j].InstanceID
+	})
+	return nodes, nil
+}
+
+// instanceGroup names the nodegroup or NodePool an instance belongs to from its tags
+func instanceGroup(tags []ec2types.Tag) string {
+	for _, tag := range tags {
+		switch aws.ToString(tag.Key) {
+		case "eks:nodegroup-name":
+			return "nodegroup " + aws.ToString(tag.Value)
+		case "karpenter.sh/nodepool":
+			return "NodePool " + aws.ToString(tag.Value)
+		}
+	}
+	return "self-managed"
+}
+
+// nodeMetadataFindings reports, per nodegroup or NodePool, instances that accept IMDSv1
+// requests or whose hop limit lets pods reach the metadata service
+func nodeMetadataFindings(nodes []NodeMetadataOptions) []findings.Finding {
+	type group struct{ imdsv1, hopLimit []string }
+	groups := make(map[string]*group)
+	var names []string
+	for _, node := range nodes {
+		g, ok := groups[node.Group]
+		if !ok {
+			g = &group{}
+			groups[node.Group] = g
+			names = append(names, node.Group)
+		}
+		if !node.Enabled {
+			continue
+		}
+		if node.IMDSv1 {
+			g.imdsv1 = append(g.imdsv1, node.InstanceID)
+		}
+		if node.HopLimit > 1 {
+			g.hopLimit = append(g.hopLimit, fmt.Sprintf("%s (%d)", node.InstanceID, node.HopLimit))
+		}
+	}
+
+	var results []findings.Finding
+	for _, name := range names {
+		g := groups[name]
+		if len(g.imdsv1) > 0 {
+			results = append(results, findings.Finding{
+				ID:          "node_imdsv1",
+				Severity:    findings.SeverityWarning,
+				Category:    "security",
+				Resource:    name,
+				Message:     fmt.Sprintf("%d instances allow IMDSv1 (HttpTokens optional), so an SSRF in any workload can read the node role credentials: %s", len(g.imdsv1), listInstances(g.imdsv1)),
+				Remediation: "Require IMDSv2 (HttpTokens required) in the launch template or EC2NodeClass metadataOptions",
+			})
+		}
+		if len(g.hopLimit) > 0 {
+			results = append(results, findings.Finding{
+				ID:          "node_imds_hop_limit",
+				Severity:    findings.SeverityWarning,
+				Category:    "security",
+				Resource:    name,
+				Message:     fmt.Sprintf("%d instances have an IMDS hop limit above 1, so pods can get the node role credentials: %s", len(g.hopLimit), listInstances(g.hopLimit)),
+				Remediation: "Set HttpPutResponseHopLimit to 1 and give pods their own credentials with IRSA or EKS Pod Identity",
+			})
+		}
+		if len(g.imdsv1) == 0 && len(g.hopLimit) == 0 {
+			results = append(results, findings.Finding{
+				ID:       "node_imds",
+				Severity: findings.SeverityPass,
+				Category: "security",
+				Resource: name,
+				Message:  "Instances require IMDSv2 with a hop limit of 1, or have the metadata endpoint disabled",
+			})
+		}
+	}
+	return results
+}
+
+// listInstances joins the first maxListedInstances instance IDs
+func listInstances(ids []string) string {
+	if len(ids) <= maxListedInstances {
+		return strings.Join(ids, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(ids[:maxListedInstances], ", "), len(ids)-maxListedInstances)
+}
@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// InstanceTypeInfo contains the capacity specs of an EC2 instance type
+type InstanceTypeInfo struct {
+	InstanceType string
+	VCPUs        int32
+	MemoryMiB    int64
+	MaxENIs      int32
+	IPsPerENI    int32
+}
+
+// MaxPods returns the maximum pods for the instance type with the default VPC CNI
+// (ENIs * (IPv4 addresses per ENI - 1) + 2)
+func (i InstanceTypeInfo) MaxPods() int64 {
+	return int64(i.MaxENIs)*int64(i.IPsPerENI-1) + 2
+}
+
+var (
+	instanceTypeCacheMu sync.Mutex
+	instanceTypeCache   = make(map[string]*InstanceTypeInfo)
+)
+
+// GetInstanceTypeInfo returns the vCPUs, memory and ENI limits of an instance type using EC2
+// DescribeInstanceTypes. Results are cached for the lifetime of the process.
+func (c *Client) GetInstanceTypeInfo(ctx context.Context, instanceType string) (*InstanceTypeInfo, error) {
+	instanceTypeCacheMu.Lock()
+	info, ok := instanceTypeCache[instanceType]
+	instanceTypeCacheMu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeInstanceTypesOutput, error) {
+		return c.EC2Client.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{
+			InstanceTypes: []ec2types.InstanceType{ec2types.InstanceType(instanceType)},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe instance type %s: %w", instanceType, err)
+	}
+	if len(result.InstanceTypes) == 0 {
+		return nil, fmt.Errorf("instance type %s not found", instanceType)
+	}
+
+	it := result.InstanceTypes[0]
+	info = &InstanceTypeInfo{InstanceType: instanceType}
+	if it.VCpuInfo != nil {
+		info.VCPUs = aws.ToInt32(it.VCpuInfo.DefaultVCpus)
+	}
+	if it.MemoryInfo != nil {
+		info.MemoryMiB = aws.ToInt64(it.MemoryInfo.SizeInMiB)
+	}
+	if it.NetworkInfo != nil {
+		info.MaxENIs = aws.ToInt32(it.NetworkInfo.MaximumNetworkInterfaces)
+		info.IPsPerENI = aws.ToInt32(it.NetworkInfo.Ipv4AddressesPerInterface)
+	}
+
+	instanceTypeCacheMu.Lock()
+	instanceTypeCache[instanceType] = info
+	instanceTypeCacheMu.Unlock()
+
+	return info, nil
+}
@@ -0,0 +1,153 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// LaunchTemplateDrift compares the launch template version a managed nodegroup runs with
+// the template's default and latest versions. A template updated out-of-band only reaches
+// the nodegroup through a nodegroup update.
+type LaunchTemplateDrift struct {
+	Nodegroup      string
+	LaunchTemplate string // Name, or ID when the nodegroup references it by ID
+	Version        int64  // Version the nodegroup runs
+	DefaultVersion int64
+	LatestVersion  int64
+	// Changes are the key field differences from the nodegroup's version to the default
+	// version, or to the latest version when the nodegroup runs the default or a newer one
+	Changes []LaunchTemplateChange
+}
+
+// LaunchTemplateChange is a launch template field that differs between two versions
+type LaunchTemplateChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// PendingUpdate returns true if the nodegroup runs an older version than the default
+func (d LaunchTemplateDrift) PendingUpdate() bool {
+	return d.Version < d.DefaultVersion
+}
+
+// GetLaunchTemplateDrift compares the launch template version of a managed nodegroup with
+// the template's $Default and $Latest versions. It returns nil for nodegroups without a
+// custom launch template.
+func (c *Client) GetLaunchTemplateDrift(ctx context.Context, ng *ekstypes.Nodegroup) (*LaunchTemplateDrift, error) {
+	lt := ng.LaunchTemplate
+	if lt == nil || (lt.Id == nil && lt.Name == nil) {
+		return nil, nil
+	}
+
+	drift := &LaunchTemplateDrift{Nodegroup: aws.ToString(ng.NodegroupName)}
+	input := &ec2.DescribeLaunchTemplateVersionsInput{}
+	if lt.Name != nil {
+		input.LaunchTemplateName = lt.Name
+		drift.LaunchTemplate = aws.ToString(lt.Name)
+	} else {
+		input.LaunchTemplateId = lt.Id
+		drift.LaunchTemplate = aws.ToString(lt.Id)
+	}
+	ngVersion := aws.ToString(lt.Version)
+	if ngVersion == "" {
+		ngVersion = "$Default"
+	}
+	// DescribeLaunchTemplateVersions rejects a version listed twice
+	input.Versions = uniqueStrings([]string{ngVersion, "$Default", "$Latest"})
+
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+		return c.EC2Client.DescribeLaunchTemplateVersions(ctx, input)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe launch template versions: %w", err)
+	}
+
+	versions := make(map[int64]ec2types.LaunchTemplateVersion)
+	for _, v := range result.LaunchTemplateVersions {
+		number := aws.ToInt64(v.VersionNumber)
+		versions[number] = v
+		if aws.ToBool(v.DefaultVersion) {
+			drift.DefaultVersion = number
+		}
+		if number > drift.LatestVersion {
+			drift.LatestVersion = number
+		}
+	}
+
+	switch ngVersion {
+	case "$Default":
+		drift.Version = drift.DefaultVersion
+	case "$Latest":
+		drift.Version = drift.LatestVersion
+	default:
+		if drift.Version, err = strconv.ParseInt(ngVersion, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid launch template version %q", ngVersion)
+		}
+	}
+
+	target := drift.DefaultVersion
+	if drift.Version >= drift.DefaultVersion {
+		target = drift.LatestVersion
+	}
+	from, fromOK := versions[drift.Version]
+	to, toOK := versions[target]
+	if target != drift.Version && fromOK && toOK {
+		drift.Changes = launchTemplateChanges(from.LaunchTemplateData, to.LaunchTemplateData)
+	}
+	return drift, nil
+}
+
+// launchTemplateChanges returns the differences in the fields that decide what nodes run:
+// AMI, instance type, user data and security groups. User data is compared by hash.
+func launchTemplateChanges(from, to *ec2types.ResponseLaunchTemplateData) []LaunchTemplateChange {
+	fields := func(data *ec2types.ResponseLaunchTemplateData) map[string]string {
+		if data == nil {
+			return map[string]string{}
+		}
+		groups := append([]string{}, data.SecurityGroupIds...)
+		for _, ni := range data.NetworkInterfaces {
+			groups = append(groups, ni.Groups...)
+		}
+		sort.Strings(groups)
+		return map[string]string{
+			"AMI":             aws.ToString(data.ImageId),
+			"Instance type":   string(data.InstanceType),
+			"User data":       userDataHash(aws.ToString(data.UserData)),
+			"Security groups": strings.Join(groups, ","),
+		}
+	}
+
+	before, after := fields(from), fields(to)
+	var changes []LaunchTemplateChange
+	for _, field := range []string{"AMI", "Instance type", "User data", "Security groups"} {
+		if before[field] != after[field] {
+			changes = append(changes, LaunchTemplateChange{Field: field, From: before[field], To: after[field]})
+		}
+	}
+	return changes
+}
+
+// userDataHash returns a short SHA-256 of the decoded user data, or "" when there is none
+func userDataHash(encoded string) string {
+	if encoded == "" {
+		return ""
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		data = []byte(encoded)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
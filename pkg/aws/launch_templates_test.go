@@ -0,0 +1,111 @@
+package aws
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestLaunchTemplateChanges(t *testing.T) {
+	userData := func(script string) *string {
+		return aws.String(base64.StdEncoding.EncodeToString([]byte(script)))
+	}
+	base := func() *ec2types.ResponseLaunchTemplateData {
+		return &ec2types.ResponseLaunchTemplateData{
+			ImageId:          aws.String("ami-1"),
+			InstanceType:     ec2types.InstanceTypeM5Large,
+			UserData:         userData("#!/bin/bash\necho one"),
+			SecurityGroupIds: []string{"sg-b", "sg-a"},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		from     *ec2types.ResponseLaunchTemplateData
+		to       func(*ec2types.ResponseLaunchTemplateData)
+		expected []LaunchTemplateChange
+	}{
+		{
+			name: "No changes",
+			from: base(),
+			to:   func(*ec2types.ResponseLaunchTemplateData) {},
+		},
+		{
+			name: "New AMI and instance type",
+			from: base(),
+			to: func(d *ec2types.ResponseLaunchTemplateData) {
+				d.ImageId = aws.String("ami-2")
+				d.InstanceType = ec2types.InstanceTypeM6iLarge
+			},
+			expected: []LaunchTemplateChange{
+				{Field: "AMI", From: "ami-1", To: "ami-2"},
+				{Field: "Instance type", From: "m5.large", To: "m6i.large"},
+			},
+		},
+		{
+			name: "User data compared by hash",
+			from: base(),
+			to: func(d *ec2types.ResponseLaunchTemplateData) {
+				d.UserData = userData("#!/bin/bash\necho two")
+			},
+			expected: []LaunchTemplateChange{{
+				Field: "User data",
+				From:  userDataHash(aws.ToString(userData("#!/bin/bash\necho one"))),
+				To:    userDataHash(aws.ToString(userData("#!/bin/bash\necho two"))),
+			}},
+		},
+		{
+			name: "Security groups moved to a network interface in another order",
+			from: base(),
+			to: func(d *ec2types.ResponseLaunchTemplateData) {
+				d.SecurityGroupIds = nil
+				d.NetworkInterfaces = []ec2types.LaunchTemplateInstanceNetworkInterfaceSpecification{{Groups: []string{"sg-a", "sg-b"}}}
+			},
+		},
+		{
+			name: "Security group added",
+			from: base(),
+			to: func(d *ec2types.ResponseLaunchTemplateData) {
+				d.SecurityGroupIds = append(d.SecurityGroupIds, "sg-c")
+			},
+			expected: []LaunchTemplateChange{{Field: "Security groups", From: "sg-a,sg-b", To: "sg-a,sg-b,sg-c"}},
+		},
+		{
+			name: "Version without data",
+			from: nil,
+			to: func(d *ec2types.ResponseLaunchTemplateData) {
+				d.UserData = nil
+				d.SecurityGroupIds = nil
+			},
+			expected: []LaunchTemplateChange{
+				{Field: "AMI", From: "", To: "ami-1"},
+				{Field: "Instance type", From: "", To: "m5.large"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			to := base()
+			tc.to(to)
+			got := launchTemplateChanges(tc.from, to)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("launchTemplateChanges() = %+v, want %+v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestUserDataHash(t *testing.T) {
+	script := "#!/bin/bash\necho hello"
+	encoded := base64.StdEncoding.EncodeToString([]byte(script))
+	if got := userDataHash(""); got != "" {
+		t.Errorf("userDataHash(\"\") = %q, want empty", got)
+	}
+	if userDataHash(encoded) != userDataHash(script) {
+		t.Error("userDataHash() differs for encoded and raw user data")
+	}
+}
@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// GetControlPlaneLogSample returns up to limit control plane log events from the last hour.
+// Control plane logging must be enabled for the cluster for any events to be returned.
+func (c *Client) GetControlPlaneLogSample(ctx context.Context, clusterName string, limit int32) ([]string, error) {
+	logGroup := fmt.Sprintf("/aws/eks/%s/cluster", clusterName)
+
+	result, err := withCredRefresh(ctx, c, func() (*cloudwatchlogs.FilterLogEventsOutput, error) {
+		return c.LogsClient.FilterLogEvents(ctx, &cloudwatchlogs.FilterLogEventsInput{
+			LogGroupName: aws.String(logGroup),
+			StartTime:    aws.Int64(time.Now().Add(-1 * time.Hour).UnixMilli()),
+			Limit:        aws.Int32(limit),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get control plane logs from %s: %w", logGroup, err)
+	}
+
+	var events []string
+	for _, event := range result.Events {
+		events = append(events, fmt.Sprintf("%s %s: %s",
+			time.UnixMilli(aws.ToInt64(event.Timestamp)).UTC().Format(time.RFC3339),
+			aws.ToString(event.LogStreamName),
+			aws.ToString(event.Message)))
+	}
+
+	return events, nil
+}
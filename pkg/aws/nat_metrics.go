@@ -0,0 +1,103 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// NATGatewayConnectionLimit is the number of simultaneous connections a NAT gateway IP
+// address supports to a single destination (IP, port and protocol)
+const NATGatewayConnectionLimit = 55000
+
+// NATGatewayMetrics holds SNAT port allocation metrics of a NAT gateway over a time window
+type NATGatewayMetrics struct {
+	NatGatewayID string
+	// ErrorPortAllocation is the number of times the gateway could not allocate a source port
+	ErrorPortAllocation float64
+	// MaxActiveConnections is the highest ActiveConnectionCount seen in the window
+	MaxActiveConnections float64
+	PacketsDropped       float64
+}
+
+// NearConnectionLimit returns true if the active connections reached 80% of the
+// per-destination connection limit
+func (m NATGatewayMetrics) NearConnectionLimit() bool {
+	return m.MaxActiveConnections >= NATGatewayConnectionLimit*0.8
+}
+
+// GetNATGatewayMetrics gets the ErrorPortAllocation, ActiveConnectionCount and
+// PacketsDropCount metrics of the NAT gateways over the given window
+func (c *Client) GetNATGatewayMetrics(ctx context.Context, natGatewayIDs []string, window time.Duration) ([]NATGatewayMetrics, error) {
+	endTime := time.Now()
+	startTime := endTime.Add(-window)
+
+	metricQuery := func(id, natGatewayID, name, stat string) cloudwatchtypes.MetricDataQuery {
+		return cloudwatchtypes.MetricDataQuery{
+			Id: aws.String(id),
+			MetricStat: &cloudwatchtypes.MetricStat{
+				Metric: &cloudwatchtypes.Metric{
+					Namespace:  aws.String("AWS/NATGateway"),
+					MetricName: aws.String(name),
+					Dimensions: []cloudwatchtypes.Dimension{
+						{
+							Name:  aws.String("NatGatewayId"),
+							Value: aws.String(natGatewayID),
+						},
+					},
+				},
+				Period: aws.Int32(300),
+				Stat:   aws.String(stat),
+			},
+		}
+	}
+
+	var queries []cloudwatchtypes.MetricDataQuery
+	for i, id := range natGatewayIDs {
+		queries = append(queries,
+			metricQuery(fmt.Sprintf("port%d", i), id, "ErrorPortAllocation", "Sum"),
+			metricQuery(fmt.Sprintf("conn%d", i), id, "ActiveConnectionCount", "Maximum"),
+			metricQuery(fmt.Sprintf("drop%d", i), id, "PacketsDropCount", "Sum"),
+		)
+	}
+
+	values := make(map[string][]float64)
+	paginator := cloudwatch.NewGetMetricDataPaginator(c.CloudWatchClient, &cloudwatch.GetMetricDataInput{
+		MetricDataQueries: queries,
+		StartTime:         aws.Time(startTime),
+		EndTime:           aws.Time(endTime),
+	})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*cloudwatch.GetMetricDataOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get NAT gateway metrics: %w", err)
+		}
+		for _, r := range page.MetricDataResults {
+			id := aws.ToString(r.Id)
+			values[id] = append(values[id], r.Values...)
+		}
+	}
+
+	metrics := make([]NATGatewayMetrics, 0, len(natGatewayIDs))
+	for i, id := range natGatewayIDs {
+		m := NATGatewayMetrics{NatGatewayID: id}
+		for _, v := range values[fmt.Sprintf("port%d", i)] {
+			m.ErrorPortAllocation += v
+		}
+		for _, v := range values[fmt.Sprintf("conn%d", i)] {
+			m.MaxActiveConnections = max(m.MaxActiveConnections, v)
+		}
+		for _, v := range values[fmt.Sprintf("drop%d", i)] {
+			m.PacketsDropped += v
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,63 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// ClusterNetworkInfo describes the IP family and address ranges of a cluster
+type ClusterNetworkInfo struct {
+	IPFamily        string // "ipv4" or "ipv6"
+	ServiceIPv4CIDR string
+	ServiceIPv6CIDR string
+	VPCID           string
+	// Pods get addresses from the VPC through the VPC CNI, so the VPC CIDRs are the pod ranges
+	VPCIPv4CIDRs []string
+	VPCIPv6CIDRs []string
+}
+
+// GetClusterNetworkInfo returns the cluster's IP family, service CIDRs and VPC CIDRs
+func (c *Client) GetClusterNetworkInfo(ctx context.Context, clusterName string) (*ClusterNetworkInfo, error) {
+	cluster, err := c.DescribeCluster(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+
+	info := &ClusterNetworkInfo{IPFamily: "ipv4"}
+	if netConfig := cluster.Cluster.KubernetesNetworkConfig; netConfig != nil {
+		if netConfig.IpFamily != "" {
+			info.IPFamily = string(netConfig.IpFamily)
+		}
+		info.ServiceIPv4CIDR = aws.ToString(netConfig.ServiceIpv4Cidr)
+		info.ServiceIPv6CIDR = aws.ToString(netConfig.ServiceIpv6Cidr)
+	}
+
+	if vpcConfig := cluster.Cluster.ResourcesVpcConfig; vpcConfig != nil && vpcConfig.VpcId != nil {
+		info.VPCID = *vpcConfig.VpcId
+		result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeVpcsOutput, error) {
+			return c.EC2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+				VpcIds: []string{info.VPCID},
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe VPC %s: %w", info.VPCID, err)
+		}
+		for _, vpc := range result.Vpcs {
+			for _, assoc := range vpc.CidrBlockAssociationSet {
+				if assoc.CidrBlock != nil {
+					info.VPCIPv4CIDRs = append(info.VPCIPv4CIDRs, *assoc.CidrBlock)
+				}
+			}
+			for _, assoc := range vpc.Ipv6CidrBlockAssociationSet {
+				if assoc.Ipv6CidrBlock != nil {
+					info.VPCIPv6CIDRs = append(info.VPCIPv6CIDRs, *assoc.Ipv6CidrBlock)
+				}
+			}
+		}
+	}
+
+	return info, nil
+}
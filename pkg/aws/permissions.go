@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// RequiredAction is an IAM action ekspeek calls and the feature that uses it
+type RequiredAction struct {
+	Action  string
+	Purpose string
+}
+
+// RequiredActions lists every AWS API action ekspeek calls. Add new actions here when
+// adding AWS calls so "ekspeek debug permissions" stays in sync.
+var RequiredActions = []RequiredAction{
+	{"eks:ListClusters", "list clusters, cluster-health --all"},
+	{"eks:DescribeCluster", "all cluster commands"},
+	{"eks:ListNodegroups", "nodegroup, security and AMI checks"},
+	{"eks:DescribeNodegroup", "nodegroup, security and AMI checks"},
+	{"eks:ListAddons", "addon checks"},
+	{"eks:DescribeAddon", "addon checks"},
+	{"eks:DescribeAddonVersions", "addon version checks"},
+	{"eks:ListUpdates", "nodegroup update history"},
+	{"eks:DescribeUpdate", "nodegroup update history"},
+	{"eks:ListTagsForResource", "tag checks"},
+	{"eks:ListAccessEntries", "access entry checks"},
+	{"eks:DescribeAccessEntry", "access entry checks"},
+	{"eks:ListAssociatedAccessPolicies", "access entry checks"},
+	{"ec2:DescribeInstances", "self-managed node AMI, instance metadata (IMDS) and EFS reachability checks"},
+	{"ec2:DescribeImages", "self-managed node AMI checks"},
+	{"ec2:DescribeInstanceTypes", "resource and max pods checks"},
+	{"ec2:DescribeVpcs", "networking checks"},
+	{"ec2:DescribeSubnets", "networking, subnet tag, public node and CNI custom networking checks"},
+	{"ec2:DescribeLaunchTemplateVersions", "public node and launch template drift checks"},
+	{"ec2:DescribeRouteTables", "egress, subnet tag and public node checks"},
+	{"ec2:DescribeNatGateways", "egress checks"},
+	{"ec2:DescribeSecurityGroups", "security group and EFS reachability checks"},
+	{"ec2:DescribeNetworkInterfaces", "orphaned VPC CNI ENI and EFS mount target checks"},
+	{"ec2:DescribeSecurityGroupRules", "security group checks"},
+	{"ecr:DescribeRepositories", "image pull checks"},
+	{"ecr:GetRepositoryPolicy", "image pull checks"},
+	{"ecr:DescribeImages", "image inventory"},
+	{"cloudwatch:GetMetricData", "metrics and NAT gateway checks"},
+	{"cloudwatch:PutMetricData", "cluster-health --publish-metrics"},
+	{"cloudwatch:ListMetrics", "debug top"},
+	{"logs:FilterLogEvents", "control plane log checks"},
+	{"iam:GetRole", "IAM role checks"},
+	{"iam:GetRolePolicy", "IAM role checks"},
+	{"iam:ListRolePolicies", "IAM role checks"},
+	{"iam:ListAttachedRolePolicies", "IAM role checks"},
+	{"iam:GetPolicy", "IAM role checks"},
+	{"iam:GetPolicyVersion", "IAM role checks"},
+	{"iam:SimulatePrincipalPolicy", "image pull and permission checks"},
+	{"ssm:GetParameterHistory", "AMI release checks"},
+}
+
+// PermissionCheck is the result of simulating one required action for the caller identity
+type PermissionCheck struct {
+	RequiredAction
+	Decision string // allowed, explicitDeny or implicitDeny
+}
+
+// Allowed returns true if the simulation allowed the action
+func (p PermissionCheck) Allowed() bool {
+	return p.Decision == "allowed"
+}
+
+// principalARN converts a caller identity ARN into an ARN accepted by
+// SimulatePrincipalPolicy. Assumed-role session ARNs are resolved to their IAM role.
+func (c *Client) principalARN(ctx context.Context, identity string) (string, error) {
+	// arn:aws:sts::123456789012:assumed-role/role-name/session-name
+	parts := strings.SplitN(identity, ":", 6)
+	if len(parts) < 6 {
+		return "", fmt.Errorf("invalid caller identity ARN %q", identity)
+	}
+	resource := parts[5]
+	if !strings.HasPrefix(resource, "assumed-role/") {
+		return identity, nil
+	}
+
+	roleName := strings.Split(strings.TrimPrefix(resource, "assumed-role/"), "/")[0]
+	role, err := withCredRefresh(ctx, c, func() (*iam.GetRoleOutput, error) {
+		return c.IAMClient.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)})
+	})
+	if err != nil {
+		// Without iam:GetRole, assume the role has no path
+		return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], roleName), nil
+	}
+	return aws.ToString(role.Role.Arn), nil
+}
+
+// SimulateRequiredActions simulates every action in RequiredActions for the caller
+// identity with IAM policy simulation and returns the principal simulated and the results.
+// Resource-level conditions and SCPs are not evaluated by the simulation.
+func (c *Client) SimulateRequiredActions(ctx context.Context) (string, []PermissionCheck, error) {
+	identity, err := c.CallerIdentity(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	principal, err := c.principalARN(ctx, identity)
+	if err != nil {
+		return "", nil, err
+	}
+
+	actions := make([]string, 0, len(RequiredActions))
+	for _, a := range RequiredActions {
+		actions = append(actions, a.Action)
+	}
+
+	decisions := make(map[string]string)
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(c.IAMClient, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principal),
+		ActionNames:     actions,
+	})
+	for paginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*iam.SimulatePrincipalPolicyOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return principal, nil, fmt.Errorf("failed to simulate principal policy: %w", err)
+		}
+		for _, r := range page.EvaluationResults {
+			decisions[aws.ToString(r.EvalActionName)] = string(r.EvalDecision)
+		}
+	}
+
+	checks := make([]PermissionCheck, 0, len(RequiredActions))
+	for _, a := range RequiredActions {
+		checks = append(checks, PermissionCheck{RequiredAction: a, Decision: decisions[a.Action]})
+	}
+	return principal, checks, nil
+}
@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/findings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// launchTemplatePublicIP returns the AssociatePublicIpAddress setting of a nodegroup's
+// launch template, or nil when the template leaves it to the subnet
+func (c *Client) launchTemplatePublicIP(ctx context.Context, lt *ekstypes.LaunchTemplateSpecification) (*bool, error) {
+	input := &ec2.DescribeLaunchTemplateVersionsInput{}
+	if lt.Id != nil {
+		input.LaunchTemplateId = lt.Id
+	} else {
+		input.LaunchTemplateName = lt.Name
+	}
+	if lt.Version != nil {
+		input.Versions = []string{aws.ToString(lt.Version)}
+	} else {
+		input.Versions = []string{"$Default"}
+	}
+
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+		return c.EC2Client.DescribeLaunchTemplateVersions(ctx, input)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe launch template versions: %w", err)
+	}
+
+	for _, version := range result.LaunchTemplateVersions {
+		if version.LaunchTemplateData == nil {
+			continue
+		}
+		for _, ni := range version.LaunchTemplateData.NetworkInterfaces {
+			if ni.AssociatePublicIpAddress != nil {
+				return ni.AssociatePublicIpAddress, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// nodegroupPublicIPFinding checks whether a nodegroup's instances get public IP addresses,
+// from its launch template or its subnets' map-public-ip-on-launch setting, and whether
+// those subnets route to an internet gateway
+func (c *Client) nodegroupPublicIPFinding(ctx context.Context, ng *ekstypes.Nodegroup, routeTables []ec2types.RouteTable) findings.Finding {
+	ngName := aws.ToString(ng.NodegroupName)
+	finding := findings.Finding{
+		ID:       "nodegroup_public_ip",
+		Category: "security",
+		Resource: ngName,
+	}
+
+	var ltPublicIP *bool
+	if ng.LaunchTemplate != nil {
+		var err error
+		ltPublicIP, err = c.launchTemplatePublicIP(ctx, ng.LaunchTemplate)
+		if err != nil {
+			finding.Severity = findings.SeverityWarning
+			finding.Message = fmt.Sprintf("Failed to check public IPs: %v", err)
+			return finding
+		}
+	}
+
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeSubnetsOutput, error) {
+		return c.EC2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{SubnetIds: ng.Subnets})
+	})
+	if err != nil {
+		finding.Severity = findings.SeverityWarning
+		finding.Message = fmt.Sprintf("Failed to check public IPs: failed to describe subnets: %v", err)
+		return finding
+	}
+
+	var exposed, publicIPOnly, publicSubnets []string
+	for _, subnet := range result.Subnets {
+		subnetID := aws.ToString(subnet.SubnetId)
+		publicIP := aws.ToBool(subnet.MapPublicIpOnLaunch)
+		if ltPublicIP != nil {
+			publicIP = *ltPublicIP
+		}
+		public := isPublicSubnet(subnetID, routeTables)
+
+		switch {
+		case publicIP && public:
+			exposed = append(exposed, subnetID)
+		case publicIP:
+			publicIPOnly = append(publicIPOnly, subnetID)
+		case public:
+			publicSubnets = append(publicSubnets, subnetID)
+		}
+	}
+
+	source := "the subnets' map-public-ip-on-launch setting"
+	if ltPublicIP != nil {
+		source = "the launch template's AssociatePublicIpAddress setting"
+	}
+	switch {
+	case len(exposed) > 0:
+		finding.Severity = findings.SeverityWarning
+		finding.Message = fmt.Sprintf("Nodes get public IP addresses from %s in public subnets %s, exposing them to the internet",
+			source, strings.Join(exposed, ", "))
+		finding.Remediation = "Run nodes in private subnets with a NAT gateway, and disable public IP assignment in the launch template or subnets"
+	case len(publicIPOnly) > 0:
+		finding.Severity = findings.SeverityInfo
+		finding.Message = fmt.Sprintf("Nodes get public IP addresses from %s in subnets %s, which do not route to an internet gateway",
+			source, strings.Join(publicIPOnly, ", "))
+		finding.Remediation = "Disable public IP assignment in the launch template or subnets"
+	case len(publicSubnets) > 0:
+		finding.Severity = findings.SeverityInfo
+		finding.Message = fmt.Sprintf("Nodes run in public subnets %s without public IP addresses", strings.Join(publicSubnets, ", "))
+		finding.Remediation = "Move nodes to private subnets so a later subnet or launch template change cannot expose them"
+	default:
+		finding.Severity = findings.SeverityPass
+		finding.Message = "Nodes run in private subnets without public IP addresses"
+	}
+	return finding
+}
@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// maxMetricDatumsPerCall is the PutMetricData limit on metric data per request
+const maxMetricDatumsPerCall = 1000
+
+// CustomMetric is a gauge published to a CloudWatch custom namespace
+type CustomMetric struct {
+	Name  string
+	Value float64
+	Unit  cloudwatchtypes.StandardUnit
+}
+
+// PublishClusterMetrics publishes metrics to the given CloudWatch namespace with a
+// ClusterName dimension, so alarms can be created per cluster
+func (c *Client) PublishClusterMetrics(ctx context.Context, namespace, clusterName string, metrics []CustomMetric) error {
+	now := time.Now()
+	data := make([]cloudwatchtypes.MetricDatum, 0, len(metrics))
+	for _, m := range metrics {
+		data = append(data, cloudwatchtypes.MetricDatum{
+			MetricName: aws.String(m.Name),
+			Value:      aws.Float64(m.Value),
+			Unit:       m.Unit,
+			Timestamp:  aws.Time(now),
+			Dimensions: []cloudwatchtypes.Dimension{
+				{
+					Name:  aws.String("ClusterName"),
+					Value: aws.String(clusterName),
+				},
+			},
+		})
+	}
+
+	for start := 0; start < len(data); start += maxMetricDatumsPerCall {
+		end := min(start+maxMetricDatumsPerCall, len(data))
+		_, err := withCredRefresh(ctx, c, func() (*cloudwatch.PutMetricDataOutput, error) {
+			return c.CloudWatchClient.PutMetricData(ctx, &cloudwatch.PutMetricDataInput{
+				Namespace:  aws.String(namespace),
+				MetricData: data[start:end],
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish metrics to %s: %w", namespace, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+
+	"ekspeek/pkg/findings"
+)
+
+// sensitivePortRange is a port range that should never be reachable from the internet
+type sensitivePortRange struct {
+	Name     string
+	FromPort int32
+	ToPort   int32
+}
+
+var sensitivePorts = []sensitivePortRange{
+	{Name: "ssh", FromPort: 22, ToPort: 22},
+	{Name: "kube-apiserver", FromPort: 6443, ToPort: 6443},
+	{Name: "etcd", FromPort: 2379, ToPort: 2380},
+	{Name: "kubelet", FromPort: 10250, ToPort: 10250},
+}
+
+// AuditSecurityGroups audits the security groups of the cluster and of the remote access
+// of its nodegroups, as described by GetSecurityAnalysis, for rules open to the internet.
+// Each finding's resource is the security group ID and port.
+func (c *Client) AuditSecurityGroups(ctx context.Context, cluster *ekstypes.Cluster, nodegroups []*ekstypes.Nodegroup) ([]findings.Finding, error) {
+	var groupIDs []string
+	if vpcConfig := cluster.ResourcesVpcConfig; vpcConfig != nil {
+		groupIDs = append(groupIDs, vpcConfig.SecurityGroupIds...)
+		if vpcConfig.ClusterSecurityGroupId != nil {
+			groupIDs = append(groupIDs, *vpcConfig.ClusterSecurityGroupId)
+		}
+	}
+	for _, ng := range nodegroups {
+		if ng.Resources != nil && ng.Resources.RemoteAccessSecurityGroup != nil {
+			groupIDs = append(groupIDs, *ng.Resources.RemoteAccessSecurityGroup)
+		}
+	}
+
+	groupIDs = uniqueStrings(groupIDs)
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeSecurityGroupsOutput, error) {
+		return c.EC2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+			GroupIds: groupIDs,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe security groups: %w", err)
+	}
+	return securityGroupFindings(result.SecurityGroups), nil
+}
+
+// securityGroupFindings reports ingress from the internet to sensitive ports as critical.
+// Egress of all traffic to the internet is the default rule of every security group, so it
+// is only reported as info.
+func securityGroupFindings(groups []ec2types.SecurityGroup) []findings.Finding {
+	var results []findings.Finding
+	for _, sg := range groups {
+		sgID := *sg.GroupId
+
+		for _, rule := range sg.IpPermissions {
+			if !isOpenToInternet(rule) {
+				continue
+			}
+			for _, port := range sensitivePorts {
+				if ruleCoversPorts(rule, port.FromPort, port.ToPort) {
+					key := fmt.Sprintf("%s:%d", sgID, port.FromPort)
+					if port.FromPort != port.ToPort {
+						key = fmt.Sprintf("%s:%d-%d", sgID, port.FromPort, port.ToPort)
+					}
+					results = append(results, findings.Finding{
+						ID:          "security_group_ingress",
+						Severity:    findings.SeverityCritical,
+						Category:    "security",
+						Resource:    key,
+						Message:     fmt.Sprintf("Ingress from the internet allowed on %s port(s) %s", port.Name, describePortRange(rule)),
+						Remediation: "Restrict the rule's source to known CIDR ranges or security groups",
+					})
+				}
+			}
+		}
+
+		for _, rule := range sg.IpPermissionsEgress {
+			if isOpenToInternet(rule) && isAllTraffic(rule) {
+				results = append(results, findings.Finding{
+					ID:          "security_group_egress",
+					Severity:    findings.SeverityInfo,
+					Category:    "security",
+					Resource:    fmt.Sprintf("%s:egress", sgID),
+					Message:     "Egress allows all traffic to the internet",
+					Remediation: "Limit egress to the destinations and ports the nodes need",
+				})
+			}
+		}
+	}
+	return results
+}
+
+// hasOpenIngress returns true if the findings of securityGroupFindings include ingress from
+// the internet to a sensitive port
+func hasOpenIngress(results []findings.Finding) bool {
+	for _, f := range results {
+		if f.ID == "security_group_ingress" {
+			return true
+		}
+	}
+	return false
+}
+
+// isOpenToInternet returns true if the rule allows 0.0.0.0/0 or ::/0
+func isOpenToInternet(rule ec2types.IpPermission) bool {
+	for _, r := range rule.IpRanges {
+		if r.CidrIp != nil && *r.CidrIp == "0.0.0.0/0" {
+			return true
+		}
+	}
+	for _, r := range rule.Ipv6Ranges {
+		if r.CidrIpv6 != nil && *r.CidrIpv6 == "::/0" {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllTraffic returns true if the rule applies to every protocol and port
+func isAllTraffic(rule ec2types.IpPermission) bool {
+	return rule.IpProtocol != nil && *rule.IpProtocol == "-1"
+}
+
+// ruleCoversPorts returns true if the rule's port range overlaps the given range
+func ruleCoversPorts(rule ec2types.IpPermission, fromPort, toPort int32) bool {
+	if isAllTraffic(rule) {
+		return true
+	}
+	if rule.IpProtocol != nil && *rule.IpProtocol != "tcp" && *rule.IpProtocol != "6" {
+		return false
+	}
+	if rule.FromPort == nil || rule.ToPort == nil {
+		return true
+	}
+	return *rule.FromPort <= toPort && *rule.ToPort >= fromPort
+}
+
+func describePortRange(rule ec2types.IpPermission) string {
+	if isAllTraffic(rule) || rule.FromPort == nil || rule.ToPort == nil {
+		return "all"
+	}
+	if *rule.FromPort == *rule.ToPort {
+		return fmt.Sprintf("%d", *rule.FromPort)
+	}
+	return fmt.Sprintf("%d-%d", *rule.FromPort, *rule.ToPort)
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
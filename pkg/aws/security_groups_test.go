@@ -0,0 +1,52 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"ekspeek/pkg/findings"
+)
+
+func TestSecurityGroupFindings(t *testing.T) {
+	internet := []ec2types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}
+	groups := []ec2types.SecurityGroup{
+		{
+			// The default egress rule every security group has
+			GroupId:             aws.String("sg-default"),
+			IpPermissionsEgress: []ec2types.IpPermission{{IpProtocol: aws.String("-1"), IpRanges: internet}},
+		},
+		{
+			GroupId: aws.String("sg-ssh"),
+			IpPermissions: []ec2types.IpPermission{{
+				IpProtocol: aws.String("tcp"),
+				FromPort:   aws.Int32(22),
+				ToPort:     aws.Int32(22),
+				IpRanges:   internet,
+			}},
+		},
+	}
+
+	results := securityGroupFindings(groups)
+	if len(results) != 2 {
+		t.Fatalf("securityGroupFindings() = %+v, want an egress and an ingress finding", results)
+	}
+	for _, f := range results {
+		switch f.Resource {
+		case "sg-default:egress":
+			if f.Severity != findings.SeverityInfo {
+				t.Errorf("default egress severity = %s, want info", f.Severity)
+			}
+		case "sg-ssh:22":
+			if f.Severity != findings.SeverityCritical {
+				t.Errorf("SSH ingress severity = %s, want critical", f.Severity)
+			}
+		default:
+			t.Errorf("unexpected finding %+v", f)
+		}
+	}
+	if !hasOpenIngress(results) || hasOpenIngress(results[:1]) {
+		t.Error("hasOpenIngress() should only count the ingress finding")
+	}
+}
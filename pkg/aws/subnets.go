@@ -0,0 +1,149 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const (
+	publicELBTag   = "kubernetes.io/role/elb"
+	internalELBTag = "kubernetes.io/role/internal-elb"
+)
+
+// SubnetTagReport describes the load balancer discovery tags of a cluster subnet
+type SubnetTagReport struct {
+	SubnetID         string
+	AvailabilityZone string
+	Public           bool // the subnet routes 0.0.0.0/0 to an internet gateway
+	Tags             map[string]string
+	MissingTags      []string
+}
+
+// GetSubnetTagReport checks the cluster's subnets for the tags the AWS Load Balancer
+// Controller and the in-tree service controller use to discover subnets: the role tag
+// (kubernetes.io/role/elb for public subnets, kubernetes.io/role/internal-elb for private
+// ones) and the kubernetes.io/cluster/<name> tag
+func (c *Client) GetSubnetTagReport(ctx context.Context, clusterName string) ([]SubnetTagReport, error) {
+	cluster, err := c.DescribeCluster(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+	vpcConfig := cluster.Cluster.ResourcesVpcConfig
+	if vpcConfig == nil || len(vpcConfig.SubnetIds) == 0 {
+		return nil, fmt.Errorf("cluster has no subnets configured")
+	}
+
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeSubnetsOutput, error) {
+		return c.EC2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+			SubnetIds: vpcConfig.SubnetIds,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe subnets: %w", err)
+	}
+
+	routeTables, err := c.GetRouteTables(ctx, aws.ToString(vpcConfig.VpcId))
+	if err != nil {
+		return nil, err
+	}
+
+	clusterTag := "kubernetes.io/cluster/" + clusterName
+	var reports []SubnetTagReport
+	for _, subnet := range result.Subnets {
+		report := SubnetTagReport{
+			SubnetID:         aws.ToString(subnet.SubnetId),
+			AvailabilityZone: aws.ToString(subnet.AvailabilityZone),
+			Public:           isPublicSubnet(aws.ToString(subnet.SubnetId), routeTables),
+			Tags:             make(map[string]string),
+		}
+		for _, tag := range subnet.Tags {
+			report.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+
+		roleTag := internalELBTag
+		if report.Public {
+			roleTag = publicELBTag
+		}
+		if v, ok := report.Tags[roleTag]; !ok || (v != "1" && v != "") {
+			report.MissingTags = append(report.MissingTags, roleTag+"=1")
+		}
+		if v := report.Tags[clusterTag]; v != "shared" && v != "owned" {
+			report.MissingTags = append(report.MissingTags, clusterTag+"=shared")
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// isPublicSubnet returns true if the subnet's route table, or the VPC's main route table when
+// the subnet has no explicit association, sends 0.0.0.0/0 to an internet gateway
+func isPublicSubnet(subnetID string, routeTables []ec2types.RouteTable) bool {
+	var main *ec2types.RouteTable
+	for i, rt := range routeTables {
+		for _, assoc := range rt.Associations {
+			if aws.ToString(assoc.SubnetId) == subnetID {
+				return routesToInternetGateway(rt)
+			}
+			if aws.ToBool(assoc.Main) {
+				main = &routeTables[i]
+			}
+		}
+	}
+	return main != nil && routesToInternetGateway(*main)
+}
+
+func routesToInternetGateway(rt ec2types.RouteTable) bool {
+	for _, route := range rt.Routes {
+		if aws.ToString(route.DestinationCidrBlock) == "0.0.0.0/0" && strings.HasPrefix(aws.ToString(route.GatewayId), "igw-") {
+			return true
+		}
+	}
+	return false
+}
+
+// SubnetCapacity describes the free IP addresses left in a subnet
+type SubnetCapacity struct {
+	SubnetID         string
+	AvailabilityZone string
+	VpcID            string
+	CIDR             string
+	AvailableIPs     int32
+}
+
+// GetSubnetCapacity returns the free IP count of the given subnets, keyed by subnet ID.
+// Subnets that do not exist are left out of the result.
+func (c *Client) GetSubnetCapacity(ctx context.Context, subnetIDs []string) (map[string]SubnetCapacity, error) {
+	capacity := make(map[string]SubnetCapacity, len(subnetIDs))
+	if len(subnetIDs) == 0 {
+		return capacity, nil
+	}
+
+	// Filter rather than pass SubnetIds so a single missing subnet does not fail the call
+	result, err := withCredRefresh(ctx, c, func() (*ec2.DescribeSubnetsOutput, error) {
+		return c.EC2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+			Filters: []ec2types.Filter{{Name: aws.String("subnet-id"), Values: subnetIDs}},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe subnets: %w", err)
+	}
+
+	for _, subnet := range result.Subnets {
+		id := aws.ToString(subnet.SubnetId)
+		capacity[id] = SubnetCapacity{
+			SubnetID:         id,
+			AvailabilityZone: aws.ToString(subnet.AvailabilityZone),
+			VpcID:            aws.ToString(subnet.VpcId),
+			CIDR:             aws.ToString(subnet.CidrBlock),
+			AvailableIPs:     aws.ToInt32(subnet.AvailableIpAddressCount),
+		}
+	}
+	return capacity, nil
+}
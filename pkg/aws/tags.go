@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+)
+
+// ResourceTags are the tags of a cluster or nodegroup
+type ResourceTags struct {
+	Kind string // cluster or nodegroup
+	Name string
+	ARN  string
+	Tags map[string]string
+}
+
+// GetClusterTags lists the tags of the cluster and of each of its managed nodegroups
+func (c *Client) GetClusterTags(ctx context.Context, clusterName string) ([]ResourceTags, error) {
+	cluster, err := c.DescribeCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	resources := []ResourceTags{{
+		Kind: "cluster",
+		Name: clusterName,
+		ARN:  aws.ToString(cluster.Cluster.Arn),
+	}}
+
+	nodegroups, err := c.GetClusterNodegroups(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodegroups: %w", err)
+	}
+	for _, ng := range nodegroups {
+		resources = append(resources, ResourceTags{
+			Kind: "nodegroup",
+			Name: aws.ToString(ng.NodegroupName),
+			ARN:  aws.ToString(ng.NodegroupArn),
+		})
+	}
+
+	for i := range resources {
+		result, err := withCredRefresh(ctx, c, func() (*eks.ListTagsForResourceOutput, error) {
+			return c.EKSClient.ListTagsForResource(ctx, &eks.ListTagsForResourceInput{
+				ResourceArn: aws.String(resources[i].ARN),
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of %s %s: %w", resources[i].Kind, resources[i].Name, err)
+		}
+		resources[i].Tags = result.Tags
+	}
+	return resources, nil
+}
@@ -0,0 +1,162 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// Resources that GetTopConsumers can rank by
+const (
+	TopByCPU    = "cpu"
+	TopByMemory = "memory"
+)
+
+// maxMetricDataQueries is the number of queries GetMetricData accepts in one request
+const maxMetricDataQueries = 500
+
+// TopConsumer is a pod, or a namespace when Pod is empty, ranked by Container Insights
+// utilization. Values are percentages of node capacity.
+type TopConsumer struct {
+	Namespace string
+	Pod       string
+	Average   float64
+	Peak      float64
+}
+
+// TopConsumers holds the heaviest pods and namespaces, most utilization first
+type TopConsumers struct {
+	Pods       []TopConsumer
+	Namespaces []TopConsumer
+}
+
+// GetTopConsumers ranks the pods and namespaces of the cluster by their average Container
+// Insights CPU or memory utilization over the last hour and returns the top n of each.
+// Namespace figures are the sum of their pods'.
+func (c *Client) GetTopConsumers(ctx context.Context, clusterName, dimension string, n int) (*TopConsumers, error) {
+	var metricName string
+	switch dimension {
+	case TopByCPU:
+		metricName = "pod_cpu_utilization"
+	case TopByMemory:
+		metricName = "pod_memory_utilization"
+	default:
+		return nil, fmt.Errorf("unsupported dimension %q (use %s or %s)", dimension, TopByCPU, TopByMemory)
+	}
+
+	// Find the per-pod series, published with the ClusterName, Namespace and PodName dimensions
+	var pods []cloudwatchtypes.Metric
+	listPaginator := cloudwatch.NewListMetricsPaginator(c.CloudWatchClient, &cloudwatch.ListMetricsInput{
+		Namespace:  aws.String("ContainerInsights"),
+		MetricName: aws.String(metricName),
+		Dimensions: []cloudwatchtypes.DimensionFilter{
+			{Name: aws.String("ClusterName"), Value: aws.String(clusterName)},
+			{Name: aws.String("Namespace")},
+			{Name: aws.String("PodName")},
+		},
+	})
+	for listPaginator.HasMorePages() {
+		page, err := withCredRefresh(ctx, c, func() (*cloudwatch.ListMetricsOutput, error) {
+			return listPaginator.NextPage(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s metrics: %w", metricName, err)
+		}
+		for _, m := range page.Metrics {
+			if len(m.Dimensions) == 3 {
+				pods = append(pods, m)
+			}
+		}
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-1 * time.Hour)
+
+	result := &TopConsumers{}
+	byNamespace := make(map[string]*TopConsumer)
+	for start := 0; start < len(pods); start += maxMetricDataQueries {
+		batch := pods[start:min(start+maxMetricDataQueries, len(pods))]
+		queries := make([]cloudwatchtypes.MetricDataQuery, 0, len(batch))
+		for i := range batch {
+			queries = append(queries, cloudwatchtypes.MetricDataQuery{
+				Id: aws.String(fmt.Sprintf("m%d", i)),
+				MetricStat: &cloudwatchtypes.MetricStat{
+					Metric: &batch[i],
+					Period: aws.Int32(300),
+					Stat:   aws.String("Average"),
+				},
+			})
+		}
+
+		values := make(map[string][]float64)
+		paginator := cloudwatch.NewGetMetricDataPaginator(c.CloudWatchClient, &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: queries,
+			StartTime:         aws.Time(startTime),
+			EndTime:           aws.Time(endTime),
+		})
+		for paginator.HasMorePages() {
+			page, err := withCredRefresh(ctx, c, func() (*cloudwatch.GetMetricDataOutput, error) {
+				return paginator.NextPage(ctx)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get metric %s: %w", metricName, err)
+			}
+			for _, r := range page.MetricDataResults {
+				id := aws.ToString(r.Id)
+				values[id] = append(values[id], r.Values...)
+			}
+		}
+
+		for i, metric := range batch {
+			points := values[fmt.Sprintf("m%d", i)]
+			if len(points) == 0 {
+				continue
+			}
+			pod := TopConsumer{}
+			for _, d := range metric.Dimensions {
+				switch aws.ToString(d.Name) {
+				case "Namespace":
+					pod.Namespace = aws.ToString(d.Value)
+				case "PodName":
+					pod.Pod = aws.ToString(d.Value)
+				}
+			}
+			var sum float64
+			for _, v := range points {
+				sum += v
+				pod.Peak = max(pod.Peak, v)
+			}
+			pod.Average = sum / float64(len(points))
+			result.Pods = append(result.Pods, pod)
+
+			ns, ok := byNamespace[pod.Namespace]
+			if !ok {
+				ns = &TopConsumer{Namespace: pod.Namespace}
+				byNamespace[pod.Namespace] = ns
+			}
+			ns.Average += pod.Average
+			ns.Peak += pod.Peak
+		}
+	}
+
+	for _, ns := range byNamespace {
+		result.Namespaces = append(result.Namespaces, *ns)
+	}
+	result.Pods = topN(result.Pods, n)
+	result.Namespaces = topN(result.Namespaces, n)
+	return result, nil
+}
+
+// topN sorts consumers by average utilization and keeps the first n
+func topN(consumers []TopConsumer, n int) []TopConsumer {
+	sort.Slice(consumers, func(i, j int) bool { return consumers[i].Average > consumers[j].Average })
+	if n > 0 && len(consumers) > n {
+		consumers = consumers[:n]
+	}
+	return consumers
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/findings"
+	"ekspeek/pkg/k8s"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// addonVersionCheck compares the running version of a cluster component with the default
+// EKS add-on version for the cluster's Kubernetes version
+type addonVersionCheck struct {
+	Name              string
+	KubernetesVersion string
+	Running           string // Image tag of the running component
+	AddonVersion      string // Version of the managed add-on, empty when self-managed
+	Recommended       string
+}
+
+// baseVersion strips the leading "v" and any "-eksbuild"/"-minimal" suffix from a version
+func baseVersion(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.Index(version, "-"); i >= 0 {
+		version = version[:i]
+	}
+	return version
+}
+
+// olderThan returns true if version is an older upstream version than recommended.
+// Versions that do not parse are only compared for equality.
+func olderThan(version, recommended string) bool {
+	v, err := utilversion.ParseGeneric(baseVersion(version))
+	if err != nil {
+		return baseVersion(version) != baseVersion(recommended)
+	}
+	r, err := utilversion.ParseGeneric(baseVersion(recommended))
+	if err != nil {
+		return baseVersion(version) != baseVersion(recommended)
+	}
+	return v.LessThan(r)
+}
+
+// Outdated returns true if the running image or the managed add-on is on an older
+// upstream version than the recommended one. Newer versions, e.g. from a manual add-on
+// upgrade, are not outdated.
+func (c addonVersionCheck) Outdated() bool {
+	if c.Running != "" && olderThan(c.Running, c.Recommended) {
+		return true
+	}
+	return c.AddonVersion != "" && olderThan(c.AddonVersion, c.Recommended)
+}
+
+// checkAddonVersions compares the running CoreDNS and kube-proxy versions, and their
+// add-on versions when they are managed add-ons, with the versions EKS recommends for
+// the cluster's Kubernetes version
+func checkAddonVersions(ctx context.Context, awsClient *aws.Client, kubeClient *k8s.KubeClient, clusterName string) ([]addonVersionCheck, error) {
+	cluster, err := awsClient.DescribeCluster(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+	kubernetesVersion := awssdk.ToString(cluster.Cluster.Version)
+
+	running, err := kubeClient.GetComponentImageVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addons, err := awsClient.GetAddons(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	addonVersions := make(map[string]string, len(addons))
+	for _, addon := range addons {
+		addonVersions[awssdk.ToString(addon.AddonName)] = awssdk.ToString(addon.AddonVersion)
+	}
+
+	var checks []addonVersionCheck
+	for _, name := range []string{k8s.ComponentCoreDNS, k8s.ComponentKubeProxy} {
+		check := addonVersionCheck{
+			Name:              name,
+			KubernetesVersion: kubernetesVersion,
+			Running:           running[name],
+			AddonVersion:      addonVersions[name],
+		}
+		if check.Running == "" && check.AddonVersion == "" {
+			continue
+		}
+		check.Recommended, err = awsClient.GetDefaultAddonVersion(ctx, name, kubernetesVersion)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, nil
+}
+
+// addonVersionFindings reports components that were not upgraded with the cluster
+func addonVersionFindings(checks []addonVersionCheck) []findings.Finding {
+	var results []findings.Finding
+	for _, c := range checks {
+		if !c.Outdated() {
+			continue
+		}
+		results = append(results, findings.Finding{
+			ID:          "addon_version_mismatch",
+			Severity:    findings.SeverityWarning,
+			Category:    "networking",
+			Resource:    c.Name,
+			Message:     fmt.Sprintf("%s is older than the recommended version %s for Kubernetes %s", c.Name, c.Recommended, c.KubernetesVersion),
+			Remediation: fmt.Sprintf("Update %s to %s", c.Name, c.Recommended),
+		})
+	}
+	return results
+}
+
+func printAddonVersionChecks(checks []addonVersionCheck) {
+	for _, c := range checks {
+		current := c.Running
+		if current == "" {
+			current = "(not running)"
+		}
+		if c.AddonVersion != "" {
+			current = fmt.Sprintf("%s (add-on %s)", current, c.AddonVersion)
+		}
+		if c.Outdated() {
+			logger.Warning("❌ %s %s is older than the recommended %s for Kubernetes %s",
+				c.Name, current, c.Recommended, c.KubernetesVersion)
+		} else {
+			logger.Success("✅ %s %s is up to date for Kubernetes %s", c.Name, current, c.KubernetesVersion)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import "testing"
+
+func TestAddonVersionCheckOutdated(t *testing.T) {
+	tests := []struct {
+		name  string
+		check addonVersionCheck
+		want  bool
+	}{
+		{
+			name:  "same version, other build",
+			check: addonVersionCheck{Running: "v1.11.1-eksbuild.3", AddonVersion: "v1.11.1-eksbuild.3", Recommended: "v1.11.1-eksbuild.9"},
+		},
+		{
+			name:  "older image",
+			check: addonVersionCheck{Running: "v1.10.1-eksbuild.7", Recommended: "v1.11.1-eksbuild.9"},
+			want:  true,
+		},
+		{
+			name:  "older add-on",
+			check: addonVersionCheck{Running: "v1.29.0-minimal-eksbuild.1", AddonVersion: "v1.28.2-eksbuild.2", Recommended: "v1.29.0-eksbuild.1"},
+			want:  true,
+		},
+		{
+			name:  "newer after a manual upgrade",
+			check: addonVersionCheck{Running: "v1.11.3-eksbuild.1", AddonVersion: "v1.11.3-eksbuild.1", Recommended: "v1.11.1-eksbuild.9"},
+		},
+		{
+			name:  "newer minor compared numerically",
+			check: addonVersionCheck{Running: "v1.10.1", Recommended: "v1.9.3-eksbuild.1"},
+		},
+		{
+			name:  "unparseable version",
+			check: addonVersionCheck{Running: "latest", Recommended: "v1.11.1-eksbuild.9"},
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check.Outdated(); got != tt.want {
+				t.Errorf("Outdated() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
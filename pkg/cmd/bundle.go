@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// bundleFile is a single file in the support bundle archive
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+func newBundleCommand() *cobra.Command {
+	var (
+		clusterName string
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bundle [cluster-name]",
+		Short: "Export a support bundle archive for a cluster",
+		Long: `Collect cluster diagnostics into a timestamped tar.gz archive for support cases:
+- Cluster description
+- Nodegroup details
+- Add-on status
+- Events, as listed and merged by object and reason
+- Pod statuses
+- Control plane log samples (if control plane logging is enabled)
+
+With --redact, account IDs, private IP addresses and tokens are replaced in the files
+before they are written, with the same placeholders as the command output.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			// Name the default archive after the resolved cluster: an ARN would add a
+			// directory and the account ID to the path
+			timestamp := time.Now().Format("20060102-150405")
+			if output == "" {
+				output = fmt.Sprintf("ekspeek-bundle-%s-%s.tgz", clusterName, timestamp)
+			}
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			// Each collector adds a file; failures are recorded in errors.txt so a partial
+			// bundle is still produced
+			var files []bundleFile
+			var collectErrors []string
+			collect := func(name string, fn func() (interface{}, error)) {
+				logger.Info("Collecting %s...", name)
+				data, err := fn()
+				if err != nil {
+					logger.Warning("Failed to collect %s: %v", name, err)
+					collectErrors = append(collectErrors, fmt.Sprintf("%s: %v", name, err))
+					return
+				}
+				content, err := json.MarshalIndent(data, "", "  ")
+				if err != nil {
+					collectErrors = append(collectErrors, fmt.Sprintf("%s: %v", name, err))
+					return
+				}
+				files = append(files, bundleFile{name: name, data: content})
+			}
+
+			collect("cluster.json", func() (interface{}, error) {
+				cluster, err := awsClient.DescribeCluster(ctx, clusterName)
+				if err != nil {
+					return nil, err
+				}
+				return cluster.Cluster, nil
+			})
+			collect("nodegroups.json", func() (interface{}, error) {
+				return awsClient.GetClusterNodegroups(ctx, clusterName)
+			})
+			collect("addons.json", func() (interface{}, error) {
+				return awsClient.GetAddons(ctx, clusterName)
+			})
+			// The raw events, and a summary merging repeated ones from the same listing
+			events := []corev1.Event{}
+			eventsListed := false
+			collect("events.json", func() (interface{}, error) {
+				listed, err := kubeClient.ListEvents(ctx, k8s.EventOptions{})
+				if err != nil {
+					return nil, err
+				}
+				events, eventsListed = append(events, listed...), true
+				return events, nil
+			})
+			if eventsListed {
+				collect("event-summary.json", func() (interface{}, error) {
+					return k8s.SummarizeEvents(events), nil
+				})
+			}
+			collect("pods.json", func() (interface{}, error) {
+				return kubeClient.GetPodStatuses(ctx, "")
+			})
+			collect("control-plane-logs.json", func() (interface{}, error) {
+				return awsClient.GetControlPlaneLogSample(ctx, clusterName, 500)
+			})
+
+			if len(collectErrors) > 0 {
+				files = append(files, bundleFile{name: "errors.txt", data: []byte(strings.Join(collectErrors, "\n") + "\n")})
+			}
+
+			if redactor != nil {
+				for i := range files {
+					files[i].data = []byte(redactor.Redact(string(files[i].data)))
+				}
+			}
+
+			dir := fmt.Sprintf("%s-%s", clusterName, timestamp)
+			if err := writeBundle(output, dir, files); err != nil {
+				return err
+			}
+
+			logger.Success("✅ Support bundle written to %s", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&forceDegraded, "force", false, "Collect the bundle even when the cluster is DEGRADED")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Path of the archive to write (default ekspeek-bundle-<cluster>-<timestamp>.tgz)")
+	return cmd
+}
+
+// writeBundle writes the files into a gzipped tarball under the given directory
+func writeBundle(output, dir string, files []bundleFile) error {
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle %s: %w", output, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	now := time.Now()
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    path.Join(dir, file.name),
+			Mode:    0644,
+			Size:    int64(len(file.data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write bundle entry %s: %w", file.name, err)
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return fmt.Errorf("failed to write bundle entry %s: %w", file.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+	return nil
+}
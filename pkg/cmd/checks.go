@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+)
+
+func newChecksCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "checks",
+		Short: "Manage the registered cluster health checks",
+	}
+
+	cmd.AddCommand(newChecksListCommand())
+	return cmd
+}
+
+func newChecksListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the health checks run by cluster-health",
+		Long: `List the registered health checks. Use the names with
+"ekspeek cluster-health --checks" or "--skip-checks" to choose which checks run.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tDESCRIPTION")
+			for _, check := range k8s.HealthChecks() {
+				fmt.Fprintf(w, "%s\t%s\n", check.Name(), check.Description())
+			}
+			return w.Flush()
+		},
+	}
+}
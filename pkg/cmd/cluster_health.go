@@ -2,33 +2,117 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
-	"ekspeek/pkg/k8s"
+	"ekspeek/pkg/aws"
 	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/findings"
+	"ekspeek/pkg/k8s"
+	"ekspeek/pkg/output"
 
 	"github.com/spf13/cobra"
 )
 
 // ClusterHealthCheckConfig contains the configuration for the health check command
 type ClusterHealthCheckConfig struct {
-	ExcludeComponents []string
-	Namespace        string
-	Timeout         time.Duration
+	ExcludeComponents  []string
+	Namespace          string
+	Timeout            time.Duration
+	AllClusters        bool
+	Concurrency        int
+	NamespaceSelector  string
+	Checks             []string
+	SkipChecks         []string
+	Namespaces         []string
+	CriticalNamespaces []string
+	CriticalLabels     []string
+}
+
+// healthSections maps each report section to the health checks that feed it. A section
+// is printed when at least one of its checks ran.
+var healthSections = map[string][]string{
+	"control-plane": {"version-mismatch"},
+	"core":          {"networking"},
+	"nodes":         {"nodes"},
+	"workloads":     {"scheduling", "tolerations", "single-replicas", "probes", "statefulsets", "daemonsets"},
+	"networking":    {"networking", "load-balancers", "ports"},
+	"storage":       {"storage", "intree-storage"},
+	"security":      {"deprecated-apis", "auth", "default-sa-token", "clock-skew"},
+	"logging":       {"logging"},
+	"resources":     {"scheduling"},
+}
+
+// showSection returns true if the report section should be printed
+func showSection(cfg ClusterHealthCheckConfig, status *k8s.ClusterHealthStatus, section string) bool {
+	if contains(cfg.ExcludeComponents, section) {
+		return false
+	}
+	for _, check := range healthSections[section] {
+		if _, failed := status.Errors[check]; failed || status.RanCheck(check) {
+			return true
+		}
+	}
+	return false
+}
+
+// printSectionErrors notes the checks of a report section that failed to collect, since
+// the section is incomplete without them
+func printSectionErrors(status *k8s.ClusterHealthStatus, section string) {
+	for _, check := range healthSections[section] {
+		if msg, failed := status.Errors[check]; failed {
+			logger.Warning("⚠️ Could not collect the %s check: %s", check, msg)
+		}
+	}
+}
+
+// partialHealthError returns err, or the health check error of a partial report so the
+// command still exits non-zero after printing it
+func partialHealthError(err error, partial *k8s.HealthCheckError) error {
+	if err != nil || partial == nil {
+		return err
+	}
+	return partial
+}
+
+// failedCheckFindings records the checks that failed to collect as findings, so structured
+// output shows the report is partial. They are not subject to --only.
+func failedCheckFindings(status *k8s.ClusterHealthStatus) []findings.Finding {
+	checks := make([]string, 0, len(status.Errors))
+	for check := range status.Errors {
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+
+	var results []findings.Finding
+	for _, check := range checks {
+		results = append(results, findings.Finding{
+			ID:          "health_check_failed",
+			Severity:    findings.SeverityWarning,
+			Category:    "health",
+			Resource:    check,
+			Message:     fmt.Sprintf("The %s check failed to collect: %s", check, status.Errors[check]),
+			Remediation: "Issues this check would find are not reported; fix the error and run the check again",
+		})
+	}
+	return results
 }
 
 func newClusterHealthCommand() *cobra.Command {
 	var (
-		clusterName string
-		profile    string
-		region     string
-		cfg        ClusterHealthCheckConfig
+		clusterName     string
+		outputFormat    string
+		publishMetrics  bool
+		metricNamespace string
+		setCurrent      bool
+		cfg             ClusterHealthCheckConfig
 	)
 
 	cmd := &cobra.Command{
-		Use:   "cluster-health [cluster-name]",
+		Use:   "cluster-health [cluster-name...]",
 		Short: "Comprehensive health check for EKS cluster",
 		Long: `Performs a thorough health check of the EKS cluster including:
   • Control Plane Status
@@ -63,11 +147,13 @@ func newClusterHealthCommand() *cobra.Command {
   • Storage
     - PVC/PV status
     - StorageClass availability
+    - StorageClasses using removed in-tree provisioners
     - Volume health
     
   • Security
     - Certificate expiration
     - RBAC configuration
+    - Pods auto-mounting the default service account token
     - Pod security policies
     - Network policies
     
@@ -80,13 +166,17 @@ func newClusterHealthCommand() *cobra.Command {
     - CPU/Memory usage
     - Pod density
     - Resource quotas
-    - Limit ranges`,
+    - Limit ranges
+
+Pass several cluster names, or --all to check every cluster in the region,
+to run the checks concurrently and print a per-cluster summary table.
+
+With --publish-metrics, the issue counts and API server certificate expiry are
+published as CloudWatch custom metrics with a ClusterName dimension, for alarming.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
-			if clusterName == "" {
-				return fmt.Errorf("cluster name is required")
+			format, err := parseOutputFormat(outputFormat)
+			if err != nil {
+				return err
 			}
 
 			ctx := context.Background()
@@ -96,100 +186,298 @@ func newClusterHealthCommand() *cobra.Command {
 				defer cancel()
 			}
 
-			// Create kubernetes client using default kubeconfig or KUBECONFIG env var
-			kubeClient, err := getKubeClient()
-			if err != nil {
-				return fmt.Errorf("failed to create kubernetes client: %w", err)
+			// Fleet mode: check several clusters and print a consolidated summary
+			if cfg.AllClusters || len(args) > 1 {
+				if format != output.FormatText {
+					return fmt.Errorf("--output %s is only supported when checking a single cluster", format)
+				}
+				if publishMetrics {
+					return fmt.Errorf("--publish-metrics is only supported when checking a single cluster")
+				}
+				if fromDump != "" {
+					return fmt.Errorf("--from-dump is only supported when checking a single cluster")
+				}
+				clusters := args
+				if cfg.AllClusters {
+					awsClient, err := newAWSClient(ctx, aws.ClientConfig{
+						Profile: profile,
+						Region:  region,
+						RoleARN: roleARN,
+					})
+					if err != nil {
+						return fmt.Errorf("failed to create AWS client: %w", err)
+					}
+					clusters, err = awsClient.ListClusters(ctx)
+					if err != nil {
+						return err
+					}
+				}
+				if len(clusters) == 0 {
+					logger.Info("No EKS clusters found")
+					return nil
+				}
+
+				logger.Info("Running health checks across %d clusters...", len(clusters))
+				results := runFleetHealthCheck(ctx, clusters, cfg.Concurrency, k8s.HealthCheckOptions{
+					NamespaceSelector:  cfg.NamespaceSelector,
+					Checks:             cfg.Checks,
+					SkipChecks:         cfg.SkipChecks,
+					Namespaces:         cfg.Namespaces,
+					CriticalNamespaces: cfg.CriticalNamespaces,
+					CriticalLabels:     cfg.CriticalLabels,
+				})
+				printFleetSummary(results)
+				return nil
+			}
+
+			clusterName = clusterNameArg(args)
+			if clusterName == "" && fromDump != "" {
+				if cluster, err := k8s.LoadDumpCluster(fromDump); err == nil && cluster != nil {
+					clusterName = cluster.Name
+				}
+			}
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+			if fromDump == "" {
+				if _, clusterName, err = activeClusterClient(ctx, cmd, clusterName); err != nil {
+					return err
+				}
+			}
+
+			// Create kubernetes client using default kubeconfig or KUBECONFIG env var. When
+			// --region, --profile or --role-arn is given, or the config file has an entry for
+			// the cluster, connect to the cluster in that region and account instead of the
+			// current context. --set-current also writes the cluster's kubeconfig entry and
+			// makes it the current context.
+			var kubeClient *k8s.KubeClient
+			if !inCluster && fromDump == "" && (setCurrent || profileSet || roleARNSet || regionSet || hasClusterConfig(clusterName)) {
+				logger.Info("Updating kubeconfig for cluster %s in %s", clusterName, clusterAWSConfig(clusterName).Region)
+				kubeClient, err = connectToCluster(ctx, clusterName, clusterAWSConfig(clusterName), k8s.KubeconfigOptions{SetCurrent: setCurrent}, logger.Default())
+				if err != nil {
+					return err
+				}
+				logKubeTarget(kubeClient)
+			} else {
+				kubeClient, err = getClusterKubeClient(ctx, clusterName)
+				if err != nil {
+					return fmt.Errorf("failed to create kubernetes client: %w", err)
+				}
+				if fromDump == "" {
+					kubeClient.MaxPodsLookup = ec2MaxPods(clusterAWSConfig(clusterName))
+				}
 			}
 
 			logger.Info("Starting comprehensive cluster health check for %s...", clusterName)
 
+			// Show which check is running on interactive terminals
+			var spinner *logger.Spinner
+			if format == output.FormatText {
+				spinner = logger.StartSpinner("Starting health checks...")
+			}
+
+			// With JSON Lines, write each check's findings as soon as it finishes
+			var stream *jsonlStream
+			var onFindings func(string, []findings.Finding) error
+			if format == output.FormatJSONL {
+				stream, err = newJSONLStream()
+				if err != nil {
+					return err
+				}
+				onFindings = func(_ string, results []findings.Finding) error {
+					return stream.write(selectFindings(results))
+				}
+			}
+
 			// Get cluster health status
-			status, err := kubeClient.CheckClusterHealth(ctx)
-			if err != nil {
+			status, err := kubeClient.CheckClusterHealthWithOptions(ctx, k8s.HealthCheckOptions{
+				NamespaceSelector:  cfg.NamespaceSelector,
+				Checks:             cfg.Checks,
+				SkipChecks:         cfg.SkipChecks,
+				Namespaces:         cfg.Namespaces,
+				CriticalNamespaces: cfg.CriticalNamespaces,
+				CriticalLabels:     cfg.CriticalLabels,
+				OnFindings:         onFindings,
+				Progress: func(e k8s.HealthCheckEvent) {
+					if !e.Done {
+						spinner.Update("Running %s check (%d/%d, %d remaining)...", e.Check, e.Index, e.Total, e.Total-e.Index)
+					}
+				},
+			})
+			spinner.Stop()
+			// With some checks failed, report the others and fail the command at the end
+			var partial *k8s.HealthCheckError
+			if err != nil && !errors.As(err, &partial) {
 				return fmt.Errorf("failed to check cluster health: %w", err)
 			}
+			if partial != nil {
+				logger.Warning("%d health checks failed to collect; the report is partial", len(partial.Errors))
+			}
+			if len(status.SkippedChecks) > 0 {
+				if len(cfg.Namespaces) > 0 {
+					logger.Info("Checking namespaces %s only; skipped checks that need cluster-wide access: %s",
+						strings.Join(cfg.Namespaces, ", "), strings.Join(status.SkippedChecks, ", "))
+				} else {
+					logger.Info("Skipped checks that need a live cluster: %s", strings.Join(status.SkippedChecks, ", "))
+				}
+			}
+
+			// CoreDNS and kube-proxy must be upgraded along with the cluster
+			var addonChecks []addonVersionCheck
+			if fromDump == "" && status.RanCheck("networking") {
+				awsClient, err := newAWSClient(ctx, clusterAWSConfig(clusterName))
+				if err == nil {
+					addonChecks, err = checkAddonVersions(ctx, awsClient, kubeClient, clusterName)
+				}
+				if err != nil {
+					logger.Warning("Skipped add-on version check: %v", err)
+				} else {
+					addonFindings := addonVersionFindings(addonChecks)
+					status.Findings = append(status.Findings, addonFindings...)
+					findings.Sort(status.Findings)
+					if stream != nil {
+						if err := stream.write(selectFindings(addonFindings)); err != nil {
+							return err
+						}
+					}
+				}
+			}
+
+			if publishMetrics {
+				if err := publishHealthMetrics(ctx, metricNamespace, clusterName, kubeClient, status); err != nil {
+					return err
+				}
+			}
+
+			if stream != nil {
+				if err := stream.write(failedCheckFindings(status)); err != nil {
+					return err
+				}
+				return partialHealthError(stream.close(), partial)
+			}
+			if format != output.FormatText {
+				results := append(selectFindings(status.Findings), failedCheckFindings(status)...)
+				return partialHealthError(printResult(format, results), partial)
+			}
 
 			// Print section headers in a more visible way
-			fmt.Println("\n" + strings.Repeat("=", 80))
-			fmt.Println("EKS CLUSTER HEALTH CHECK RESULTS")
-			fmt.Println("Cluster: " + clusterName)
-			fmt.Println("Time: " + time.Now().Format(time.RFC1123))
-			fmt.Println(strings.Repeat("=", 80))
+			logger.Plain("\n%s", strings.Repeat("=", 80))
+			logger.Plain("EKS CLUSTER HEALTH CHECK RESULTS")
+			logger.Plain("Cluster: %s", clusterName)
+			logger.Plain("Time: %s", time.Now().Format(time.RFC1123))
+			logger.Plain("%s", strings.Repeat("=", 80))
 
 			// Control Plane Status
-			if !contains(cfg.ExcludeComponents, "control-plane") {
+			if showSection(cfg, status, "control-plane") {
 				logger.Info("\n=== Control Plane Status ===")
+				printSectionErrors(status, "control-plane")
 				printControlPlaneStatus(status)
 			}
 
 			// Core Components Status
-			if !contains(cfg.ExcludeComponents, "core") {
+			if showSection(cfg, status, "core") {
 				logger.Info("\n=== Core Components Status ===")
+				printSectionErrors(status, "core")
 				printCoreComponentsStatus(status)
 			}
 
 			// Node Health
-			if !contains(cfg.ExcludeComponents, "nodes") {
+			if showSection(cfg, status, "nodes") {
 				logger.Info("\n=== Node Health ===")
+				printSectionErrors(status, "nodes")
 				printNodeStatus(status.NodeStatus)
 			}
 
 			// Workload Health
-			if !contains(cfg.ExcludeComponents, "workloads") {
+			if showSection(cfg, status, "workloads") {
 				logger.Info("\n=== Workload Health ===")
+				printSectionErrors(status, "workloads")
 				printWorkloadStatus(status, cfg.Namespace)
 			}
 
 			// Networking Status
-			if !contains(cfg.ExcludeComponents, "networking") {
+			if showSection(cfg, status, "networking") {
 				logger.Info("\n=== Networking Status ===")
+				printSectionErrors(status, "networking")
 				printNetworkingStatus(status.NetworkingStatus)
+				printAddonVersionChecks(addonChecks)
+				if status.RanCheck("ports") {
+					printPortStatus(status.PortStatus)
+				}
 			}
 
 			// Storage Status
-			if !contains(cfg.ExcludeComponents, "storage") {
+			if showSection(cfg, status, "storage") {
 				logger.Info("\n=== Storage Status ===")
+				printSectionErrors(status, "storage")
 				printStorageStatus(status)
 			}
 
 			// Security Status
-			if !contains(cfg.ExcludeComponents, "security") {
+			if showSection(cfg, status, "security") {
 				logger.Info("\n=== Security Status ===")
+				printSectionErrors(status, "security")
 				printSecurityStatus(status)
 			}
 
 			// Logging & Monitoring
-			if !contains(cfg.ExcludeComponents, "logging") {
+			if showSection(cfg, status, "logging") {
 				logger.Info("\n=== Logging & Monitoring Status ===")
+				printSectionErrors(status, "logging")
 				printLoggingStatus(status.LoggingStatus)
 			}
 
 			// Resource Utilization
-			if !contains(cfg.ExcludeComponents, "resources") {
+			if showSection(cfg, status, "resources") {
 				logger.Info("\n=== Resource Utilization ===")
+				printSectionErrors(status, "resources")
 				printResourceUtilization(status)
 			}
 
 			// Add summary section at the end
-			fmt.Println("\n" + strings.Repeat("=", 80))
-			fmt.Println("SUMMARY")
-			fmt.Println(strings.Repeat("=", 80))
+			logger.Plain("\n%s", strings.Repeat("=", 80))
+			logger.Plain("SUMMARY")
+			logger.Plain("%s", strings.Repeat("=", 80))
 			printHealthSummary(status)
 
-			return nil
+			return partialHealthError(saveResult(append(selectFindings(status.Findings), failedCheckFindings(status)...)), partial)
 		},
 	}
 
 	// Add flags
-	cmd.Flags().StringVar(&profile, "profile", "", "AWS profile to use")
-	cmd.Flags().StringVar(&region, "region", "", "AWS region of the EKS cluster")
 	cmd.Flags().StringSliceVar(&cfg.ExcludeComponents, "exclude", []string{},
 		"Components to exclude from health check (comma-separated: control-plane,core,nodes,workloads,networking,storage,security,logging,resources)")
+	cmd.Flags().MarkDeprecated("exclude", "use --checks or --skip-checks with names from \"ekspeek checks list\"")
+	cmd.Flags().StringSliceVar(&cfg.Checks, "checks", nil,
+		"Only run these health checks (comma-separated, see \"ekspeek checks list\")")
+	cmd.Flags().StringSliceVar(&cfg.SkipChecks, "skip-checks", nil,
+		"Health checks to skip (comma-separated, see \"ekspeek checks list\")")
 	cmd.Flags().StringVarP(&cfg.Namespace, "namespace", "n", "",
 		"Namespace to check (default is all namespaces)")
+	cmd.Flags().StringVar(&cfg.NamespaceSelector, "namespace-selector", "",
+		"Label selector limiting workload, storage, networking and security checks to matching namespaces (e.g. team=payments)")
+	cmd.Flags().StringSliceVar(&cfg.Namespaces, "namespaces", nil,
+		"Only check these namespaces (comma-separated), for users with namespace-scoped RBAC; checks that need cluster-wide access are skipped")
+	cmd.Flags().StringSliceVar(&cfg.CriticalNamespaces, "critical-namespaces", nil,
+		"Namespaces whose single-replica workloads are flagged as cluster infrastructure (default "+strings.Join(k8s.DefaultCriticalNamespaces, ",")+")")
+	cmd.Flags().StringArrayVar(&cfg.CriticalLabels, "critical-labels", nil,
+		"Label selector marking workloads in any namespace as cluster infrastructure; repeat for several (default "+strings.Join(k8s.DefaultCriticalLabels, ", ")+")")
 	cmd.Flags().DurationVar(&cfg.Timeout, "timeout", 5*time.Minute,
 		"Timeout for the health check (e.g. 5m, 1h)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text",
+		"Output format: text, json, jsonl, yaml or sarif (sarif includes security findings only; jsonl streams findings as each check finishes)")
+	cmd.Flags().BoolVar(&cfg.AllClusters, "all", false,
+		"Check every cluster in the region and print a summary table")
+	cmd.Flags().IntVar(&cfg.Concurrency, "concurrency", 4,
+		"Maximum number of clusters to check concurrently in fleet mode")
+	cmd.Flags().BoolVar(&publishMetrics, "publish-metrics", false,
+		"Publish issue counts and certificate expiry as CloudWatch custom metrics")
+	cmd.Flags().StringVar(&metricNamespace, "metric-namespace", "EKSPeek/ClusterHealth",
+		"CloudWatch namespace for --publish-metrics")
+	cmd.Flags().BoolVar(&setCurrent, "set-current", false,
+		"Switch the kubeconfig current-context to this cluster")
+	cmd.Flags().BoolVar(&forceDegraded, "force", false,
+		"Run the checks even when the cluster is DEGRADED")
 
 	return cmd
 }
@@ -208,7 +496,7 @@ func printControlPlaneStatus(status *k8s.ClusterHealthStatus) {
 	if len(status.NodeVersions) > 1 {
 		logger.Warning("❌ Version mismatch detected:")
 		for version, nodes := range status.NodeVersions {
-			fmt.Printf("Version %s: %d nodes (%s)\n", version, len(nodes), strings.Join(nodes, ", "))
+			logger.Detail("Version %s: %d nodes (%s)", version, len(nodes), strings.Join(nodes, ", "))
 		}
 	} else {
 		logger.Success("✅ All nodes running same Kubernetes version")
@@ -218,7 +506,7 @@ func printControlPlaneStatus(status *k8s.ClusterHealthStatus) {
 func printCoreComponentsStatus(status *k8s.ClusterHealthStatus) {
 	// CoreDNS Status
 	if len(status.NetworkingStatus.CoreDNSStatus) > 0 {
-		fmt.Println("\nCoreDNS Status:")
+		logger.Detail("\nCoreDNS Status:")
 		for _, pod := range status.NetworkingStatus.CoreDNSStatus {
 			if pod.Status != "Running" {
 				logger.Warning("❌ CoreDNS pod %s is %s: %s", pod.Name, pod.Status, pod.Message)
@@ -230,7 +518,7 @@ func printCoreComponentsStatus(status *k8s.ClusterHealthStatus) {
 
 	// CNI Status
 	if len(status.NetworkingStatus.CNIStatus) > 0 {
-		fmt.Println("\nCNI Status:")
+		logger.Detail("\nCNI Status:")
 		for _, pod := range status.NetworkingStatus.CNIStatus {
 			if pod.Status != "Running" {
 				logger.Warning("❌ CNI pod %s is %s: %s", pod.Name, pod.Status, pod.Message)
@@ -246,16 +534,56 @@ func printWorkloadStatus(status *k8s.ClusterHealthStatus, namespace string) {
 		logger.Warning("❌ Pods pending scheduling:")
 		for _, pod := range status.SchedulingStatus.PendingPods {
 			if namespace == "" || namespace == pod.Namespace {
-				fmt.Printf("- %s/%s: %s\n", pod.Namespace, pod.Pod, pod.Reason)
+				logger.Detail("- %s/%s: %s", pod.Namespace, pod.Pod, pod.Reason)
+				for _, shortage := range pod.ResourceShortages {
+					logger.Detail("  No node fits %s", shortage)
+				}
+				if pod.SpreadConstraint != "" {
+					logger.Detail("  Topology spread: %s", pod.SpreadConstraint)
+				}
 			}
 		}
 	} else {
 		logger.Success("✅ All pods are scheduled correctly")
 	}
 
+	printPodCapacityIssues(status.SchedulingStatus)
+
+	if len(status.TolerationIssues) > 0 {
+		logger.Warning("❌ Pods on tainted nodes through over-broad tolerations:")
+		for _, issue := range status.TolerationIssues {
+			if namespace == "" || namespace == issue.Namespace {
+				logger.Detail("- %s/%s on %s: tolerates %s via %s",
+					issue.Namespace, issue.Pod, issue.Node, issue.Taint, issue.Toleration)
+			}
+		}
+	}
+
+	if len(status.SingleReplicaWorkloads) > 0 {
+		logger.Warning("⚠️ Cluster infrastructure with a single replica and no PodDisruptionBudget:")
+		for _, w := range status.SingleReplicaWorkloads {
+			if namespace == "" || namespace == w.Namespace {
+				logger.Detail("- %s %s/%s (%s)", w.Kind, w.Namespace, w.Name, w.Reason)
+			}
+		}
+	}
+
+	if len(status.ProbeIssues) > 0 {
+		logger.Warning("⚠️ Misconfigured container probes:")
+		for _, issue := range status.ProbeIssues {
+			if namespace == "" || namespace == issue.Namespace {
+				logger.Detail("- %s/%s container %s, %s probe (%d restarts): %s",
+					issue.Namespace, issue.Pod, issue.Container, issue.Probe, issue.RestartCount, issue.Config)
+				for _, problem := range issue.Problems {
+					logger.Detail("  %s", problem)
+				}
+			}
+		}
+	}
+
 	// Add StatefulSet status
 	if len(status.StatefulSetStatus) > 0 {
-		fmt.Println("\nStatefulSet Status:")
+		logger.Detail("\nStatefulSet Status:")
 		for _, sts := range status.StatefulSetStatus {
 			if sts.ReadyReplicas != sts.DesiredReplicas {
 				logger.Warning("❌ StatefulSet %s/%s: %d/%d replicas ready",
@@ -269,7 +597,7 @@ func printWorkloadStatus(status *k8s.ClusterHealthStatus, namespace string) {
 
 	// Add DaemonSet status
 	if len(status.DaemonSetStatus) > 0 {
-		fmt.Println("\nDaemonSet Status:")
+		logger.Detail("\nDaemonSet Status:")
 		for _, ds := range status.DaemonSetStatus {
 			if ds.NumberUnavailable > 0 {
 				logger.Warning("❌ DaemonSet %s/%s: %d pods unavailable",
@@ -282,10 +610,37 @@ func printWorkloadStatus(status *k8s.ClusterHealthStatus, namespace string) {
 	}
 }
 
+func printPortStatus(status k8s.PortStatus) {
+	logger.Detail("\nHost Ports and NodePorts:")
+	if len(status.HostPortConflicts) == 0 && len(status.PendingHostPortPods) == 0 {
+		logger.Success("✅ No host port conflicts")
+	}
+	for _, c := range status.HostPortConflicts {
+		logger.Warning("❌ Host port %d/%s on node %s is bound by several pods:", c.Port, c.Protocol, c.NodeName)
+		for _, pod := range c.Pods {
+			logger.Detail("  - %s", pod)
+		}
+	}
+	for _, p := range status.PendingHostPortPods {
+		logger.Warning("⚠️ Pending pod %s needs host port %d/%s, in use on %d of %d nodes",
+			p.Pod, p.Port, p.Protocol, p.NodesInUse, p.TotalNodes)
+	}
+
+	if len(status.NodePortServices) > 0 {
+		logger.Info("%d services use NodePorts:", len(status.NodePortServices))
+		for _, svc := range status.NodePortServices {
+			logger.Detail("  - %s/%s (%s): %v", svc.Namespace, svc.Name, svc.Type, svc.NodePorts)
+		}
+	}
+	for _, issue := range status.NodePortIssues {
+		logger.Warning("❌ %s", issue)
+	}
+}
+
 func printStorageStatus(status *k8s.ClusterHealthStatus) {
 	// Check PVC status
 	if len(status.PVCStatus) > 0 {
-		fmt.Println("\nPersistent Volume Claims:")
+		logger.Detail("\nPersistent Volume Claims:")
 		for _, pvc := range status.PVCStatus {
 			if pvc.Status.Phase != "Bound" {
 				logger.Warning("❌ PVC %s/%s is %s", pvc.Namespace, pvc.Name, pvc.Status.Phase)
@@ -295,9 +650,21 @@ func printStorageStatus(status *k8s.ClusterHealthStatus) {
 		}
 	}
 
+	// Correlate Pending StatefulSet pods with their volumes
+	if len(status.StatefulSetVolumeIssues) > 0 {
+		logger.Detail("\nStatefulSet Volumes:")
+		for _, issue := range status.StatefulSetVolumeIssues {
+			logger.Warning("❌ Pod %s/%s (StatefulSet %s) is Pending; PVC %s is %s", issue.Namespace, issue.Pod, issue.StatefulSet, issue.PVC, issue.Phase)
+			if issue.Provisioner != "" {
+				logger.Detail("  StorageClass %s: provisioner %s, binding mode %s", issue.StorageClass, issue.Provisioner, issue.BindingMode)
+			}
+			logger.Detail("  %s", issue.Diagnosis)
+		}
+	}
+
 	// Check StorageClass status
 	if len(status.StorageClasses) > 0 {
-		fmt.Println("\nStorage Classes:")
+		logger.Detail("\nStorage Classes:")
 		for _, sc := range status.StorageClasses {
 			if sc.DefaultClass {
 				logger.Info("ℹ️ Default StorageClass: %s", sc.Name)
@@ -308,13 +675,27 @@ func printStorageStatus(status *k8s.ClusterHealthStatus) {
 	} else {
 		logger.Warning("⚠️ No StorageClasses found in cluster")
 	}
+
+	// StorageClasses still using in-tree provisioners
+	for _, sc := range status.InTreeStorageClasses {
+		switch {
+		case !sc.CSIDriverInstalled && sc.Removed:
+			logger.Warning("❌ StorageClass %s uses %s, removed in %s, and CSI driver %s is not installed; dynamic provisioning fails", sc.Name, sc.Provisioner, sc.RemovedIn, sc.CSIDriver)
+		case !sc.CSIDriverInstalled && sc.Migrated:
+			logger.Warning("❌ StorageClass %s uses %s, migrated to CSI in %s, and CSI driver %s is not installed; dynamic provisioning fails", sc.Name, sc.Provisioner, sc.MigratedIn, sc.CSIDriver)
+		case !sc.CSIDriverInstalled:
+			logger.Warning("⚠️ StorageClass %s uses %s, migrated to CSI in %s; install CSI driver %s before upgrading", sc.Name, sc.Provisioner, sc.MigratedIn, sc.CSIDriver)
+		default:
+			logger.Info("ℹ️ StorageClass %s uses %s; provisioned by %s through CSI migration", sc.Name, sc.Provisioner, sc.CSIDriver)
+		}
+	}
 }
 
 func printSecurityStatus(status *k8s.ClusterHealthStatus) {
 	if len(status.DeprecatedAPIs) > 0 {
 		logger.Warning("❌ Deprecated API usage detected:")
 		for _, api := range status.DeprecatedAPIs {
-			fmt.Printf("- %s\n", api)
+			logger.Detail("- %s", api)
 		}
 	} else {
 		logger.Success("✅ No deprecated API usage found")
@@ -323,7 +704,7 @@ func printSecurityStatus(status *k8s.ClusterHealthStatus) {
 	if len(status.AuthStatus.IRSAIssues) > 0 {
 		logger.Warning("\n❌ IRSA issues detected:")
 		for _, issue := range status.AuthStatus.IRSAIssues {
-			fmt.Printf("- %s\n", issue)
+			logger.Detail("- %s", issue)
 		}
 	} else {
 		logger.Success("✅ No IRSA issues detected")
@@ -332,18 +713,44 @@ func printSecurityStatus(status *k8s.ClusterHealthStatus) {
 	if len(status.AuthStatus.RBACIssues) > 0 {
 		logger.Warning("\n❌ RBAC issues detected:")
 		for _, issue := range status.AuthStatus.RBACIssues {
-			fmt.Printf("- %s\n", issue)
+			logger.Detail("- %s", issue)
 		}
 	} else {
 		logger.Success("✅ No RBAC issues detected")
 	}
+
+	if status.RanCheck("default-sa-token") {
+		if len(status.DefaultTokenNamespaces) > 0 {
+			logger.Warning("\n❌ Pods auto-mounting the default service account token:")
+			for _, ns := range status.DefaultTokenNamespaces {
+				logger.Detail("- %s: %d pods", ns.Namespace, ns.Pods)
+				for _, pod := range ns.Exposed {
+					logger.Detail("  - Behind a load balancer: %s", pod)
+				}
+			}
+			logger.Detail("  Set automountServiceAccountToken: false on the default service account of these namespaces")
+		} else {
+			logger.Success("✅ No pods auto-mount the default service account token")
+		}
+	}
+
+	if status.RanCheck("clock-skew") {
+		if len(status.ClockSkew) > 0 {
+			logger.Warning("\n❌ Node clocks off from the control plane:")
+			for _, skew := range status.ClockSkew {
+				logger.Detail("- %s: %s", skew.Node, skew)
+			}
+		} else {
+			logger.Success("✅ Node clocks match the control plane")
+		}
+	}
 }
 
 // Print logging and monitoring status
 func printLoggingStatus(status k8s.LoggingStatus) {
 	// FluentBit Status
 	if len(status.FluentBitStatus) > 0 {
-		fmt.Println("\nFluentBit Status:")
+		logger.Detail("\nFluentBit Status:")
 		for _, pod := range status.FluentBitStatus {
 			if pod.Status != "Running" {
 				logger.Warning("❌ FluentBit pod %s is %s: %s", pod.Name, pod.Status, pod.Message)
@@ -357,7 +764,7 @@ func printLoggingStatus(status k8s.LoggingStatus) {
 
 	// CloudWatch Status
 	if len(status.CloudWatchStatus) > 0 {
-		fmt.Println("\nCloudWatch Agent Status:")
+		logger.Detail("\nCloudWatch Agent Status:")
 		for _, pod := range status.CloudWatchStatus {
 			if pod.Status != "Running" {
 				logger.Warning("❌ CloudWatch pod %s is %s: %s", pod.Name, pod.Status, pod.Message)
@@ -371,7 +778,7 @@ func printLoggingStatus(status k8s.LoggingStatus) {
 
 	// Metrics Server Status
 	if len(status.MetricsServerStatus) > 0 {
-		fmt.Println("\nMetrics Server Status:")
+		logger.Detail("\nMetrics Server Status:")
 		for _, pod := range status.MetricsServerStatus {
 			if pod.Status != "Running" {
 				logger.Warning("❌ Metrics Server pod %s is %s: %s", pod.Name, pod.Status, pod.Message)
@@ -385,7 +792,7 @@ func printLoggingStatus(status k8s.LoggingStatus) {
 
 	// Dynatrace Status
 	if len(status.DynatraceStatus) > 0 {
-		fmt.Println("\nDynatrace OneAgent Status:")
+		logger.Detail("\nDynatrace OneAgent Status:")
 		for _, pod := range status.DynatraceStatus {
 			if pod.Status != "Running" {
 				logger.Warning("❌ Dynatrace OneAgent pod %s is %s: %s", pod.Name, pod.Status, pod.Message)
@@ -399,17 +806,17 @@ func printLoggingStatus(status k8s.LoggingStatus) {
 }
 
 func printResourceUtilization(status *k8s.ClusterHealthStatus) {
-	fmt.Printf("\nCluster Resource Usage by Node:\n")
+	logger.Detail("\nCluster Resource Usage by Node:")
 
 	if len(status.SchedulingStatus.ResourceIssues) > 0 {
-		fmt.Printf("\nNodes with Resource Pressure:\n")
+		logger.Detail("\nNodes with Resource Pressure:")
 		for _, issue := range status.SchedulingStatus.ResourceIssues {
-			fmt.Printf("\nNode %s:\n", issue.NodeName)
-			fmt.Printf("  CPU: %.1f%% utilized (%.1f/%.1f cores)\n",
+			logger.Detail("\nNode %s:", issue.NodeName)
+			logger.Detail("  CPU: %.1f%% utilized (%.1f/%.1f cores)",
 				issue.CPU.Utilization,
 				float64(issue.CPU.Allocated)/1000,
 				float64(issue.CPU.Capacity)/1000)
-			fmt.Printf("  Memory: %.1f%% utilized (%.1f/%.1f GB)\n",
+			logger.Detail("  Memory: %.1f%% utilized (%.1f/%.1f GB)",
 				issue.Memory.Utilization,
 				float64(issue.Memory.Allocated)/(1024*1024*1024),
 				float64(issue.Memory.Capacity)/(1024*1024*1024))
@@ -417,51 +824,37 @@ func printResourceUtilization(status *k8s.ClusterHealthStatus) {
 	}
 }
 
+// countHealthIssues returns the total and critical issue counts for a health check result
+func countHealthIssues(status *k8s.ClusterHealthStatus) (totalIssues, criticalIssues int) {
+	totalIssues = findings.Count(status.Findings, findings.SeverityWarning)
+	criticalIssues = findings.Count(status.Findings, findings.SeverityCritical)
+
+	return totalIssues, criticalIssues
+}
+
 func printHealthSummary(status *k8s.ClusterHealthStatus) {
-	var (
-		totalIssues    int
-		criticalIssues int
-	)
+	totalIssues, criticalIssues := countHealthIssues(status)
 
-	// Count issues by category
-	if len(status.NodeVersions) > 1 {
-		criticalIssues++ // Version mismatch is critical
+	if len(status.Errors) > 0 {
+		logger.Warning("%d checks failed to collect, so issues they would find are not counted:", len(status.Errors))
+		checks := make([]string, 0, len(status.Errors))
+		for check := range status.Errors {
+			checks = append(checks, check)
+		}
+		sort.Strings(checks)
+		for _, check := range checks {
+			logger.Detail("- %s: %s", check, status.Errors[check])
+		}
 	}
-	totalIssues += len(status.DeprecatedAPIs)
-	totalIssues += len(status.AuthStatus.IRSAIssues)
-	totalIssues += len(status.AuthStatus.RBACIssues)
-	totalIssues += len(status.NodeStatus.NotReady)
-	totalIssues += len(status.SchedulingStatus.PendingPods)
-	totalIssues += len(status.LoadBalancerStatus.PendingServices)
 
 	if criticalIssues > 0 {
 		logger.Warning("Found %d critical issues that need immediate attention", criticalIssues)
 	}
-	
+
 	if totalIssues > 0 {
 		logger.Warning("Total issues found: %d", totalIssues)
-		fmt.Println("\nRecommended actions:")
-		if len(status.NodeVersions) > 1 {
-			fmt.Println("1. Upgrade nodes to match control plane version")
-		}
-		if len(status.DeprecatedAPIs) > 0 {
-			fmt.Println("2. Update applications using deprecated APIs")
-		}
-		if len(status.AuthStatus.IRSAIssues) > 0 {
-			fmt.Println("3. Fix IRSA configuration issues")
-		}
-		if len(status.AuthStatus.RBACIssues) > 0 {
-			fmt.Println("4. Review and fix RBAC issues")
-		}
-		if len(status.NodeStatus.NotReady) > 0 {
-			fmt.Println("5. Investigate nodes in NotReady state")
-		}
-		if len(status.SchedulingStatus.PendingPods) > 0 {
-			fmt.Println("6. Address pod scheduling issues")
-		}
-		if len(status.LoadBalancerStatus.PendingServices) > 0 {
-			fmt.Println("7. Check LoadBalancer provisioning issues")
-		}
+		printFindings(status.Findings)
+		printRootCauses(diagnose(status))
 	} else {
 		logger.Success("No issues found - cluster is healthy!")
 	}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"ekspeek/pkg/k8s"
+)
+
+func TestFailedCheckFindings(t *testing.T) {
+	status := &k8s.ClusterHealthStatus{Errors: map[string]string{
+		"storage":     "persistentvolumeclaims is forbidden",
+		"deployments": "deployments.apps is forbidden",
+	}}
+
+	got := failedCheckFindings(status)
+	if len(got) != 2 {
+		t.Fatalf("failedCheckFindings() = %+v, want 2 findings", got)
+	}
+	for i, check := range []string{"deployments", "storage"} {
+		if got[i].ID != "health_check_failed" || got[i].Resource != check || !got[i].IsIssue() {
+			t.Errorf("findings[%d] = %+v, want a health_check_failed issue for %s", i, got[i], check)
+		}
+	}
+
+	if got := failedCheckFindings(&k8s.ClusterHealthStatus{}); len(got) != 0 {
+		t.Errorf("failedCheckFindings() of a full report = %+v, want none", got)
+	}
+}
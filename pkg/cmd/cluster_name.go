@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/spf13/cobra"
+)
+
+// resolveClusterName turns the cluster argument of a command into a cluster name. It
+// accepts a cluster ARN (arn:aws:eks:region:account:cluster/name), an exact name, or a
+// prefix matching exactly one cluster in the region.
+func resolveClusterName(ctx context.Context, client *aws.Client, input string) (string, error) {
+	if name, ok := clusterNameFromARN(input); ok {
+		return name, nil
+	}
+
+	_, err := client.DescribeCluster(ctx, input)
+	var notFound *ekstypes.ResourceNotFoundException
+	if err == nil || !errors.As(err, &notFound) {
+		// Other errors are reported by the command's own calls
+		return input, nil
+	}
+
+	clusters, err := client.ListClusters(ctx)
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, c := range clusters {
+		if strings.HasPrefix(c, input) {
+			matches = append(matches, c)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("cluster %q not found", input)
+	case 1:
+		logger.Info("Using cluster %s", matches[0])
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("cluster name %q is ambiguous; matches %s", input, strings.Join(matches, ", "))
+}
+
+// clusterNameFromARN returns the cluster name of an EKS cluster ARN
+func clusterNameFromARN(arn string) (string, bool) {
+	_, name, err := parseClusterARN(arn)
+	return name, err == nil
+}
+
+// parseClusterARN returns the region and cluster name of an EKS cluster ARN
+// (arn:partition:eks:region:account:cluster/name)
+func parseClusterARN(arn string) (region, name string, err error) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 || parts[0] != "arn" || parts[2] != "eks" || !strings.HasPrefix(parts[5], "cluster/") {
+		return "", "", fmt.Errorf("%q is not an EKS cluster ARN (arn:aws:eks:region:account:cluster/name)", arn)
+	}
+	region, name = parts[3], strings.TrimPrefix(parts[5], "cluster/")
+	if region == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("%q is not an EKS cluster ARN (arn:aws:eks:region:account:cluster/name)", arn)
+	}
+	return region, name, nil
+}
+
+// clusterARNEnv names the environment variable read when --cluster-arn is not set
+const clusterARNEnv = "EKSPEEK_CLUSTER_ARN"
+
+// applyClusterARN reads the cluster ARN from --cluster-arn or EKSPEEK_CLUSTER_ARN.
+// Commands fall back to the cluster name and region from the ARN when their cluster
+// argument is empty, see clusterNameArg.
+func applyClusterARN() error {
+	if clusterARN == "" {
+		clusterARN = os.Getenv(clusterARNEnv)
+	}
+	if clusterARN == "" {
+		return nil
+	}
+
+	var err error
+	arnRegion, arnClusterName, err = parseClusterARN(clusterARN)
+	if err != nil {
+		return fmt.Errorf("invalid cluster ARN: %w", err)
+	}
+	return nil
+}
+
+// clusterNameArg returns the cluster name argument of a command, or the cluster name
+// from the cluster ARN when the argument is empty. Only in that case the region is taken
+// from the ARN too, unless --region is set, so an exported EKSPEEK_CLUSTER_ARN does not
+// move commands naming another cluster to the ARN's region.
+func clusterNameArg(args []string) string {
+	if len(args) > 0 && args[0] != "" {
+		return args[0]
+	}
+	if arnClusterName == "" {
+		return ""
+	}
+	if !regionSet {
+		region, regionSet = arnRegion, true
+	} else if region != arnRegion {
+		logger.Warning("⚠️ --region %s differs from the region %s of cluster ARN %s", region, arnRegion, clusterARN)
+	}
+	return arnClusterName
+}
+
+// clusterClient resolves the cluster name given to a command with resolveClusterName and
+// returns an AWS client with the profile, role and region of the resolved cluster, from
+// clusterAWSConfig. The name is looked up with the settings of the name as given, which
+// are those of the flags unless it is the exact name of a config file entry.
+func clusterClient(ctx context.Context, name string) (*aws.Client, string, error) {
+	lookup := clusterAWSConfig(configClusterName(name))
+	client, err := newAWSClient(ctx, lookup)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create AWS client: %w", err)
+	}
+	resolved, err := resolveClusterName(ctx, client, name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if access := clusterAWSConfig(resolved); access != lookup {
+		logger.Debug("Using config file settings for cluster %s (profile %q, role %q, region %s)", resolved, access.Profile, access.RoleARN, access.Region)
+		client, err = newAWSClient(ctx, access)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create AWS client: %w", err)
+		}
+	}
+	return client, resolved, nil
+}
+
+// resolveClusterConfig is clusterClient for commands that only need the resolved name,
+// and reach the cluster with clusterAWSConfig
+func resolveClusterConfig(ctx context.Context, name string) (string, error) {
+	_, resolved, err := clusterClient(ctx, name)
+	return resolved, err
+}
+
+// configClusterName returns the config file key of a cluster name or ARN
+func configClusterName(name string) string {
+	if arnName, ok := clusterNameFromARN(name); ok {
+		return arnName
+	}
+	return name
+}
+
+// clusterStatusDegraded is the status of a cluster whose control plane is impaired. The
+// SDK has no constant for it.
+const clusterStatusDegraded ekstypes.ClusterStatus = "DEGRADED"
+
+// requireActiveCluster stops a command early when the cluster is not ACTIVE, printing its
+// status and health issues, since checks against a cluster that is still being created
+// or updated fail in confusing ways. DEGRADED clusters can still be diagnosed with --force.
+// Errors describing the cluster are left to the command's own calls.
+func requireActiveCluster(ctx context.Context, cmd *cobra.Command, client *aws.Client, clusterName string) error {
+	if err := checkClusterActive(ctx, logger.Default(), client, clusterName); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	return nil
+}
+
+// checkClusterActive is requireActiveCluster logging to log, for fleet runs
+func checkClusterActive(ctx context.Context, log *logger.Logger, client *aws.Client, clusterName string) error {
+	result, err := client.DescribeCluster(ctx, clusterName)
+	if err != nil || result.Cluster == nil {
+		return nil
+	}
+	cluster := result.Cluster
+	if cluster.Status == ekstypes.ClusterStatusActive {
+		return nil
+	}
+
+	log.Warning("⚠️ Cluster %s is %s", clusterName, cluster.Status)
+	if cluster.Health != nil {
+		for _, issue := range cluster.Health.Issues {
+			log.Detail("- %s: %s", issue.Code, awssdk.ToString(issue.Message))
+			if len(issue.ResourceIds) > 0 {
+				log.Detail("  Resources: %s", strings.Join(issue.ResourceIds, ", "))
+			}
+		}
+	}
+
+	if cluster.Status == clusterStatusDegraded && forceDegraded {
+		log.Warning("⚠️ Continuing because of --force; some checks may fail")
+		return nil
+	}
+
+	switch cluster.Status {
+	case ekstypes.ClusterStatusCreating, ekstypes.ClusterStatusUpdating, ekstypes.ClusterStatusPending:
+		log.Detail("Wait for the operation to finish and run the command again")
+	case clusterStatusDegraded:
+		log.Detail("Resolve the health issues above, or pass --force to run the diagnostics anyway")
+	case ekstypes.ClusterStatusFailed:
+		log.Detail("The cluster cannot recover from FAILED; resolve the health issues above and recreate it")
+	case ekstypes.ClusterStatusDeleting:
+		log.Detail("The cluster is being deleted")
+	}
+	return fmt.Errorf("cluster %s is %s, not ACTIVE", clusterName, cluster.Status)
+}
+
+// activeClusterClient is clusterClient for commands that inspect a cluster, which also stop
+// early with requireActiveCluster when the cluster is not ACTIVE
+func activeClusterClient(ctx context.Context, cmd *cobra.Command, name string) (*aws.Client, string, error) {
+	client, resolved, err := clusterClient(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := requireActiveCluster(ctx, cmd, client, resolved); err != nil {
+		return nil, "", err
+	}
+	return client, resolved, nil
+}
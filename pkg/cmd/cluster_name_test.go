@@ -0,0 +1,31 @@
+package cmd
+
+import "testing"
+
+func TestClusterNameArgRegion(t *testing.T) {
+	defer func(arn, name, arnReg, reg string, set bool) {
+		clusterARN, arnClusterName, arnRegion, region, regionSet = arn, name, arnReg, reg, set
+	}(clusterARN, arnClusterName, arnRegion, region, regionSet)
+
+	clusterARN = "arn:aws:eks:eu-west-1:111122223333:cluster/prod"
+	if err := applyClusterARN(); err != nil {
+		t.Fatalf("applyClusterARN() error = %v", err)
+	}
+
+	// A cluster named on the command line keeps the region it was given
+	region, regionSet = "us-east-1", false
+	if got := clusterNameArg([]string{"staging"}); got != "staging" || region != "us-east-1" {
+		t.Errorf("clusterNameArg(staging) = %q in %s, want staging in us-east-1", got, region)
+	}
+
+	// The cluster from the ARN is looked up in the ARN's region
+	if got := clusterNameArg(nil); got != "prod" || region != "eu-west-1" || !regionSet {
+		t.Errorf("clusterNameArg() = %q in %s, want prod in eu-west-1", got, region)
+	}
+
+	// --region takes precedence over the ARN
+	region, regionSet = "us-east-1", true
+	if got := clusterNameArg(nil); got != "prod" || region != "us-east-1" {
+		t.Errorf("clusterNameArg() with --region = %q in %s, want prod in us-east-1", got, region)
+	}
+}
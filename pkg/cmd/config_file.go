@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// configFileEnv names the environment variable read when --config is not set
+const configFileEnv = "EKSPEEK_CONFIG"
+
+// defaultConfigFile is the config file read from the home directory when it exists
+const defaultConfigFile = ".ekspeek.yaml"
+
+// configFile is the ekspeek config file:
+//
+//	clusters:
+//	  prod:
+//	    profile: prod-admin
+//	    roleArn: arn:aws:iam::111122223333:role/ekspeek
+//	    region: eu-west-1
+//	requiredTags: [Environment, Team]
+//	diagImage: 111122223333.dkr.ecr.eu-west-1.amazonaws.com/busybox:1.36
+type configFile struct {
+	// Clusters maps cluster names to the AWS credentials and region used to reach them
+	Clusters map[string]clusterAccess `json:"clusters"`
+	// RequiredTags are the tag keys debug tags expects on clusters and nodegroups
+	RequiredTags []string `json:"requiredTags,omitempty"`
+	// DiagImage and DiagImagePullSecrets are the defaults of --diag-image and
+	// --diag-image-pull-secrets
+	DiagImage            string   `json:"diagImage,omitempty"`
+	DiagImagePullSecrets []string `json:"diagImagePullSecrets,omitempty"`
+}
+
+// clusterAccess is how to reach one cluster. Empty fields fall back to the global flags.
+type clusterAccess struct {
+	Profile string `json:"profile,omitempty"`
+	RoleARN string `json:"roleArn,omitempty"`
+	Region  string `json:"region,omitempty"`
+}
+
+var (
+	// loadedConfig is the config file, empty when there is none
+	loadedConfig configFile
+	// profileSet, roleARNSet and regionSet record which of the AWS flags were given, since
+	// flags take precedence over the config file. regionSet is also set when the region is
+	// taken from the cluster ARN.
+	profileSet, roleARNSet, regionSet bool
+)
+
+// loadConfigFile reads the config file from --config, EKSPEEK_CONFIG or ~/.ekspeek.yaml.
+// A missing default file is not an error.
+func loadConfigFile(cmd *cobra.Command) error {
+	profileSet = cmd.Flags().Changed("profile")
+	roleARNSet = cmd.Flags().Changed("role-arn")
+	regionSet = cmd.Flags().Changed("region")
+
+	path, explicit := configPath, true
+	if path == "" {
+		path = os.Getenv(configFileEnv)
+	}
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		path, explicit = filepath.Join(home, defaultConfigFile), false
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(data, &loadedConfig); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	logger.Debug("Loaded config file %s with %d clusters", path, len(loadedConfig.Clusters))
+
+	if !cmd.Flags().Changed("diag-image") {
+		diagImage = loadedConfig.DiagImage
+	}
+	if !cmd.Flags().Changed("diag-image-pull-secrets") {
+		diagImagePullSecrets = loadedConfig.DiagImagePullSecrets
+	}
+	return nil
+}
+
+// clusterAWSConfig returns the AWS client config for a cluster: --profile, --role-arn and
+// --region where given, otherwise the cluster's entry in the config file, otherwise the
+// flag defaults
+func clusterAWSConfig(name string) aws.ClientConfig {
+	cfg := aws.ClientConfig{Profile: profile, Region: region, RoleARN: roleARN}
+	access, ok := loadedConfig.Clusters[name]
+	if !ok {
+		return cfg
+	}
+	if !profileSet && access.Profile != "" {
+		cfg.Profile = access.Profile
+	}
+	if !roleARNSet && access.RoleARN != "" {
+		cfg.RoleARN = access.RoleARN
+	}
+	if !regionSet && access.Region != "" {
+		cfg.Region = access.Region
+	}
+	return cfg
+}
+
+// hasClusterConfig returns true if the config file has an entry for the cluster
+func hasClusterConfig(name string) bool {
+	_, ok := loadedConfig.Clusters[name]
+	return ok
+}
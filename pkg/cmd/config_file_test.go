@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"testing"
+
+	"ekspeek/pkg/aws"
+)
+
+func TestClusterAWSConfig(t *testing.T) {
+	defer func(c configFile, p, r, g string, ps, rs, gs bool) {
+		loadedConfig, profile, roleARN, region = c, p, r, g
+		profileSet, roleARNSet, regionSet = ps, rs, gs
+	}(loadedConfig, profile, roleARN, region, profileSet, roleARNSet, regionSet)
+
+	loadedConfig = configFile{Clusters: map[string]clusterAccess{
+		"prod":    {Profile: "prod-admin", RoleARN: "arn:aws:iam::111122223333:role/ekspeek", Region: "eu-west-1"},
+		"staging": {Region: "eu-central-1"},
+	}}
+
+	tests := []struct {
+		name                              string
+		cluster                           string
+		profile, roleARN, region          string
+		profileSet, roleARNSet, regionSet bool
+		want                              aws.ClientConfig
+	}{
+		{
+			name:    "config entry over flag defaults",
+			cluster: "prod",
+			region:  "us-east-1",
+			want:    aws.ClientConfig{Profile: "prod-admin", RoleARN: "arn:aws:iam::111122223333:role/ekspeek", Region: "eu-west-1"},
+		},
+		{
+			name:       "flags over config entry",
+			cluster:    "prod",
+			profile:    "ops",
+			region:     "us-west-2",
+			profileSet: true,
+			regionSet:  true,
+			want:       aws.ClientConfig{Profile: "ops", RoleARN: "arn:aws:iam::111122223333:role/ekspeek", Region: "us-west-2"},
+		},
+		{
+			name:    "empty entry fields fall back to flags",
+			cluster: "staging",
+			profile: "default",
+			region:  "us-east-1",
+			want:    aws.ClientConfig{Profile: "default", Region: "eu-central-1"},
+		},
+		{
+			name:    "no entry",
+			cluster: "prod-eu",
+			profile: "default",
+			region:  "us-east-1",
+			want:    aws.ClientConfig{Profile: "default", Region: "us-east-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, roleARN, region = tt.profile, tt.roleARN, tt.region
+			profileSet, roleARNSet, regionSet = tt.profileSet, tt.roleARNSet, tt.regionSet
+
+			if got := clusterAWSConfig(tt.cluster); got != tt.want {
+				t.Errorf("clusterAWSConfig(%q) = %+v, want %+v", tt.cluster, got, tt.want)
+			}
+			// The globals stay as the flags set them
+			if profile != tt.profile || roleARN != tt.roleARN || region != tt.region {
+				t.Errorf("clusterAWSConfig(%q) changed the flags to %q, %q, %q", tt.cluster, profile, roleARN, region)
+			}
+		})
+	}
+}
+
+func TestConfigClusterName(t *testing.T) {
+	if got := configClusterName("arn:aws:eks:eu-west-1:111122223333:cluster/prod"); got != "prod" {
+		t.Errorf("configClusterName(ARN) = %q, want prod", got)
+	}
+	if got := configClusterName("prod"); got != "prod" {
+		t.Errorf("configClusterName(prod) = %q, want prod", got)
+	}
+}
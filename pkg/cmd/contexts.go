@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+)
+
+func newContextsCommand() *cobra.Command {
+	var kubeconfig string
+
+	cmd := &cobra.Command{
+		Use:   "contexts",
+		Short: "List kubeconfig contexts and the clusters they point at",
+		Long: `List the kubeconfig contexts with the current one marked by "*", showing each
+context's cluster entry and API server, and for EKS endpoints the region and the EKS
+cluster name taken from the cluster ARN or the "aws eks get-token" arguments.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := k8s.ListKubeContexts(kubeconfig)
+			if err != nil {
+				return err
+			}
+			if len(contexts) == 0 {
+				logger.Info("No contexts found in kubeconfig")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "CURRENT\tNAME\tCLUSTER\tSERVER\tEKS")
+			for _, c := range contexts {
+				current := ""
+				if c.Current {
+					current = "*"
+				}
+				eks := "no"
+				if c.EKS {
+					eks = c.Region
+					if c.EKSCluster != "" {
+						eks = fmt.Sprintf("%s/%s", c.Region, c.EKSCluster)
+					}
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, c.Name, c.Cluster, c.Server, eks)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file (default ~/.kube/config)")
+
+	return cmd
+}
@@ -9,7 +9,9 @@ import (
 	"ekspeek/pkg/aws"
 	"ekspeek/pkg/k8s"
 	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/output"
 
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,20 +19,46 @@ import (
 
 // getKubeClient is a helper function to create a new KubeClient
 func getKubeClient() (*k8s.KubeClient, error) {
+	if fromDump != "" {
+		return k8s.NewKubeClientFromDump(fromDump)
+	}
 	cfg := k8s.KubeClientConfig{
 		KubeConfig: "",  // Use default location
 		Context:    "",  // Use current context
+		InCluster:  inCluster,
+
+		DiagImage:            diagImage,
+		DiagImagePullSecrets: diagImagePullSecrets,
+	}
+	client, err := k8s.NewKubeClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	logKubeTarget(client)
+	return client, nil
+}
+
+// logKubeTarget logs the kubeconfig context and API server a command inspects, so it is
+// obvious when the current context points at another cluster
+func logKubeTarget(client *k8s.KubeClient) {
+	switch {
+	case client.Config == nil:
+		return
+	case client.Context != "":
+		logger.Info("Using kubeconfig context %s (%s)", client.Context, client.Config.Host)
+	default:
+		logger.Info("Using in-cluster config (%s)", client.Config.Host)
 	}
-	return k8s.NewKubeClient(cfg)
 }
 
 // getAWSClient is a helper function to create a new AWS Client
 func getAWSClient(ctx context.Context) (*aws.Client, error) {
 	cfg := aws.ClientConfig{
-		Profile: "",  // Use default profile
+		Profile: profile,
 		Region:  region,
+		RoleARN: roleARN,
 	}
-	return aws.NewClient(ctx, cfg)
+	return newAWSClient(ctx, cfg)
 }
 
 func NewDebugCommand() *cobra.Command {
@@ -39,6 +67,7 @@ func NewDebugCommand() *cobra.Command {
 		Short: "Debug EKS cluster components and resources",
 		Long:  `Commands for debugging EKS cluster components including performance metrics, security analysis, and other resources`,
 	}
+	debugCmd.PersistentFlags().BoolVar(&forceDegraded, "force", false, "Run the checks even when the cluster is DEGRADED")
 
 	debugCmd.AddCommand(
 		newDebugPerformanceCommand(),
@@ -55,6 +84,31 @@ func NewDebugCommand() *cobra.Command {
 		newDebugCrossAccountCommand(),
 		newDebugTLSCommand(),
 		newDebugKarpenterCommand(),
+		newDebugNetworkPolicyCommand(),
+		newDebugIAMCommand(),
+		newDebugHPACommand(),
+		newDebugPDBCommand(),
+		newDebugAccessCommand(),
+		newDebugEndpointsCommand(),
+		newDebugQoSCommand(),
+		newDebugCoreDNSCommand(),
+		newDebugDNSConfigCommand(),
+		newDebugImagePullCommand(),
+		newDebugEventsCommand(),
+		newDebugAZBalanceCommand(),
+		newDebugSubnetTagsCommand(),
+		newDebugAMICommand(),
+		newDebugPermissionsCommand(),
+		newDebugNodegroupUpdatesCommand(),
+		newDebugSecretsCommand(),
+		newDebugDrainCheckCommand(),
+		newDebugCNIConfigCommand(),
+		newDebugTopCommand(),
+		newDebugWebhooksCommand(),
+		newDebugGPUCommand(),
+		newDebugSnapshotsCommand(),
+		newDebugScalingCommand(),
+		newDebugTagsCommand(),
 	)
 
 	return debugCmd
@@ -67,20 +121,19 @@ func newDebugPerformanceCommand() *cobra.Command {
 		Use:   "performance [cluster-name]",
 		Short: "Debug cluster performance metrics",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
 
 			ctx := context.Background()
 
-			// Create AWS client
-			awsClient, err := getAWSClient(ctx)
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to create AWS client: %w", err)
+				return err
 			}
+			clusterName = resolved
 
 			// Get performance metrics
 			logger.Info("Collecting performance metrics for cluster %s...", clusterName)
@@ -103,26 +156,32 @@ func newDebugPerformanceCommand() *cobra.Command {
 }
 
 func newDebugSecurityCommand() *cobra.Command {
-	var clusterName string
+	var (
+		clusterName  string
+		outputFormat string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "security [cluster-name]",
 		Short: "Analyze cluster security configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
+			format, err := parseOutputFormat(outputFormat)
+			if err != nil {
+				return err
+			}
 
 			ctx := context.Background()
 
-			// Create AWS client
-			awsClient, err := getAWSClient(ctx)
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to create AWS client: %w", err)
+				return err
 			}
+			clusterName = resolved
 
 			// Get security analysis
 			logger.Info("Analyzing security configuration for cluster %s...", clusterName)
@@ -131,20 +190,20 @@ func newDebugSecurityCommand() *cobra.Command {
 				return fmt.Errorf("failed to get security analysis: %w", err)
 			}
 
+			if format != output.FormatText {
+				return writeFindings(format, findings)
+			}
+
 			// Print findings
 			logger.Success("Security Analysis Results:")
-			for check, result := range findings {
-				if strings.HasPrefix(result, "WARNING") {
-					logger.Warning("  %s: %s", check, result)
-				} else {
-					logger.Info("  %s: %s", check, result)
-				}
-			}
+			printFindings(findings)
 
-			return nil
+			return saveFindings(findings)
 		},
 	}
 
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, jsonl, yaml or sarif")
+
 	return cmd
 }
 
@@ -153,51 +212,121 @@ func newDebugEFSCommand() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "efs [cluster-name]",
-		Short: "Debug EFS CSI driver status",
+		Short: "Debug EFS CSI driver status and mount target reachability",
+		Long: `Check the EFS CSI driver pods, then, for each EFS file system used by a
+PersistentVolume, check that:
+- The file system has a mount target in every availability zone with nodes
+- The mount target security groups allow NFS (TCP 2049) from the nodes, by node
+  security group or by an address range holding the node IP`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
+			cmd.SilenceUsage = true
 
 			ctx := context.Background()
 
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
 			// Create kubernetes client
-			kubeClient, err := getKubeClient()
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
 			if err != nil {
 				return err
 			}
 
+			var failed checkErrors
+
 			// Get EFS CSI driver status
 			logger.Info("Checking EFS CSI driver status...")
 			pods, err := kubeClient.GetEFSCSIStatus(ctx)
 			if err != nil {
-				return err
-			}
-
-			if len(pods) == 0 {
+				failed.add("check EFS CSI driver pods", err)
+			} else if len(pods) == 0 {
 				logger.Warning("No EFS CSI driver pods found. Is the driver installed?")
-				return nil
+			} else {
+				logger.Success("Found %d EFS CSI driver pods:", len(pods))
+				for _, pod := range pods {
+					status := "Healthy"
+					if pod.Phase != corev1.PodRunning {
+						status = fmt.Sprintf("Unhealthy (%s)", pod.Phase)
+					}
+					logger.Detail("Pod: %s\nStatus: %s\nNode: %s\n", pod.Name, status, pod.NodeName)
+				}
 			}
 
-			logger.Success("Found %d EFS CSI driver pods:", len(pods))
-			for _, pod := range pods {
-				status := "Healthy"
-				if pod.Phase != corev1.PodRunning {
-					status = fmt.Sprintf("Unhealthy (%s)", pod.Phase)
-				}
-				fmt.Printf("Pod: %s\nStatus: %s\nNode: %s\n\n", pod.Name, status, pod.NodeName)
+			logger.Info("Checking EFS mount target reachability...")
+			if err := printEFSReachability(ctx, awsClient, kubeClient); err != nil {
+				failed.add("check EFS mount targets", err)
 			}
 
-			return nil
+			return failed.err()
 		},
 	}
 
 	return cmd
 }
 
+// printEFSReachability prints, per EFS file system used by a PersistentVolume and per
+// availability zone with nodes, whether the nodes can reach a mount target
+func printEFSReachability(ctx context.Context, awsClient *aws.Client, kubeClient *k8s.KubeClient) error {
+	volumes, err := kubeClient.GetEFSVolumes(ctx)
+	if err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		logger.Info("No EFS PersistentVolumes found")
+		return nil
+	}
+
+	nodes, err := kubeClient.GetNodeInstances(ctx)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		logger.Info("No EC2 nodes found")
+		return nil
+	}
+
+	fileSystemIDs := make([]string, 0, len(volumes))
+	for _, vol := range volumes {
+		fileSystemIDs = append(fileSystemIDs, vol.FileSystemID)
+	}
+	results, err := awsClient.CheckEFSReachability(ctx, fileSystemIDs, nodes)
+	if err != nil {
+		return err
+	}
+
+	volumeNames := make(map[string][]string, len(volumes))
+	for _, vol := range volumes {
+		volumeNames[vol.FileSystemID] = vol.PersistentVolumes
+	}
+	fileSystem := ""
+	for _, r := range results {
+		if r.FileSystemID != fileSystem {
+			fileSystem = r.FileSystemID
+			logger.Plain("\n%s (used by %s):", fileSystem, strings.Join(volumeNames[fileSystem], ", "))
+		}
+		switch {
+		case r.MountTarget == nil:
+			logger.Warning("❌ %s: no mount target, so nodes %s cannot mount the file system", r.AvailabilityZone, strings.Join(r.Nodes, ", "))
+			logger.Detail("- Create a mount target in a node subnet of %s", r.AvailabilityZone)
+		case len(r.BlockedNodes) > 0:
+			logger.Warning("❌ %s: mount target %s (%s) security groups %s do not allow TCP 2049 from nodes %s",
+				r.AvailabilityZone, r.MountTarget.ID, r.MountTarget.IPAddress, strings.Join(r.MountTarget.SecurityGroups, ", "), strings.Join(r.BlockedNodes, ", "))
+			logger.Detail("- Add an inbound rule for TCP 2049 from the node security group to %s", strings.Join(r.MountTarget.SecurityGroups, ", "))
+		default:
+			logger.Success("✅ %s: mount target %s (%s) reachable from %d nodes", r.AvailabilityZone, r.MountTarget.ID, r.MountTarget.IPAddress, len(r.Nodes))
+		}
+	}
+	return nil
+}
+
 func newDebugPVCCommand() *cobra.Command {
 	var (
 		clusterName string
@@ -208,9 +337,7 @@ func newDebugPVCCommand() *cobra.Command {
 		Use:   "pvc [cluster-name]",
 		Short: "Debug PVC status",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
@@ -218,7 +345,7 @@ func newDebugPVCCommand() *cobra.Command {
 			ctx := context.Background()
 
 			// Create kubernetes client
-			kubeClient, err := getKubeClient()
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
 			if err != nil {
 				return err
 			}
@@ -237,7 +364,7 @@ func newDebugPVCCommand() *cobra.Command {
 
 			logger.Success("Found %d PVCs:", len(pvcs))
 			for _, pvc := range pvcs {
-				fmt.Printf("Name: %s\nNamespace: %s\nStatus: %s\nVolume: %s\nStorage Class: %s\nCapacity: %s\n\n",
+				logger.Detail("Name: %s\nNamespace: %s\nStatus: %s\nVolume: %s\nStorage Class: %s\nCapacity: %s\n",
 					pvc.Name,
 					pvc.Namespace,
 					pvc.Status.Phase,
@@ -259,15 +386,14 @@ func newDebugPodsCommand() *cobra.Command {
 		clusterName string
 		namespace   string
 		showLogs    bool
+		includeJobs bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "pods [cluster-name]",
 		Short: "Debug pod status and show failed pods",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
@@ -275,14 +401,14 @@ func newDebugPodsCommand() *cobra.Command {
 			ctx := context.Background()
 
 			// Create kubernetes client
-			kubeClient, err := getKubeClient()
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
 			if err != nil {
 				return err
 			}
 
 			// Get failed pods
 			logger.Info("Checking for failed pods...")
-			pods, err := kubeClient.GetFailedPods(ctx, namespace)
+			pods, err := kubeClient.GetFailedPods(ctx, namespace, includeJobs)
 			if err != nil {
 				return err
 			}
@@ -294,11 +420,12 @@ func newDebugPodsCommand() *cobra.Command {
 
 			logger.Warning("Found %d failed pods:", len(pods))
 			for _, pod := range pods {
-				fmt.Printf("\nPod: %s\nNamespace: %s\nStatus: %s\nMessage: %s\n",
+				logger.Detail("\nPod: %s\nNamespace: %s\nStatus: %s\nMessage: %s",
 					pod.Name,
 					pod.Namespace,
 					pod.Status,
 					pod.Message)
+				printContainerStatuses(pod.Containers)
 
 				if showLogs {
 					logs, err := kubeClient.GetPodLogs(ctx, pod.Namespace, pod.Name, "")
@@ -306,7 +433,7 @@ func newDebugPodsCommand() *cobra.Command {
 						logger.Warning("Failed to get logs for pod %s: %v", pod.Name, err)
 						continue
 					}
-					fmt.Printf("\nLogs:\n%s\n", strings.TrimSpace(logs))
+					logger.Detail("\nLogs:\n%s", strings.TrimSpace(logs))
 				}
 			}
 
@@ -316,9 +443,28 @@ func newDebugPodsCommand() *cobra.Command {
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check pods in (default is all namespaces)")
 	cmd.Flags().BoolVar(&showLogs, "logs", false, "Show logs for failed pods")
+	cmd.Flags().BoolVar(&includeJobs, "include-jobs", false, "Include failed pods of Jobs that have since completed")
 	return cmd
 }
 
+// printContainerStatuses prints the state, restarts and last termination of each container
+func printContainerStatuses(containers []k8s.ContainerStatus) {
+	if len(containers) == 0 {
+		return
+	}
+	logger.Detail("Containers:")
+	for _, c := range containers {
+		state := c.State
+		if c.Reason != "" {
+			state = fmt.Sprintf("%s (%s)", c.State, c.Reason)
+		}
+		logger.Detail("  - %s: %s, ready=%t, restarts=%d", c.Name, state, c.Ready, c.RestartCount)
+		if c.LastTermination != "" {
+			logger.Detail("    Last termination: %s (exit code %d)", c.LastTermination, c.LastExitCode)
+		}
+	}
+}
+
 func newDebugResourcesCommand() *cobra.Command {
 	var clusterName string
 
@@ -326,9 +472,7 @@ func newDebugResourcesCommand() *cobra.Command {
 		Use:   "resources [cluster-name]",
 		Short: "Show cluster resource usage",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
@@ -336,7 +480,7 @@ func newDebugResourcesCommand() *cobra.Command {
 			ctx := context.Background()
 
 			// Create kubernetes client
-			kubeClient, err := getKubeClient()
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
 			if err != nil {
 				return err
 			}
@@ -348,15 +492,24 @@ func newDebugResourcesCommand() *cobra.Command {
 				return err
 			}
 
-			fmt.Printf("\nCluster Resource Usage:\n")
-			fmt.Printf("CPU Usage: %.2f%% (%.2f/%.2f cores)\n",
+			logger.Plain("\nCluster Resource Usage:")
+			logger.Plain("CPU Usage: %.2f%% (%.2f/%.2f cores)",
 				resources.CPUPercentage,
 				float64(resources.AllocatedCPU)/1000,
 				float64(resources.TotalCPU)/1000)
-			fmt.Printf("Memory Usage: %.2f%% (%.2f/%.2f GB)\n",
+			logger.Plain("Memory Usage: %.2f%% (%.2f/%.2f GB)",
 				resources.MemPercentage,
 				float64(resources.AllocatedMemory)/(1024*1024*1024),
 				float64(resources.TotalMemory)/(1024*1024*1024))
+			logger.Plain("CPU Limits: %.2f cores (overcommit ratio %.2f)",
+				float64(resources.LimitCPU)/1000,
+				resources.CPULimitRatio)
+			logger.Plain("Memory Limits: %.2f GB (overcommit ratio %.2f)",
+				float64(resources.LimitMemory)/(1024*1024*1024),
+				resources.MemLimitRatio)
+			if resources.CPULimitRatio > 1 || resources.MemLimitRatio > 1 {
+				logger.Warning("⚠️  Limits exceed node capacity; pods may be throttled or OOM-killed under load")
+			}
 
 			return nil
 		},
@@ -415,7 +568,7 @@ func newDebugIRSACommand() *cobra.Command {
 				return err
 			}
 
-			fmt.Printf("✅ IRSA configuration for pod %s is valid\n", podName)
+			logger.Success("✅ IRSA configuration for pod %s is valid", podName)
 			return nil
 		},
 	}
@@ -424,6 +577,11 @@ func newDebugIRSACommand() *cobra.Command {
 }
 
 func newDebugAutoscalerCommand() *cobra.Command {
+	var (
+		since     time.Duration
+		maxEvents int
+	)
+
 	cmd := &cobra.Command{
 		Use:   "autoscaler [cluster-name]",
 		Short: "Debug Cluster Autoscaler issues",
@@ -435,26 +593,22 @@ func newDebugAutoscalerCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
 
-			// Create k8s client
-			kubeClient, err := getKubeClient()
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to create kubernetes client: %w", err)
+				return err
 			}
+			clusterName = resolved
 
-			// Create AWS client
-			awsClient, err := aws.NewClient(ctx, aws.ClientConfig{
-				Profile: profile,
-				Region:  region,
-			})
+			// Create k8s client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to create AWS client: %w", err)
+				return fmt.Errorf("failed to create kubernetes client: %w", err)
 			}
 
 			// 1. Get Cluster Autoscaler pod
@@ -465,24 +619,52 @@ func newDebugAutoscalerCommand() *cobra.Command {
 			logger.Success("✅ Found Cluster Autoscaler pod: %s/%s", caPod.Namespace, caPod.Name)
 
 			// 2. Check Cluster Autoscaler logs
-			logs, err := kubeClient.GetPodLogs(ctx, caPod.Namespace, caPod.Name, "")
+			matches, err := kubeClient.ScanPodLogs(ctx, caPod.Namespace, caPod.Name, "",
+				[]string{"FailedToUpdateNodeGroupSize", "NoScaleUpGroups", "Started CA"})
 			if err != nil {
 				return err
 			}
+			found := make(map[string]bool)
+			for _, m := range matches {
+				found[m.Matcher] = true
+			}
 
 			// Analyze logs for common issues
-			if strings.Contains(logs, "FailedToUpdateNodeGroupSize") {
+			if found["FailedToUpdateNodeGroupSize"] {
 				logger.Warning("❌ Node group size update failures detected")
 			}
-			if strings.Contains(logs, "NoScaleUpGroups") {
+			if found["NoScaleUpGroups"] {
 				logger.Warning("❌ Unable to scale up - no node groups available")
 			}
-			if !strings.Contains(logs, "Started CA") {
+			if !found["Started CA"] {
 				logger.Warning("❌ Cluster Autoscaler may not be properly initialized")
 			}
 
+			// Check whether PodDisruptionBudgets are blocking scale-down
+			pdbs, err := kubeClient.GetPDBStatus(ctx, "")
+			if err != nil {
+				logger.Warning("Failed to check PodDisruptionBudgets: %v", err)
+			} else {
+				var blocking []k8s.PDBStatus
+				for _, pdb := range pdbs {
+					if len(pdb.BlockedNodes) > 0 {
+						blocking = append(blocking, pdb)
+					}
+				}
+				if len(blocking) > 0 {
+					logger.Warning("❌ Scale-down is blocked by PodDisruptionBudgets allowing zero disruptions:")
+					for _, pdb := range blocking {
+						printPDBStatus(pdb)
+					}
+					logger.Detail("Run 'ekspeek debug pdb %s' for details", clusterName)
+				}
+			}
+
 			// 3. Analyze scaling events
-			events, err := kubeClient.GetScalingEvents(ctx)
+			events, err := kubeClient.GetScalingEvents(ctx, k8s.EventOptions{
+				Since:     since,
+				MaxEvents: maxEvents,
+			})
 			if err != nil {
 				return err
 			}
@@ -513,6 +695,8 @@ func newDebugAutoscalerCommand() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().DurationVar(&since, "since", time.Hour, "Only show scaling events last seen within this duration (0 for all)")
+	cmd.Flags().IntVar(&maxEvents, "max-events", 1000, "Maximum number of most recent scaling events to keep (0 for no limit)")
 	return cmd
 }
 
@@ -524,23 +708,19 @@ func newDebugThrottlingCommand() *cobra.Command {
 		Short: "Debug API throttling issues",
 		Long:  "Analyze control plane API throttling and provide recommendations",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
 
 			ctx := context.Background()
 
-			// Create AWS client
-			awsClient, err := aws.NewClient(ctx, aws.ClientConfig{
-				Profile: profile,
-				Region:  region,
-			})
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to create AWS client: %w", err)
+				return err
 			}
+			clusterName = resolved
 
 			// Get throttling metrics
 			logger.Info("Fetching API throttling metrics for cluster %s...", clusterName)
@@ -567,10 +747,10 @@ func newDebugThrottlingCommand() *cobra.Command {
 			}
 
 			// Display summary
-			fmt.Printf("\nThrottling Analysis for cluster %s:\n", clusterName)
-			fmt.Printf("Time period: Last hour\n")
-			fmt.Printf("Total throttled calls: %.0f\n", totalThrottles)
-			fmt.Printf("Maximum error rate: %.2f%%\n\n", maxErrorRate)
+			logger.Plain("\nThrottling Analysis for cluster %s:", clusterName)
+			logger.Plain("Time period: Last hour")
+			logger.Plain("Total throttled calls: %.0f", totalThrottles)
+			logger.Plain("Maximum error rate: %.2f%%\n", maxErrorRate)
 
 			// Provide recommendations
 			if totalThrottles > 0 {
@@ -582,12 +762,12 @@ func newDebugThrottlingCommand() *cobra.Command {
 					logger.Warning("- Error rate peaked at %.2f%% which is above recommended threshold (5%%)", maxErrorRate)
 				}
 
-				fmt.Printf("\nRecommendations:\n")
-				fmt.Printf("1. Implement exponential backoff in your applications\n")
-				fmt.Printf("2. Consider using client-side caching where appropriate\n")
+				logger.Plain("\nRecommendations:")
+				logger.Plain("1. Implement exponential backoff in your applications")
+				logger.Plain("2. Consider using client-side caching where appropriate")
 				if maxErrorRate > 10 {
-					fmt.Printf("3. Review applications making frequent API calls\n")
-					fmt.Printf("4. Consider requesting a service quota increase\n")
+					logger.Plain("3. Review applications making frequent API calls")
+					logger.Plain("4. Consider requesting a service quota increase")
 				}
 			} else {
 				logger.Success("✅ No significant API throttling detected")
@@ -613,23 +793,22 @@ func newDebugNetworkingCommand() *cobra.Command {
 			if len(args) < 2 {
 				return fmt.Errorf("both cluster name and pod name are required")
 			}
+			clusterName := args[0]
 			podName = args[1]
 
 			ctx := context.Background()
 
-			// Create k8s client
-			kubeClient, err := getKubeClient()
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to create kubernetes client: %w", err)
+				return err
 			}
+			clusterName = resolved
 
-			// Create AWS client
-			awsClient, err := aws.NewClient(ctx, aws.ClientConfig{
-				Profile: profile,
-				Region:  region,
-			})
+			// Create k8s client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to create AWS client: %w", err)
+				return fmt.Errorf("failed to create kubernetes client: %w", err)
 			}
 
 			// 1. Get pod details
@@ -639,10 +818,27 @@ func newDebugNetworkingCommand() *cobra.Command {
 				return fmt.Errorf("failed to get pod %s: %w", podName, err)
 			}
 
-			fmt.Printf("\nPod Network Configuration:\n")
-			fmt.Printf("Pod IP: %s\n", pod.Status.PodIP)
-			fmt.Printf("Host IP: %s\n", pod.Status.HostIP)
-			fmt.Printf("Node: %s\n\n", pod.Spec.NodeName)
+			// Detect the cluster's IP family so the tests use the right addresses
+			family := corev1.IPv4Protocol
+			netInfo, err := awsClient.GetClusterNetworkInfo(ctx, clusterName)
+			if err != nil {
+				logger.Warning("Failed to get cluster network configuration, assuming IPv4: %v", err)
+			} else {
+				if netInfo.IPFamily == "ipv6" {
+					family = corev1.IPv6Protocol
+				}
+				printClusterNetworkInfo(netInfo, pod)
+			}
+
+			logger.Detail("\nPod Network Configuration:")
+			for _, podIP := range pod.Status.PodIPs {
+				logger.Detail("Pod IP: %s", podIP.IP)
+			}
+			if len(pod.Status.PodIPs) == 0 {
+				logger.Detail("Pod IP: %s", pod.Status.PodIP)
+			}
+			logger.Detail("Host IP: %s", pod.Status.HostIP)
+			logger.Detail("Node: %s\n", pod.Spec.NodeName)
 
 			// 2. Check network policies
 			logger.Info("Checking network policies...")
@@ -655,9 +851,9 @@ func newDebugNetworkingCommand() *cobra.Command {
 				} else {
 					logger.Success("Found %d NetworkPolicies:", len(policies.Items))
 					for _, policy := range policies.Items {
-						fmt.Printf("- %s\n", policy.Name)
+						logger.Detail("- %s", policy.Name)
 						if len(policy.Spec.PodSelector.MatchLabels) > 0 {
-							fmt.Printf("  Applies to pods with labels: %v\n", policy.Spec.PodSelector.MatchLabels)
+							logger.Detail("  Applies to pods with labels: %v", policy.Spec.PodSelector.MatchLabels)
 						}
 					}
 				}
@@ -665,7 +861,7 @@ func newDebugNetworkingCommand() *cobra.Command {
 
 			// 3. Check DNS resolution
 			logger.Info("Testing DNS resolution...")
-			success, err := kubeClient.TestPodDNS(ctx, pod.Namespace, pod.Name, "kubernetes.default.svc.cluster.local")
+			success, err := kubeClient.TestPodDNS(ctx, pod.Namespace, pod.Name, "kubernetes.default.svc.cluster.local", family)
 			if err != nil {
 				logger.Warning("❌ DNS resolution test failed: %v", err)
 			} else if !success {
@@ -685,34 +881,34 @@ func newDebugNetworkingCommand() *cobra.Command {
 				if err != nil {
 					logger.Warning("Failed to get VPC info: %v", err)
 				} else {
-					fmt.Printf("\nVPC Configuration:\n")
-					fmt.Printf("VPC ID: %s\n", vpcInfo.VPCID)
-					fmt.Printf("Subnet ID: %s\n", vpcInfo.SubnetID)
-					fmt.Printf("Security Groups: %v\n", vpcInfo.SecurityGroups)
+					logger.Detail("\nVPC Configuration:")
+					logger.Detail("VPC ID: %s", vpcInfo.VPCID)
+					logger.Detail("Subnet ID: %s", vpcInfo.SubnetID)
+					logger.Detail("Security Groups: %v", vpcInfo.SecurityGroups)
 				}
 			}
 
 			// 5. Check connectivity
 			logger.Info("Testing pod connectivity...")
-			if err := kubeClient.TestPodConnectivity(ctx, pod.Namespace, pod.Name, "default", "kubernetes"); err != nil {
+			if err := kubeClient.TestPodConnectivity(ctx, pod.Namespace, pod.Name, "default", "kubernetes", family); err != nil {
 				logger.Warning("❌ Connectivity test failed: %v", err)
 			} else {
 				logger.Success("✅ Pod connectivity test passed")
 			}
 
 			// 6. Provide recommendations
-			fmt.Printf("\nRecommendations:\n")
+			logger.Plain("\nRecommendations:")
 			if policies == nil || len(policies.Items) == 0 {
-				fmt.Printf("1. Consider implementing NetworkPolicies to secure pod communication\n")
+				logger.Plain("1. Consider implementing NetworkPolicies to secure pod communication")
 			}
 			mtuMap, err := kubeClient.CheckMTU(ctx)
 			if err != nil {
-				fmt.Printf("2. Review MTU settings: %v\n", err)
+				logger.Plain("2. Review MTU settings: %v", err)
 			} else if len(mtuMap) == 0 {
-				fmt.Printf("2. Could not determine MTU settings\n")
+				logger.Plain("2. Could not determine MTU settings")
 			}
 			if pod.Spec.HostNetwork {
-				fmt.Printf("3. Pod is using host network - review if this is intended\n")
+				logger.Plain("3. Pod is using host network - review if this is intended")
 			}
 
 			return nil
@@ -723,10 +919,40 @@ func newDebugNetworkingCommand() *cobra.Command {
 	return cmd
 }
 
+// printClusterNetworkInfo prints the cluster's IP family, stack and CIDRs
+func printClusterNetworkInfo(info *aws.ClusterNetworkInfo, pod *corev1.Pod) {
+	stack := "single-stack IPv4"
+	if info.IPFamily == "ipv6" {
+		stack = "single-stack IPv6"
+	}
+	if len(pod.Status.PodIPs) > 1 {
+		stack = "dual-stack"
+	}
+
+	logger.Detail("\nCluster Network Configuration:")
+	logger.Detail("IP family: %s (%s)", info.IPFamily, stack)
+	if info.ServiceIPv4CIDR != "" {
+		logger.Detail("Service IPv4 CIDR: %s", info.ServiceIPv4CIDR)
+	}
+	if info.ServiceIPv6CIDR != "" {
+		logger.Detail("Service IPv6 CIDR: %s", info.ServiceIPv6CIDR)
+	}
+	if len(info.VPCIPv4CIDRs) > 0 {
+		logger.Detail("Pod (VPC) IPv4 CIDRs: %s", strings.Join(info.VPCIPv4CIDRs, ", "))
+	}
+	if len(info.VPCIPv6CIDRs) > 0 {
+		logger.Detail("Pod (VPC) IPv6 CIDRs: %s", strings.Join(info.VPCIPv6CIDRs, ", "))
+	}
+	if info.IPFamily == "ipv6" && len(info.VPCIPv6CIDRs) == 0 {
+		logger.Warning("❌ Cluster uses IPv6 but VPC %s has no IPv6 CIDR", info.VPCID)
+	}
+}
+
 func newDebugEgressCommand() *cobra.Command {
 	var (
-		clusterName string
-		namespace   string
+		clusterName      string
+		namespace        string
+		natMetricsWindow time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -735,73 +961,108 @@ func newDebugEgressCommand() *cobra.Command {
 		Long: `Analyze pod egress traffic configuration including:
 - Security group egress rules
 - NAT gateway configuration
+- NAT gateway SNAT port exhaustion (ErrorPortAllocation) and connection counts
 - Network policies
 - VPC routing tables`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) == 0 {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
-			clusterName = args[0]
 
 			ctx := context.Background()
 
-			// Create AWS client
-			awsClient, err := aws.NewClient(ctx, aws.ClientConfig{
-				Profile: profile,
-				Region:  region,
-			})
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to create AWS client: %w", err)
+				return err
 			}
+			clusterName = resolved
 
 			// Create Kubernetes client
-			kubeClient, err := getKubeClient()
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
 			if err != nil {
 				return fmt.Errorf("failed to create kubernetes client: %w", err)
 			}
 
+			// Checks that fail are collected and reported at the end
+			cmd.SilenceUsage = true
+			var failed checkErrors
+
 			// 1. Get cluster VPC configuration
 			logger.Info("Getting cluster VPC configuration...")
+			var vpcConfig *ekstypes.VpcConfigResponse
 			cluster, err := awsClient.DescribeCluster(ctx, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to get cluster details: %w", err)
-			}
-
-			vpcConfig := cluster.Cluster.ResourcesVpcConfig
-			if vpcConfig == nil {
-				return fmt.Errorf("cluster VPC configuration not found")
+				failed.add("get cluster details", err)
+			} else if vpcConfig = cluster.Cluster.ResourcesVpcConfig; vpcConfig == nil {
+				failed.add("get cluster VPC configuration", fmt.Errorf("cluster VPC configuration not found"))
 			}
 
 			// 2. Check NAT gateway configuration
-			logger.Info("Checking NAT gateway configuration...")
-			natGateways, err := awsClient.GetNATGateways(ctx, *vpcConfig.VpcId)
-			if err != nil {
-				logger.Warning("Failed to get NAT gateways: %v", err)
-			} else if len(natGateways) == 0 {
-				logger.Warning("❌ No NAT gateways found in the VPC")
-			} else {
-				logger.Success("✅ Found %d NAT gateways", len(natGateways))
-				for _, ng := range natGateways {
-					fmt.Printf("NAT Gateway: %s (State: %s)\n", *ng.NatGatewayId, ng.State)
+			if vpcConfig != nil {
+				logger.Info("Checking NAT gateway configuration...")
+				natGateways, err := awsClient.GetNATGateways(ctx, *vpcConfig.VpcId)
+				if err != nil {
+					failed.add("get NAT gateways", err)
+				} else if len(natGateways) == 0 {
+					logger.Warning("❌ No NAT gateways found in the VPC")
+				} else {
+					logger.Success("✅ Found %d NAT gateways", len(natGateways))
+					var available []string
+					for _, ng := range natGateways {
+						logger.Detail("NAT Gateway: %s (State: %s)", *ng.NatGatewayId, ng.State)
+						if ng.State == "available" {
+							available = append(available, *ng.NatGatewayId)
+						}
+					}
+
+					if len(available) > 0 {
+						logger.Info("Checking NAT gateway port allocation over the last %s...", natMetricsWindow)
+						metrics, err := awsClient.GetNATGatewayMetrics(ctx, available, natMetricsWindow)
+						if err != nil {
+							failed.add("get NAT gateway metrics", err)
+						}
+						for _, m := range metrics {
+							switch {
+							case m.ErrorPortAllocation > 0:
+								logger.Warning("❌ %s: %.0f SNAT port allocation errors, peak %.0f active connections",
+									m.NatGatewayID, m.ErrorPortAllocation, m.MaxActiveConnections)
+								logger.Detail("- Connections to a single destination are exhausting the gateway's source ports; " +
+									"add secondary IP addresses to the NAT gateway or spread traffic across destinations or gateways")
+							case m.NearConnectionLimit():
+								logger.Warning("⚠️ %s: peak %.0f active connections is near the %d per-destination limit",
+									m.NatGatewayID, m.MaxActiveConnections, aws.NATGatewayConnectionLimit)
+							default:
+								logger.Success("✅ %s: no port allocation errors, peak %.0f active connections",
+									m.NatGatewayID, m.MaxActiveConnections)
+							}
+							if m.PacketsDropped > 0 {
+								logger.Detail("- %s dropped %.0f packets", m.NatGatewayID, m.PacketsDropped)
+							}
+						}
+					}
 				}
 			}
 
 			// 3. Check security group egress rules
-			logger.Info("Checking security group egress rules...")
-			for i := range vpcConfig.SecurityGroupIds {
-				sgID := vpcConfig.SecurityGroupIds[i]
-				rules, err := awsClient.GetSecurityGroupEgressRules(ctx, sgID)
-				if err != nil {
-					logger.Warning("Failed to get egress rules for %s: %v", sgID, err)
-					continue
-				}
-				
-				if len(rules) == 0 {
-					logger.Warning("❌ No egress rules found for security group %s", sgID)
-				} else {
-					logger.Success("✅ Found %d egress rules for security group %s", len(rules), sgID)
-					for _, rule := range rules {
-						fmt.Printf("  - %s: %d -> %d\n", *rule.IpProtocol, *rule.FromPort, *rule.ToPort)
+			if vpcConfig != nil {
+				logger.Info("Checking security group egress rules...")
+				for i := range vpcConfig.SecurityGroupIds {
+					sgID := vpcConfig.SecurityGroupIds[i]
+					rules, err := awsClient.GetSecurityGroupEgressRules(ctx, sgID)
+					if err != nil {
+						failed.add(fmt.Sprintf("get egress rules for %s", sgID), err)
+						continue
+					}
+
+					if len(rules) == 0 {
+						logger.Warning("❌ No egress rules found for security group %s", sgID)
+					} else {
+						logger.Success("✅ Found %d egress rules for security group %s", len(rules), sgID)
+						for _, rule := range rules {
+							logger.Detail("  - %s: %d -> %d", *rule.IpProtocol, *rule.FromPort, *rule.ToPort)
+						}
 					}
 				}
 			}
@@ -810,26 +1071,26 @@ func newDebugEgressCommand() *cobra.Command {
 			logger.Info("Checking network policies...")
 			policies, err := kubeClient.GetNetworkPolicies(ctx, namespace)
 			if err != nil {
-				logger.Warning("Failed to get network policies: %v", err)
+				failed.add("get network policies", err)
 			} else if policies != nil && len(policies.Items) == 0 {
 				logger.Warning("❌ No network policies found")
 			} else if policies != nil {
 				logger.Success("✅ Found %d network policies", len(policies.Items))
 				for _, policy := range policies.Items {
-					fmt.Printf("\nPolicy: %s/%s\n", policy.Namespace, policy.Name)
+					logger.Detail("\nPolicy: %s/%s", policy.Namespace, policy.Name)
 					if len(policy.Spec.Egress) == 0 {
-						fmt.Println("  - No egress rules (traffic blocked)")
+						logger.Detail("  - No egress rules (traffic blocked)")
 					} else {
 						for _, rule := range policy.Spec.Egress {
-							fmt.Println("  - Egress rule:")
+							logger.Detail("  - Egress rule:")
 							for _, port := range rule.Ports {
-								fmt.Printf("    Port: %s/%d\n", *port.Protocol, *port.Port)
+								logger.Detail("    Port: %s/%s", *port.Protocol, port.Port.String())
 							}
 							for _, to := range rule.To {
 								if to.IPBlock != nil {
-									fmt.Printf("    CIDR: %s\n", to.IPBlock.CIDR)
+									logger.Detail("    CIDR: %s", to.IPBlock.CIDR)
 									if len(to.IPBlock.Except) > 0 {
-										fmt.Printf("    Except: %v\n", to.IPBlock.Except)
+										logger.Detail("    Except: %v", to.IPBlock.Except)
 									}
 								}
 							}
@@ -839,37 +1100,39 @@ func newDebugEgressCommand() *cobra.Command {
 			}
 
 			// 5. Check VPC route tables
-			logger.Info("Checking VPC route tables...")
-			routeTables, err := awsClient.GetRouteTables(ctx, *vpcConfig.VpcId)
-			if err != nil {
-				logger.Warning("Failed to get route tables: %v", err)
-			} else {
-				logger.Success("✅ Found %d route tables", len(routeTables))
-				for _, rt := range routeTables {
-					fmt.Printf("\nRoute Table: %s\n", *rt.RouteTableId)
-					for _, route := range rt.Routes {
-						if route.DestinationCidrBlock != nil {
-							fmt.Printf("  %s -> ", *route.DestinationCidrBlock)
-							switch {
-							case route.GatewayId != nil:
-								fmt.Printf("IGW: %s\n", *route.GatewayId)
-							case route.NatGatewayId != nil:
-								fmt.Printf("NAT: %s\n", *route.NatGatewayId)
-							case route.VpcPeeringConnectionId != nil:
-								fmt.Printf("VPC Peering: %s\n", *route.VpcPeeringConnectionId)
-							default:
-								fmt.Println("Other")
+			if vpcConfig != nil {
+				logger.Info("Checking VPC route tables...")
+				routeTables, err := awsClient.GetRouteTables(ctx, *vpcConfig.VpcId)
+				if err != nil {
+					failed.add("get route tables", err)
+				} else {
+					logger.Success("✅ Found %d route tables", len(routeTables))
+					for _, rt := range routeTables {
+						logger.Detail("\nRoute Table: %s", *rt.RouteTableId)
+						for _, route := range rt.Routes {
+							if route.DestinationCidrBlock != nil {
+								target := "Other"
+								switch {
+								case route.GatewayId != nil:
+									target = fmt.Sprintf("IGW: %s", *route.GatewayId)
+								case route.NatGatewayId != nil:
+									target = fmt.Sprintf("NAT: %s", *route.NatGatewayId)
+								case route.VpcPeeringConnectionId != nil:
+									target = fmt.Sprintf("VPC Peering: %s", *route.VpcPeeringConnectionId)
+								}
+								logger.Detail("  %s -> %s", *route.DestinationCidrBlock, target)
 							}
 						}
 					}
 				}
 			}
 
-			return nil
+			return failed.err()
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check network policies in (default is all namespaces)")
+	cmd.Flags().DurationVar(&natMetricsWindow, "nat-metrics-window", 3*time.Hour, "Time window of NAT gateway CloudWatch metrics to check")
 	return cmd
 }
 
@@ -885,50 +1148,50 @@ func newDebugCrossAccountCommand() *cobra.Command {
 - Cross-account networking configuration
 - Cross-account service permissions`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
 
 			ctx := context.Background()
 
-			// Create AWS client
-			awsClient, err := aws.NewClient(ctx, aws.ClientConfig{
-				Profile: profile,
-				Region:  region,
-			})
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to create AWS client: %w", err)
+				return err
 			}
+			clusterName = resolved
+
+			// Checks that fail are collected and reported at the end
+			cmd.SilenceUsage = true
+			var failed checkErrors
 
 			// Get cluster details
 			logger.Info("Getting cluster details for %s...", clusterName)
 			cluster, err := awsClient.DescribeCluster(ctx, clusterName)
 			if err != nil {
-				return fmt.Errorf("failed to get cluster details: %w", err)
-			}
-
-			// 1. Check cluster role trust relationships
-			logger.Info("Checking cluster IAM role trust relationships...")
-			roleARN := *cluster.Cluster.RoleArn
-			if err := aws.VerifyIAMRoleTrust(roleARN); err != nil {
-				logger.Warning("❌ Cluster role trust relationship issue: %v", err)
+				failed.add("get cluster details", err)
 			} else {
-				logger.Success("✅ Cluster role trust relationship is valid")
+				// 1. Check cluster role trust relationships
+				logger.Info("Checking cluster IAM role trust relationships...")
+				roleARN := *cluster.Cluster.RoleArn
+				if err := aws.VerifyIAMRoleTrust(roleARN); err != nil {
+					logger.Warning("❌ Cluster role trust relationship issue: %v", err)
+				} else {
+					logger.Success("✅ Cluster role trust relationship is valid")
+				}
 			}
 
 			// 2. Check node role trust relationships
 			logger.Info("Checking node IAM role trust relationships...")
 			nodegroups, err := awsClient.ListNodegroups(ctx, clusterName)
 			if err != nil {
-				logger.Warning("Failed to list nodegroups: %v", err)
+				failed.add("list nodegroups", err)
 			} else {
 				for _, ng := range nodegroups {
 					ngDetails, err := awsClient.DescribeNodegroup(ctx, clusterName, ng)
 					if err != nil {
-						logger.Warning("Failed to get details for nodegroup %s: %v", ng, err)
+						failed.add(fmt.Sprintf("get details for nodegroup %s", ng), err)
 						continue
 					}
 					
@@ -944,12 +1207,12 @@ func newDebugCrossAccountCommand() *cobra.Command {
 			logger.Info("Checking addon service account configurations...")
 			addons, err := awsClient.ListAddons(ctx, clusterName)
 			if err != nil {
-				logger.Warning("Failed to list addons: %v", err)
+				failed.add("list addons", err)
 			} else {
 				for _, addon := range addons {
 					addonDetails, err := awsClient.DescribeAddon(ctx, clusterName, addon)
 					if err != nil {
-						logger.Warning("Failed to get details for addon %s: %v", addon, err)
+						failed.add(fmt.Sprintf("get details for addon %s", addon), err)
 						continue
 					}
 
@@ -965,10 +1228,9 @@ func newDebugCrossAccountCommand() *cobra.Command {
 			}
 
 			// 4. Check cross-account VPC access
-			logger.Info("Checking cross-account VPC access...")
-			vpcConfig := cluster.Cluster.ResourcesVpcConfig
-			if vpcConfig != nil && len(vpcConfig.SecurityGroupIds) > 0 {
-				for _, sgID := range vpcConfig.SecurityGroupIds {
+			if cluster != nil && cluster.Cluster.ResourcesVpcConfig != nil {
+				logger.Info("Checking cross-account VPC access...")
+				for _, sgID := range cluster.Cluster.ResourcesVpcConfig.SecurityGroupIds {
 					if err := awsClient.ValidateSecurityGroupAccess(ctx, sgID); err != nil {
 						logger.Warning("❌ Security group access issue for %s: %v", sgID, err)
 					} else {
@@ -977,7 +1239,7 @@ func newDebugCrossAccountCommand() *cobra.Command {
 				}
 			}
 
-			return nil
+			return failed.err()
 		},
 	}
 
@@ -1006,7 +1268,7 @@ func newDebugTLSCommand() *cobra.Command {
 			ctx := context.Background()
 
 			// Create k8s client
-			kubeClient, err := getKubeClient()
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
 			if err != nil {
 				return fmt.Errorf("failed to create kubernetes client: %w", err)
 			}
@@ -1017,10 +1279,10 @@ func newDebugTLSCommand() *cobra.Command {
 			if err != nil {
 				logger.Warning("Failed to get API server certificate: %v", err)
 			} else {
-				fmt.Printf("\nAPI Server Certificate:\n")
-				fmt.Printf("Subject: %s\n", apiCert.Subject)
-				fmt.Printf("Issuer: %s\n", apiCert.Issuer)
-				fmt.Printf("Valid Until: %s\n", apiCert.NotAfter.Format("2006-01-02 15:04:05 MST"))
+				logger.Detail("\nAPI Server Certificate:")
+				logger.Detail("Subject: %s", apiCert.Subject)
+				logger.Detail("Issuer: %s", apiCert.Issuer)
+				logger.Detail("Valid Until: %s", apiCert.NotAfter.Format("2006-01-02 15:04:05 MST"))
 
 				// Check expiration
 				daysUntilExpiry := time.Until(apiCert.NotAfter).Hours() / 24
@@ -1040,12 +1302,12 @@ func newDebugTLSCommand() *cobra.Command {
 				if len(ingCerts) == 0 {
 					logger.Info("No Ingress TLS certificates found")
 				} else {
-					fmt.Printf("\nIngress TLS Certificates:\n")
+					logger.Detail("\nIngress TLS Certificates:")
 					for host, cert := range ingCerts {
-						fmt.Printf("\nHost: %s\n", host)
-						fmt.Printf("Subject: %s\n", cert.Subject)
-						fmt.Printf("Issuer: %s\n", cert.Issuer)
-						fmt.Printf("Valid Until: %s\n", cert.NotAfter.Format("2006-01-02 15:04:05 MST"))
+						logger.Detail("\nHost: %s", host)
+						logger.Detail("Subject: %s", cert.Subject)
+						logger.Detail("Issuer: %s", cert.Issuer)
+						logger.Detail("Valid Until: %s", cert.NotAfter.Format("2006-01-02 15:04:05 MST"))
 
 						daysUntilExpiry := time.Until(cert.NotAfter).Hours() / 24
 						if daysUntilExpiry < 30 {
@@ -1066,12 +1328,12 @@ func newDebugTLSCommand() *cobra.Command {
 				if len(svcCerts) == 0 {
 					logger.Info("No service TLS certificates found")
 				} else {
-					fmt.Printf("\nService TLS Certificates:\n")
+					logger.Detail("\nService TLS Certificates:")
 					for svc, cert := range svcCerts {
-						fmt.Printf("\nService: %s\n", svc)
-						fmt.Printf("Subject: %s\n", cert.Subject)
-						fmt.Printf("Issuer: %s\n", cert.Issuer)
-						fmt.Printf("Valid Until: %s\n", cert.NotAfter.Format("2006-01-02 15:04:05 MST"))
+						logger.Detail("\nService: %s", svc)
+						logger.Detail("Subject: %s", cert.Subject)
+						logger.Detail("Issuer: %s", cert.Issuer)
+						logger.Detail("Valid Until: %s", cert.NotAfter.Format("2006-01-02 15:04:05 MST"))
 
 						daysUntilExpiry := time.Until(cert.NotAfter).Hours() / 24
 						if daysUntilExpiry < 30 {
@@ -1094,29 +1356,29 @@ func newDebugTLSCommand() *cobra.Command {
 				} else {
 					logger.Warning("Found certificate chain issues:")
 					for resource, issue := range chainIssues {
-						fmt.Printf("- %s: %s\n", resource, issue)
+						logger.Detail("- %s: %s", resource, issue)
 					}
 				}
 			}
 
 			// 5. Provide recommendations
-			fmt.Printf("\nRecommendations:\n")
+			logger.Plain("\nRecommendations:")
 			anyIssues := false
 
 			if daysUntilExpiry := time.Until(apiCert.NotAfter).Hours() / 24; daysUntilExpiry < 90 {
-				fmt.Printf("1. Plan to rotate API server certificate within %.0f days\n", daysUntilExpiry)
+				logger.Plain("1. Plan to rotate API server certificate within %.0f days", daysUntilExpiry)
 				anyIssues = true
 			}
 
 			for host, cert := range ingCerts {
 				if daysUntilExpiry := time.Until(cert.NotAfter).Hours() / 24; daysUntilExpiry < 30 {
-					fmt.Printf("2. Renew certificate for %s (expires in %.0f days)\n", host, daysUntilExpiry)
+					logger.Plain("2. Renew certificate for %s (expires in %.0f days)", host, daysUntilExpiry)
 					anyIssues = true
 				}
 			}
 
 			if len(chainIssues) > 0 {
-				fmt.Printf("3. Fix certificate chain issues for identified resources\n")
+				logger.Plain("3. Fix certificate chain issues for identified resources")
 				anyIssues = true
 			}
 
@@ -1138,11 +1400,13 @@ func newDebugKarpenterCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "karpenter [cluster-name]",
 		Short: "Debug Karpenter issues",
-		Long:  "Debug Karpenter provisioner configuration, node states, and scaling decisions",
+		Long: `Debug Karpenter provisioner configuration, node states, and scaling decisions:
+- NodeClaims and their Launched, Registered and Initialized conditions
+- Disruption (consolidation, drift) decisions from the controller logs
+- DisruptionBlocked and Unconsolidatable events explaining why nodes are not scaled down
+- Pods annotated karpenter.sh/do-not-disrupt that block consolidation`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
@@ -1150,7 +1414,7 @@ func newDebugKarpenterCommand() *cobra.Command {
 			ctx := context.Background()
 
 			// Create kubernetes client
-			kubeClient, err := getKubeClient()
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
 			if err != nil {
 				return err
 			}
@@ -1176,11 +1440,11 @@ func newDebugKarpenterCommand() *cobra.Command {
 
 			logger.Success("Found %d Karpenter provisioners:", len(provisioners))
 			for _, p := range provisioners {
-				fmt.Printf("\nProvisioner: %s\n", p.Name)
-				fmt.Printf("Requirements:\n  CPU: %s\n  Memory: %s\n",
+				logger.Detail("\nProvisioner: %s", p.Name)
+				logger.Detail("Requirements:\n  CPU: %s\n  Memory: %s",
 					p.Requirements.CPU,
 					p.Requirements.Memory)
-				fmt.Printf("Limits:\n  CPU: %s\n  Memory: %s\n",
+				logger.Detail("Limits:\n  CPU: %s\n  Memory: %s",
 					p.Limits.CPU,
 					p.Limits.Memory)
 			}
@@ -1194,16 +1458,66 @@ func newDebugKarpenterCommand() *cobra.Command {
 
 			logger.Success("Found %d Karpenter managed nodes:", len(nodes))
 			for _, node := range nodes {
-				fmt.Printf("\nNode: %s\n", node.Name)
-				fmt.Printf("Instance Type: %s\n", node.InstanceType)
-				fmt.Printf("Capacity:\n  CPU: %s\n  Memory: %s\n",
+				logger.Detail("\nNode: %s", node.Name)
+				logger.Detail("Instance Type: %s", node.InstanceType)
+				logger.Detail("Capacity:\n  CPU: %s\n  Memory: %s",
 					node.Capacity.CPU,
 					node.Capacity.Memory)
-				fmt.Printf("Usage:\n  CPU: %.2f%%\n  Memory: %.2f%%\n",
+				logger.Detail("Usage:\n  CPU: %.2f%%\n  Memory: %.2f%%",
 					node.Usage.CPUPercent,
 					node.Usage.MemoryPercent)
 			}
 
+			// Check NodeClaim launch status
+			logger.Info("Checking Karpenter NodeClaims...")
+			claims, err := kubeClient.GetKarpenterNodeClaims(ctx)
+			if err != nil {
+				return err
+			}
+			logger.Success("Found %d NodeClaims", len(claims))
+			for _, claim := range claims {
+				line := fmt.Sprintf("%s (nodepool %s, %s %s in %s) node=%s", claim.Name, claim.NodePool,
+					claim.CapacityType, claim.InstanceType, claim.Zone, claim.NodeName)
+				if claim.Launched && claim.Registered && claim.Initialized {
+					logger.Detail("- %s: ready", line)
+					continue
+				}
+				logger.Warning("❌ %s: launched=%t registered=%t initialized=%t", line, claim.Launched, claim.Registered, claim.Initialized)
+				for _, cond := range claim.FailedConditions {
+					logger.Detail("- %s", cond)
+				}
+			}
+
+			// Check disruption (consolidation) decisions and blockers
+			logger.Info("Checking Karpenter disruption decisions...")
+			disruption, err := kubeClient.GetKarpenterDisruption(ctx)
+			if err != nil {
+				return err
+			}
+			if disruption.LogError != "" {
+				logger.Warning("⚠️ Could not read Karpenter controller logs: %s", disruption.LogError)
+			}
+			decisions := disruption.Decisions
+			if len(decisions) > 10 {
+				decisions = decisions[len(decisions)-10:]
+			}
+			logger.Success("Found %d disruption decisions in the controller logs", len(disruption.Decisions))
+			for _, d := range decisions {
+				logger.Detail("- %s [%s %s] %s", d.Time, d.Reason, d.Decision, d.Message)
+			}
+			if len(disruption.Blocked) > 0 {
+				logger.Warning("⚠️ Nodes Karpenter cannot disrupt or consolidate:")
+				for _, b := range disruption.Blocked {
+					logger.Detail("- %s", b)
+				}
+			}
+			if len(disruption.DoNotDisruptPods) > 0 {
+				logger.Warning("⚠️ %d pods with karpenter.sh/do-not-disrupt block consolidation of their nodes:", len(disruption.DoNotDisruptPods))
+				for _, pod := range disruption.DoNotDisruptPods {
+					logger.Detail("- %s", pod)
+				}
+			}
+
 			// Check pending pods that Karpenter should handle
 			logger.Info("Checking for pending pods...")
 			pendingPods, err := kubeClient.GetKarpenterPendingPods(ctx)
@@ -1214,11 +1528,11 @@ func newDebugKarpenterCommand() *cobra.Command {
 			if len(pendingPods) > 0 {
 				logger.Warning("Found %d pending pods that Karpenter should handle:", len(pendingPods))
 				for _, pod := range pendingPods {
-					fmt.Printf("\nPod: %s/%s\n", pod.Namespace, pod.Name)
-					fmt.Printf("Requirements:\n  CPU: %s\n  Memory: %s\n",
+					logger.Detail("\nPod: %s/%s", pod.Namespace, pod.Name)
+					logger.Detail("Requirements:\n  CPU: %s\n  Memory: %s",
 						pod.Requirements.CPU,
 						pod.Requirements.Memory)
-					fmt.Printf("Status: %s\n", pod.Status)
+					logger.Detail("Status: %s", pod.Status)
 				}
 			} else {
 				logger.Success("No pending pods found that need Karpenter provisioning")
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/spf13/cobra"
+)
+
+func newDebugAccessCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "access [cluster-name]",
+		Short: "Debug EKS access entries and cluster authentication mode",
+		Long: `Debug EKS access entries (the EKS access management API) including:
+- Cluster authentication mode (CONFIG_MAP, API or API_AND_CONFIG_MAP)
+- IAM principals with access entries, their Kubernetes username and groups
+- Associated access policies and their scope (cluster or namespaces)
+- Principals mapped in both access entries and the aws-auth ConfigMap`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Getting cluster details for %s...", clusterName)
+			cluster, err := awsClient.DescribeCluster(ctx, clusterName)
+			if err != nil {
+				return fmt.Errorf("failed to get cluster details: %w", err)
+			}
+
+			mode := ekstypes.AuthenticationModeConfigMap
+			if cluster.Cluster.AccessConfig != nil && cluster.Cluster.AccessConfig.AuthenticationMode != "" {
+				mode = cluster.Cluster.AccessConfig.AuthenticationMode
+			}
+			logger.Plain("\nAuthentication mode: %s", mode)
+
+			mappings, err := kubeClient.GetAWSAuthMappings(ctx)
+			if err != nil {
+				logger.Warning("Failed to read aws-auth ConfigMap: %v", err)
+			}
+
+			if mode == ekstypes.AuthenticationModeConfigMap {
+				logger.Warning("⚠️ Access entries are disabled; the cluster only uses the aws-auth ConfigMap")
+				return nil
+			}
+			if mode == ekstypes.AuthenticationModeApi && len(mappings) > 0 {
+				logger.Warning("⚠️ aws-auth has %d mappings but is ignored in API authentication mode", len(mappings))
+			}
+
+			logger.Info("Checking access entries...")
+			entries, err := awsClient.GetAccessEntries(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			if len(entries) == 0 {
+				logger.Warning("❌ No access entries found")
+			}
+
+			for _, entry := range entries {
+				logger.Plain("\nPrincipal: %s (%s)", entry.PrincipalARN, entry.Type)
+				if entry.Username != "" {
+					logger.Plain("  Username: %s", entry.Username)
+				}
+				if len(entry.KubernetesGroups) > 0 {
+					logger.Plain("  Groups: %s", strings.Join(entry.KubernetesGroups, ", "))
+				}
+				for _, policy := range entry.Policies {
+					scope := policy.ScopeType
+					if len(policy.Namespaces) > 0 {
+						scope = fmt.Sprintf("%s (%s)", scope, strings.Join(policy.Namespaces, ", "))
+					}
+					logger.Plain("  Policy: %s [%s]", policy.PolicyARN, scope)
+				}
+			}
+
+			// Principals in both places resolve to the access entry, which can silently
+			// override the username and groups mapped in aws-auth
+			if mode == ekstypes.AuthenticationModeApiAndConfigMap {
+				entriesByARN := make(map[string]aws.AccessEntryInfo)
+				for _, entry := range entries {
+					entriesByARN[entry.PrincipalARN] = entry
+				}
+
+				var conflicts []string
+				for _, mapping := range mappings {
+					entry, ok := entriesByARN[mapping.ARN]
+					if !ok {
+						continue
+					}
+					conflicts = append(conflicts, fmt.Sprintf("%s (aws-auth username %q groups [%s], access entry username %q groups [%s])",
+						mapping.ARN, mapping.Username, strings.Join(mapping.Groups, ", "),
+						entry.Username, strings.Join(entry.KubernetesGroups, ", ")))
+				}
+
+				if len(conflicts) > 0 {
+					logger.Warning("❌ Principals mapped in both access entries and aws-auth (access entries take precedence):")
+					for _, conflict := range conflicts {
+						logger.Detail("- %s", conflict)
+					}
+				} else {
+					logger.Success("✅ No conflicts between access entries and aws-auth")
+				}
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
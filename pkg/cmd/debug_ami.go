@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugAMICommand() *cobra.Command {
+	var (
+		clusterName string
+		maxAge      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ami [cluster-name]",
+		Short: "Detect nodes running outdated AMIs",
+		Long: `Check whether nodes are missing AMI security patches:
+- Compares each managed nodegroup's AMI release version with the latest EKS-optimized
+  release for its Kubernetes version (from the public SSM parameters) and flags
+  nodegroups more than one release behind
+- Flags self-managed nodes whose AMI is older than --max-age`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+			cmd.SilenceUsage = true
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			var failed checkErrors
+
+			logger.Info("Checking managed nodegroup AMI releases...")
+			statuses, err := awsClient.GetNodegroupAMIStatus(ctx, clusterName)
+			if err != nil {
+				failed.add("check managed nodegroup AMIs", err)
+			}
+			if err == nil && len(statuses) == 0 {
+				logger.Info("No managed nodegroups found")
+			}
+			for _, s := range statuses {
+				switch {
+				case s.Error != "":
+					logger.Warning("⚠️ %s (%s): %s", s.Nodegroup, s.AMIType, s.Error)
+				case s.LatestVersion == "":
+					logger.Warning("⚠️ %s (%s): release %s, no update history to compare with", s.Nodegroup, s.AMIType, s.ReleaseVersion)
+				case s.Outdated():
+					logger.Warning("❌ %s (%s): release %s is %d releases behind %s", s.Nodegroup, s.AMIType, s.ReleaseVersion, s.ReleasesBehind, s.LatestVersion)
+					logger.Detail("- Run: aws eks update-nodegroup-version --cluster-name %s --nodegroup-name %s", clusterName, s.Nodegroup)
+				case s.ReleasesBehind == 1:
+					logger.Success("✅ %s (%s): release %s, one release behind %s", s.Nodegroup, s.AMIType, s.ReleaseVersion, s.LatestVersion)
+				default:
+					logger.Success("✅ %s (%s): release %s is the latest", s.Nodegroup, s.AMIType, s.ReleaseVersion)
+				}
+			}
+
+			logger.Info("Checking self-managed node AMIs...")
+			nodes, err := awsClient.GetSelfManagedNodeAMIs(ctx, clusterName)
+			if err != nil {
+				failed.add("check self-managed node AMIs", err)
+			}
+			if err == nil && len(nodes) == 0 {
+				logger.Info("No self-managed nodes found")
+			}
+			for _, n := range nodes {
+				switch {
+				case n.Error != "":
+					logger.Warning("⚠️ %s (%s): %s", n.InstanceID, n.ImageID, n.Error)
+				case n.Age() > maxAge:
+					logger.Warning("❌ %s: AMI %s (%s) is %d days old", n.InstanceID, n.ImageID, n.ImageName, int(n.Age().Hours()/24))
+				default:
+					logger.Success("✅ %s: AMI %s (%s) is %d days old", n.InstanceID, n.ImageID, n.ImageName, int(n.Age().Hours()/24))
+				}
+			}
+
+			return failed.err()
+		},
+	}
+
+	cmd.Flags().DurationVar(&maxAge, "max-age", 90*24*time.Hour, "Flag self-managed nodes whose AMI is older than this")
+
+	return cmd
+}
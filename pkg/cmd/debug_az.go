@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugAZBalanceCommand() *cobra.Command {
+	var (
+		clusterName string
+		showAll     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "az-balance [cluster-name]",
+		Short: "Check node and workload distribution across availability zones",
+		Long: `Check how nodes and workloads are spread across availability zones:
+- Node count per AZ, using the topology.kubernetes.io/zone label
+- Pod distribution across AZs for each Deployment and StatefulSet
+- Workloads with all their pods in a single AZ
+- Zone topology spread constraints whose skew exceeds maxSkew`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking AZ distribution...")
+			dist, err := kubeClient.GetAZDistribution(ctx)
+			if err != nil {
+				return err
+			}
+
+			zones := dist.Zones()
+			logger.Plain("\nNodes per AZ:")
+			minNodes, maxNodes := -1, 0
+			for _, zone := range zones {
+				count := dist.NodesByZone[zone]
+				logger.Plain("  %s: %d", zone, count)
+				if minNodes < 0 || count < minNodes {
+					minNodes = count
+				}
+				if count > maxNodes {
+					maxNodes = count
+				}
+			}
+			switch {
+			case len(zones) == 1:
+				logger.Warning("⚠️ All nodes are in a single AZ; an AZ outage takes down the cluster's capacity")
+			case maxNodes > 2*minNodes:
+				logger.Warning("⚠️ Nodes are unevenly spread across AZs (%d to %d per AZ)", minNodes, maxNodes)
+			default:
+				logger.Success("✅ Nodes are spread across %d AZs", len(zones))
+			}
+
+			logger.Plain("\nWorkload distribution:")
+			issues := 0
+			for _, w := range dist.Workloads {
+				flagged := w.Concentrated || len(w.SpreadViolations) > 0
+				if !flagged && !showAll {
+					continue
+				}
+
+				line := fmt.Sprintf("%s/%s (%d pods): %s", w.Namespace, w.Owner, w.TotalPods, formatZoneCounts(w.PodsByZone, zones))
+				if !flagged {
+					logger.Plain("  %s", line)
+					continue
+				}
+
+				issues++
+				logger.Warning("❌ %s", line)
+				if w.Concentrated {
+					logger.Detail("- All pods run in a single AZ")
+				}
+				for _, v := range w.SpreadViolations {
+					logger.Detail("- Topology spread: %s", v)
+				}
+			}
+			if issues == 0 {
+				logger.Success("✅ No workloads concentrated in one AZ or violating zone spread constraints")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&showAll, "all", false, "Show every workload, not only those with AZ issues")
+
+	return cmd
+}
+
+// formatZoneCounts formats per-zone pod counts in zone order, including zones with no pods
+func formatZoneCounts(podsByZone map[string]int, zones []string) string {
+	parts := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		parts = append(parts, fmt.Sprintf("%s=%d", zone, podsByZone[zone]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/cobra"
+)
+
+func newDebugCNIConfigCommand() *cobra.Command {
+	var (
+		clusterName string
+		minFreeIPs  int32
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cni-config [cluster-name]",
+		Short: "Verify VPC CNI custom networking, ENIConfig setup and leaked ENIs",
+		Long: `Verify the VPC CNI custom networking configuration:
+- ENIs the VPC CNI created for nodes that are left in the available state, holding
+  subnet IPs after their node is gone
+- AWS_VPC_K8S_CNI_CUSTOM_NETWORK_CFG, ENI_CONFIG_LABEL_DEF and ENI_CONFIG_ANNOTATION_DEF on aws-node
+- ENIConfig resources: subnet set, present in the cluster VPC and with free IPs
+- The ENIConfig each node selects exists and its subnet is in the node's AZ
+
+Pods on a node whose ENIConfig is missing or points at a subnet in another AZ, or at
+an exhausted subnet, are stuck in ContainerCreating without an IP.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return fmt.Errorf("failed to create kubernetes client: %w", err)
+			}
+
+			cmd.SilenceUsage = true
+			var failed checkErrors
+
+			logger.Info("Checking for orphaned VPC CNI ENIs...")
+			enis, err := awsClient.GetOrphanedENIs(ctx, clusterName)
+			if err != nil {
+				failed.add("check orphaned ENIs", err)
+			} else {
+				printOrphanedENIs(enis)
+			}
+
+			logger.Info("\nChecking VPC CNI custom networking for cluster %s...", clusterName)
+			cfg, err := kubeClient.GetCNICustomNetworking(ctx)
+			if err != nil {
+				return err
+			}
+
+			if !cfg.Enabled {
+				logger.Success("✅ Custom networking is disabled (AWS_VPC_K8S_CNI_CUSTOM_NETWORK_CFG is not true); pods use the node subnets")
+				if len(cfg.ENIConfigs) > 0 {
+					logger.Warning("⚠️ %d ENIConfig resources exist but are ignored while custom networking is disabled", len(cfg.ENIConfigs))
+				}
+				return failed.err()
+			}
+
+			logger.Success("✅ Custom networking is enabled")
+			logger.Detail("- ENIConfig label: %s", cfg.LabelDef)
+			logger.Detail("- ENIConfig annotation: %s", cfg.AnnotationDef)
+			if cfg.ENIConfigError != "" {
+				failed.add("list ENIConfigs", fmt.Errorf("%s", cfg.ENIConfigError))
+			}
+
+			var vpcID string
+			cluster, err := awsClient.DescribeCluster(ctx, clusterName)
+			if err != nil {
+				failed.add("get cluster details", err)
+			} else if cluster.Cluster.ResourcesVpcConfig != nil {
+				vpcID = awssdk.ToString(cluster.Cluster.ResourcesVpcConfig.VpcId)
+			}
+
+			var subnetIDs []string
+			for _, eniConfig := range cfg.ENIConfigs {
+				if eniConfig.Subnet != "" {
+					subnetIDs = append(subnetIDs, eniConfig.Subnet)
+				}
+			}
+			subnets, err := awsClient.GetSubnetCapacity(ctx, subnetIDs)
+			if err != nil {
+				failed.add("get ENIConfig subnets", err)
+			}
+
+			// ENIConfig resources
+			logger.Info("\nENIConfigs:")
+			if len(cfg.ENIConfigs) == 0 {
+				logger.Warning("❌ No ENIConfig resources found; nodes cannot allocate pod IPs")
+			}
+			for _, eniConfig := range cfg.ENIConfigs {
+				subnet, found := subnets[eniConfig.Subnet]
+				switch {
+				case eniConfig.Subnet == "":
+					logger.Warning("❌ %s: no subnet set", eniConfig.Name)
+				case subnets == nil:
+					logger.Detail("- %s: subnet %s (not verified)", eniConfig.Name, eniConfig.Subnet)
+				case !found:
+					logger.Warning("❌ %s: subnet %s does not exist", eniConfig.Name, eniConfig.Subnet)
+				case vpcID != "" && subnet.VpcID != vpcID:
+					logger.Warning("❌ %s: subnet %s is in %s, not the cluster VPC %s",
+						eniConfig.Name, subnet.SubnetID, subnet.VpcID, vpcID)
+				case subnet.AvailableIPs < minFreeIPs:
+					logger.Warning("⚠️ %s: subnet %s (%s, %s) has only %d free IPs",
+						eniConfig.Name, subnet.SubnetID, subnet.AvailabilityZone, subnet.CIDR, subnet.AvailableIPs)
+				default:
+					logger.Success("✅ %s: subnet %s (%s, %s) has %d free IPs",
+						eniConfig.Name, subnet.SubnetID, subnet.AvailabilityZone, subnet.CIDR, subnet.AvailableIPs)
+				}
+				if len(eniConfig.SecurityGroups) == 0 {
+					logger.Detail("  No securityGroups set; pod ENIs use the node's primary ENI security groups")
+				}
+			}
+
+			// Nodes, grouped by AZ
+			logger.Info("\nNodes by AZ:")
+			byZone := make(map[string][]string)
+			var zones []string
+			for _, node := range cfg.Nodes {
+				if _, ok := byZone[node.Zone]; !ok {
+					zones = append(zones, node.Zone)
+				}
+				byZone[node.Zone] = append(byZone[node.Zone], node.Name)
+			}
+			sort.Strings(zones)
+
+			for _, zone := range zones {
+				zoneName := zone
+				if zoneName == "" {
+					zoneName = "<no zone label>"
+				}
+				var problems []string
+				used := make(map[string]bool)
+				for _, node := range cfg.Nodes {
+					if node.Zone != zone {
+						continue
+					}
+					if node.ENIConfig == "" {
+						problems = append(problems, fmt.Sprintf("%s: no %s label or annotation", node.Name, cfg.LabelDef))
+						continue
+					}
+					eniConfig, ok := cfg.ENIConfig(node.ENIConfig)
+					if !ok {
+						problems = append(problems, fmt.Sprintf("%s: ENIConfig %q does not exist", node.Name, node.ENIConfig))
+						continue
+					}
+					if subnet, found := subnets[eniConfig.Subnet]; found && zone != "" && subnet.AvailabilityZone != zone {
+						problems = append(problems, fmt.Sprintf("%s: ENIConfig %s uses subnet %s in %s",
+							node.Name, eniConfig.Name, subnet.SubnetID, subnet.AvailabilityZone))
+						continue
+					}
+					used[eniConfig.Name] = true
+				}
+
+				if len(problems) == 0 {
+					var names []string
+					for name := range used {
+						names = append(names, name)
+					}
+					sort.Strings(names)
+					logger.Success("✅ %s: %d nodes use ENIConfig %v", zoneName, len(byZone[zone]), names)
+					continue
+				}
+				logger.Warning("❌ %s: %d of %d nodes cannot get pod IPs from a valid ENIConfig:",
+					zoneName, len(problems), len(byZone[zone]))
+				for _, problem := range problems {
+					logger.Detail("- %s", problem)
+				}
+			}
+
+			logger.Detail("\nNodes pick up ENIConfig changes only when they are replaced or aws-node is restarted")
+			return failed.err()
+		},
+	}
+
+	cmd.Flags().Int32Var(&minFreeIPs, "min-free-ips", 32, "Warn when an ENIConfig subnet has fewer free IPs than this")
+
+	return cmd
+}
+
+// printOrphanedENIs prints the leaked VPC CNI ENIs with their count per subnet
+func printOrphanedENIs(enis []aws.OrphanedENI) {
+	if len(enis) == 0 {
+		logger.Success("✅ No orphaned VPC CNI ENIs")
+		return
+	}
+
+	logger.Warning("❌ %d VPC CNI ENIs are not attached to any instance and hold subnet IPs:", len(enis))
+	var subnets []string
+	bySubnet := make(map[string][]aws.OrphanedENI)
+	for _, eni := range enis {
+		if _, ok := bySubnet[eni.SubnetID]; !ok {
+			subnets = append(subnets, eni.SubnetID)
+		}
+		bySubnet[eni.SubnetID] = append(bySubnet[eni.SubnetID], eni)
+	}
+	for _, subnet := range subnets {
+		ips := 0
+		for _, eni := range bySubnet[subnet] {
+			ips += eni.IPs
+		}
+		logger.Detail("- %s (%s): %d ENIs holding %d IPs", subnet, bySubnet[subnet][0].AvailabilityZone, len(bySubnet[subnet]), ips)
+		for _, eni := range bySubnet[subnet] {
+			created := "unknown"
+			if !eni.Created.IsZero() {
+				created = eni.Created.Format("2006-01-02 15:04:05")
+			}
+			logger.Detail("  %s: created for %s at %s", eni.ID, eni.InstanceID, created)
+		}
+	}
+	logger.Detail("- Delete them with: aws ec2 delete-network-interface --network-interface-id <eni-id>")
+}
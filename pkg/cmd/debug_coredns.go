@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugCoreDNSCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+		dryRun      bool
+		queries     int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "coredns [cluster-name]",
+		Short: "Debug CoreDNS scaling, configuration and query latency",
+		Long: `Debug CoreDNS including:
+- Replica count relative to cluster size and whether it is autoscaled
+- Cache TTL and forward configuration from the Corefile
+- Forward health check failures and query latency from CoreDNS metrics
+- Pod ndots and dnsConfig settings, flagging workloads whose external hostnames are
+  expanded through the cluster search domains (the ndots:5 default)
+- p50/p99 resolution time from a short benchmark pod (skipped with --dry-run)`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking CoreDNS...")
+			report, err := kubeClient.GetCoreDNSReport(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger.Plain("  Replicas: %d (recommended at least %d for %d nodes)", report.Replicas, report.RecommendedReplicas, report.Nodes)
+			logger.Plain("  Autoscaled: %t", report.Autoscaled)
+			logger.Plain("  Cache TTL: %s", valueOrUnknown(report.CacheTTL))
+			logger.Plain("  Forward: %s", valueOrUnknown(report.Forward))
+
+			if report.Underprovisioned {
+				logger.Warning("❌ CoreDNS has a static replica count of %d, below the recommended %d; consider the cluster-proportional-autoscaler",
+					report.Replicas, report.RecommendedReplicas)
+			} else {
+				logger.Success("✅ CoreDNS replica count is adequate for the cluster size")
+			}
+
+			if report.ForwardFailures > 0 {
+				logger.Warning("❌ CoreDNS forward health checks failed %.0f times; upstream resolvers may be unhealthy", report.ForwardFailures)
+			}
+
+			if report.Latency != nil {
+				printDNSLatency(report.Latency)
+				logger.Plain("  Requests: %.0f (%.0f NXDOMAIN responses)", report.Requests, report.NXDomainResponses)
+			} else {
+				logger.Info("CoreDNS metrics are not reachable through the API server proxy")
+			}
+
+			logger.Info("Checking pod ndots and dnsConfig settings...")
+			search, err := kubeClient.GetDNSSearchReport(ctx, namespace)
+			if err != nil {
+				return err
+			}
+			printDNSSearchReport(search, report)
+
+			if dryRun {
+				logger.Info("Dry run: skipping DNS benchmark pod")
+				return nil
+			}
+
+			logger.Info("Running DNS benchmark pod with %d queries...", queries)
+			latency, err := kubeClient.RunDNSBenchmark(ctx, "default", "kubernetes.default.svc.cluster.local", queries)
+			if err != nil {
+				logger.Warning("❌ DNS benchmark failed: %v", err)
+				return nil
+			}
+			printDNSLatency(latency)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check pod DNS settings in (default is all namespaces)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Do not create the DNS benchmark pod")
+	cmd.Flags().IntVar(&queries, "queries", 50, "Number of DNS queries run by the benchmark pod")
+	return cmd
+}
+
+func printDNSLatency(latency *k8s.DNSLatency) {
+	logger.Plain("  DNS latency (%s, %d samples): p50=%.1fms p99=%.1fms", latency.Source, latency.Samples, latency.P50, latency.P99)
+	if latency.P99 > 100 {
+		logger.Warning("⚠️ DNS p99 latency is high; check conntrack limits, ndots and CoreDNS scaling")
+	}
+}
+
+func printDNSSearchReport(search *k8s.DNSSearchReport, report *k8s.CoreDNSReport) {
+	logger.Plain("  Pods: %d running, %d with the default ndots:5, %d with a custom dnsConfig",
+		search.Pods, search.DefaultNdotsPods, search.CustomDNSConfigPods)
+
+	if len(search.Issues) == 0 {
+		logger.Success("✅ No workload calls external hostnames that are expanded through the search domains")
+		return
+	}
+
+	logger.Warning("⚠️ %d workloads call external hostnames with fewer dots than ndots; each lookup first tries every search domain:", len(search.Issues))
+	for _, issue := range search.Issues {
+		logger.Detail("- %s %s (%d pods, ndots:%d): %s", issue.Namespace, issue.Owner, issue.Pods, issue.Ndots, strings.Join(issue.ExternalHosts, ", "))
+	}
+	if report.Requests > 0 {
+		logger.Detail("CoreDNS answered %.0f%% of its %.0f requests with NXDOMAIN, which search expansion inflates",
+			100*report.NXDomainResponses/report.Requests, report.Requests)
+	}
+	logger.Detail("Set ndots:2 in the pod spec, or use fully qualified names with a trailing dot:")
+	logger.Detail("  dnsConfig:")
+	logger.Detail("    options:")
+	logger.Detail("      - name: ndots")
+	logger.Detail("        value: \"2\"")
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "(unknown)"
+	}
+	return value
+}
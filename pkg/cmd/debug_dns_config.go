@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugDNSConfigCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "dns-config [cluster-name]",
+		Short: "Check the CoreDNS Corefile and logs for loop, ready and health misconfigurations",
+		Long: `Check CoreDNS for the misconfigurations that break DNS on a fresh cluster:
+- The loop plugin detecting a forwarding loop in the CoreDNS logs, including the logs
+  of the previous container for crashlooping pods
+- A Corefile without the ready or health plugin, which fails the readiness or liveness probe
+- A Corefile without the loop plugin
+- forward . /etc/resolv.conf while the deployment's dnsPolicy is not Default, and forward
+  upstreams on loopback or the kube-dns Service IP
+
+Each problem is reported with the exact misconfiguration and its fix.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+			cmd.SilenceUsage = true
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking CoreDNS configuration...")
+			report, err := kubeClient.GetDNSConfigReport(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger.Plain("  Plugins: %s", valueOrUnknown(strings.Join(report.Plugins, ", ")))
+			logger.Plain("  Forward: %s", valueOrUnknown(strings.Join(report.Forward, " ")))
+			logger.Plain("  dnsPolicy: %s", report.DNSPolicy)
+
+			if len(report.Issues) == 0 {
+				logger.Success("✅ No CoreDNS loop, ready or health misconfiguration found")
+				return nil
+			}
+			for _, issue := range report.Issues {
+				logger.Warning("❌ %s", issue.Problem)
+				logger.Detail("  - Fix: %s", issue.Fix)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugDrainCheckCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drain-check [node-name]",
+		Short: "Assess what draining a node would break",
+		Long: `Assess whether a node can be drained for maintenance, without cordoning or draining it:
+- Pods covered by PodDisruptionBudgets that allow no disruptions
+- Bare pods without a controller, which are deleted and not rescheduled
+- Pods using emptyDir (data lost) or hostPath (data left on the node) volumes
+- DaemonSet and static pods, which stay on the node
+
+Ends with a go/no-go summary.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeName := args[0]
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getKubeClient()
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Assessing drain of node %s...", nodeName)
+			assessment, err := kubeClient.AssessNodeDrain(ctx, nodeName)
+			if err != nil {
+				return err
+			}
+			if assessment.Cordoned {
+				logger.Info("Node %s is already cordoned", nodeName)
+			}
+
+			var evicted, stays, localStorage int
+			for _, p := range assessment.Pods {
+				name := p.Namespace + "/" + p.Name
+				switch {
+				case p.DaemonSet:
+					stays++
+					logger.Detail("- %s (%s): DaemonSet pod, stays on the node", name, p.Owner)
+					continue
+				case p.Static:
+					stays++
+					logger.Detail("- %s: static pod, stays on the node", name)
+					continue
+				}
+
+				evicted++
+				switch {
+				case len(p.BlockingPDBs) > 0:
+					logger.Warning("❌ %s (%s): blocked by PodDisruptionBudget %s allowing no disruptions",
+						name, p.Owner, strings.Join(p.BlockingPDBs, ", "))
+				case p.Bare():
+					logger.Warning("❌ %s: bare pod without a controller, it will be deleted and not rescheduled", name)
+				default:
+					logger.Detail("- %s (%s): will be evicted and rescheduled", name, p.Owner)
+				}
+				if len(p.EmptyDirVolumes) > 0 {
+					localStorage++
+					logger.Warning("⚠️ %s: emptyDir data is lost on eviction: %s", name, strings.Join(p.EmptyDirVolumes, ", "))
+				}
+				if len(p.HostPathVolumes) > 0 {
+					localStorage++
+					logger.Warning("⚠️ %s: hostPath data stays on this node: %s", name, strings.Join(p.HostPathVolumes, ", "))
+				}
+			}
+
+			logger.Plain("\nSummary: %d pods would be evicted, %d stay on the node, %d use local storage",
+				evicted, stays, localStorage)
+			if assessment.Ready() {
+				logger.Success("✅ GO: node %s can be drained", nodeName)
+			} else {
+				blocking := 0
+				for _, p := range assessment.Pods {
+					if p.Blocking() {
+						blocking++
+					}
+				}
+				logger.Warning("❌ NO-GO: %d pods block the drain of node %s", blocking, nodeName)
+				logger.Detail("Scale up or relax the PodDisruptionBudgets, and recreate bare pods under a controller or delete them first")
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
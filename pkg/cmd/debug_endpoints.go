@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugEndpointsCommand() *cobra.Command {
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:   "endpoints [service]",
+		Short: "Show service endpoints and flag services without ready backends",
+		Long: `Show the EndpointSlices behind a service including:
+- Backing pods that are ready vs. not ready
+- Services whose selector matches no pods
+- Services with no ready endpoints
+
+Without a service name, every service in the namespace is checked and only services
+without ready endpoints are reported.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getKubeClient()
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				endpoints, err := kubeClient.GetServiceEndpoints(ctx, namespace, args[0])
+				if err != nil {
+					return err
+				}
+				printServiceEndpoints(endpoints)
+				return nil
+			}
+
+			logger.Info("Checking endpoints for services in namespace %s...", namespace)
+			services, err := kubeClient.GetServices(ctx, namespace)
+			if err != nil {
+				return fmt.Errorf("failed to list services: %w", err)
+			}
+
+			unhealthy := 0
+			for _, svc := range services.Items {
+				// Services without a selector (e.g. ExternalName) manage their own endpoints
+				if len(svc.Spec.Selector) == 0 {
+					continue
+				}
+				endpoints, err := kubeClient.GetServiceEndpoints(ctx, svc.Namespace, svc.Name)
+				if err != nil {
+					logger.Warning("Failed to get endpoints for service %s: %v", svc.Name, err)
+					continue
+				}
+				if len(endpoints.Ready) == 0 {
+					unhealthy++
+					printServiceEndpoints(endpoints)
+				}
+			}
+
+			if unhealthy == 0 {
+				logger.Success("✅ All services have ready endpoints")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the service")
+	return cmd
+}
+
+func printServiceEndpoints(endpoints *k8s.ServiceEndpoints) {
+	logger.Plain("\nService: %s/%s", endpoints.Namespace, endpoints.Service)
+	if endpoints.Selector != "" {
+		logger.Plain("  Selector: %s (%d matching pods)", endpoints.Selector, endpoints.MatchingPods)
+	}
+	if len(endpoints.EndpointSlices) > 0 {
+		logger.Plain("  EndpointSlices: %s", strings.Join(endpoints.EndpointSlices, ", "))
+	}
+
+	if len(endpoints.Ready) > 0 {
+		logger.Success("✅ %d ready endpoints:", len(endpoints.Ready))
+		for _, e := range endpoints.Ready {
+			logger.Detail("- %s", describeEndpoint(e))
+		}
+	}
+
+	if len(endpoints.NotReady) > 0 {
+		logger.Warning("⚠️ %d not ready endpoints:", len(endpoints.NotReady))
+		for _, e := range endpoints.NotReady {
+			logger.Detail("- %s", describeEndpoint(e))
+		}
+	}
+
+	if len(endpoints.Ready) == 0 {
+		if endpoints.SelectorMismatch {
+			logger.Warning("❌ Service %s has no ready endpoints: its selector matches no pods", endpoints.Service)
+		} else {
+			logger.Warning("❌ Service %s has no ready endpoints: all backing pods are unhealthy", endpoints.Service)
+		}
+	}
+}
+
+func describeEndpoint(e k8s.EndpointInfo) string {
+	description := e.Address
+	if e.PodName != "" {
+		description += " (pod " + e.PodName
+		if e.NodeName != "" {
+			description += " on " + e.NodeName
+		}
+		description += ")"
+	}
+	return description
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugEventsCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+		since       time.Duration
+		eventType   string
+		reasons     []string
+		maxEvents   int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events [cluster-name]",
+		Short: "Show recent cluster events grouped by object kind",
+		Long: `List recent events across the cluster, filtered by type and age.
+Repeated events for the same object and reason are shown once with their total count,
+sorted by when they were last seen and grouped by the kind of the involved object.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+			if eventType != "" && eventType != "Warning" && eventType != "Normal" {
+				return fmt.Errorf("invalid --type %q: must be Warning or Normal", eventType)
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Gathering events from the last %s...", since)
+			events, err := kubeClient.GetRecentEvents(ctx, k8s.EventOptions{
+				Namespace: namespace,
+				Since:     since,
+				Type:      eventType,
+				Reasons:   reasons,
+				MaxEvents: maxEvents,
+			})
+			if err != nil {
+				return err
+			}
+			if len(events) == 0 {
+				logger.Success("✅ No matching events found")
+				return nil
+			}
+
+			byKind := make(map[string][]k8s.EventSummary)
+			for _, e := range events {
+				byKind[e.Kind] = append(byKind[e.Kind], e)
+			}
+			kinds := make([]string, 0, len(byKind))
+			for kind := range byKind {
+				kinds = append(kinds, kind)
+			}
+			sort.Strings(kinds)
+
+			for _, kind := range kinds {
+				logger.Plain("\n%s (%d):", kind, len(byKind[kind]))
+				for _, e := range byKind[kind] {
+					object := e.Name
+					if e.Namespace != "" {
+						object = e.Namespace + "/" + e.Name
+					}
+					line := fmt.Sprintf("  %s ago  %s  %s  x%d  %s: %s",
+						time.Since(e.LastSeen).Round(time.Second), e.Type, object, e.Count, e.Reason,
+						strings.TrimSpace(e.Message))
+					if e.Type == "Warning" {
+						logger.Warning("%s", line)
+					} else {
+						logger.Plain("%s", line)
+					}
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to list events from (default: all namespaces)")
+	cmd.Flags().DurationVar(&since, "since", 30*time.Minute, "Only show events last seen within this duration")
+	cmd.Flags().StringVar(&eventType, "type", "", "Only show events of this type (Warning or Normal)")
+	cmd.Flags().StringSliceVar(&reasons, "reason", nil, "Only show events with these reasons (comma-separated, e.g. FailedScheduling,BackOff)")
+	cmd.Flags().IntVar(&maxEvents, "max-events", 5000, "Maximum number of most recent events to keep (0 for no limit)")
+
+	return cmd
+}
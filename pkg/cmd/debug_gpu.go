@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugGPUCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "gpu [cluster-name]",
+		Short: "Debug GPU nodes and the NVIDIA device plugin",
+		Long: `Check GPU capacity for ML workloads:
+- Nodes advertising nvidia.com/gpu, and NVIDIA GPU instances advertising none
+- Health of the nvidia-device-plugin DaemonSets
+- GPU nodes without a ready device plugin pod, whose GPUs the scheduler cannot see
+- GPUs requested against allocatable per node, and pending pods waiting for GPUs`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking GPU nodes and the NVIDIA device plugin...")
+			status, err := kubeClient.GetGPUStatus(ctx)
+			if err != nil {
+				return err
+			}
+			if len(status.Nodes) == 0 && len(status.PendingPods) == 0 {
+				logger.Info("No GPU nodes found")
+				return nil
+			}
+
+			var advertising int
+			for _, n := range status.Nodes {
+				if !n.Invisible() {
+					advertising++
+				}
+			}
+			if len(status.DevicePlugins) == 0 {
+				if advertising > 0 {
+					logger.Info("ℹ️ No nvidia-device-plugin DaemonSet found, but %d GPU nodes advertise GPUs, so a device plugin runs outside a DaemonSet of that name", advertising)
+				} else {
+					logger.Warning("❌ No nvidia-device-plugin DaemonSet found; GPUs are not advertised to the scheduler")
+					logger.Detail("- Install the NVIDIA device plugin or the NVIDIA GPU Operator")
+				}
+			}
+			for _, p := range status.DevicePlugins {
+				if p.Ready < p.Desired {
+					logger.Warning("⚠️ DaemonSet %s/%s: %d/%d pods ready", p.Namespace, p.Name, p.Ready, p.Desired)
+				} else {
+					logger.Success("✅ DaemonSet %s/%s: %d/%d pods ready", p.Namespace, p.Name, p.Ready, p.Desired)
+				}
+			}
+
+			var invisible, overcommitted int
+			if len(status.Nodes) > 0 {
+				logger.Info("\nGPU nodes:")
+				w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "NODE\tINSTANCE TYPE\tALLOCATABLE\tREQUESTED\tDEVICE PLUGIN")
+				for _, n := range status.Nodes {
+					plugin := "ready"
+					if len(status.DevicePlugins) == 0 {
+						plugin = "-"
+					} else if !n.DevicePlugin {
+						plugin = "missing"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", n.Name, n.InstanceType, n.Allocatable, n.Requested, plugin)
+					if n.Invisible() {
+						invisible++
+					}
+					if n.Overcommitted() {
+						overcommitted++
+					}
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+
+			if invisible > 0 {
+				logger.Warning("\n❌ %d GPU nodes advertise no GPUs; their GPUs are invisible to the scheduler", invisible)
+				for _, n := range status.Nodes {
+					if n.Invisible() {
+						logger.Detail("- %s (%s)", n.Name, n.InstanceType)
+					}
+				}
+				logger.Detail("Check the device plugin pod on these nodes and that its tolerations match the GPU node taints")
+			}
+			if overcommitted > 0 {
+				logger.Warning("\n⚠️ %d nodes have more GPUs requested than allocatable:", overcommitted)
+				for _, n := range status.Nodes {
+					if n.Overcommitted() {
+						logger.Detail("- %s: %d requested, %d allocatable", n.Name, n.Requested, n.Allocatable)
+					}
+				}
+			}
+			if len(status.PendingPods) > 0 {
+				logger.Warning("\n⚠️ %d pending pods are waiting for %d GPUs:", len(status.PendingPods), status.PendingGPUs)
+				for _, pod := range status.PendingPods {
+					logger.Detail("- %s", pod)
+				}
+			}
+			if invisible == 0 && overcommitted == 0 && len(status.PendingPods) == 0 {
+				logger.Success("\n✅ All GPU nodes advertise their GPUs and no pods are waiting for GPUs")
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
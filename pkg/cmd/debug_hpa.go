@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugHPACommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "hpa [cluster-name]",
+		Short: "Debug HorizontalPodAutoscaler status",
+		Long: `Debug HorizontalPodAutoscalers (autoscaling/v2) including:
+- Current and desired replicas
+- Target vs. current metric values
+- ScalingActive=False / AbleToScale=False conditions (e.g. metrics-server down)
+- HPAs pinned at their min or max replicas for an extended period`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking HorizontalPodAutoscalers...")
+			hpas, err := kubeClient.GetHPAStatus(ctx, namespace)
+			if err != nil {
+				return err
+			}
+
+			if len(hpas) == 0 {
+				logger.Info("No HorizontalPodAutoscalers found")
+				return nil
+			}
+
+			for _, hpa := range hpas {
+				logger.Plain("\nHPA: %s/%s -> %s", hpa.Namespace, hpa.Name, hpa.Target)
+				logger.Plain("  Replicas: current=%d desired=%d (min=%d, max=%d)",
+					hpa.CurrentReplicas, hpa.DesiredReplicas, hpa.MinReplicas, hpa.MaxReplicas)
+				for _, metric := range hpa.Metrics {
+					logger.Plain("  Metric %s", metric)
+				}
+
+				if len(hpa.Conditions) > 0 {
+					logger.Warning("❌ HPA %s/%s cannot scale:", hpa.Namespace, hpa.Name)
+					for _, condition := range hpa.Conditions {
+						logger.Detail("- %s", condition)
+					}
+				}
+
+				if hpa.PinnedAt != "" {
+					logger.Warning("⚠️ HPA %s/%s has been pinned at its %s replicas; review its bounds and targets",
+						hpa.Namespace, hpa.Name, hpa.PinnedAt)
+				}
+
+				if len(hpa.Conditions) == 0 && hpa.PinnedAt == "" {
+					logger.Success("✅ HPA %s/%s is scaling normally", hpa.Namespace, hpa.Name)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check (default is all namespaces)")
+	return cmd
+}
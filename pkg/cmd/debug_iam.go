@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugIAMCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "iam [cluster-name]",
+		Short: "Validate IAM policies attached to cluster and node roles",
+		Long: `List and validate IAM policies attached to the cluster and node roles including:
+- Attached managed and inline policies
+- Required EKS managed policies that are missing
+- Overly permissive policies granting "*" on "*"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			logger.Info("Getting cluster details for %s...", clusterName)
+			cluster, err := awsClient.DescribeCluster(ctx, clusterName)
+			if err != nil {
+				return fmt.Errorf("failed to get cluster details: %w", err)
+			}
+
+			// 1. Check the cluster role
+			logger.Info("Checking cluster role policies...")
+			report, err := awsClient.AnalyzeRolePolicies(ctx, *cluster.Cluster.RoleArn, aws.RequiredClusterRolePolicies)
+			if err != nil {
+				logger.Warning("Failed to analyze cluster role: %v", err)
+			} else {
+				printRolePolicyReport("Cluster role", report)
+			}
+
+			// 2. Check each distinct node role
+			logger.Info("Checking node role policies...")
+			nodegroups, err := awsClient.GetClusterNodegroups(ctx, clusterName)
+			if err != nil {
+				logger.Warning("Failed to get nodegroups: %v", err)
+				return nil
+			}
+
+			checked := make(map[string]bool)
+			for _, ng := range nodegroups {
+				if ng.NodeRole == nil || checked[*ng.NodeRole] {
+					continue
+				}
+				checked[*ng.NodeRole] = true
+
+				report, err := awsClient.AnalyzeRolePolicies(ctx, *ng.NodeRole, aws.RequiredNodeRolePolicies)
+				if err != nil {
+					logger.Warning("Failed to analyze node role for nodegroup %s: %v", *ng.NodegroupName, err)
+					continue
+				}
+				printRolePolicyReport(fmt.Sprintf("Node role (nodegroup %s)", *ng.NodegroupName), report)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func printRolePolicyReport(title string, report *aws.RolePolicyReport) {
+	logger.Plain("\n%s: %s", title, report.RoleName)
+	logger.Plain("  Managed policies: %s", strings.Join(report.ManagedPolicies, ", "))
+	if len(report.InlinePolicies) > 0 {
+		logger.Plain("  Inline policies: %s", strings.Join(report.InlinePolicies, ", "))
+	}
+
+	if len(report.MissingPolicies) > 0 {
+		logger.Warning("❌ Role %s is missing required policies:", report.RoleName)
+		for _, policy := range report.MissingPolicies {
+			logger.Detail("- %s", policy)
+		}
+	} else {
+		logger.Success("✅ Role %s has all required EKS policies", report.RoleName)
+	}
+
+	if len(report.PermissivePolicies) > 0 {
+		logger.Warning("❌ Role %s has overly permissive policies (*:*):", report.RoleName)
+		for _, policy := range report.PermissivePolicies {
+			logger.Detail("- %s", policy)
+		}
+	}
+}
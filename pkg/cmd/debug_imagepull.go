@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/cobra"
+)
+
+func newDebugImagePullCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "imagepull [cluster-name]",
+		Short: "Debug ImagePullBackOff and ECR authentication problems",
+		Long: `Find pods stuck in ImagePullBackOff or ErrImagePull and, for ECR images, check:
+- The registry account and whether the pull is cross-account
+- That the repository exists
+- That the node role is allowed to pull from the repository
+- That the repository policy grants the cluster's account for cross-account pulls`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking for image pull failures...")
+			failures, err := kubeClient.GetImagePullFailures(ctx, namespace)
+			if err != nil {
+				return err
+			}
+			if len(failures) == 0 {
+				logger.Success("✅ No containers are failing to pull their image")
+				return nil
+			}
+
+			cluster, err := awsClient.DescribeCluster(ctx, clusterName)
+			if err != nil {
+				return fmt.Errorf("failed to get cluster details: %w", err)
+			}
+			clusterAccount := accountFromARN(awssdk.ToString(cluster.Cluster.Arn))
+
+			nodeRoles := make(map[string]string)
+			checks := make(map[string]*aws.ECRPullCheck)
+			for _, f := range failures {
+				logger.Warning("❌ %s/%s container %s: %s", f.Namespace, f.Pod, f.Container, f.Reason)
+				logger.Detail("- Image: %s", f.Image)
+				if f.Message != "" {
+					logger.Detail("- Message: %s", f.Message)
+				}
+
+				ref, ok := aws.ParseECRImage(f.Image)
+				if !ok {
+					logger.Detail("- Not a private ECR image; check the image name, tag and imagePullSecrets")
+					continue
+				}
+
+				nodeRole, ok := nodeRoles[f.Nodegroup]
+				if !ok && f.Nodegroup != "" {
+					if ng, err := awsClient.DescribeNodegroup(ctx, clusterName, f.Nodegroup); err == nil {
+						nodeRole = awssdk.ToString(ng.Nodegroup.NodeRole)
+					}
+					nodeRoles[f.Nodegroup] = nodeRole
+				}
+
+				key := ref.Image + "|" + nodeRole
+				check, ok := checks[key]
+				if !ok {
+					check = awsClient.CheckECRPull(ctx, ref, clusterAccount, nodeRole)
+					checks[key] = check
+				}
+				printECRPullCheck(check)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check (default: all namespaces)")
+
+	return cmd
+}
+
+func printECRPullCheck(check *aws.ECRPullCheck) {
+	logger.Detail("- Registry account: %s (%s)", check.Ref.AccountID, check.Ref.Region)
+	if check.CrossAccount {
+		logger.Detail("- Cross-account pull: yes")
+	}
+
+	if check.RepositoryExists {
+		logger.Detail("- Repository %s exists", check.Ref.Repository)
+	} else {
+		logger.Warning("  ❌ Repository %s: %s", check.Ref.Repository, check.RepositoryError)
+	}
+
+	switch {
+	case check.NodeRole == "":
+		logger.Detail("- Node role unknown (node is not in a managed nodegroup); node pull permission not checked")
+	case check.RoleError != "":
+		logger.Warning("  ⚠️ Could not check node role %s: %s", check.NodeRole, check.RoleError)
+	case *check.RoleCanPull:
+		logger.Success("  ✅ Node role %s can pull from the repository", check.NodeRole)
+	default:
+		logger.Warning("  ❌ Node role %s is denied %s", check.NodeRole, strings.Join(check.DeniedActions, ", "))
+		logger.Detail("  Attach AmazonEC2ContainerRegistryPullOnly (or ReadOnly) to the node role")
+	}
+
+	if !check.CrossAccount {
+		return
+	}
+	switch {
+	case check.PolicyError != "":
+		logger.Warning("  ⚠️ Could not read the repository policy: %s", check.PolicyError)
+	case check.PolicyAllows == nil:
+	case *check.PolicyAllows:
+		logger.Success("  ✅ Repository policy allows pulls from the cluster's account")
+	default:
+		logger.Warning("  ❌ Repository policy does not grant pulls to the cluster's account")
+		logger.Detail("  Add a repository policy allowing ecr:BatchGetImage and ecr:GetDownloadUrlForLayer for the node role or account")
+	}
+}
+
+// accountFromARN returns the account ID field of an ARN
+func accountFromARN(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[4]
+}
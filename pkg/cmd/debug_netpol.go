@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugNetworkPolicyCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "netpol [cluster-name]",
+		Short: "Analyze NetworkPolicy coverage and conflicts",
+		Long: `Analyze NetworkPolicy coverage including:
+- Pods selected by at least one policy vs. unprotected (default-allow) pods
+- Namespaces with a default-deny policy but no allow rules (full lockdown)
+- Policies whose podSelector matches zero pods (dead rules)`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Analyzing NetworkPolicy coverage...")
+			analysis, err := kubeClient.AnalyzeNetworkPolicies(ctx, namespace)
+			if err != nil {
+				return err
+			}
+
+			total := len(analysis.ProtectedPods) + len(analysis.UnprotectedPods)
+			logger.Detail("\nPod Coverage: %d/%d pods selected by at least one policy",
+				len(analysis.ProtectedPods), total)
+
+			if len(analysis.UnprotectedPods) > 0 {
+				logger.Warning("⚠️ %d pods are not selected by any NetworkPolicy (default-allow):", len(analysis.UnprotectedPods))
+				for _, pod := range analysis.UnprotectedPods {
+					logger.Detail("- %s", pod)
+				}
+			} else if total > 0 {
+				logger.Success("✅ All pods are covered by a NetworkPolicy")
+			}
+
+			if len(analysis.LockedDownNamespaces) > 0 {
+				logger.Warning("❌ Namespaces with default-deny but no allow rules in that direction:")
+				for _, ns := range analysis.LockedDownNamespaces {
+					logger.Detail("- %s", ns)
+				}
+			} else {
+				logger.Success("✅ No fully locked-down namespaces detected")
+			}
+
+			if len(analysis.DeadPolicies) > 0 {
+				logger.Warning("❌ Policies whose podSelector matches zero pods:")
+				for _, policy := range analysis.DeadPolicies {
+					logger.Detail("- %s", policy)
+				}
+			} else {
+				logger.Success("✅ No dead NetworkPolicies found")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to analyze (default is all namespaces)")
+	return cmd
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/eks"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/spf13/cobra"
+)
+
+func newDebugNodegroupUpdatesCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "nodegroup-updates [cluster-name] [nodegroup]",
+		Short: "Show the update history and launch template drift of managed nodegroups",
+		Long: `Show past and in-progress updates of a managed nodegroup, or of every nodegroup in the
+cluster when no nodegroup is given: the update type (version or config), status, parameters
+and the errors reported for failed updates. This surfaces why a nodegroup is stuck UPDATING.
+
+For nodegroups with a custom launch template, the template version the nodegroup runs is
+compared with the template's default and latest versions. A nodegroup on an older version
+than the default has a pending update; the AMI, instance type, user data hash and security
+groups that differ are listed.`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			handler := eks.NewHandler(awsClient)
+			nodegroups := args[1:]
+			if len(nodegroups) == 0 {
+				nodegroups, err = handler.ListNodegroups(ctx, clusterName)
+				if err != nil {
+					return err
+				}
+			}
+			if len(nodegroups) == 0 {
+				logger.Info("No nodegroups found in cluster %s", clusterName)
+				return nil
+			}
+
+			cmd.SilenceUsage = true
+			var failed checkErrors
+			for _, ng := range nodegroups {
+				logger.Info("\nNodegroup %s:", ng)
+				updates, err := handler.GetNodegroupUpdateHistory(ctx, clusterName, ng)
+				if err != nil {
+					failed.add(fmt.Sprintf("get update history of nodegroup %s", ng), err)
+				} else if len(updates) == 0 {
+					logger.Detail("- No updates")
+				}
+				for _, u := range updates {
+					printNodegroupUpdate(u)
+				}
+
+				nodegroup, err := handler.DescribeNodegroup(ctx, clusterName, ng)
+				if err != nil {
+					failed.add(fmt.Sprintf("describe nodegroup %s", ng), err)
+					continue
+				}
+				drift, err := awsClient.GetLaunchTemplateDrift(ctx, nodegroup)
+				if err != nil {
+					failed.add(fmt.Sprintf("check launch template of nodegroup %s", ng), err)
+					continue
+				}
+				if drift != nil {
+					printLaunchTemplateDrift(drift)
+				}
+			}
+
+			return failed.err()
+		},
+	}
+
+	return cmd
+}
+
+func printLaunchTemplateDrift(drift *aws.LaunchTemplateDrift) {
+	switch {
+	case drift.PendingUpdate():
+		logger.Warning("⚠️ Launch template %s: nodegroup runs version %d, default is %d (update pending)",
+			drift.LaunchTemplate, drift.Version, drift.DefaultVersion)
+	case drift.Version == drift.DefaultVersion && drift.Version < drift.LatestVersion:
+		logger.Info("ℹ️ Launch template %s: nodegroup runs default version %d, latest is %d",
+			drift.LaunchTemplate, drift.Version, drift.LatestVersion)
+	case drift.Version < drift.LatestVersion:
+		logger.Info("ℹ️ Launch template %s: nodegroup runs version %d, newer than default %d, latest is %d",
+			drift.LaunchTemplate, drift.Version, drift.DefaultVersion, drift.LatestVersion)
+	default:
+		logger.Success("✅ Launch template %s: nodegroup runs the latest version %d", drift.LaunchTemplate, drift.Version)
+	}
+	for _, c := range drift.Changes {
+		from, to := c.From, c.To
+		if from == "" {
+			from = "(none)"
+		}
+		if to == "" {
+			to = "(none)"
+		}
+		logger.Detail("- %s: %s -> %s", c.Field, from, to)
+	}
+}
+
+func printNodegroupUpdate(u *ekstypes.Update) {
+	summary := fmt.Sprintf("%s %s (%s, started %s)", u.Type, u.Status, awssdk.ToString(u.Id),
+		awssdk.ToTime(u.CreatedAt).Format("2006-01-02 15:04:05"))
+	switch u.Status {
+	case ekstypes.UpdateStatusSuccessful:
+		logger.Success("✅ %s", summary)
+	case ekstypes.UpdateStatusInProgress:
+		logger.Info("⏳ %s", summary)
+	default:
+		logger.Warning("❌ %s", summary)
+	}
+
+	var params []string
+	for _, p := range u.Params {
+		params = append(params, fmt.Sprintf("%s=%s", p.Type, awssdk.ToString(p.Value)))
+	}
+	if len(params) > 0 {
+		logger.Detail("- Parameters: %s", strings.Join(params, ", "))
+	}
+	for _, e := range u.Errors {
+		logger.Detail("- Error %s: %s", e.ErrorCode, awssdk.ToString(e.ErrorMessage))
+		if len(e.ResourceIds) > 0 {
+			logger.Detail("  Resources: %s", strings.Join(e.ResourceIds, ", "))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugPDBCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "pdb [cluster-name]",
+		Short: "Detect PodDisruptionBudgets blocking drains and scale-down",
+		Long: `Detect PodDisruptionBudgets that block node drains and Cluster Autoscaler scale-down:
+- PDBs that currently allow zero disruptions
+- Nodes running pods protected by those PDBs
+- Nodes the Cluster Autoscaler flagged as unremovable because of them`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking PodDisruptionBudgets...")
+			pdbs, err := kubeClient.GetPDBStatus(ctx, namespace)
+			if err != nil {
+				return err
+			}
+
+			if len(pdbs) == 0 {
+				logger.Success("✅ No PodDisruptionBudgets are blocking disruptions")
+				return nil
+			}
+
+			logger.Warning("❌ Found %d PodDisruptionBudgets allowing zero disruptions:", len(pdbs))
+			for _, pdb := range pdbs {
+				printPDBStatus(pdb)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check (default is all namespaces)")
+	return cmd
+}
+
+func printPDBStatus(pdb k8s.PDBStatus) {
+	logger.Detail("- %s/%s: healthy %d/%d (expected pods %d)",
+		pdb.Namespace, pdb.Name, pdb.CurrentHealthy, pdb.DesiredHealthy, pdb.ExpectedPods)
+	if len(pdb.Nodes) > 0 {
+		logger.Detail("    Nodes: %s", strings.Join(pdb.Nodes, ", "))
+	}
+	if len(pdb.BlockedNodes) > 0 {
+		logger.Detail("    Blocking autoscaler scale-down of: %s", strings.Join(pdb.BlockedNodes, ", "))
+	}
+}
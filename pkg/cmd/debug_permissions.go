@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugPermissionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "permissions",
+		Short: "Check that the current AWS identity can perform the actions ekspeek uses",
+		Long: `Simulate every AWS action ekspeek calls against the current identity's IAM policies
+(iam:SimulatePrincipalPolicy) and print a pass/fail matrix, so missing permissions are
+found before a real run fails midway.
+
+The simulation evaluates identity-based policies only; service control policies,
+permission boundaries set outside the identity, and resource policies may still deny
+an action.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			// Create AWS client
+			awsClient, err := newAWSClient(ctx, aws.ClientConfig{
+				Profile: profile,
+				Region:  region,
+				RoleARN: roleARN,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create AWS client: %w", err)
+			}
+
+			logger.Info("Simulating %d actions for the current identity...", len(aws.RequiredActions))
+			principal, checks, err := awsClient.SimulateRequiredActions(ctx)
+			if err != nil {
+				return err
+			}
+			logger.Info("Principal: %s", principal)
+
+			denied := 0
+			w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ACTION\tRESULT\tUSED FOR")
+			for _, check := range checks {
+				result := "✅ allowed"
+				if !check.Allowed() {
+					denied++
+					result = "❌ " + check.Decision
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", check.Action, result, check.Purpose)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			if denied > 0 {
+				logger.Warning("❌ %d of %d actions are denied; commands using them will skip those checks", denied, len(checks))
+			} else {
+				logger.Success("✅ All %d actions are allowed", len(checks))
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newDebugQoSCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "qos [cluster-name]",
+		Short: "Find pods without resource requests and show QoS class distribution",
+		Long: `Check pod resource configuration including:
+- QoS class distribution (Guaranteed/Burstable/BestEffort)
+- Pods whose containers omit CPU/memory requests, grouped by namespace and owner
+- Pods whose containers omit CPU/memory limits`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking QoS class distribution...")
+			distribution, err := kubeClient.GetQoSDistribution(ctx, namespace)
+			if err != nil {
+				return err
+			}
+			for _, class := range []corev1.PodQOSClass{corev1.PodQOSGuaranteed, corev1.PodQOSBurstable, corev1.PodQOSBestEffort} {
+				logger.Plain("  %-11s %d pods", class+":", distribution[class])
+			}
+
+			logger.Info("Checking pods without resource requests...")
+			issues, err := kubeClient.GetPodsWithoutRequests(ctx, namespace)
+			if err != nil {
+				return err
+			}
+
+			withoutRequests := 0
+			for _, issue := range issues {
+				if len(issue.MissingRequests) > 0 {
+					withoutRequests++
+				}
+			}
+			if withoutRequests == 0 {
+				logger.Success("✅ All pods set CPU and memory requests")
+			} else {
+				logger.Warning("❌ %d pods are missing CPU/memory requests (BestEffort pods are evicted first and break autoscaler bin-packing):", withoutRequests)
+				group := ""
+				for _, issue := range issues {
+					if len(issue.MissingRequests) == 0 {
+						continue
+					}
+					if current := issue.Namespace + "/" + issue.Owner; current != group {
+						group = current
+						logger.Detail("%s %s:", issue.Namespace, issue.Owner)
+					}
+					logger.Detail("  - %s [%s] containers: %s", issue.Pod, issue.QOSClass, strings.Join(issue.MissingRequests, ", "))
+				}
+			}
+
+			// Pods missing requests were reported above; only list those missing just limits
+			var withoutLimits []string
+			for _, issue := range issues {
+				if len(issue.MissingRequests) == 0 && len(issue.MissingLimits) > 0 {
+					withoutLimits = append(withoutLimits, fmt.Sprintf("%s/%s (%s) containers: %s",
+						issue.Namespace, issue.Pod, issue.Owner, strings.Join(issue.MissingLimits, ", ")))
+				}
+			}
+			if len(withoutLimits) > 0 {
+				logger.Warning("⚠️ %d pods set requests but are missing CPU/memory limits:", len(withoutLimits))
+				for _, pod := range withoutLimits {
+					logger.Detail("- %s", pod)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check (default is all namespaces)")
+	return cmd
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// nodegroupTaintEffects maps EKS nodegroup taint effects to Kubernetes taint effects
+var nodegroupTaintEffects = map[ekstypes.TaintEffect]corev1.TaintEffect{
+	ekstypes.TaintEffectNoSchedule:       corev1.TaintEffectNoSchedule,
+	ekstypes.TaintEffectNoExecute:        corev1.TaintEffectNoExecute,
+	ekstypes.TaintEffectPreferNoSchedule: corev1.TaintEffectPreferNoSchedule,
+}
+
+func newDebugScalingCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "scaling [cluster-name]",
+		Short: "Explain why pending pods are not getting new nodes",
+		Long: `Correlate pending pods with autoscaler decisions:
+- Lists pending pods with their CPU, memory and GPU requests
+- Detects whether Cluster Autoscaler or Karpenter is installed
+- Shows the autoscaler events and controller log lines naming each pod
+- Reports which managed nodegroups (Cluster Autoscaler) or NodePools (Karpenter) match
+  the pod's nodeSelector and tolerations, and why the others do not`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+			cmd.SilenceUsage = true
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking pending pods and autoscalers...")
+			status, err := kubeClient.GetScalingStatus(ctx)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case status.ClusterAutoscaler != "" && status.Karpenter != "":
+				logger.Warning("⚠️ Both Cluster Autoscaler (%s) and Karpenter (%s) are running; they may race to add nodes", status.ClusterAutoscaler, status.Karpenter)
+			case status.ClusterAutoscaler != "":
+				logger.Success("✅ Cluster Autoscaler is running (%s)", status.ClusterAutoscaler)
+			case status.Karpenter != "":
+				logger.Success("✅ Karpenter is running (%s)", status.Karpenter)
+			default:
+				logger.Warning("❌ Neither Cluster Autoscaler nor Karpenter is running; pending pods will not trigger new nodes")
+			}
+			if status.LogError != "" {
+				logger.Warning("⚠️ Could not read autoscaler logs: %s", status.LogError)
+			}
+
+			if len(status.Pods) == 0 {
+				logger.Success("\n✅ No pending pods")
+				return nil
+			}
+
+			var nodegroups []*ekstypes.Nodegroup
+			if status.ClusterAutoscaler != "" {
+				nodegroups, err = awsClient.GetClusterNodegroups(ctx, clusterName)
+				if err != nil {
+					logger.Warning("⚠️ Failed to list nodegroups: %v", err)
+				}
+			}
+
+			logger.Info("\n%d pending pods:", len(status.Pods))
+			for _, pod := range status.Pods {
+				printScalingPod(pod, status, nodegroups)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// printScalingPod prints a pending pod's requests, what the autoscalers said about it and
+// the nodegroups or NodePools that could run it
+func printScalingPod(pod k8s.ScalingPod, status *k8s.ScalingStatus, nodegroups []*ekstypes.Nodegroup) {
+	requests := []string{fmt.Sprintf("cpu %dm", pod.CPU), fmt.Sprintf("memory %.1fGiB", float64(pod.Memory)/(1<<30))}
+	if pod.GPU > 0 {
+		requests = append(requests, fmt.Sprintf("gpu %d", pod.GPU))
+	}
+	logger.Plain("\n%s/%s (%s)", pod.Namespace, pod.Name, strings.Join(requests, ", "))
+	if pod.SchedulerMessage != "" {
+		logger.Detail("- Scheduler: %s", pod.SchedulerMessage)
+	}
+	for _, event := range pod.Events {
+		logger.Detail("- Event: %s", event)
+	}
+	for _, line := range pod.LogLines {
+		logger.Detail("- Log: %s", line)
+	}
+
+	matched := false
+	if status.ClusterAutoscaler != "" {
+		for _, ng := range nodegroups {
+			labels, taints := nodegroupPlacement(ng)
+			name := *ng.NodegroupName
+			ok, reason := pod.MatchNodeGroup(labels, taints)
+			if !ok {
+				logger.Detail("- Nodegroup %s does not match: %s", name, reason)
+				continue
+			}
+			matched = true
+			if sc := ng.ScalingConfig; sc != nil && sc.DesiredSize != nil && sc.MaxSize != nil {
+				if *sc.DesiredSize >= *sc.MaxSize {
+					logger.Warning("  ⚠️ Nodegroup %s matches but is at its max size (%d)", name, *sc.MaxSize)
+					continue
+				}
+				logger.Detail("- Nodegroup %s matches (%d/%d nodes)", name, *sc.DesiredSize, *sc.MaxSize)
+				continue
+			}
+			logger.Detail("- Nodegroup %s matches", name)
+		}
+	}
+	if status.Karpenter != "" {
+		for _, pool := range status.NodePools {
+			if ok, reason := pod.MatchNodePool(pool); ok {
+				matched = true
+				logger.Detail("- NodePool %s matches", pool.Name)
+			} else {
+				logger.Detail("- NodePool %s does not match: %s", pool.Name, reason)
+			}
+		}
+	}
+
+	if !matched && (status.ClusterAutoscaler != "" || status.Karpenter != "") {
+		logger.Warning("  ❌ No nodegroup or NodePool matches the pod's nodeSelector and tolerations")
+	}
+}
+
+// nodegroupPlacement returns the labels and taints a managed nodegroup puts on its nodes
+func nodegroupPlacement(ng *ekstypes.Nodegroup) (map[string]string, []corev1.Taint) {
+	labels := map[string]string{"eks.amazonaws.com/nodegroup": *ng.NodegroupName}
+	if ng.CapacityType != "" {
+		labels["eks.amazonaws.com/capacityType"] = string(ng.CapacityType)
+	}
+	for k, v := range ng.Labels {
+		labels[k] = v
+	}
+
+	var taints []corev1.Taint
+	for _, t := range ng.Taints {
+		taint := corev1.Taint{Effect: nodegroupTaintEffects[t.Effect]}
+		if t.Key != nil {
+			taint.Key = *t.Key
+		}
+		if t.Value != nil {
+			taint.Value = *t.Value
+		}
+		taints = append(taints, taint)
+	}
+	return labels, taints
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugSecretsCommand() *cobra.Command {
+	var (
+		clusterName      string
+		namespace        string
+		maxPerNamespace  int
+		largeKiB         int
+		maxHelmRevisions int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "secrets [cluster-name]",
+		Short: "Find namespaces with too many Secrets and Secrets near the size limit",
+		Long: `Check Secret counts and sizes, which slow down the API server and etcd when excessive:
+- Secret count per namespace, flagging namespaces over --max-per-namespace
+- Secrets approaching the 1MiB size limit
+- Helm release history stored as helm.sh/release.v1 Secrets
+
+Only Secret metadata is reported, never Secret contents.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Counting secrets...")
+			report, err := kubeClient.GetSecretReport(ctx, namespace, largeKiB*1024)
+			if err != nil {
+				return err
+			}
+			logger.Info("Found %d secrets in %d namespaces", report.Total, len(report.Namespaces))
+
+			crowded := 0
+			for _, ns := range report.Namespaces {
+				if ns.Count > maxPerNamespace {
+					crowded++
+				}
+			}
+			if crowded == 0 {
+				logger.Success("✅ No namespace has more than %d secrets", maxPerNamespace)
+			} else {
+				logger.Warning("❌ %d namespaces have more than %d secrets:", crowded, maxPerNamespace)
+				for _, ns := range report.Namespaces {
+					if ns.Count > maxPerNamespace {
+						logger.Detail("- %s: %d secrets (%d Helm release secrets), %.1f MiB",
+							ns.Namespace, ns.Count, ns.HelmReleases, float64(ns.TotalBytes)/(1024*1024))
+					}
+				}
+			}
+
+			if len(report.LargeSecrets) == 0 {
+				logger.Success("✅ No secret is larger than %d KiB", largeKiB)
+			} else {
+				logger.Warning("⚠️ %d secrets are approaching the %d KiB size limit:", len(report.LargeSecrets), k8s.SecretSizeLimit/1024)
+				for _, s := range report.LargeSecrets {
+					logger.Detail("- %s/%s (%s): %d KiB", s.Namespace, s.Name, s.Type, s.Bytes/1024)
+				}
+			}
+
+			var longHistory []k8s.HelmReleaseHistory
+			for _, r := range report.HelmReleases {
+				if r.Revisions > maxHelmRevisions {
+					longHistory = append(longHistory, r)
+				}
+			}
+			if len(longHistory) == 0 {
+				logger.Success("✅ No Helm release keeps more than %d revisions", maxHelmRevisions)
+			} else {
+				logger.Warning("⚠️ %d Helm releases keep more than %d revisions as secrets:", len(longHistory), maxHelmRevisions)
+				for _, r := range longHistory {
+					logger.Detail("- %s/%s: %d revisions", r.Namespace, r.Release, r.Revisions)
+				}
+				logger.Detail("Limit the history with: helm upgrade --history-max %d ...", maxHelmRevisions)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check (default is all namespaces)")
+	cmd.Flags().IntVar(&maxPerNamespace, "max-per-namespace", 1000, "Flag namespaces with more secrets than this")
+	cmd.Flags().IntVar(&largeKiB, "large-kib", 768, "Flag secrets larger than this many KiB")
+	cmd.Flags().IntVar(&maxHelmRevisions, "max-helm-revisions", 10, "Flag Helm releases keeping more revisions than this")
+	return cmd
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugSnapshotsCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+		stuckAfter  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "snapshots [cluster-name]",
+		Short: "Check the CSI snapshot controller and VolumeSnapshots",
+		Long: `Check CSI volume snapshots:
+- Whether the snapshot.storage.k8s.io CRDs and the snapshot-controller are installed
+- snapshot-controller deployment health
+- VolumeSnapshotClasses, and EBS StorageClasses without one
+- VolumeSnapshots with their readyToUse status, flagging failed snapshots, snapshots
+  not ready after --stuck-after and snapshots naming a missing VolumeSnapshotClass`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking volume snapshots...")
+			status, err := kubeClient.GetSnapshotStatus(ctx, namespace)
+			if err != nil {
+				return err
+			}
+
+			if len(status.Controllers) == 0 {
+				logger.Warning("❌ No snapshot-controller deployment found; VolumeSnapshots are never processed")
+				logger.Detail("- Install the snapshot-controller EKS add-on or the external-snapshotter controller")
+			}
+			for _, c := range status.Controllers {
+				if c.Ready < c.Desired || c.Ready == 0 {
+					logger.Warning("❌ Deployment %s/%s: %d/%d replicas ready", c.Namespace, c.Name, c.Ready, c.Desired)
+				} else {
+					logger.Success("✅ Deployment %s/%s: %d/%d replicas ready", c.Namespace, c.Name, c.Ready, c.Desired)
+				}
+			}
+			if !status.CRDsInstalled {
+				logger.Warning("❌ The snapshot.storage.k8s.io CRDs are not installed")
+				return nil
+			}
+
+			if len(status.Classes) == 0 {
+				logger.Warning("❌ No VolumeSnapshotClasses found; snapshots cannot be taken")
+			} else {
+				logger.Info("\nVolumeSnapshotClasses:")
+				for _, c := range status.Classes {
+					suffix := ""
+					if c.Default {
+						suffix = " (default)"
+					}
+					logger.Detail("- %s%s: driver %s, deletionPolicy %s", c.Name, suffix, c.Driver, c.DeletionPolicy)
+				}
+			}
+			for _, driver := range status.DriversWithoutClass {
+				logger.Warning("⚠️ StorageClasses use %s but no VolumeSnapshotClass does; its volumes cannot be snapshotted", driver)
+			}
+
+			if len(status.Snapshots) == 0 {
+				logger.Info("\nNo VolumeSnapshots found")
+				return nil
+			}
+
+			logger.Info("\nVolumeSnapshots:")
+			w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAMESPACE\tNAME\tSOURCE PVC\tCLASS\tREADY\tAGE")
+			var problems []string
+			for _, s := range status.Snapshots {
+				age := time.Since(s.Created).Round(time.Second)
+				class := s.Class
+				if class == "" {
+					class = "<default>"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%s\n", s.Namespace, s.Name, s.SourcePVC, class, s.ReadyToUse, age)
+
+				name := s.Namespace + "/" + s.Name
+				switch {
+				case s.Error != "":
+					problems = append(problems, fmt.Sprintf("%s failed: %s", name, s.Error))
+				case !s.ReadyToUse && age > stuckAfter:
+					problems = append(problems, fmt.Sprintf("%s has not been ready for %s", name, age))
+				}
+				if s.ReadyToUse {
+					continue
+				}
+				if _, ok := status.Class(s.Class); !ok {
+					if s.Class == "" {
+						problems = append(problems, fmt.Sprintf("%s names no VolumeSnapshotClass and there is no default class", name))
+					} else {
+						problems = append(problems, fmt.Sprintf("%s uses VolumeSnapshotClass %s, which does not exist", name, s.Class))
+					}
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			if len(problems) == 0 {
+				logger.Success("\n✅ All %d VolumeSnapshots are ready or in progress", len(status.Snapshots))
+				return nil
+			}
+			logger.Warning("\n❌ %d snapshot problems:", len(problems))
+			for _, p := range problems {
+				logger.Detail("- %s", p)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check VolumeSnapshots in (default is all namespaces)")
+	cmd.Flags().DurationVar(&stuckAfter, "stuck-after", time.Hour, "Flag VolumeSnapshots that are not ready after this long")
+
+	return cmd
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugSubnetTagsCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "subnet-tags [cluster-name]",
+		Short: "Check cluster subnets for load balancer discovery tags",
+		Long: `Check the cluster's subnets for the tags used to place load balancers:
+- kubernetes.io/role/elb=1 on public subnets (internet-facing load balancers)
+- kubernetes.io/role/internal-elb=1 on private subnets (internal load balancers)
+- kubernetes.io/cluster/<cluster-name>=shared or owned
+
+Missing tags are a common reason for LoadBalancer services stuck in Pending.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			logger.Info("Checking subnet tags for cluster %s...", clusterName)
+			reports, err := awsClient.GetSubnetTagReport(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			var public, private int
+			for _, r := range reports {
+				kind := "private"
+				if r.Public {
+					kind = "public"
+					public++
+				} else {
+					private++
+				}
+
+				if len(r.MissingTags) == 0 {
+					logger.Success("✅ %s (%s, %s): tagged for load balancers", r.SubnetID, r.AvailabilityZone, kind)
+					continue
+				}
+				logger.Warning("❌ %s (%s, %s) is missing tags:", r.SubnetID, r.AvailabilityZone, kind)
+				for _, tag := range r.MissingTags {
+					logger.Detail("- %s", tag)
+				}
+			}
+
+			if public == 0 {
+				logger.Warning("⚠️ No public subnets: internet-facing load balancers cannot be created")
+			}
+			if private == 0 {
+				logger.Warning("⚠️ No private subnets: internal load balancers cannot be created")
+			}
+			logger.Detail("\nTag subnets with: aws ec2 create-tags --resources <subnet-id> --tags Key=<key>,Value=<value>")
+			logger.Detail("The cluster tag is optional for AWS Load Balancer Controller v2.1.1+ but required by older controllers")
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+// costAllocationTags are the tag keys commonly activated as cost allocation tags
+var costAllocationTags = []string{"Environment", "Team", "CostCenter"}
+
+func newDebugTagsCommand() *cobra.Command {
+	var (
+		clusterName  string
+		requiredTags []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tags [cluster-name]",
+		Short: "Check cluster and nodegroup tags for required and cost allocation tags",
+		Long: `List the tags of the cluster and each managed nodegroup, and check them for:
+- The required tags from --required-tags, or the requiredTags list of the config file
+- The cost allocation tags Environment, Team and CostCenter
+
+Tag keys are case-sensitive, in Cost Explorer too, so a key that only differs in case
+from a required or cost allocation tag is reported.
+
+The command fails when a required tag is missing, empty or in the wrong case, so it
+can gate a pipeline.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+			cmd.SilenceUsage = true
+			if !cmd.Flags().Changed("required-tags") {
+				requiredTags = loadedConfig.RequiredTags
+			}
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			logger.Info("Checking tags for cluster %s...", clusterName)
+			resources, err := awsClient.GetClusterTags(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			var missingRequired int
+			for _, r := range resources {
+				missingRequired += printResourceTags(r, requiredTags)
+			}
+
+			if len(requiredTags) == 0 {
+				logger.Detail("\nNo required tags configured; set them with --required-tags or requiredTags in the config file")
+			} else if missingRequired == 0 {
+				logger.Success("\n✅ All resources carry the required tags %s", strings.Join(requiredTags, ", "))
+			}
+			logger.Detail("\nTag resources with: aws eks tag-resource --resource-arn <arn> --tags <key>=<value>")
+			logger.Detail("Nodegroup tags are not propagated to the EC2 instances; tag the launch template to attribute instance costs")
+
+			if missingRequired > 0 {
+				return fmt.Errorf("%d required tags are missing, empty or in the wrong case", missingRequired)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&requiredTags, "required-tags", nil, "Tag keys every cluster and nodegroup must carry (default: requiredTags from the config file)")
+
+	return cmd
+}
+
+// printResourceTags prints a resource's tags, its missing required tags and its cost
+// allocation tags, and returns the number of missing required tags
+func printResourceTags(r aws.ResourceTags, requiredTags []string) int {
+	logger.Plain("\n%s %s:", strings.ToUpper(r.Kind[:1])+r.Kind[1:], r.Name)
+
+	keys := make([]string, 0, len(r.Tags))
+	for key := range r.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		logger.Detail("  No tags")
+	}
+	for _, key := range keys {
+		logger.Detail("  %s=%s", key, r.Tags[key])
+	}
+
+	var missing int
+	for _, key := range requiredTags {
+		switch actual, ok := findTag(r.Tags, key); {
+		case !ok:
+			logger.Warning("❌ Missing required tag %s", key)
+			missing++
+		case actual != key:
+			logger.Warning("❌ Required tag %s is tagged as %s", key, actual)
+			missing++
+		case r.Tags[key] == "":
+			logger.Warning("❌ Required tag %s is empty", key)
+			missing++
+		}
+	}
+
+	var present, absent []string
+	for _, key := range costAllocationTags {
+		actual, ok := findTag(r.Tags, key)
+		switch {
+		case !ok:
+			absent = append(absent, key)
+		case actual != key:
+			absent = append(absent, fmt.Sprintf("%s (tagged as %s)", key, actual))
+		default:
+			present = append(present, key)
+		}
+	}
+	if len(absent) == 0 {
+		logger.Success("✅ Cost allocation tags: %s", strings.Join(present, ", "))
+	} else {
+		logger.Warning("⚠️ Cost allocation tags missing: %s", strings.Join(absent, ", "))
+	}
+	return missing
+}
+
+// findTag looks up a tag key ignoring case, returning the key as it is tagged
+func findTag(tags map[string]string, key string) (string, bool) {
+	if _, ok := tags[key]; ok {
+		return key, true
+	}
+	for actual := range tags {
+		if strings.EqualFold(actual, key) {
+			return actual, true
+		}
+	}
+	return "", false
+}
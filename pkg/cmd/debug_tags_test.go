@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"testing"
+
+	"ekspeek/pkg/aws"
+)
+
+func TestFindTag(t *testing.T) {
+	tags := map[string]string{"Team": "payments", "costcenter": "42", "Environment": ""}
+
+	tests := []struct {
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{key: "Team", want: "Team", wantOK: true},
+		{key: "CostCenter", want: "costcenter", wantOK: true},
+		{key: "Environment", want: "Environment", wantOK: true},
+		{key: "Owner", want: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		got, ok := findTag(tags, tt.key)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("findTag(%q) = %q, %v, want %q, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestPrintResourceTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		required []string
+		want     int
+	}{
+		{
+			name:     "all required tags",
+			tags:     map[string]string{"Team": "payments", "Environment": "prod"},
+			required: []string{"Team", "Environment"},
+			want:     0,
+		},
+		{
+			name:     "missing tag",
+			tags:     map[string]string{"Team": "payments"},
+			required: []string{"Team", "Environment"},
+			want:     1,
+		},
+		{
+			name:     "case mismatched key",
+			tags:     map[string]string{"team": "payments", "Environment": "prod"},
+			required: []string{"Team", "Environment"},
+			want:     1,
+		},
+		{
+			name:     "empty value",
+			tags:     map[string]string{"Team": "", "Environment": "prod"},
+			required: []string{"Team", "Environment"},
+			want:     1,
+		},
+		{
+			name:     "no tags",
+			required: []string{"Team", "Environment"},
+			want:     2,
+		},
+		{
+			name: "nothing required",
+			tags: map[string]string{"team": ""},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := aws.ResourceTags{Kind: "nodegroup", Name: "workers", Tags: tt.tags}
+			if got := printResourceTags(r, tt.required); got != tt.want {
+				t.Errorf("printResourceTags() = %d missing, want %d", got, tt.want)
+			}
+		})
+	}
+}
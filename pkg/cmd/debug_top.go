@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugTopCommand() *cobra.Command {
+	var (
+		clusterName string
+		by          string
+		top         int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "top [cluster-name]",
+		Short: "Show the heaviest pods and namespaces from Container Insights",
+		Long: `Rank pods and namespaces by their average CPU or memory utilization over the
+last hour, read from CloudWatch Container Insights. Unlike kubectl top this works
+without metrics-server, but requires Container Insights to be enabled.
+
+Utilization is a percentage of the capacity of the node the pod runs on; namespace
+figures are the sum of their pods'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+			if by != aws.TopByCPU && by != aws.TopByMemory {
+				return fmt.Errorf("invalid --by %q (use %s or %s)", by, aws.TopByCPU, aws.TopByMemory)
+			}
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			logger.Info("Collecting Container Insights %s utilization for cluster %s...", by, clusterName)
+			consumers, err := awsClient.GetTopConsumers(ctx, clusterName, by, top)
+			if err != nil {
+				return err
+			}
+			if len(consumers.Pods) == 0 {
+				logger.Warning("⚠️ No Container Insights pod metrics found in the last hour; is Container Insights enabled?")
+				return nil
+			}
+
+			logger.Info("\nTop pods by %s:", by)
+			w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAMESPACE\tPOD\tAVG %\tPEAK %")
+			for _, c := range consumers.Pods {
+				fmt.Fprintf(w, "%s\t%s\t%.1f\t%.1f\n", c.Namespace, c.Pod, c.Average, c.Peak)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			logger.Info("\nTop namespaces by %s:", by)
+			w = tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAMESPACE\tAVG %\tPEAK %")
+			for _, c := range consumers.Namespaces {
+				fmt.Fprintf(w, "%s\t%.1f\t%.1f\n", c.Namespace, c.Average, c.Peak)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&by, "by", aws.TopByCPU, "Resource to rank by (cpu or memory)")
+	cmd.Flags().IntVar(&top, "top", 10, "Number of pods and namespaces to show")
+
+	return cmd
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+)
+
+func newDebugWebhooksCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "webhooks [cluster-name]",
+		Short: "Find admission webhooks that could block API requests",
+		Long: `Check the validating and mutating admission webhooks for:
+- Webhooks whose backend service has no ready endpoints; with failurePolicy Fail every
+  request they intercept is rejected
+- Webhooks failing closed whose namespaceSelector and objectSelector catch kube-system
+  or kube-node-lease, which can keep the cluster from recovering when the webhook is down`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Checking admission webhooks...")
+			webhooks, err := kubeClient.GetWebhookStatus(ctx)
+			if err != nil {
+				return err
+			}
+			if len(webhooks) == 0 {
+				logger.Success("✅ No admission webhooks configured")
+				return nil
+			}
+
+			var issues int
+			for _, w := range webhooks {
+				if printWebhookStatus(w) {
+					issues++
+				}
+			}
+
+			if issues == 0 {
+				logger.Success("\n✅ All %d webhooks have ready backends and spare the critical namespaces", len(webhooks))
+			} else {
+				logger.Warning("\n⚠️ %d of %d webhooks need attention", issues, len(webhooks))
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// printWebhookStatus prints a webhook and returns true if it has an issue
+func printWebhookStatus(w k8s.WebhookStatus) bool {
+	backend := w.Service
+	if backend == "" {
+		backend = w.URL
+	}
+	policy := string(w.FailurePolicy)
+	if policy == "" {
+		policy = "Fail"
+	}
+	name := fmt.Sprintf("%s webhook %s (%s)", w.Kind, w.Name, w.Configuration)
+
+	switch {
+	case w.BackendDown() && w.FailsClosed():
+		logger.Warning("❌ %s fails closed and its backend %s has no ready endpoints", name, backend)
+		logger.Detail("- Every request it intercepts is rejected; restore the backend or set failurePolicy: Ignore")
+	case w.BackendDown():
+		logger.Warning("⚠️ %s: backend %s has no ready endpoints; requests are admitted after the webhook times out", name, backend)
+	case w.FailsClosed() && len(w.CriticalNamespaces) > 0:
+		logger.Warning("⚠️ %s fails closed on %s", name, strings.Join(w.CriticalNamespaces, ", "))
+		logger.Detail("- Exclude them in namespaceSelector so the cluster can recover if %s goes down", backend)
+	default:
+		logger.Success("✅ %s: backend %s (%d ready endpoints), failurePolicy %s", name, backend, w.ReadyEndpoints, policy)
+		return false
+	}
+	if w.BackendError != "" {
+		logger.Detail("- %s", w.BackendError)
+	}
+	return true
+}
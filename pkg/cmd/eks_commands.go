@@ -3,11 +3,18 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
 	"ekspeek/pkg/aws"
 	"ekspeek/pkg/common/logger"
 	"ekspeek/pkg/eks"
+	"ekspeek/pkg/findings"
+	"ekspeek/pkg/output"
 
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/spf13/cobra"
 )
 
@@ -19,12 +26,31 @@ func NewEKSCommand() *cobra.Command {
 		Long: `ekspeek is a command-line tool that helps you inspect and manage
 your Amazon EKS clusters. It provides commands for listing clusters,
 describing their configuration, and managing their components.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			logger.SetQuiet(quiet)
+			logger.SetDebugMode(debug)
+
+			severities, err := findings.ParseSeverities(onlySeverities)
+			if err != nil {
+				return fmt.Errorf("invalid --only value: %w", err)
+			}
+			onlyFilter = severities
+
+			if err := applyClusterARN(); err != nil {
+				return err
+			}
+			if err := loadConfigFile(cmd); err != nil {
+				return err
+			}
+
+			if redact {
+				enableRedaction(cmd.Root())
+			}
+			return nil
+		},
 	}
 
-	// Add global flags
-	cmd.PersistentFlags().StringVar(&profile, "profile", "", "AWS profile to use")
-	cmd.PersistentFlags().StringVar(&region, "region", "", "AWS region to use")
-	cmd.PersistentFlags().BoolVar(&debug, "debug", false, "Enable debug logging")
+	AddGlobalFlags(cmd)
 
 	// Add all subcommands
 	cmd.AddCommand(
@@ -34,6 +60,11 @@ describing their configuration, and managing their components.`,
 		NewDescribeNodegroupCmd(),
 		NewDebugCommand(),
 		newClusterHealthCommand(),
+		newBundleCommand(),
+		newChecksCommand(),
+		newContextsCommand(),
+		newExecCommand(),
+		newInventoryCommand(),
 	)
 
 	return cmd
@@ -59,21 +90,35 @@ func NewDescribeNodegroupCmd() *cobra.Command {
 	return newDescribeNodegroupCmd()
 }
 
+// listDescribeConcurrency is the number of DescribeCluster calls list makes at a time
+const listDescribeConcurrency = 8
+
 func newListClustersCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		tags     []string
+		detailed bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all EKS clusters",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			tagFilter, err := parseTagFilters(tags)
+			if err != nil {
+				return err
+			}
+
 			ctx := context.Background()
-			client, err := aws.NewClient(ctx, aws.ClientConfig{
+			client, err := newAWSClient(ctx, aws.ClientConfig{
 				Profile: profile,
 				Region:  region,
+				RoleARN: roleARN,
 			})
 			if err != nil {
 				return err
 			}
 
-			handler := eks.NewHandler(client.EKSClient)
+			handler := eks.NewHandler(client)
 			clusters, err := handler.ListClusters(ctx)
 			if err != nil {
 				return err
@@ -84,84 +129,262 @@ func newListClustersCmd() *cobra.Command {
 				return nil
 			}
 
-			logger.Success("Found %d clusters:", len(clusters))
-			for _, cluster := range clusters {
-				fmt.Println(cluster)
+			if len(tagFilter) == 0 && !detailed {
+				logger.Success("Found %d clusters:", len(clusters))
+				for _, cluster := range clusters {
+					logger.Plain("%s", cluster)
+				}
+				return nil
+			}
+
+			described, err := handler.DescribeClusters(ctx, clusters, listDescribeConcurrency)
+			if err != nil {
+				return err
+			}
+			var matched []*ekstypes.Cluster
+			for _, cluster := range described {
+				if matchTags(cluster.Tags, tagFilter) {
+					matched = append(matched, cluster)
+				}
+			}
+			if len(matched) == 0 {
+				logger.Info("No EKS clusters in region %s have tags %s", region, strings.Join(tags, ", "))
+				return nil
 			}
 
+			logger.Success("Found %d clusters:", len(matched))
+			if detailed {
+				printClustersDetailed(matched)
+				return nil
+			}
+			for _, cluster := range matched {
+				logger.Plain("%s", awssdk.ToString(cluster.Name))
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "Only list clusters with this tag, as key=value; repeat to require several tags")
+	cmd.Flags().BoolVar(&detailed, "detailed", false, "Show version, status, creation time and tags for each cluster")
+	return cmd
+}
+
+// parseTagFilters parses key=value tag filters into a map
+func parseTagFilters(tags []string) (map[string]string, error) {
+	filter := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --tag %q, expected key=value", tag)
+		}
+		filter[key] = value
+	}
+	return filter, nil
+}
+
+// matchTags returns true if the tags contain every key and value of the filter
+func matchTags(tags, filter map[string]string) bool {
+	for key, value := range filter {
+		if v, ok := tags[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func printClustersDetailed(clusters []*ekstypes.Cluster) {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tSTATUS\tCREATED\tTAGS")
+	for _, cluster := range clusters {
+		tags := make([]string, 0, len(cluster.Tags))
+		for key, value := range cluster.Tags {
+			tags = append(tags, key+"="+value)
+		}
+		sort.Strings(tags)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			awssdk.ToString(cluster.Name),
+			awssdk.ToString(cluster.Version),
+			cluster.Status,
+			awssdk.ToTime(cluster.CreatedAt).Format("2006-01-02 15:04:05"),
+			strings.Join(tags, ","))
+	}
+	w.Flush()
 }
 
 func newDescribeClusterCmd() *cobra.Command {
-	var clusterName string
+	var (
+		clusterName  string
+		full         bool
+		outputFormat string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "describe [cluster-name]",
 		Short: "Describe an EKS cluster",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
+			format, err := parseOutputFormat(outputFormat)
+			if err != nil {
+				return err
+			}
 
 			ctx := context.Background()
-			client, err := aws.NewClient(ctx, aws.ClientConfig{
-				Profile: profile,
-				Region:  region,
-			})
+			client, resolved, err := clusterClient(ctx, clusterName)
 			if err != nil {
 				return err
 			}
+			clusterName = resolved
 
-			handler := eks.NewHandler(client.EKSClient)
+			handler := eks.NewHandler(client)
 			cluster, err := handler.DescribeCluster(ctx, clusterName)
 			if err != nil {
 				return err
 			}
 
+			// Structured output carries every field of the cluster
+			if format != output.FormatText {
+				return printResult(format, cluster)
+			}
+
 			// Print cluster details in a formatted way
-			fmt.Printf("Name: %s\n", *cluster.Name)
-			fmt.Printf("Version: %s\n", *cluster.Version)
-			fmt.Printf("Status: %s\n", cluster.Status)
-			fmt.Printf("Endpoint: %s\n", *cluster.Endpoint)
-			fmt.Printf("ARN: %s\n", *cluster.Arn)
-			fmt.Printf("Created: %s\n", cluster.CreatedAt.Format("2006-01-02 15:04:05"))
-			
-			return nil
+			logger.Plain("Name: %s", *cluster.Name)
+			logger.Plain("Version: %s", *cluster.Version)
+			logger.Plain("Status: %s", cluster.Status)
+			logger.Plain("Endpoint: %s", *cluster.Endpoint)
+			logger.Plain("ARN: %s", *cluster.Arn)
+			logger.Plain("Created: %s", cluster.CreatedAt.Format("2006-01-02 15:04:05"))
+
+			if full {
+				printClusterDetails(cluster)
+
+				addons, err := client.GetAddons(ctx, clusterName)
+				if err != nil {
+					logger.Warning("Failed to get addons: %v", err)
+				} else {
+					logger.Plain("\nAdd-ons:")
+					if len(addons) == 0 {
+						logger.Plain("  (none)")
+					}
+					for _, addon := range addons {
+						logger.Plain("  %s: %s (%s)", awssdk.ToString(addon.AddonName), awssdk.ToString(addon.AddonVersion), addon.Status)
+					}
+				}
+
+				logger.Plain("\nAdd-on Versions:")
+				if kubeClient, err := getClusterKubeClient(ctx, clusterName); err != nil {
+					logger.Warning("Skipped add-on version check: %v", err)
+				} else if checks, err := checkAddonVersions(ctx, client, kubeClient, clusterName); err != nil {
+					logger.Warning("Skipped add-on version check: %v", err)
+				} else {
+					printAddonVersionChecks(checks)
+				}
+			}
+
+			return saveResult(cluster)
 		},
 	}
 
+	cmd.Flags().BoolVar(&full, "full", false, "Show logging, OIDC, networking, tag and add-on configuration")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json or yaml (the complete DescribeCluster result)")
 	return cmd
 }
 
+// printClusterDetails prints the extended cluster configuration shown by describe --full
+func printClusterDetails(cluster *ekstypes.Cluster) {
+	logger.Plain("Platform Version: %s", awssdk.ToString(cluster.PlatformVersion))
+	if cluster.Identity != nil && cluster.Identity.Oidc != nil {
+		logger.Plain("OIDC Issuer: %s", awssdk.ToString(cluster.Identity.Oidc.Issuer))
+	}
+
+	logger.Plain("\nControl Plane Logging:")
+	var enabled, disabled []string
+	if cluster.Logging != nil {
+		for _, setup := range cluster.Logging.ClusterLogging {
+			for _, logType := range setup.Types {
+				if awssdk.ToBool(setup.Enabled) {
+					enabled = append(enabled, string(logType))
+				} else {
+					disabled = append(disabled, string(logType))
+				}
+			}
+		}
+	}
+	logger.Plain("  Enabled: %s", joinOrNone(enabled))
+	logger.Plain("  Disabled: %s", joinOrNone(disabled))
+
+	if vpc := cluster.ResourcesVpcConfig; vpc != nil {
+		logger.Plain("\nNetworking:")
+		logger.Plain("  VPC ID: %s", awssdk.ToString(vpc.VpcId))
+		logger.Plain("  Subnets: %s", joinOrNone(vpc.SubnetIds))
+		logger.Plain("  Security Groups: %s", joinOrNone(vpc.SecurityGroupIds))
+		logger.Plain("  Cluster Security Group: %s", awssdk.ToString(vpc.ClusterSecurityGroupId))
+		logger.Plain("  Public Endpoint: %t", vpc.EndpointPublicAccess)
+		if vpc.EndpointPublicAccess {
+			logger.Plain("  Public Access CIDRs: %s", joinOrNone(vpc.PublicAccessCidrs))
+		}
+		logger.Plain("  Private Endpoint: %t", vpc.EndpointPrivateAccess)
+	}
+
+	logger.Plain("\nTags:")
+	if len(cluster.Tags) == 0 {
+		logger.Plain("  (none)")
+	}
+	keys := make([]string, 0, len(cluster.Tags))
+	for key := range cluster.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		logger.Plain("  %s=%s", key, cluster.Tags[key])
+	}
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
+
 func newListNodegroupsCmd() *cobra.Command {
-	var clusterName string
+	var (
+		clusterName string
+		wide        bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "list-nodegroups [cluster-name]",
 		Short: "List all nodegroups in an EKS cluster",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
 
 			ctx := context.Background()
-			client, err := aws.NewClient(ctx, aws.ClientConfig{
-				Profile: profile,
-				Region:  region,
-			})
+			client, resolved, err := clusterClient(ctx, clusterName)
 			if err != nil {
 				return err
 			}
+			clusterName = resolved
+
+			if wide {
+				nodegroups, err := client.GetClusterNodegroups(ctx, clusterName)
+				if err != nil {
+					return err
+				}
+				if len(nodegroups) == 0 {
+					logger.Info("No nodegroups found in cluster %s", clusterName)
+					return nil
+				}
+				printNodegroupsWide(nodegroups)
+				return nil
+			}
 
-			handler := eks.NewHandler(client.EKSClient)
+			handler := eks.NewHandler(client)
 			nodegroups, err := handler.ListNodegroups(ctx, clusterName)
 			if err != nil {
 				return err
@@ -174,20 +397,44 @@ func newListNodegroupsCmd() *cobra.Command {
 
 			logger.Success("Found %d nodegroups:", len(nodegroups))
 			for _, ng := range nodegroups {
-				fmt.Println(ng)
+				logger.Plain("%s", ng)
 			}
 
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&wide, "wide", false, "Show status, capacity type, instance types, scaling and AMI release for each nodegroup")
 	return cmd
 }
 
+// printNodegroupsWide prints an aligned table with the configuration of each nodegroup
+func printNodegroupsWide(nodegroups []*ekstypes.Nodegroup) {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tCAPACITY\tINSTANCE TYPES\tDESIRED\tMIN\tMAX\tRELEASE")
+	for _, ng := range nodegroups {
+		var desired, minSize, maxSize int32
+		if ng.ScalingConfig != nil {
+			desired = awssdk.ToInt32(ng.ScalingConfig.DesiredSize)
+			minSize = awssdk.ToInt32(ng.ScalingConfig.MinSize)
+			maxSize = awssdk.ToInt32(ng.ScalingConfig.MaxSize)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			awssdk.ToString(ng.NodegroupName),
+			ng.Status,
+			ng.CapacityType,
+			strings.Join(ng.InstanceTypes, ","),
+			desired, minSize, maxSize,
+			awssdk.ToString(ng.ReleaseVersion))
+	}
+	w.Flush()
+}
+
 func newDescribeNodegroupCmd() *cobra.Command {
 	var (
 		clusterName   string
 		nodegroupName string
+		outputFormat  string
 	)
 
 	cmd := &cobra.Command{
@@ -199,35 +446,43 @@ func newDescribeNodegroupCmd() *cobra.Command {
 			}
 			clusterName = args[0]
 			nodegroupName = args[1]
+			format, err := parseOutputFormat(outputFormat)
+			if err != nil {
+				return err
+			}
 
 			ctx := context.Background()
-			client, err := aws.NewClient(ctx, aws.ClientConfig{
-				Profile: profile,
-				Region:  region,
-			})
+			client, resolved, err := clusterClient(ctx, clusterName)
 			if err != nil {
 				return err
 			}
+			clusterName = resolved
 
-			handler := eks.NewHandler(client.EKSClient)
+			handler := eks.NewHandler(client)
 			nodegroup, err := handler.DescribeNodegroup(ctx, clusterName, nodegroupName)
 			if err != nil {
 				return err
 			}
 
-			// Print nodegroup details in a formatted way
-			fmt.Printf("Nodegroup Name: %s\n", *nodegroup.NodegroupName)
-			fmt.Printf("Status: %s\n", nodegroup.Status)
-			fmt.Printf("Cluster Name: %s\n", *nodegroup.ClusterName)
-			fmt.Printf("Instance Types: %v\n", nodegroup.InstanceTypes)
-			fmt.Printf("Desired Size: %d\n", nodegroup.ScalingConfig.DesiredSize)
-			fmt.Printf("Min Size: %d\n", nodegroup.ScalingConfig.MinSize)
-			fmt.Printf("Max Size: %d\n", nodegroup.ScalingConfig.MaxSize)
-			fmt.Printf("Created: %s\n", nodegroup.CreatedAt.Format("2006-01-02 15:04:05"))
+			// Structured output carries every field of the nodegroup
+			if format != output.FormatText {
+				return printResult(format, nodegroup)
+			}
 
-			return nil
+			// Print nodegroup details in a formatted way
+			logger.Plain("Nodegroup Name: %s", *nodegroup.NodegroupName)
+			logger.Plain("Status: %s", nodegroup.Status)
+			logger.Plain("Cluster Name: %s", *nodegroup.ClusterName)
+			logger.Plain("Instance Types: %v", nodegroup.InstanceTypes)
+			logger.Plain("Desired Size: %d", nodegroup.ScalingConfig.DesiredSize)
+			logger.Plain("Min Size: %d", nodegroup.ScalingConfig.MinSize)
+			logger.Plain("Max Size: %d", nodegroup.ScalingConfig.MaxSize)
+			logger.Plain("Created: %s", nodegroup.CreatedAt.Format("2006-01-02 15:04:05"))
+
+			return saveResult(nodegroup)
 		},
 	}
 
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json or yaml (the complete DescribeNodegroup result)")
 	return cmd
 }
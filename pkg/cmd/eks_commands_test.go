@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRootCommandDiagImageFlags(t *testing.T) {
+	root := NewEKSCommand()
+	err := root.ParseFlags([]string{
+		"--diag-image", "111122223333.dkr.ecr.eu-west-1.amazonaws.com/busybox:1.36",
+		"--diag-image-pull-secrets", "ecr-pull,mirror-pull",
+	})
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if diagImage != "111122223333.dkr.ecr.eu-west-1.amazonaws.com/busybox:1.36" {
+		t.Errorf("diagImage = %q, want the ECR image", diagImage)
+	}
+	if got := strings.Join(diagImagePullSecrets, ","); got != "ecr-pull,mirror-pull" {
+		t.Errorf("diagImagePullSecrets = %s, want ecr-pull,mirror-pull", got)
+	}
+
+	debug, _, err := root.Find([]string{"debug", "networking"})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if debug.InheritedFlags().Lookup("diag-image") == nil || debug.InheritedFlags().Lookup("diag-image-pull-secrets") == nil {
+		t.Error("debug networking does not inherit --diag-image and --diag-image-pull-secrets")
+	}
+}
+
+func TestRootCommandQuietAndDebug(t *testing.T) {
+	defer func(q, d bool) { quiet, debug = q, d }(quiet, debug)
+
+	root := NewEKSCommand()
+	root.SetArgs([]string{"list", "-q", "--debug"})
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "[debug quiet]") {
+		t.Errorf("Execute() error = %v, want -q and --debug rejected together", err)
+	}
+}
+
+func TestRootCommandWithMetadataDefault(t *testing.T) {
+	defer func(include bool) { includeMetadata = include }(includeMetadata)
+
+	root := NewEKSCommand()
+	if err := root.ParseFlags(nil); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if !includeMetadata {
+		t.Error("includeMetadata = false by default, want JSON and YAML results wrapped with the caller identity")
+	}
+	if err := root.ParseFlags([]string{"--with-metadata=false"}); err != nil || includeMetadata {
+		t.Errorf("--with-metadata=false: includeMetadata = %v, err = %v, want false", includeMetadata, err)
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+)
+
+// checkErrors collects the failures of individual checks so a command can print a partial
+// report instead of stopping at the first error
+type checkErrors struct {
+	errs []error
+}
+
+// add records a failed check and logs it as a warning. Checks that failed for lack of IAM
+// permissions are reported as skipped.
+func (c *checkErrors) add(check string, err error) {
+	if aws.IsAccessDenied(err) {
+		logger.Warning("Skipped %s: %v", check, err)
+	} else {
+		logger.Warning("Failed to %s: %v", check, err)
+	}
+	c.errs = append(c.errs, fmt.Errorf("%s: %w", check, err))
+}
+
+// err prints a footer with the number of failed checks and returns them joined, or nil
+// if every check ran
+func (c *checkErrors) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	logger.Warning("\n%d checks failed; the report above is partial", len(c.errs))
+	return errors.Join(c.errs...)
+}
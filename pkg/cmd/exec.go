@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"ekspeek/pkg/k8s"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func newExecCommand() *cobra.Command {
+	var (
+		namespace string
+		container string
+		stdin     bool
+		tty       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "exec [pod-name] -- command [args...]",
+		Short: "Run a command in a running pod",
+		Long: `Run a command in a container of a running pod through pods/exec, like kubectl
+exec, and print its output as it is written. Handy for quick checks from the pod's point
+of view, e.g. DNS lookups or curl to an endpoint, without a test pod.
+
+Use -i to pass stdin to the command and -t to run it in a terminal, e.g. -it for a shell.
+With --redact, output is passed on a line at a time.
+
+The command exits with an error when the remote command exits non-zero.`,
+		Example: `  ekspeek exec web-app-pod -n default -- nslookup kubernetes.default
+  ekspeek exec web-app-pod -c app -- cat /etc/resolv.conf
+  ekspeek exec web-app-pod -it -- sh`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			if dash != 1 || len(args) < 2 {
+				return fmt.Errorf("usage: ekspeek exec <pod-name> -- <command> [args...]")
+			}
+			podName, command := args[0], args[1:]
+			cmd.SilenceUsage = true
+
+			ctx := context.Background()
+
+			// Create kubernetes client
+			kubeClient, err := getKubeClient()
+			if err != nil {
+				return err
+			}
+
+			streams := k8s.ExecStreams{Stdout: stdout, Stderr: cmd.ErrOrStderr(), TTY: tty}
+			if stdin {
+				streams.Stdin = os.Stdin
+			}
+			if tty {
+				// The remote terminal handles line editing and echo, so the local one is
+				// put in raw mode
+				if !stdin || !term.IsTerminal(int(os.Stdin.Fd())) {
+					return fmt.Errorf("--tty requires --stdin and a terminal on stdin")
+				}
+				state, err := term.MakeRaw(int(os.Stdin.Fd()))
+				if err != nil {
+					return fmt.Errorf("failed to put the terminal in raw mode: %w", err)
+				}
+				defer term.Restore(int(os.Stdin.Fd()), state)
+			}
+
+			err = kubeClient.StreamInPod(ctx, namespace, podName, container, command, streams)
+			if err != nil {
+				if code, ok := k8s.ExecExitCode(err); ok {
+					return fmt.Errorf("command exited with status %d", code)
+				}
+				return err
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the pod")
+	cmd.Flags().StringVarP(&container, "container", "c", "", "Container to run the command in (default is the pod's default container)")
+	cmd.Flags().BoolVarP(&stdin, "stdin", "i", false, "Pass stdin to the command")
+	cmd.Flags().BoolVarP(&tty, "tty", "t", false, "Run the command in a terminal; requires --stdin")
+
+	return cmd
+}
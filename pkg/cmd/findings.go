@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/findings"
+	"ekspeek/pkg/output"
+)
+
+// printFindings prints findings from most to least severe along with their remediation.
+// Findings are limited to the severities selected with --only.
+func printFindings(fs []findings.Finding) {
+	for _, f := range selectFindings(fs) {
+		subject := f.ID
+		if f.Resource != "" {
+			subject = f.ID + " (" + f.Resource + ")"
+		}
+
+		switch f.Severity {
+		case findings.SeverityCritical:
+			logger.Error("  ❌ [%s] %s: %s", f.Severity, subject, f.Message)
+		case findings.SeverityWarning:
+			logger.Warning("  ⚠️  [%s] %s: %s", f.Severity, subject, f.Message)
+		case findings.SeverityInfo:
+			logger.Info("  [%s] %s: %s", f.Severity, subject, f.Message)
+		default:
+			logger.Success("  ✅ %s: %s", subject, f.Message)
+		}
+		if f.Remediation != "" {
+			logger.Detail("     → %s", f.Remediation)
+		}
+	}
+}
+
+// selectFindings returns the findings with the severities selected with --only, from most
+// to least severe
+func selectFindings(fs []findings.Finding) []findings.Finding {
+	fs = findings.Filter(fs, onlyFilter)
+	sorted := make([]findings.Finding, len(fs))
+	copy(sorted, fs)
+	findings.Sort(sorted)
+	return sorted
+}
+
+// writeFindings writes findings in a structured output format with printResult, limited to
+// the severities selected with --only
+func writeFindings(format output.Format, fs []findings.Finding) error {
+	return printResult(format, selectFindings(fs))
+}
+
+// saveFindings saves findings printed as text to --output-file, when set
+func saveFindings(fs []findings.Finding) error {
+	return saveResult(selectFindings(fs))
+}
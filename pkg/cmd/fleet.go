@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+)
+
+// FleetResult contains the health check outcome for a single cluster in a fleet run
+type FleetResult struct {
+	ClusterName    string
+	TotalIssues    int
+	CriticalIssues int
+	// FailedChecks counts the health checks that failed to collect, so the issue counts
+	// may be incomplete
+	FailedChecks int
+	Err          error
+}
+
+// kubeconfigMu serializes kubeconfig updates, since every cluster in a fleet run writes the same file
+var kubeconfigMu sync.Mutex
+
+// runFleetHealthCheck runs the cluster health check against each cluster concurrently, with at
+// most concurrency checks in flight. Failures are recorded per cluster instead of aborting the run.
+func runFleetHealthCheck(ctx context.Context, clusters []string, concurrency int, opts k8s.HealthCheckOptions) []FleetResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]FleetResult, len(clusters))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, name := range clusters {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = checkFleetCluster(ctx, name, opts)
+		}(i, name)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func checkFleetCluster(ctx context.Context, clusterName string, opts k8s.HealthCheckOptions) FleetResult {
+	result := FleetResult{ClusterName: clusterName}
+	log := logger.WithPrefix(clusterName)
+	log.Info("Checking cluster health...")
+
+	access := clusterAWSConfig(clusterName)
+	if awsClient, err := newAWSClient(ctx, access); err == nil {
+		if err := checkClusterActive(ctx, log, awsClient, clusterName); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	kubeClient, err := connectToCluster(ctx, clusterName, access, k8s.KubeconfigOptions{}, log)
+	if err != nil {
+		result.Err = err
+		log.Warning("%v", result.Err)
+		return result
+	}
+
+	status, err := kubeClient.CheckClusterHealthWithOptions(ctx, opts)
+	var partial *k8s.HealthCheckError
+	if err != nil && !errors.As(err, &partial) {
+		result.Err = fmt.Errorf("failed to check cluster health: %w", err)
+		log.Warning("%v", result.Err)
+		return result
+	}
+	if partial != nil {
+		result.FailedChecks = len(partial.Errors)
+		log.Warning("%v", partial)
+	}
+
+	result.TotalIssues, result.CriticalIssues = countHealthIssues(status)
+	log.Info("Found %d issues (%d critical)", result.TotalIssues, result.CriticalIssues)
+	return result
+}
+
+// printFleetSummary prints a per-cluster summary table for a fleet run
+func printFleetSummary(results []FleetResult) {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ClusterName < results[j].ClusterName
+	})
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tISSUES\tCRITICAL\tSTATUS")
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\tERROR: %v\n", r.ClusterName, r.Err)
+			continue
+		}
+		state := "Healthy"
+		if r.CriticalIssues > 0 {
+			state = "Critical"
+		} else if r.TotalIssues > 0 {
+			state = "Degraded"
+		}
+		if r.FailedChecks > 0 {
+			state = fmt.Sprintf("%s (%d checks failed)", state, r.FailedChecks)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", r.ClusterName, r.TotalIssues, r.CriticalIssues, state)
+	}
+	w.Flush()
+}
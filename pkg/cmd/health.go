@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"ekspeek/pkg/common/logger"
 	"ekspeek/pkg/k8s"
@@ -30,33 +32,45 @@ func newHealthCheckCommand() *cobra.Command {
 - Authentication and authorization issues
 - Node group and worker node issues`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 0 {
-				clusterName = args[0]
-			}
+			clusterName = clusterNameArg(args)
 			if clusterName == "" {
 				return fmt.Errorf("cluster name is required")
 			}
 
 			ctx := context.Background()
 
-			// Update kubeconfig
-			logger.Info("Updating kubeconfig for cluster %s", clusterName)
-			if err := k8s.UpdateKubeconfig(ctx, clusterName, region); err != nil {
-				return err
-			}
+			var kubeClient *k8s.KubeClient
+			var err error
+			switch {
+			case fromDump != "":
+				kubeClient, err = k8s.NewKubeClientFromDump(fromDump)
+			case inCluster:
+				kubeClient, err = k8s.NewKubeClient(k8s.KubeClientConfig{InCluster: true})
+			default:
+				clusterName, err = resolveClusterConfig(ctx, clusterName)
+				if err != nil {
+					return err
+				}
 
-			// Create kubernetes client
-			kubeClient, err := getKubeClient()
+				// Update kubeconfig and use the cluster's context, which may not be the current one
+				logger.Info("Updating kubeconfig for cluster %s", clusterName)
+				kubeClient, err = connectToCluster(ctx, clusterName, clusterAWSConfig(clusterName), k8s.KubeconfigOptions{}, logger.Default())
+			}
 			if err != nil {
 				return err
 			}
+			logKubeTarget(kubeClient)
 
 			// Perform health check
 			logger.Info("Performing comprehensive health check...")
 			status, err := kubeClient.CheckClusterHealth(ctx)
-			if err != nil {
+			var partial *k8s.HealthCheckError
+			if err != nil && !errors.As(err, &partial) {
 				return err
 			}
+			if partial != nil {
+				logger.Warning("%v; the sections below are incomplete", partial)
+			}
 
 			// Print results based on components flag or all if none specified
 			if len(components) == 0 {
@@ -65,7 +79,7 @@ func newHealthCheckCommand() *cobra.Command {
 				printSelectedHealthStatus(status, components)
 			}
 
-			return nil
+			return partialHealthError(nil, partial)
 		},
 	}
 
@@ -80,7 +94,7 @@ func printFullHealthStatus(status *k8s.ClusterHealthStatus) {
 	if len(status.NodeVersions) > 1 {
 		logger.Warning("❌ Version mismatch detected:")
 		for version, nodes := range status.NodeVersions {
-			fmt.Printf("Version %s: %d nodes (%s)\n", version, len(nodes), strings.Join(nodes, ", "))
+			logger.Detail("Version %s: %d nodes (%s)", version, len(nodes), strings.Join(nodes, ", "))
 		}
 	} else {
 		logger.Success("✅ All nodes running same Kubernetes version")
@@ -91,7 +105,7 @@ func printFullHealthStatus(status *k8s.ClusterHealthStatus) {
 	if len(status.DeprecatedAPIs) > 0 {
 		logger.Warning("❌ Deprecated API usage detected:")
 		for _, api := range status.DeprecatedAPIs {
-			fmt.Printf("- %s\n", api)
+			logger.Detail("- %s", api)
 		}
 	} else {
 		logger.Success("✅ No deprecated API usage found")
@@ -128,12 +142,12 @@ func printSelectedHealthStatus(status *k8s.ClusterHealthStatus, components []str
 		case "versions":
 			logger.Info("\n=== Version Status ===")
 			for version, nodes := range status.NodeVersions {
-				fmt.Printf("Version %s: %d nodes\n", version, len(nodes))
+				logger.Detail("Version %s: %d nodes", version, len(nodes))
 			}
 		case "apis":
 			logger.Info("\n=== API Status ===")
 			for _, api := range status.DeprecatedAPIs {
-				fmt.Printf("- %s\n", api)
+				logger.Detail("- %s", api)
 			}
 		case "logging":
 			logger.Info("\n=== Logging & Monitoring Status ===")
@@ -165,7 +179,7 @@ func getLoggingStatus(status k8s.LoggingStatus) {
 func printNetworkingStatus(status k8s.NetworkingStatus) {
 	// CNI Status
 	if len(status.CNIStatus) > 0 {
-		fmt.Println("\nAWS CNI Status:")
+		logger.Detail("\nAWS CNI Status:")
 		for _, pod := range status.CNIStatus {
 			if pod.Status != "Running" {
 				logger.Warning("❌ AWS CNI pod %s is %s: %s", pod.Name, pod.Status, pod.Message)
@@ -177,7 +191,7 @@ func printNetworkingStatus(status k8s.NetworkingStatus) {
 
 	// CoreDNS Status
 	if len(status.CoreDNSStatus) > 0 {
-		fmt.Println("\nCoreDNS Status:")
+		logger.Detail("\nCoreDNS Status:")
 		for _, pod := range status.CoreDNSStatus {
 			if pod.Status != "Running" {
 				logger.Warning("❌ CoreDNS pod %s is %s: %s", pod.Name, pod.Status, pod.Message)
@@ -205,21 +219,21 @@ func printLoadBalancerStatus(status k8s.LoadBalancerStatus) {
 	if len(status.PendingServices) > 0 {
 		logger.Warning("❌ Services pending LoadBalancer provisioning:")
 		for _, svc := range status.PendingServices {
-			fmt.Printf("- %s\n", svc)
+			logger.Detail("- %s", svc)
 		}
 	} else {
 		logger.Success("✅ All LoadBalancer services are provisioned")
 	}
 
 	if len(status.IngressStatus) > 0 {
-		fmt.Println("\nIngress Status:")
+		logger.Detail("\nIngress Status:")
 		for _, ing := range status.IngressStatus {
 			if ing.Status == "Ready" {
 				logger.Success("✅ Ingress %s/%s is ready", ing.Namespace, ing.Name)
 			} else {
 				logger.Warning("❌ Ingress %s/%s is %s", ing.Namespace, ing.Name, ing.Status)
 				for _, problem := range ing.Problems {
-					fmt.Printf("  - %s\n", problem)
+					logger.Detail("  - %s", problem)
 				}
 			}
 		}
@@ -230,21 +244,29 @@ func printSchedulingStatus(status k8s.SchedulingStatus) {
 	if len(status.PendingPods) > 0 {
 		logger.Warning("❌ Pods pending scheduling:")
 		for _, pod := range status.PendingPods {
-			fmt.Printf("- %s/%s: %s\n", pod.Namespace, pod.Pod, pod.Reason)
+			logger.Detail("- %s/%s: %s", pod.Namespace, pod.Pod, pod.Reason)
+			for _, shortage := range pod.ResourceShortages {
+				logger.Detail("  No node fits %s", shortage)
+			}
+			if pod.SpreadConstraint != "" {
+				logger.Detail("  Topology spread: %s", pod.SpreadConstraint)
+			}
 		}
 	} else {
 		logger.Success("✅ All pods are scheduled")
 	}
 
+	printPodCapacityIssues(status)
+
 	if len(status.ResourceIssues) > 0 {
 		logger.Warning("\n❌ Resource issues detected:")
 		for _, issue := range status.ResourceIssues {
-			fmt.Printf("Node %s:\n", issue.NodeName)
-			fmt.Printf("  CPU: %.1f%% utilized (%.1f/%.1f cores)\n",
+			logger.Detail("Node %s:", issue.NodeName)
+			logger.Detail("  CPU: %.1f%% utilized (%.1f/%.1f cores)",
 				issue.CPU.Utilization,
 				float64(issue.CPU.Allocated)/1000,
 				float64(issue.CPU.Capacity)/1000)
-			fmt.Printf("  Memory: %.1f%% utilized (%.1f/%.1f GB)\n",
+			logger.Detail("  Memory: %.1f%% utilized (%.1f/%.1f GB)",
 				issue.Memory.Utilization,
 				float64(issue.Memory.Allocated)/(1024*1024*1024),
 				float64(issue.Memory.Capacity)/(1024*1024*1024))
@@ -252,11 +274,24 @@ func printSchedulingStatus(status k8s.SchedulingStatus) {
 	}
 }
 
+func printPodCapacityIssues(status k8s.SchedulingStatus) {
+	if status.CapacityError != "" {
+		logger.Warning("\n⚠️ Could not check node pod capacity: %s", status.CapacityError)
+	} else if len(status.PodCapacityIssues) > 0 {
+		logger.Warning("\n❌ Nodes at pod capacity (pending pods may be waiting for IP addresses):")
+		for _, issue := range status.PodCapacityIssues {
+			logger.Detail("- %s", issue)
+		}
+	} else {
+		logger.Success("✅ No nodes at pod capacity")
+	}
+}
+
 func printAuthStatus(status k8s.AuthStatus) {
 	if len(status.IRSAIssues) > 0 {
 		logger.Warning("❌ IRSA issues detected:")
 		for _, issue := range status.IRSAIssues {
-			fmt.Printf("- %s\n", issue)
+			logger.Detail("- %s", issue)
 		}
 	} else {
 		logger.Success("✅ No IRSA issues detected")
@@ -265,7 +300,7 @@ func printAuthStatus(status k8s.AuthStatus) {
 	if len(status.RBACIssues) > 0 {
 		logger.Warning("\n❌ RBAC issues detected:")
 		for _, issue := range status.RBACIssues {
-			fmt.Printf("- %s\n", issue)
+			logger.Detail("- %s", issue)
 		}
 	} else {
 		logger.Success("✅ No RBAC issues detected")
@@ -274,7 +309,7 @@ func printAuthStatus(status k8s.AuthStatus) {
 	if len(status.IAMAuthIssues) > 0 {
 		logger.Warning("\n❌ IAM authentication issues detected:")
 		for _, issue := range status.IAMAuthIssues {
-			fmt.Printf("- %s\n", issue)
+			logger.Detail("- %s", issue)
 		}
 	} else {
 		logger.Success("✅ No IAM authentication issues detected")
@@ -285,23 +320,42 @@ func printNodeStatus(status k8s.NodeStatus) {
 	if len(status.NotReady) > 0 {
 		logger.Warning("❌ Nodes in NotReady state:")
 		for _, node := range status.NotReady {
-			fmt.Printf("- %s\n", node)
+			logger.Detail("- %s", node)
 		}
 	} else {
 		logger.Success("✅ All nodes are Ready")
 	}
 
+	if len(status.Flapping) > 0 {
+		logger.Warning("\n⚠️ Nodes flapping between Ready and NotReady:")
+		for _, flap := range status.Flapping {
+			state := "NotReady"
+			if flap.Ready {
+				state = "Ready"
+			}
+			logger.Detail("- %s: %d transitions, %s since %s", flap.Node, flap.Transitions, state,
+				flap.LastTransition.Format(time.RFC3339))
+		}
+	}
+
+	if len(status.CertIssues) > 0 {
+		logger.Warning("\n❌ Kubelet certificate issues on NotReady nodes:")
+		for _, issue := range status.CertIssues {
+			logger.Detail("- %s: %s", issue.Node, issue.Reason)
+		}
+	}
+
 	if len(status.ASGIssues) > 0 {
 		logger.Warning("\n❌ Auto Scaling Group issues:")
 		for _, issue := range status.ASGIssues {
-			fmt.Printf("- %s\n", issue)
+			logger.Detail("- %s", issue)
 		}
 	}
 
 	if len(status.BootstrapIssues) > 0 {
 		logger.Warning("\n❌ Node bootstrap issues:")
 		for _, issue := range status.BootstrapIssues {
-			fmt.Printf("- %s\n", issue)
+			logger.Detail("- %s", issue)
 		}
 	}
 }
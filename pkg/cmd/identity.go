@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/output"
+)
+
+var (
+	identityMu sync.Mutex
+	// identities holds the caller identity of each credential source (profile and role)
+	// looked up in this run, so each is fetched and logged once
+	identities = make(map[aws.ClientConfig]*aws.Identity)
+	// runIdentity is the first caller identity of the run, reported in the metadata of
+	// structured output
+	runIdentity *aws.Identity
+)
+
+// resultMetadata describes the run that produced a structured result
+type resultMetadata struct {
+	Account string `json:"account,omitempty"`
+	ARN     string `json:"arn,omitempty"`
+}
+
+// resultDocument is the JSON and YAML output of a command unless --with-metadata=false: the
+// result with the metadata of the run
+type resultDocument struct {
+	Metadata resultMetadata `json:"metadata"`
+	Result   interface{}    `json:"result"`
+}
+
+// newAWSClient creates an AWS client and logs the account and principal its credentials
+// belong to, so it is clear which account a command works on
+func newAWSClient(ctx context.Context, cfg aws.ClientConfig) (*aws.Client, error) {
+	client, err := aws.NewClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	logCallerIdentity(ctx, client, cfg)
+	return client, nil
+}
+
+// logCallerIdentity logs the client's caller identity at Info level the first time its
+// credential source is used in this run
+func logCallerIdentity(ctx context.Context, client *aws.Client, cfg aws.ClientConfig) {
+	key := aws.ClientConfig{Profile: cfg.Profile, RoleARN: cfg.RoleARN}
+
+	identityMu.Lock()
+	_, ok := identities[key]
+	identityMu.Unlock()
+	if ok {
+		return
+	}
+
+	// Look up outside the lock, so fleet workers do not wait on each other's STS calls.
+	// Workers sharing a credential source may both look it up; only the first logs it.
+	identity, err := client.Identity(ctx)
+
+	identityMu.Lock()
+	defer identityMu.Unlock()
+	if _, ok := identities[key]; ok {
+		return
+	}
+	if err != nil {
+		logger.Warning("Could not determine the AWS account: %v", err)
+		identities[key] = nil
+		return
+	}
+	identities[key] = &identity
+	if runIdentity == nil {
+		runIdentity = &identity
+	}
+	logger.Info("AWS account %s as %s", identity.Account, identity.ARN)
+}
+
+// withMetadata wraps JSON and YAML results in a resultDocument, unless --with-metadata=false.
+// JSON Lines and SARIF results, which are read a record at a time, are written as they are.
+func withMetadata(format output.Format, v interface{}) interface{} {
+	if !includeMetadata || (format != output.FormatJSON && format != output.FormatYAML) {
+		return v
+	}
+	doc := resultDocument{Result: v}
+	identityMu.Lock()
+	if runIdentity != nil {
+		doc.Metadata = resultMetadata{Account: runIdentity.Account, ARN: runIdentity.ARN}
+	}
+	identityMu.Unlock()
+	return doc
+}
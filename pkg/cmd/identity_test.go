@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/output"
+)
+
+func TestWithMetadata(t *testing.T) {
+	defer func(include bool, identity *aws.Identity) {
+		includeMetadata, runIdentity = include, identity
+	}(includeMetadata, runIdentity)
+	runIdentity = &aws.Identity{Account: "111122223333", ARN: "arn:aws:sts::111122223333:assumed-role/Admin/jane"}
+	result := []string{"finding"}
+
+	includeMetadata = false
+	if _, wrapped := withMetadata(output.FormatJSON, result).(resultDocument); wrapped {
+		t.Error("withMetadata() wrapped the result with --with-metadata=false")
+	}
+
+	includeMetadata = true
+	doc, wrapped := withMetadata(output.FormatYAML, result).(resultDocument)
+	if !wrapped || doc.Metadata.Account != "111122223333" {
+		t.Errorf("withMetadata() = %+v, want the result wrapped with account 111122223333", doc)
+	}
+	if _, wrapped := withMetadata(output.FormatJSONL, result).(resultDocument); wrapped {
+		t.Error("withMetadata() wrapped a JSON Lines result")
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/output"
+
+	"github.com/spf13/cobra"
+)
+
+// InventoryImage is a distinct image in the cluster, with its ECR size and push time for
+// images hosted in private ECR
+type InventoryImage struct {
+	Image      string     `json:"image"`
+	Digest     string     `json:"digest,omitempty"`
+	Containers int        `json:"containers"`
+	Namespaces []string   `json:"namespaces"`
+	SizeBytes  int64      `json:"sizeBytes,omitempty"`
+	PushedAt   *time.Time `json:"pushedAt,omitempty"`
+	ECRError   string     `json:"ecrError,omitempty"`
+}
+
+func newInventoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Inventory cluster resources",
+	}
+	cmd.AddCommand(newInventoryImagesCommand())
+	return cmd
+}
+
+func newInventoryImagesCommand() *cobra.Command {
+	var (
+		clusterName  string
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "images [cluster-name]",
+		Short: "List every distinct container image in the cluster",
+		Long: `List the distinct images of all containers across all pods. Each image is listed
+with the digest it resolved to (from the container statuses), the number of containers
+using it and their namespaces. For private ECR images the size and last push time come
+from ecr:DescribeImages.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName = clusterNameArg(args)
+			if clusterName == "" {
+				return fmt.Errorf("cluster name is required")
+			}
+			format, err := parseOutputFormat(outputFormat)
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			ctx := context.Background()
+
+			// Create AWS client with the cluster's settings
+			awsClient, resolved, err := activeClusterClient(ctx, cmd, clusterName)
+			if err != nil {
+				return err
+			}
+			clusterName = resolved
+
+			// Create kubernetes client
+			kubeClient, err := getClusterKubeClient(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Listing container images...")
+			images, err := kubeClient.GetImageInventory(ctx)
+			if err != nil {
+				return err
+			}
+
+			// With JSON Lines, write each image once its ECR details are known
+			var stream *jsonlStream
+			if format == output.FormatJSONL {
+				if stream, err = newJSONLStream(); err != nil {
+					return err
+				}
+			}
+
+			inventory := make([]InventoryImage, 0, len(images))
+			for _, image := range images {
+				item := InventoryImage{
+					Image:      image.Image,
+					Digest:     image.Digest,
+					Containers: image.Containers,
+					Namespaces: image.Namespaces,
+				}
+				if ref, ok := aws.ParseECRImage(image.Image); ok {
+					detail, err := awsClient.DescribeECRImage(ctx, ref, image.Digest)
+					if err != nil {
+						item.ECRError = err.Error()
+					} else {
+						item.SizeBytes = detail.SizeBytes
+						item.PushedAt = &detail.PushedAt
+					}
+				}
+				if stream != nil {
+					if err := stream.write(item); err != nil {
+						return err
+					}
+					continue
+				}
+				inventory = append(inventory, item)
+			}
+
+			if stream != nil {
+				return stream.close()
+			}
+			if format != output.FormatText {
+				return printResult(format, inventory)
+			}
+
+			if len(inventory) == 0 {
+				logger.Info("No pods found")
+				return nil
+			}
+			logger.Success("Found %d distinct images:", len(inventory))
+			printImageInventory(inventory)
+			for _, item := range inventory {
+				if item.ECRError != "" {
+					logger.Warning("⚠️ %s", item.ECRError)
+				}
+			}
+			return saveResult(inventory)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, jsonl or yaml")
+	return cmd
+}
+
+func printImageInventory(inventory []InventoryImage) {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "IMAGE\tDIGEST\tCONTAINERS\tNAMESPACES\tSIZE\tPUSHED")
+	for _, item := range inventory {
+		digest := "-"
+		if item.Digest != "" {
+			digest = strings.TrimPrefix(item.Digest, "sha256:")
+			if len(digest) > 12 {
+				digest = digest[:12]
+			}
+		}
+		size, pushed := "-", "-"
+		if item.PushedAt != nil {
+			size = fmt.Sprintf("%.1fMiB", float64(item.SizeBytes)/(1<<20))
+			pushed = item.PushedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n",
+			item.Image, digest, item.Containers, strings.Join(item.Namespaces, ","), size, pushed)
+	}
+	w.Flush()
+}
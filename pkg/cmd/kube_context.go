@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+)
+
+// getClusterKubeClient creates the Kubernetes client for a command that names an EKS
+// cluster, by name, prefix or ARN, and checks that the client actually points at that
+// cluster
+func getClusterKubeClient(ctx context.Context, clusterName string) (*k8s.KubeClient, error) {
+	kubeClient, err := getKubeClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyKubeContext(ctx, kubeClient, clusterName); err != nil {
+		return nil, err
+	}
+	return kubeClient, nil
+}
+
+// connectToCluster writes the kubeconfig entry for an EKS cluster, reached with the
+// profile, role and region of access, and creates a client for its context. Right after a
+// cluster is created or the context is written, DescribeCluster and the endpoint can
+// briefly fail, so transient failures of the kubeconfig update and the first API call are
+// retried --connect-retries times with --connect-backoff. Retries and the client's later
+// log lines go to log. A private only endpoint that cannot be reached fails at once
+// instead of being retried.
+func connectToCluster(ctx context.Context, clusterName string, access aws.ClientConfig, opts k8s.KubeconfigOptions, log *logger.Logger) (*k8s.KubeClient, error) {
+	opts.Profile, opts.RoleARN = access.Profile, access.RoleARN
+	retry := k8s.RetryOptions{
+		Retries: connectRetries,
+		Backoff: connectBackoff,
+		OnRetry: func(attempt int, err error, wait time.Duration) {
+			log.Warning("Cluster %s is not ready (attempt %d/%d): %v; retrying in %s", clusterName, attempt, connectRetries+1, err, wait)
+		},
+	}
+
+	err := k8s.Retry(ctx, retry, func() error {
+		// Fleet runs update the same kubeconfig file from several goroutines
+		kubeconfigMu.Lock()
+		defer kubeconfigMu.Unlock()
+		return k8s.UpdateKubeconfig(ctx, clusterName, access.Region, opts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update kubeconfig: %w", err)
+	}
+
+	kubeClient, err := k8s.NewKubeClient(k8s.KubeClientConfig{
+		KubeConfig:           opts.Path,
+		Context:              clusterName,
+		DiagImage:            diagImage,
+		DiagImagePullSecrets: diagImagePullSecrets,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	kubeClient.Log = log
+	if err := checkClusterEndpoint(ctx, log, clusterName, access, kubeClient.Config.Host); err != nil {
+		return nil, err
+	}
+	if err := kubeClient.WaitReady(ctx, retry); err != nil {
+		return nil, err
+	}
+	kubeClient.MaxPodsLookup = ec2MaxPods(access)
+	return kubeClient, nil
+}
+
+// checkClusterEndpoint dials the API server of a cluster whose endpoint is private only
+// with checkPrivateEndpoint. When the cluster cannot be described the dial is skipped and
+// the first API call reports the problem.
+func checkClusterEndpoint(ctx context.Context, log *logger.Logger, clusterName string, access aws.ClientConfig, host string) error {
+	awsClient, err := newAWSClient(ctx, access)
+	if err != nil {
+		log.Debug("Not checking the API endpoint of cluster %s: %v", clusterName, err)
+		return nil
+	}
+	cluster, err := awsClient.DescribeCluster(ctx, clusterName)
+	if err != nil {
+		log.Debug("Not checking the API endpoint of cluster %s: %v", clusterName, err)
+		return nil
+	}
+	if vpcConfig := cluster.Cluster.ResourcesVpcConfig; vpcConfig != nil && !vpcConfig.EndpointPublicAccess {
+		return checkPrivateEndpoint(ctx, log, clusterName, host)
+	}
+	return nil
+}
+
+// ec2MaxPods returns a k8s.KubeClient MaxPodsLookup that reads the ENI limits of an
+// instance type from EC2 DescribeInstanceTypes. The AWS client is created on first use, so
+// clusters whose instance types are all in the built-in table make no EC2 calls.
+func ec2MaxPods(access aws.ClientConfig) func(ctx context.Context, instanceType string) (int64, error) {
+	var (
+		once      sync.Once
+		awsClient *aws.Client
+		clientErr error
+	)
+	return func(ctx context.Context, instanceType string) (int64, error) {
+		once.Do(func() {
+			awsClient, clientErr = newAWSClient(ctx, access)
+		})
+		if clientErr != nil {
+			return 0, clientErr
+		}
+		info, err := awsClient.GetInstanceTypeInfo(ctx, instanceType)
+		if err != nil {
+			return 0, err
+		}
+		if info.MaxENIs == 0 || info.IPsPerENI == 0 {
+			return 0, fmt.Errorf("EC2 reports no ENI limits for instance type %s", instanceType)
+		}
+		return info.MaxPods(), nil
+	}
+}
+
+// verifyKubeContext compares the API server of the Kubernetes client with the endpoint of
+// the named EKS cluster, whose name prefix or ARN is resolved with clusterClient. On a
+// mismatch the Kubernetes checks would inspect another cluster than the one named, which
+// is logged as a warning, or returned as an error with --strict-context. The check is
+// skipped when the cluster cannot be described, which --strict-context also treats as an
+// error. In-cluster clients reach the API server through the kubernetes Service address,
+// which never matches the endpoint, and are not checked.
+func verifyKubeContext(ctx context.Context, kubeClient *k8s.KubeClient, clusterName string) error {
+	if kubeClient.Config == nil || kubeClient.InCluster || clusterName == "" {
+		return nil
+	}
+
+	awsClient, resolved, err := clusterClient(ctx, clusterName)
+	if err == nil {
+		clusterName = resolved
+		var cluster *eks.DescribeClusterOutput
+		cluster, err = awsClient.DescribeCluster(ctx, clusterName)
+		if err == nil {
+			return compareKubeContext(ctx, kubeClient, clusterName, cluster)
+		}
+	}
+	if strictContext {
+		return fmt.Errorf("cannot verify kubeconfig context: %w", err)
+	}
+	logger.Debug("Not verifying the kubeconfig context: %v", err)
+	return nil
+}
+
+// compareKubeContext compares the API server of the Kubernetes client with the endpoint
+// of the described cluster for verifyKubeContext
+func compareKubeContext(ctx context.Context, kubeClient *k8s.KubeClient, clusterName string, cluster *eks.DescribeClusterOutput) error {
+	endpoint := awssdk.ToString(cluster.Cluster.Endpoint)
+	if sameHost(endpoint, kubeClient.Config.Host) {
+		vpcConfig := cluster.Cluster.ResourcesVpcConfig
+		if vpcConfig != nil && !vpcConfig.EndpointPublicAccess {
+			return checkPrivateEndpoint(ctx, logger.Default(), clusterName, kubeClient.Config.Host)
+		}
+		return nil
+	}
+
+	target := kubeClient.Config.Host
+	if kubeClient.Context != "" {
+		target = fmt.Sprintf("context %s (%s)", kubeClient.Context, kubeClient.Config.Host)
+	}
+	if strictContext {
+		return fmt.Errorf("cluster %s has endpoint %s but Kubernetes calls would go to %s", clusterName, endpoint, target)
+	}
+	logger.Warning("⚠️ Cluster %s has endpoint %s but Kubernetes calls go to %s", clusterName, endpoint, target)
+	logger.Warning("⚠️ Kubernetes results below are for that cluster; switch context or pass --strict-context to stop")
+	return nil
+}
+
+// checkPrivateEndpoint dials the API server of a cluster whose endpoint is private only.
+// Outside the cluster VPC the endpoint does not resolve or does not answer, and every
+// Kubernetes call would hang until it times out, so fail early with an explanation.
+// Connections through an HTTPS proxy are not tested.
+func checkPrivateEndpoint(ctx context.Context, log *logger.Logger, clusterName, host string) error {
+	if os.Getenv("HTTPS_PROXY") != "" || os.Getenv("https_proxy") != "" {
+		return nil
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	dialer := net.Dialer{Timeout: endpointTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		log.Warning("❌ Cluster %s only has a private API endpoint, which is not reachable from here: %v", clusterName, err)
+		log.Detail("- Run ekspeek from inside the cluster VPC, or connect through a VPN, Direct Connect or a bastion host")
+		log.Detail("- Or enable public endpoint access: aws eks update-cluster-config --name %s --resources-vpc-config endpointPublicAccess=true", clusterName)
+		return fmt.Errorf("private API endpoint of cluster %s is not reachable", clusterName)
+	}
+	conn.Close()
+	return nil
+}
+
+// sameHost returns true if both URLs have the same host name
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Hostname(), ub.Hostname())
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"ekspeek/pkg/k8s"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/client-go/rest"
+)
+
+func TestVerifyKubeContextInCluster(t *testing.T) {
+	defer func(strict bool) { strictContext = strict }(strictContext)
+	strictContext = true
+
+	// The in-cluster host is the kubernetes Service address, never the EKS endpoint, and
+	// the cluster must not be described
+	kubeClient := &k8s.KubeClient{
+		Config:    &rest.Config{Host: "https://10.100.0.1:443"},
+		InCluster: true,
+	}
+	if err := verifyKubeContext(context.Background(), kubeClient, "prod"); err != nil {
+		t.Errorf("verifyKubeContext() = %v for an in-cluster client, want nil", err)
+	}
+}
+
+func TestCompareKubeContextMismatch(t *testing.T) {
+	defer func(strict bool) { strictContext = strict }(strictContext)
+	strictContext = true
+
+	cluster := &eks.DescribeClusterOutput{Cluster: &ekstypes.Cluster{
+		Endpoint:           awssdk.String("https://ABCDEF.gr7.us-west-2.eks.amazonaws.com"),
+		ResourcesVpcConfig: &ekstypes.VpcConfigResponse{EndpointPublicAccess: true},
+	}}
+	kubeClient := &k8s.KubeClient{
+		Config:  &rest.Config{Host: "https://012345.yl4.us-east-1.eks.amazonaws.com"},
+		Context: "staging",
+	}
+	if err := compareKubeContext(context.Background(), kubeClient, "prod", cluster); err == nil {
+		t.Error("compareKubeContext() = nil for another cluster's endpoint with --strict-context, want an error")
+	}
+
+	kubeClient.Config.Host = "https://abcdef.gr7.us-west-2.eks.amazonaws.com"
+	if err := compareKubeContext(context.Background(), kubeClient, "prod", cluster); err != nil {
+		t.Errorf("compareKubeContext() = %v for the cluster endpoint, want nil", err)
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/output"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// stdout receives command output written outside the logger, such as tables and
+	// structured results
+	stdout io.Writer = os.Stdout
+	// redactor is set when --redact is given
+	redactor *output.Redactor
+)
+
+// enableRedaction routes the logger, command output and cobra's error output through a
+// redactor. Output is redacted by line; the end of an unfinished line is written when the
+// command finishes.
+func enableRedaction(root *cobra.Command) {
+	redactor = output.NewRedactor()
+	out := redactor.Writer(os.Stdout)
+	errOut := redactor.Writer(os.Stderr)
+	stdout = out
+	logger.SetOutput(out, errOut)
+	root.SetErr(errOut)
+	cobra.OnFinalize(func() {
+		out.Close()
+		errOut.Close()
+	})
+}
+
+// parseOutputFormat parses a command's -o value
+func parseOutputFormat(name string) (output.Format, error) {
+	return output.ParseFormat(name)
+}
+
+// resultFileFormat returns the format written to --output-file: the -o format when it is
+// structured, otherwise the format implied by the file extension, defaulting to JSON
+func resultFileFormat(format output.Format) output.Format {
+	if format != output.FormatText {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(outputFile)) {
+	case ".yaml", ".yml":
+		return output.FormatYAML
+	case ".sarif":
+		return output.FormatSARIF
+	case ".jsonl":
+		return output.FormatJSONL
+	default:
+		return output.FormatJSON
+	}
+}
+
+// printResult writes a command's structured result to stdout, or to --output-file when
+// set. JSON and YAML results are wrapped with the run metadata.
+func printResult(format output.Format, v interface{}) error {
+	if outputFile == "" {
+		return output.Write(stdout, format, withMetadata(format, v))
+	}
+	return writeResultFile(format, v)
+}
+
+// saveResult persists the result of a command rendered as text to --output-file, in the
+// format given by the file extension. It does nothing when --output-file is not set, so
+// text output stays on the terminal and the structured result is saved in the same run.
+func saveResult(v interface{}) error {
+	if outputFile == "" {
+		return nil
+	}
+	return writeResultFile(resultFileFormat(output.FormatText), v)
+}
+
+// writeResultFile writes v to --output-file in format, creating the file's parent
+// directories
+func writeResultFile(format output.Format, v interface{}) error {
+	w, err := createOutputFile()
+	if err != nil {
+		return err
+	}
+	if err := output.Write(w, format, withMetadata(format, v)); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	logger.Success("Wrote %s output to %s", format, outputFile)
+	return nil
+}
+
+// jsonlStream writes results as JSON Lines while they are produced, to stdout or
+// --output-file, so nothing is buffered until the end of the run
+type jsonlStream struct {
+	w    io.Writer
+	file io.Closer // Set when writing to --output-file
+}
+
+// newJSONLStream starts a JSON Lines stream
+func newJSONLStream() (*jsonlStream, error) {
+	if outputFile == "" {
+		return &jsonlStream{w: stdout}, nil
+	}
+	w, err := createOutputFile()
+	if err != nil {
+		return nil, err
+	}
+	return &jsonlStream{w: w, file: w}, nil
+}
+
+// write writes v on its own line, or each element on its own line if v is a slice
+func (s *jsonlStream) write(v interface{}) error {
+	return output.Write(s.w, output.FormatJSONL, v)
+}
+
+// close finishes the stream, closing --output-file when it was written
+func (s *jsonlStream) close() error {
+	if s.file == nil {
+		return nil
+	}
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	logger.Success("Wrote %s output to %s", output.FormatJSONL, outputFile)
+	return nil
+}
+
+// createOutputFile creates --output-file and its parent directories, returning the writer
+// to write results to, which redacts them with --redact. Closing it closes the file.
+func createOutputFile() (io.WriteCloser, error) {
+	if dir := filepath.Dir(outputFile); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	if redactor == nil {
+		return f, nil
+	}
+	return redactedFile{WriteCloser: redactor.Writer(f), file: f}, nil
+}
+
+// redactedFile is --output-file written through a redactor
+type redactedFile struct {
+	io.WriteCloser
+	file *os.File
+}
+
+// Close writes the rest of the redacted output and closes the file
+func (r redactedFile) Close() error {
+	if err := r.WriteCloser.Close(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
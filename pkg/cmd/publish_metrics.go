@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ekspeek/pkg/aws"
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// publishHealthMetrics publishes the health check result as CloudWatch custom metrics:
+// FailedCheckCount, IssueCount and CriticalIssueCount, and CertExpiryDays for the API
+// server certificate when it can be read. The issue counts are left out of a partial
+// report, where a drop in them may only mean a check failed.
+func publishHealthMetrics(ctx context.Context, namespace, clusterName string, kubeClient *k8s.KubeClient, status *k8s.ClusterHealthStatus) error {
+	awsClient, err := newAWSClient(ctx, clusterAWSConfig(clusterName))
+	if err != nil {
+		return fmt.Errorf("failed to create AWS client: %w", err)
+	}
+
+	metrics := []aws.CustomMetric{
+		{Name: "FailedCheckCount", Value: float64(len(status.Errors)), Unit: cloudwatchtypes.StandardUnitCount},
+	}
+	if len(status.Errors) > 0 {
+		logger.Warning("Not publishing IssueCount and CriticalIssueCount: %d checks failed to collect", len(status.Errors))
+	} else {
+		warningIssues, criticalIssues := countHealthIssues(status)
+		metrics = append(metrics,
+			aws.CustomMetric{Name: "IssueCount", Value: float64(warningIssues + criticalIssues), Unit: cloudwatchtypes.StandardUnitCount},
+			aws.CustomMetric{Name: "CriticalIssueCount", Value: float64(criticalIssues), Unit: cloudwatchtypes.StandardUnitCount},
+		)
+	}
+
+	cert, err := kubeClient.GetAPIServerCertificate(ctx)
+	if err != nil {
+		logger.Debug("Not publishing CertExpiryDays: %v", err)
+	} else {
+		days := time.Until(cert.NotAfter).Hours() / 24
+		metrics = append(metrics, aws.CustomMetric{Name: "CertExpiryDays", Value: days, Unit: cloudwatchtypes.StandardUnitNone})
+	}
+
+	if err := awsClient.PublishClusterMetrics(ctx, namespace, clusterName, metrics); err != nil {
+		return err
+	}
+	logger.Info("Published %d metrics to CloudWatch namespace %s", len(metrics), namespace)
+	return nil
+}
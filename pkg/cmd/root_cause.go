@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/k8s"
+)
+
+// RootCause is a likely cause connecting several health findings
+type RootCause struct {
+	Name        string
+	Evidence    []string // The signals the cause was derived from
+	Remediation string
+}
+
+// rootCauseRule maps a combination of signals to a named cause. Signals are the IDs of
+// the health findings plus the derived signals of healthSignals. A rule matches when all
+// signals in All and, if Any is set, at least one signal in Any are present.
+type rootCauseRule struct {
+	Name        string
+	All         []string
+	Any         []string
+	Remediation string
+}
+
+// rootCauseRules are checked in order; add new correlations here
+var rootCauseRules = []rootCauseRule{
+	{
+		Name:        "VPC CNI IP address exhaustion",
+		All:         []string{"pod_pending", "node_pod_capacity"},
+		Remediation: "Nodes have run out of pod IPs: enable prefix delegation (ENABLE_PREFIX_DELEGATION on aws-node), use larger instance types or add subnets with free IPs; see \"ekspeek debug cni-config\"",
+	},
+	{
+		Name:        "VPC CNI (aws-node) failure",
+		All:         []string{"aws_node_not_ready"},
+		Any:         []string{"pod_pending", "node_not_ready"},
+		Remediation: "Pods get no IPs while aws-node is down: check the aws-node logs, the CNI IAM role (AmazonEKS_CNI_Policy) and that the add-on version supports the cluster version",
+	},
+	{
+		Name:        "Cluster out of CPU or memory",
+		All:         []string{"pod_pending", "pending_insufficient_resources"},
+		Remediation: "Add capacity: check that the Cluster Autoscaler or Karpenter is running and that node group maximums and instance quotas allow scaling out; see \"ekspeek debug autoscaler\"",
+	},
+	{
+		Name:        "Cluster DNS outage",
+		All:         []string{"coredns_not_ready"},
+		Remediation: "Service discovery fails while CoreDNS is down: check the CoreDNS pods and logs, and that they can be scheduled and reach the API server; see \"ekspeek debug coredns\"",
+	},
+	{
+		Name:        "Unstable nodes",
+		All:         []string{"node_not_ready", "node_flapping"},
+		Remediation: "Nodes keep losing their kubelet heartbeat: check kubelet and container runtime logs, memory and disk pressure, and kubelet certificate rotation",
+	},
+	{
+		Name:        "Kubelet certificate expiry",
+		All:         []string{"node_not_ready", "kubelet_certificate"},
+		Remediation: "The kubelet cannot renew its client certificate, so it stops posting node status: approve the pending kubelet CSRs, check that the node IAM role is still mapped (aws-auth or access entries) and that node clocks are in sync, or replace the nodes",
+	},
+	{
+		Name:        "Incomplete cluster upgrade",
+		All:         []string{"node_version_mismatch", "addon_version_mismatch"},
+		Remediation: "Finish the upgrade: roll the node groups to the control plane version and update the CoreDNS, kube-proxy and VPC CNI add-ons",
+	},
+	{
+		Name:        "Node clock skew breaking IRSA",
+		All:         []string{"node_clock_skew", "irsa"},
+		Remediation: "STS rejects web identity tokens and signed requests from nodes with a skewed clock: fix time sync (chronyd) on the skewed nodes",
+	},
+	{
+		Name:        "Host port exhaustion",
+		All:         []string{"host_port_unavailable", "pod_pending"},
+		Remediation: "Pending pods need a host port taken on every candidate node: add nodes, free the port, or replace hostPort with a Service",
+	},
+}
+
+// diagnose correlates the findings of a health check into likely root causes
+func diagnose(status *k8s.ClusterHealthStatus) []RootCause {
+	signals := healthSignals(status)
+
+	var causes []RootCause
+	for _, rule := range rootCauseRules {
+		var evidence []string
+		matched := true
+		for _, signal := range rule.All {
+			description, ok := signals[signal]
+			if !ok {
+				matched = false
+				break
+			}
+			evidence = append(evidence, description)
+		}
+		if !matched {
+			continue
+		}
+		if len(rule.Any) > 0 {
+			matched = false
+			for _, signal := range rule.Any {
+				if description, ok := signals[signal]; ok {
+					evidence = append(evidence, description)
+					matched = true
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		causes = append(causes, RootCause{
+			Name:        rule.Name,
+			Evidence:    evidence,
+			Remediation: rule.Remediation,
+		})
+	}
+	return causes
+}
+
+// healthSignals returns the signals present in a health check result, each with a short
+// description for the evidence of a root cause
+func healthSignals(status *k8s.ClusterHealthStatus) map[string]string {
+	counts := make(map[string]int)
+	for _, f := range status.Findings {
+		if f.IsIssue() {
+			counts[f.ID]++
+		}
+	}
+	signals := make(map[string]string, len(counts))
+	for id, n := range counts {
+		signals[id] = fmt.Sprintf("%d %s", n, id)
+	}
+
+	if notReady := notReadyPods(status.NetworkingStatus.CNIStatus); notReady > 0 {
+		signals["aws_node_not_ready"] = fmt.Sprintf("%d aws-node pods not ready", notReady)
+	}
+	if status.RanCheck("networking") {
+		if len(status.NetworkingStatus.CoreDNSStatus) == 0 {
+			signals["coredns_not_ready"] = "no CoreDNS pods"
+		} else if notReady := notReadyPods(status.NetworkingStatus.CoreDNSStatus); notReady == len(status.NetworkingStatus.CoreDNSStatus) {
+			signals["coredns_not_ready"] = fmt.Sprintf("all %d CoreDNS pods not ready", notReady)
+		}
+	}
+
+	var insufficient int
+	for _, pod := range status.SchedulingStatus.PendingPods {
+		if strings.Contains(pod.Reason, "Insufficient cpu") || strings.Contains(pod.Reason, "Insufficient memory") {
+			insufficient++
+		}
+	}
+	if insufficient > 0 {
+		signals["pending_insufficient_resources"] = fmt.Sprintf("%d pods pending on insufficient CPU or memory", insufficient)
+	}
+
+	return signals
+}
+
+// notReadyPods counts the pods that are not running with all containers ready
+func notReadyPods(pods []k8s.PodStatus) int {
+	var count int
+	for _, pod := range pods {
+		ready := pod.Status == "Running"
+		for _, c := range pod.Containers {
+			ready = ready && c.Ready
+		}
+		if !ready {
+			count++
+		}
+	}
+	return count
+}
+
+// printRootCauses prints the likely root causes of a health check result
+func printRootCauses(causes []RootCause) {
+	if len(causes) == 0 {
+		return
+	}
+	logger.Warning("\nLikely root causes:")
+	for _, cause := range causes {
+		logger.Warning("  🔎 %s", cause.Name)
+		logger.Detail("     Evidence: %s", strings.Join(cause.Evidence, ", "))
+		logger.Detail("     → %s", cause.Remediation)
+	}
+}
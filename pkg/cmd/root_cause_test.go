@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"ekspeek/pkg/findings"
+	"ekspeek/pkg/k8s"
+)
+
+func TestDiagnose(t *testing.T) {
+	issue := func(id string) findings.Finding {
+		return findings.Finding{ID: id, Severity: findings.SeverityWarning}
+	}
+	notReady := k8s.PodStatus{Name: "aws-node-1", Status: "Running", Containers: []k8s.ContainerStatus{{Name: "aws-node", Ready: false}}}
+
+	tests := []struct {
+		name   string
+		status *k8s.ClusterHealthStatus
+		want   []string
+	}{
+		{
+			name:   "healthy",
+			status: &k8s.ClusterHealthStatus{},
+		},
+		{
+			name: "ip exhaustion",
+			status: &k8s.ClusterHealthStatus{Findings: []findings.Finding{
+				issue("pod_pending"), issue("node_pod_capacity"),
+			}},
+			want: []string{"VPC CNI IP address exhaustion"},
+		},
+		{
+			name: "kubelet certificate expiry",
+			status: &k8s.ClusterHealthStatus{Findings: []findings.Finding{
+				issue("node_not_ready"), issue("kubelet_certificate"),
+			}},
+			want: []string{"Kubelet certificate expiry"},
+		},
+		{
+			name: "not ready node without certificate problems",
+			status: &k8s.ClusterHealthStatus{Findings: []findings.Finding{
+				issue("node_not_ready"),
+			}},
+		},
+		{
+			name: "informational findings are no signals",
+			status: &k8s.ClusterHealthStatus{Findings: []findings.Finding{
+				issue("pod_pending"), {ID: "node_pod_capacity", Severity: findings.SeverityInfo},
+			}},
+		},
+		{
+			name: "aws-node failure needs one of its symptoms",
+			status: &k8s.ClusterHealthStatus{
+				NetworkingStatus: k8s.NetworkingStatus{CNIStatus: []k8s.PodStatus{notReady}},
+			},
+		},
+		{
+			name: "aws-node failure with pending pods",
+			status: &k8s.ClusterHealthStatus{
+				Findings:         []findings.Finding{issue("pod_pending")},
+				NetworkingStatus: k8s.NetworkingStatus{CNIStatus: []k8s.PodStatus{notReady}},
+			},
+			want: []string{"VPC CNI (aws-node) failure"},
+		},
+		{
+			name: "pending on insufficient resources",
+			status: &k8s.ClusterHealthStatus{
+				Findings: []findings.Finding{issue("pod_pending")},
+				SchedulingStatus: k8s.SchedulingStatus{PendingPods: []k8s.PodSchedulingIssue{
+					{Pod: "web", Namespace: "shop", Reason: "0/3 nodes are available: 3 Insufficient cpu."},
+				}},
+			},
+			want: []string{"Cluster out of CPU or memory"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, cause := range diagnose(tt.status) {
+				got = append(got, cause.Name)
+				if len(cause.Evidence) == 0 || cause.Remediation == "" {
+					t.Errorf("cause %+v lacks evidence or remediation", cause)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diagnose() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthSignals(t *testing.T) {
+	ready := k8s.PodStatus{Status: "Running", Containers: []k8s.ContainerStatus{{Ready: true}}}
+	pending := k8s.PodStatus{Status: "Pending"}
+
+	tests := []struct {
+		name   string
+		status *k8s.ClusterHealthStatus
+		want   map[string]string
+	}{
+		{
+			name: "finding counts",
+			status: &k8s.ClusterHealthStatus{Findings: []findings.Finding{
+				{ID: "pod_pending", Severity: findings.SeverityWarning},
+				{ID: "pod_pending", Severity: findings.SeverityCritical},
+				{ID: "irsa", Severity: findings.SeverityInfo},
+			}},
+			want: map[string]string{"pod_pending": "2 pod_pending"},
+		},
+		{
+			name: "coredns down",
+			status: &k8s.ClusterHealthStatus{
+				ChecksRun:        []string{"networking"},
+				NetworkingStatus: k8s.NetworkingStatus{CoreDNSStatus: []k8s.PodStatus{pending, pending}},
+			},
+			want: map[string]string{"coredns_not_ready": "all 2 CoreDNS pods not ready"},
+		},
+		{
+			name: "coredns partly ready",
+			status: &k8s.ClusterHealthStatus{
+				ChecksRun:        []string{"networking"},
+				NetworkingStatus: k8s.NetworkingStatus{CoreDNSStatus: []k8s.PodStatus{ready, pending}},
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "coredns missing",
+			status: &k8s.ClusterHealthStatus{
+				ChecksRun: []string{"networking"},
+			},
+			want: map[string]string{"coredns_not_ready": "no CoreDNS pods"},
+		},
+		{
+			name:   "networking not checked",
+			status: &k8s.ClusterHealthStatus{},
+			want:   map[string]string{},
+		},
+		{
+			name: "aws-node not ready",
+			status: &k8s.ClusterHealthStatus{
+				NetworkingStatus: k8s.NetworkingStatus{CNIStatus: []k8s.PodStatus{ready, pending}},
+			},
+			want: map[string]string{"aws_node_not_ready": "1 aws-node pods not ready"},
+		},
+		{
+			name: "pending on memory",
+			status: &k8s.ClusterHealthStatus{
+				SchedulingStatus: k8s.SchedulingStatus{PendingPods: []k8s.PodSchedulingIssue{
+					{Reason: "0/2 nodes are available: 2 Insufficient memory."},
+					{Reason: "0/2 nodes are available: 2 node(s) had untolerated taint."},
+				}},
+			},
+			want: map[string]string{"pending_insufficient_resources": "1 pods pending on insufficient CPU or memory"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := healthSignals(tt.status); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("healthSignals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,18 +1,76 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"time"
+
+	"ekspeek/pkg/findings"
+
+	"github.com/spf13/cobra"
+)
 
 // Variables used across commands
 var (
-	profile     string
-	region      string
+	profile string
+	region  string
+	// roleARN is the IAM role assumed with the profile's credentials
+	roleARN string
+	// configPath is the config file mapping cluster names to profiles, roles and regions
+	configPath  string
 	debug       bool
+	quiet       bool
 	clusterName string
+	inCluster   bool
+	outputFile  string
+	redact      bool
+	fromDump    string
+	// includeMetadata wraps JSON and YAML results with the caller identity of the run, on by
+	// default
+	includeMetadata bool
+	// strictContext fails commands whose kubeconfig context is not the named cluster
+	strictContext bool
+	// endpointTimeout bounds the reachability test of private-only API endpoints
+	endpointTimeout time.Duration
+	// connectRetries and connectBackoff bound the retries of the kubeconfig update and
+	// first API call, which can fail briefly right after a cluster is created
+	connectRetries int
+	connectBackoff time.Duration
+	// forceDegraded lets debug commands run against a DEGRADED cluster
+	forceDegraded bool
+	// clusterARN identifies the cluster when commands get no cluster argument;
+	// arnClusterName and arnRegion are the cluster name and region parsed from it
+	clusterARN     string
+	arnClusterName string
+	arnRegion      string
+	// diagImage and diagImagePullSecrets override the image of the diagnostic test pods
+	diagImage            string
+	diagImagePullSecrets []string
+
+	// onlySeverities holds the raw --only values; onlyFilter is the parsed form
+	onlySeverities []string
+	onlyFilter     []findings.Severity
 )
 
 // AddGlobalFlags adds global flags to the root command
 func AddGlobalFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "AWS profile to use")
-	rootCmd.PersistentFlags().StringVar(&region, "region", "us-west-2", "AWS region to use")
+	rootCmd.PersistentFlags().StringVar(&region, "region", "", "AWS region to use")
+	rootCmd.PersistentFlags().StringVar(&roleARN, "role-arn", "", "IAM role to assume with the profile's credentials")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file mapping cluster names to a profile, role and region (env EKSPEEK_CONFIG, default ~/.ekspeek.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Enable debug logging")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print warnings and errors")
+	rootCmd.PersistentFlags().BoolVar(&inCluster, "in-cluster", false, "Use the in-cluster service account instead of kubeconfig")
+	rootCmd.PersistentFlags().StringVar(&outputFile, "output-file", "", "Write the structured result to this file: the -o format, or with text output the format of the file extension (.json, .jsonl, .yaml, .sarif)")
+	rootCmd.PersistentFlags().BoolVar(&redact, "redact", false, "Replace account IDs, private IP addresses and tokens in all output with placeholders")
+	rootCmd.PersistentFlags().BoolVar(&includeMetadata, "with-metadata", true, "Wrap JSON and YAML results as {metadata, result}, with the AWS account and caller ARN in metadata; --with-metadata=false writes the bare result")
+	rootCmd.PersistentFlags().StringVar(&fromDump, "from-dump", "", "Run Kubernetes checks against a directory of \"kubectl get -o yaml\" dumps instead of a live cluster")
+	rootCmd.PersistentFlags().BoolVar(&strictContext, "strict-context", false, "Fail instead of warning when the kubeconfig context does not point at the named cluster")
+	rootCmd.PersistentFlags().DurationVar(&endpointTimeout, "endpoint-timeout", 5*time.Second, "Timeout for reaching the API server of a cluster with only a private endpoint")
+	rootCmd.PersistentFlags().IntVar(&connectRetries, "connect-retries", 3, "Retries of the kubeconfig update and first API call when a cluster is not ready yet")
+	rootCmd.PersistentFlags().DurationVar(&connectBackoff, "connect-backoff", 2*time.Second, "Wait before the first connection retry, doubled for each further retry")
+	rootCmd.PersistentFlags().StringVar(&clusterARN, "cluster-arn", "", "EKS cluster ARN to use, with its region, when no cluster name is given (env EKSPEEK_CLUSTER_ARN)")
+	rootCmd.PersistentFlags().StringSliceVar(&onlySeverities, "only", nil, "Only report findings with these severities (critical,warning,info,pass)")
+	rootCmd.PersistentFlags().StringVar(&diagImage, "diag-image", "", "Image of the diagnostic test pods, for clusters that only pull from a private registry (default busybox; diagImage in the config file)")
+	rootCmd.PersistentFlags().StringSliceVar(&diagImagePullSecrets, "diag-image-pull-secrets", nil, "Image pull secrets of the diagnostic test pods, in the pod's namespace (diagImagePullSecrets in the config file)")
+	// --debug lowers the level below info, which would undo --quiet
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "debug")
 }
@@ -2,54 +2,181 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
 )
 
+// Level represents the minimum severity of messages that are printed
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
 var (
 	debugMode    bool
+	level        = LevelInfo
 	infoColor    = color.New(color.FgCyan)
 	successColor = color.New(color.FgGreen)
 	warningColor = color.New(color.FgYellow)
 	errorColor   = color.New(color.FgRed)
+
+	// mu serializes writes so lines from concurrent checks never interleave
+	mu  sync.Mutex
+	std = &Logger{lastVisible: true}
+
+	// stdout receives Plain and Detail lines, stderr leveled messages
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
 )
 
+// Logger writes log lines tagged with an optional prefix, such as a cluster or check name
+type Logger struct {
+	prefix      string
+	lastVisible bool
+}
+
+// WithPrefix returns a logger that tags every line with the prefix, so output from
+// parallel checks stays attributable
+func WithPrefix(prefix string) *Logger {
+	return &Logger{prefix: "[" + prefix + "] ", lastVisible: true}
+}
+
+// Default returns the logger behind the package-level functions
+func Default() *Logger {
+	return std
+}
+
 // SetDebugMode enables or disables debug logging
 func SetDebugMode(enabled bool) {
 	debugMode = enabled
+	if enabled && level > LevelDebug {
+		level = LevelDebug
+	}
+}
+
+// SetLevel sets the minimum level of messages that are printed
+func SetLevel(l Level) {
+	level = l
+}
+
+// SetOutput replaces the writers used for stdout and stderr output, e.g. to redact it
+func SetOutput(out, errOut io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	stdout = out
+	stderr = errOut
+}
+
+// SetQuiet suppresses Info and Success messages so only warnings and errors are printed
+func SetQuiet(enabled bool) {
+	if enabled {
+		level = LevelWarn
+	} else {
+		level = LevelInfo
+	}
 }
 
 // Info prints an info message
 func Info(format string, a ...interface{}) {
-	logMessage(infoColor, "INFO", format, a...)
+	std.Info(format, a...)
 }
 
 // Success prints a success message
 func Success(format string, a ...interface{}) {
-	logMessage(successColor, "SUCCESS", format, a...)
+	std.Success(format, a...)
 }
 
 // Warning prints a warning message
 func Warning(format string, a ...interface{}) {
-	logMessage(warningColor, "WARNING", format, a...)
+	std.Warning(format, a...)
 }
 
 // Error prints an error message
 func Error(format string, a ...interface{}) {
-	logMessage(errorColor, "ERROR", format, a...)
+	std.Error(format, a...)
 }
 
 // Debug prints a debug message if debug mode is enabled
 func Debug(format string, a ...interface{}) {
+	std.Debug(format, a...)
+}
+
+// Detail prints a detail line to stdout without a level prefix. Detail lines belong to the
+// most recently logged message, so they are only printed if that message was printed.
+func Detail(format string, a ...interface{}) {
+	std.Detail(format, a...)
+}
+
+// Plain prints a line to stdout regardless of the log level. It is used for a command's
+// primary output, such as section bodies and reports, which must never be suppressed.
+func Plain(format string, a ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if activeSpinner != nil {
+		clearLine()
+	}
+	fmt.Fprintf(stdout, format+"\n", a...)
+}
+
+// Info prints an info message
+func (l *Logger) Info(format string, a ...interface{}) {
+	l.logMessage(LevelInfo, infoColor, "INFO", format, a...)
+}
+
+// Success prints a success message
+func (l *Logger) Success(format string, a ...interface{}) {
+	l.logMessage(LevelInfo, successColor, "SUCCESS", format, a...)
+}
+
+// Warning prints a warning message
+func (l *Logger) Warning(format string, a ...interface{}) {
+	l.logMessage(LevelWarn, warningColor, "WARNING", format, a...)
+}
+
+// Error prints an error message
+func (l *Logger) Error(format string, a ...interface{}) {
+	l.logMessage(LevelError, errorColor, "ERROR", format, a...)
+}
+
+// Debug prints a debug message if debug mode is enabled
+func (l *Logger) Debug(format string, a ...interface{}) {
 	if debugMode {
-		logMessage(infoColor, "DEBUG", format, a...)
+		l.logMessage(LevelDebug, infoColor, "DEBUG", format, a...)
 	}
 }
 
-func logMessage(c *color.Color, level, format string, a ...interface{}) {
+// Detail prints a detail line belonging to the logger's most recent message
+func (l *Logger) Detail(format string, a ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !l.lastVisible {
+		return
+	}
+	if activeSpinner != nil {
+		clearLine()
+	}
+	fmt.Fprintf(stdout, l.prefix+format+"\n", a...)
+}
+
+func (l *Logger) logMessage(lvl Level, c *color.Color, levelName, format string, a ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	l.lastVisible = lvl >= level
+	if !l.lastVisible {
+		return
+	}
+	if activeSpinner != nil {
+		clearLine()
+	}
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
 	message := fmt.Sprintf(format, a...)
-	fmt.Fprintf(os.Stderr, "[%s] %s: %s\n", timestamp, c.Sprint(level), message)
+	fmt.Fprintf(stderr, "[%s] %s: %s%s\n", timestamp, c.Sprint(levelName), l.prefix, message)
 }
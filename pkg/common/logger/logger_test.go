@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPrefixedLoggersConcurrently(t *testing.T) {
+	var out, errOut bytes.Buffer
+	SetOutput(&out, &errOut)
+	defer SetOutput(os.Stdout, os.Stderr)
+	defer SetLevel(level)
+	SetLevel(LevelWarn)
+
+	// A running spinner clears and redraws its line between the log lines
+	s := &Spinner{message: "checking", stop: make(chan struct{}), done: make(chan struct{})}
+	mu.Lock()
+	activeSpinner = s
+	mu.Unlock()
+	go s.run()
+
+	const workers, lines = 8, 50
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			log := WithPrefix(fmt.Sprintf("cluster-%d", w))
+			for i := 0; i < lines; i++ {
+				log.Info("hidden")
+				log.Detail("hidden detail")
+				log.Warning("shown")
+				log.Detail("shown detail")
+			}
+		}(w)
+	}
+	wg.Wait()
+	s.Stop()
+
+	if strings.Contains(out.String(), "hidden detail") {
+		t.Error("a detail line of a suppressed message was printed")
+	}
+	for w := 0; w < workers; w++ {
+		detail := fmt.Sprintf("[cluster-%d] shown detail\n", w)
+		if got := strings.Count(out.String(), detail); got != lines {
+			t.Errorf("%q printed %d times, want %d", detail, got, lines)
+		}
+		warning := fmt.Sprintf("[cluster-%d] shown\n", w)
+		if got := strings.Count(errOut.String(), warning); got != lines {
+			t.Errorf("%q printed %d times, want %d", warning, got, lines)
+		}
+	}
+}
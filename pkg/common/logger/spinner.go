@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// activeSpinner is the running spinner, if any. Log lines clear its status line before
+// printing and it is redrawn on the next tick. Guarded by mu.
+var activeSpinner *Spinner
+
+// Spinner shows an animated status line on stderr while a long operation runs
+type Spinner struct {
+	message string
+	frame   int
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// ProgressEnabled returns true if progress output should be shown: info messages are not
+// suppressed with --quiet and stderr is an interactive terminal
+func ProgressEnabled() bool {
+	if level > LevelInfo {
+		return false
+	}
+	fi, err := os.Stderr.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// StartSpinner starts a spinner with the given status message. It returns nil when
+// progress output is disabled; the methods of a nil Spinner do nothing.
+func StartSpinner(message string) *Spinner {
+	if !ProgressEnabled() {
+		return nil
+	}
+
+	s := &Spinner{
+		message: message,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	mu.Lock()
+	activeSpinner = s
+	s.draw()
+	mu.Unlock()
+
+	go s.run()
+	return s
+}
+
+// Update replaces the spinner's status message
+func (s *Spinner) Update(format string, a ...interface{}) {
+	if s == nil {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	s.message = fmt.Sprintf(format, a...)
+	s.draw()
+}
+
+// Stop stops the spinner and clears its status line
+func (s *Spinner) Stop() {
+	if s == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+
+	mu.Lock()
+	defer mu.Unlock()
+	activeSpinner = nil
+	clearLine()
+}
+
+func (s *Spinner) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			mu.Lock()
+			s.frame = (s.frame + 1) % len(spinnerFrames)
+			s.draw()
+			mu.Unlock()
+		}
+	}
+}
+
+// draw redraws the status line; the caller must hold mu
+func (s *Spinner) draw() {
+	clearLine()
+	fmt.Fprintf(stderr, "%s %s", infoColor.Sprint(spinnerFrames[s.frame]), s.message)
+}
+
+// clearLine erases the current terminal line on stderr
+func clearLine() {
+	fmt.Fprint(stderr, "\r\033[K")
+}
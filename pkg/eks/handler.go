@@ -3,50 +3,118 @@ package eks
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+
+	awsclient "ekspeek/pkg/aws"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
 )
 
-// Handler handles EKS-related operations
+// Handler handles EKS-related operations. Its calls go through the AWS client, so expired
+// credentials are refreshed and access denied errors name the missing permission.
 type Handler struct {
+	aws    *awsclient.Client
 	client *eks.Client
+
+	// clusters caches DescribeCluster results for the lifetime of the handler
+	clustersMu sync.Mutex
+	clusters   map[string]*types.Cluster
 }
 
-// NewHandler creates a new EKS handler
-func NewHandler(client *eks.Client) *Handler {
-	return &Handler{client: client}
+// NewHandler creates a new EKS handler using the EKS client of client
+func NewHandler(client *awsclient.Client) *Handler {
+	return &Handler{aws: client, client: client.EKSClient, clusters: make(map[string]*types.Cluster)}
 }
 
 // ListClusters returns a list of all EKS clusters in the region
 func (h *Handler) ListClusters(ctx context.Context) ([]string, error) {
-	input := &eks.ListClustersInput{}
-	result, err := h.client.ListClusters(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	var clusters []string
+	paginator := eks.NewListClustersPaginator(h.client, &eks.ListClustersInput{})
+	for paginator.HasMorePages() {
+		var page *eks.ListClustersOutput
+		err := h.aws.Call(ctx, func() (err error) {
+			page, err = paginator.NextPage(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list clusters: %w", err)
+		}
+		clusters = append(clusters, page.Clusters...)
 	}
-	return result.Clusters, nil
+	return clusters, nil
 }
 
 // DescribeCluster returns detailed information about a specific cluster
 func (h *Handler) DescribeCluster(ctx context.Context, clusterName string) (*types.Cluster, error) {
+	h.clustersMu.Lock()
+	cluster, ok := h.clusters[clusterName]
+	h.clustersMu.Unlock()
+	if ok {
+		return cluster, nil
+	}
+
 	input := &eks.DescribeClusterInput{
 		Name: aws.String(clusterName),
 	}
-	result, err := h.client.DescribeCluster(ctx, input)
+	var result *eks.DescribeClusterOutput
+	err := h.aws.Call(ctx, func() (err error) {
+		result, err = h.client.DescribeCluster(ctx, input)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe cluster %s: %w", clusterName, err)
 	}
+
+	h.clustersMu.Lock()
+	h.clusters[clusterName] = result.Cluster
+	h.clustersMu.Unlock()
 	return result.Cluster, nil
 }
 
+// DescribeClusters describes the clusters concurrently, with at most concurrency calls in
+// flight, and returns them in the order of clusterNames
+func (h *Handler) DescribeClusters(ctx context.Context, clusterNames []string, concurrency int) ([]*types.Cluster, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	clusters := make([]*types.Cluster, len(clusterNames))
+	errs := make([]error, len(clusterNames))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, name := range clusterNames {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			clusters[i], errs[i] = h.DescribeCluster(ctx, name)
+		}(i, name)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return clusters, nil
+}
+
 // ListNodegroups returns a list of all nodegroups in a cluster
 func (h *Handler) ListNodegroups(ctx context.Context, clusterName string) ([]string, error) {
 	input := &eks.ListNodegroupsInput{
 		ClusterName: aws.String(clusterName),
 	}
-	result, err := h.client.ListNodegroups(ctx, input)
+	var result *eks.ListNodegroupsOutput
+	err := h.aws.Call(ctx, func() (err error) {
+		result, err = h.client.ListNodegroups(ctx, input)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list nodegroups for cluster %s: %w", clusterName, err)
 	}
@@ -59,7 +127,11 @@ func (h *Handler) DescribeNodegroup(ctx context.Context, clusterName, nodegroupN
 		ClusterName:   aws.String(clusterName),
 		NodegroupName: aws.String(nodegroupName),
 	}
-	result, err := h.client.DescribeNodegroup(ctx, input)
+	var result *eks.DescribeNodegroupOutput
+	err := h.aws.Call(ctx, func() (err error) {
+		result, err = h.client.DescribeNodegroup(ctx, input)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe nodegroup %s in cluster %s: %w", nodegroupName, clusterName, err)
 	}
@@ -74,3 +146,64 @@ func (h *Handler) GetNodegroupScaling(ctx context.Context, clusterName, nodegrou
 	}
 	return nodegroup.ScalingConfig, nil
 }
+
+// ListNodegroupUpdates returns the IDs of all updates of a nodegroup
+func (h *Handler) ListNodegroupUpdates(ctx context.Context, clusterName, nodegroupName string) ([]string, error) {
+	var ids []string
+	paginator := eks.NewListUpdatesPaginator(h.client, &eks.ListUpdatesInput{
+		Name:          aws.String(clusterName),
+		NodegroupName: aws.String(nodegroupName),
+	})
+	for paginator.HasMorePages() {
+		var page *eks.ListUpdatesOutput
+		err := h.aws.Call(ctx, func() (err error) {
+			page, err = paginator.NextPage(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list updates for nodegroup %s in cluster %s: %w", nodegroupName, clusterName, err)
+		}
+		ids = append(ids, page.UpdateIds...)
+	}
+	return ids, nil
+}
+
+// DescribeNodegroupUpdate returns the type, status, parameters and errors of a nodegroup update
+func (h *Handler) DescribeNodegroupUpdate(ctx context.Context, clusterName, nodegroupName, updateID string) (*types.Update, error) {
+	input := &eks.DescribeUpdateInput{
+		Name:          aws.String(clusterName),
+		NodegroupName: aws.String(nodegroupName),
+		UpdateId:      aws.String(updateID),
+	}
+	var result *eks.DescribeUpdateOutput
+	err := h.aws.Call(ctx, func() (err error) {
+		result, err = h.client.DescribeUpdate(ctx, input)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe update %s: %w", updateID, err)
+	}
+	return result.Update, nil
+}
+
+// GetNodegroupUpdateHistory returns every update of a nodegroup, most recent first
+func (h *Handler) GetNodegroupUpdateHistory(ctx context.Context, clusterName, nodegroupName string) ([]*types.Update, error) {
+	ids, err := h.ListNodegroupUpdates(ctx, clusterName, nodegroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]*types.Update, 0, len(ids))
+	for _, id := range ids {
+		update, err := h.DescribeNodegroupUpdate(ctx, clusterName, nodegroupName, id)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return aws.ToTime(updates[i].CreatedAt).After(aws.ToTime(updates[j].CreatedAt))
+	})
+	return updates, nil
+}
@@ -0,0 +1,139 @@
+package findings
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity represents how serious a finding is
+type Severity int
+
+const (
+	// SeverityPass records a check that passed
+	SeverityPass Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityPass:     "pass",
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityCritical: "critical",
+}
+
+// String returns the lower-case name of the severity
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// MarshalJSON encodes the severity as its name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes a severity from its name
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	severity, err := ParseSeverity(name)
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// ParseSeverity returns the severity with the given name
+func ParseSeverity(name string) (Severity, error) {
+	for severity, n := range severityNames {
+		if strings.EqualFold(n, name) {
+			return severity, nil
+		}
+	}
+	return SeverityPass, fmt.Errorf("unknown severity %q", name)
+}
+
+// Finding is the result of a single check against a resource
+type Finding struct {
+	ID          string   `json:"id"`
+	Severity    Severity `json:"severity"`
+	Category    string   `json:"category"`
+	Resource    string   `json:"resource,omitempty"`
+	Message     string   `json:"message"`
+	Remediation string   `json:"remediation,omitempty"`
+}
+
+// IsIssue returns true if the finding is a warning or worse
+func (f Finding) IsIssue() bool {
+	return f.Severity >= SeverityWarning
+}
+
+// Sort orders findings from most to least severe, then by ID and resource
+func Sort(fs []Finding) {
+	sort.SliceStable(fs, func(i, j int) bool {
+		if fs[i].Severity != fs[j].Severity {
+			return fs[i].Severity > fs[j].Severity
+		}
+		if fs[i].ID != fs[j].ID {
+			return fs[i].ID < fs[j].ID
+		}
+		return fs[i].Resource < fs[j].Resource
+	})
+}
+
+// Count returns the number of findings at or above the given severity
+func Count(fs []Finding, min Severity) int {
+	count := 0
+	for _, f := range fs {
+		if f.Severity >= min {
+			count++
+		}
+	}
+	return count
+}
+
+// Filter returns the findings whose severity is in the given set. An empty set keeps every finding.
+func Filter(fs []Finding, severities []Severity) []Finding {
+	if len(severities) == 0 {
+		return fs
+	}
+
+	keep := make(map[Severity]bool, len(severities))
+	for _, s := range severities {
+		keep[s] = true
+	}
+
+	var filtered []Finding
+	for _, f := range fs {
+		if keep[f.Severity] {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// ParseSeverities parses a list of severity names, e.g. from "--only critical,warning"
+func ParseSeverities(names []string) ([]Severity, error) {
+	var severities []Severity
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		severity, err := ParseSeverity(name)
+		if err != nil {
+			return nil, err
+		}
+		severities = append(severities, severity)
+	}
+	return severities, nil
+}
@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Components whose image versions GetComponentImageVersions reads
+const (
+	ComponentCoreDNS   = "coredns"
+	ComponentKubeProxy = "kube-proxy"
+)
+
+// imageTag returns the tag of a container image reference, or "" if it has none
+func imageTag(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// GetComponentImageVersions returns the image tags of the CoreDNS Deployment and the
+// kube-proxy DaemonSet in kube-system, keyed by component name. Components that are not
+// installed are left out.
+func (k *KubeClient) GetComponentImageVersions(ctx context.Context) (map[string]string, error) {
+	versions := make(map[string]string)
+
+	deployment, err := k.Clientset.AppsV1().Deployments("kube-system").Get(ctx, ComponentCoreDNS, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get coredns deployment: %w", err)
+	}
+	if err == nil {
+		for _, c := range deployment.Spec.Template.Spec.Containers {
+			if c.Name == ComponentCoreDNS {
+				versions[ComponentCoreDNS] = imageTag(c.Image)
+			}
+		}
+	}
+
+	ds, err := k.Clientset.AppsV1().DaemonSets("kube-system").Get(ctx, ComponentKubeProxy, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get kube-proxy daemonset: %w", err)
+	}
+	if err == nil {
+		for _, c := range ds.Spec.Template.Spec.Containers {
+			if c.Name == ComponentKubeProxy {
+				versions[ComponentKubeProxy] = imageTag(c.Image)
+			}
+		}
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// AWSAuthMapping is an IAM role or user mapping from the aws-auth ConfigMap
+type AWSAuthMapping struct {
+	ARN      string
+	Username string
+	Groups   []string
+}
+
+type awsAuthEntry struct {
+	RoleARN  string   `json:"rolearn"`
+	UserARN  string   `json:"userarn"`
+	Username string   `json:"username"`
+	Groups   []string `json:"groups"`
+}
+
+// GetAWSAuthMappings returns the role and user mappings from the kube-system/aws-auth
+// ConfigMap, or no mappings if the ConfigMap does not exist
+func (k *KubeClient) GetAWSAuthMappings(ctx context.Context) ([]AWSAuthMapping, error) {
+	cm, err := k.Clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "aws-auth", metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get aws-auth configmap: %w", err)
+	}
+
+	var mappings []AWSAuthMapping
+	for _, key := range []string{"mapRoles", "mapUsers"} {
+		data, ok := cm.Data[key]
+		if !ok {
+			continue
+		}
+
+		var entries []awsAuthEntry
+		if err := yaml.Unmarshal([]byte(data), &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse aws-auth %s: %w", key, err)
+		}
+
+		for _, entry := range entries {
+			arn := entry.RoleARN
+			if arn == "" {
+				arn = entry.UserARN
+			}
+			mappings = append(mappings, AWSAuthMapping{
+				ARN:      arn,
+				Username: entry.Username,
+				Groups:   entry.Groups,
+			})
+		}
+	}
+
+	return mappings, nil
+}
@@ -0,0 +1,137 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// zoneLabel is the well-known node label holding the node's availability zone
+const zoneLabel = "topology.kubernetes.io/zone"
+
+// AZDistribution holds the per-AZ node counts and the AZ spread of each workload
+type AZDistribution struct {
+	NodesByZone map[string]int
+	Workloads   []WorkloadAZDistribution
+}
+
+// Zones returns the zones that have nodes, sorted by name
+func (d *AZDistribution) Zones() []string {
+	zones := make([]string, 0, len(d.NodesByZone))
+	for zone := range d.NodesByZone {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// WorkloadAZDistribution describes how a Deployment or StatefulSet's pods are spread across AZs
+type WorkloadAZDistribution struct {
+	Namespace  string
+	Owner      string // Kind/name, e.g. Deployment/web
+	PodsByZone map[string]int
+	TotalPods  int
+	// Concentrated is set when a workload with several pods runs them all in one AZ
+	// although other AZs have nodes
+	Concentrated bool
+	// SpreadViolations describes zone topology spread constraints whose skew exceeds maxSkew
+	SpreadViolations []string
+}
+
+// GetAZDistribution groups nodes by availability zone and reports the AZ spread of the
+// running pods of each Deployment and StatefulSet
+func (k *KubeClient) GetAZDistribution(ctx context.Context) (*AZDistribution, error) {
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	dist := &AZDistribution{NodesByZone: make(map[string]int)}
+	nodeZones := make(map[string]string)
+	for _, node := range nodes.Items {
+		zone := node.Labels[zoneLabel]
+		if zone == "" {
+			zone = "unknown"
+		}
+		nodeZones[node.Name] = zone
+		dist.NodesByZone[zone]++
+	}
+
+	pods, err := k.Clientset.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	owners := make(map[string]string)
+	workloads := make(map[string]*WorkloadAZDistribution)
+	constraints := make(map[string][]corev1.TopologySpreadConstraint)
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		owner := k.getPodOwner(ctx, &pod, owners)
+		if !strings.HasPrefix(owner, "Deployment/") && !strings.HasPrefix(owner, "StatefulSet/") {
+			continue
+		}
+
+		key := pod.Namespace + "/" + owner
+		w, ok := workloads[key]
+		if !ok {
+			w = &WorkloadAZDistribution{
+				Namespace:  pod.Namespace,
+				Owner:      owner,
+				PodsByZone: make(map[string]int),
+			}
+			workloads[key] = w
+			constraints[key] = pod.Spec.TopologySpreadConstraints
+		}
+		w.PodsByZone[nodeZones[pod.Spec.NodeName]]++
+		w.TotalPods++
+	}
+
+	zones := dist.Zones()
+	for key, w := range workloads {
+		w.Concentrated = w.TotalPods > 1 && len(w.PodsByZone) == 1 && len(zones) > 1
+		w.SpreadViolations = zoneSpreadViolations(w.PodsByZone, zones, constraints[key])
+		dist.Workloads = append(dist.Workloads, *w)
+	}
+	sort.Slice(dist.Workloads, func(i, j int) bool {
+		if dist.Workloads[i].Namespace != dist.Workloads[j].Namespace {
+			return dist.Workloads[i].Namespace < dist.Workloads[j].Namespace
+		}
+		return dist.Workloads[i].Owner < dist.Workloads[j].Owner
+	})
+
+	return dist, nil
+}
+
+// zoneSpreadViolations returns the zone topology spread constraints whose current skew,
+// the difference between the most and least loaded zones, exceeds maxSkew
+func zoneSpreadViolations(podsByZone map[string]int, zones []string, constraints []corev1.TopologySpreadConstraint) []string {
+	var violations []string
+	for _, c := range constraints {
+		if c.TopologyKey != zoneLabel || len(zones) == 0 {
+			continue
+		}
+
+		minPods, maxPods := -1, 0
+		for _, zone := range zones {
+			count := podsByZone[zone]
+			if minPods < 0 || count < minPods {
+				minPods = count
+			}
+			if count > maxPods {
+				maxPods = count
+			}
+		}
+
+		if skew := int32(maxPods - minPods); skew > c.MaxSkew {
+			violations = append(violations, fmt.Sprintf("zone skew %d exceeds maxSkew %d (%s)", skew, c.MaxSkew, c.WhenUnsatisfiable))
+		}
+	}
+	return violations
+}
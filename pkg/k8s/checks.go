@@ -0,0 +1,278 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"ekspeek/pkg/findings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HealthCheck is a single cluster health check. Checks are registered with
+// RegisterHealthCheck and can be listed and selected by name.
+type HealthCheck interface {
+	Name() string
+	Description() string
+	Run(ctx context.Context, k *KubeClient) ([]findings.Finding, error)
+}
+
+var (
+	healthChecksMu sync.RWMutex
+	healthChecks   []HealthCheck
+)
+
+// RegisterHealthCheck adds a check to the registry. It panics if a check with the same
+// name is already registered.
+func RegisterHealthCheck(check HealthCheck) {
+	healthChecksMu.Lock()
+	defer healthChecksMu.Unlock()
+
+	for _, existing := range healthChecks {
+		if existing.Name() == check.Name() {
+			panic(fmt.Sprintf("health check %q registered twice", check.Name()))
+		}
+	}
+	healthChecks = append(healthChecks, check)
+}
+
+// HealthChecks returns the registered checks in registration order
+func HealthChecks() []HealthCheck {
+	healthChecksMu.RLock()
+	defer healthChecksMu.RUnlock()
+
+	checks := make([]HealthCheck, len(healthChecks))
+	copy(checks, healthChecks)
+	return checks
+}
+
+// SelectHealthChecks returns the registered checks to run. If enable is non-empty only those
+// checks run; checks named in disable are skipped. Unknown names are an error.
+func SelectHealthChecks(enable, disable []string) ([]HealthCheck, error) {
+	checks := HealthChecks()
+	known := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		known[check.Name()] = true
+	}
+
+	toSet := func(names []string) (map[string]bool, error) {
+		set := make(map[string]bool, len(names))
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if !known[name] {
+				return nil, fmt.Errorf("unknown health check %q (see \"ekspeek checks list\")", name)
+			}
+			set[name] = true
+		}
+		return set, nil
+	}
+	enabled, err := toSet(enable)
+	if err != nil {
+		return nil, err
+	}
+	disabled, err := toSet(disable)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []HealthCheck
+	for _, check := range checks {
+		if len(enabled) > 0 && !enabled[check.Name()] {
+			continue
+		}
+		if disabled[check.Name()] {
+			continue
+		}
+		selected = append(selected, check)
+	}
+	return selected, nil
+}
+
+// builtinCheck adapts one of the built-in checks, which fill in a section of
+// ClusterHealthStatus, to the HealthCheck interface
+type builtinCheck struct {
+	name        string
+	description string
+	populate    func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error
+	findings    func(status *ClusterHealthStatus) []findings.Finding
+	// namespaced is true if the check only reads the namespaces it is given, so it can run
+	// with namespace-scoped RBAC
+	namespaced bool
+}
+
+func (c *builtinCheck) Name() string        { return c.name }
+func (c *builtinCheck) Description() string { return c.description }
+
+// Run runs the check across all namespaces and returns its findings
+func (c *builtinCheck) Run(ctx context.Context, k *KubeClient) ([]findings.Finding, error) {
+	status := &ClusterHealthStatus{NodeVersions: make(map[string][]string)}
+	if err := c.populate(ctx, k, []string{metav1.NamespaceAll}, status); err != nil {
+		return nil, err
+	}
+	return c.results(status), nil
+}
+
+func (c *builtinCheck) results(status *ClusterHealthStatus) []findings.Finding {
+	if c.findings == nil {
+		return nil
+	}
+	return c.findings(status)
+}
+
+func init() {
+	for _, check := range []*builtinCheck{
+		{
+			name:        "version-mismatch",
+			description: "Nodes running different Kubernetes versions",
+			populate: func(ctx context.Context, k *KubeClient, _ []string, status *ClusterHealthStatus) error {
+				return k.checkVersionMismatch(ctx, status)
+			},
+			findings: versionMismatchFindings,
+		},
+		{
+			name:        "deprecated-apis",
+			description: "Resources applied with deprecated or removed API versions",
+			populate: func(ctx context.Context, k *KubeClient, _ []string, status *ClusterHealthStatus) error {
+				return k.checkDeprecatedAPIs(ctx, status)
+			},
+			findings: deprecatedAPIFindings,
+		},
+		{
+			name:        "logging",
+			description: "Logging and monitoring agents (Fluent Bit, CloudWatch, metrics-server, Dynatrace)",
+			populate: func(ctx context.Context, k *KubeClient, _ []string, status *ClusterHealthStatus) error {
+				return k.checkLoggingStatus(ctx, status)
+			},
+		},
+		{
+			name:        "networking",
+			description: "VPC CNI and CoreDNS pods",
+			populate: func(ctx context.Context, k *KubeClient, _ []string, status *ClusterHealthStatus) error {
+				return k.checkNetworkingStatus(ctx, &status.NetworkingStatus)
+			},
+		},
+		{
+			name:        "load-balancers",
+			description: "LoadBalancer services without an address and ingress problems",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkLoadBalancerStatus(ctx, namespaces, &status.LoadBalancerStatus)
+			},
+			findings:   loadBalancerFindings,
+			namespaced: true,
+		},
+		{
+			name:        "scheduling",
+			description: "Pending pods, node resource pressure and pod capacity",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkSchedulingStatus(ctx, namespaces, status.namespaceScoped, &status.SchedulingStatus)
+			},
+			findings:   schedulingFindings,
+			namespaced: true,
+		},
+		{
+			name:        "ports",
+			description: "Host port conflicts and NodePort allocations",
+			populate: func(ctx context.Context, k *KubeClient, _ []string, status *ClusterHealthStatus) error {
+				return k.checkPortStatus(ctx, &status.PortStatus)
+			},
+			findings: portFindings,
+		},
+		{
+			name:        "auth",
+			description: "IRSA and RBAC configuration",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkAuthStatus(ctx, namespaces, &status.AuthStatus)
+			},
+			findings:   authFindings,
+			namespaced: true,
+		},
+		{
+			name:        "default-sa-token",
+			description: "Pods auto-mounting the default service account token, and those behind a LoadBalancer or Ingress",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkDefaultServiceAccountTokens(ctx, namespaces, status)
+			},
+			findings:   defaultTokenFindings,
+			namespaced: true,
+		},
+		{
+			name:        "nodes",
+			description: "Node readiness, kubelet certificates of NotReady nodes and Ready/NotReady flapping",
+			populate: func(ctx context.Context, k *KubeClient, _ []string, status *ClusterHealthStatus) error {
+				return k.checkNodeStatus(ctx, &status.NodeStatus)
+			},
+			findings: nodeFindings,
+		},
+		{
+			name:        "clock-skew",
+			description: "Node clocks drifting from the control plane, which breaks TLS and IRSA tokens",
+			populate: func(ctx context.Context, k *KubeClient, _ []string, status *ClusterHealthStatus) error {
+				return k.checkClockSkew(ctx, status)
+			},
+			findings: clockSkewFindings,
+		},
+		{
+			name:        "tolerations",
+			description: "Workload pods on tainted nodes through wildcard or node-role tolerations",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkTolerations(ctx, namespaces, status)
+			},
+			findings: tolerationFindings,
+		},
+		{
+			name:        "probes",
+			description: "Liveness, readiness and startup probes with tight timings on restarting containers or ports the container does not expose",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkProbes(ctx, namespaces, status)
+			},
+			findings:   probeFindings,
+			namespaced: true,
+		},
+		{
+			name:        "statefulsets",
+			description: "StatefulSet replica readiness",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkStatefulSetStatus(ctx, namespaces, status)
+			},
+			namespaced: true,
+		},
+		{
+			name:        "single-replicas",
+			description: "Cluster infrastructure Deployments and StatefulSets with one replica and no PodDisruptionBudget",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkSingleReplicaWorkloads(ctx, namespaces, status)
+			},
+			findings:   singleReplicaFindings,
+			namespaced: true,
+		},
+		{
+			name:        "daemonsets",
+			description: "DaemonSet pod availability",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkDaemonSetStatus(ctx, namespaces, status)
+			},
+			namespaced: true,
+		},
+		{
+			name:        "storage",
+			description: "PVC binding, StorageClasses and StatefulSet volumes",
+			populate: func(ctx context.Context, k *KubeClient, namespaces []string, status *ClusterHealthStatus) error {
+				return k.checkStorageStatus(ctx, namespaces, status)
+			},
+			findings:   storageFindings,
+			namespaced: true,
+		},
+		{
+			name:        "intree-storage",
+			description: "StorageClasses using removed in-tree provisioners, and whether their CSI replacement is installed",
+			populate: func(ctx context.Context, k *KubeClient, _ []string, status *ClusterHealthStatus) error {
+				return k.checkInTreeStorageClasses(ctx, status)
+			},
+			findings: inTreeStorageFindings,
+		},
+	} {
+		RegisterHealthCheck(check)
+	}
+}
@@ -6,50 +6,95 @@ import (
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
-	"encoding/json"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"ekspeek/pkg/common/logger"
+
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const (
+	// testPodTimeout bounds how long to wait for a test pod when the context has no deadline
+	testPodTimeout = 2 * time.Minute
+	// maxWatchBackoff caps the delay between attempts to re-establish a test pod watch
+	maxWatchBackoff = 8 * time.Second
+	// maxDNSExecCandidates bounds the other pods TestPodDNS tries to exec a lookup in
+	maxDNSExecCandidates = 3
+	// dnsLookupAttempts is how often a failing lookup is retried before DNS is reported broken
+	dnsLookupAttempts = 2
+	// DefaultDiagImage is the image of the DNS, connectivity and MTU test pods
+	DefaultDiagImage = "busybox"
 )
 
 // KubeClientConfig holds the configuration for the Kubernetes client
 type KubeClientConfig struct {
 	KubeConfig string
 	Context    string
+	// InCluster forces the in-cluster service account config instead of kubeconfig
+	InCluster bool
+	// DiagImage overrides the image of all diagnostic test pods
+	DiagImage string
+	// DiagImagePullSecrets are the image pull secrets of the diagnostic test pods
+	DiagImagePullSecrets []string
 }
 
 // KubeClient wraps the Kubernetes clientset and config
 type KubeClient struct {
-	Clientset *kubernetes.Clientset
+	Clientset kubernetes.Interface
 	Config    *rest.Config
+	// Context is the kubeconfig context in use, empty for in-cluster config and dumps
+	Context string
+	// InCluster is true when the client uses the in-cluster service account config, whose
+	// host is the kubernetes Service address rather than the cluster endpoint
+	InCluster bool
+	// DiagImage overrides the image of all diagnostic test pods, empty for the defaults
+	DiagImage string
+	// DiagImagePullSecrets are the image pull secrets of the diagnostic test pods
+	DiagImagePullSecrets []string
+	// MaxPodsLookup, if set, returns the ENI-based maximum pods of instance types missing
+	// from the built-in table, e.g. from EC2 DescribeInstanceTypes
+	MaxPodsLookup func(ctx context.Context, instanceType string) (int64, error)
+	// DumpDir is the directory a client from NewKubeClientFromDump serves, empty for live
+	// clusters
+	DumpDir string
+	// Log receives the client's log lines, e.g. a logger prefixed with the cluster name in
+	// fleet runs; nil logs through the package-level logger
+	Log *logger.Logger
 }
 
-// NewKubeClient creates a new Kubernetes client
-func NewKubeClient(cfg KubeClientConfig) (*KubeClient, error) {
-	configPath := cfg.KubeConfig
-	if configPath == "" {
-		configPath = filepath.Join(os.Getenv("HOME"), ".kube", "config")
+// log returns the logger of the client
+func (k *KubeClient) log() *logger.Logger {
+	if k.Log != nil {
+		return k.Log
 	}
+	return logger.Default()
+}
 
-	// Use the current context in kubeconfig
-	config, err := clientcmd.BuildConfigFromFlags("", configPath)
+// NewKubeClient creates a new Kubernetes client. When no kubeconfig path or context is
+// requested, the in-cluster config is tried first so ekspeek can run inside a pod.
+func NewKubeClient(cfg KubeClientConfig) (*KubeClient, error) {
+	config, contextName, inCluster, err := buildRestConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build config from flags: %w", err)
+		return nil, err
 	}
 
 	// Create the clientset
@@ -59,18 +104,89 @@ func NewKubeClient(cfg KubeClientConfig) (*KubeClient, error) {
 	}
 
 	return &KubeClient{
-		Clientset: clientset,
-		Config:    config,
+		Clientset:            clientset,
+		Config:               config,
+		Context:              contextName,
+		InCluster:            inCluster,
+		DiagImage:            cfg.DiagImage,
+		DiagImagePullSecrets: cfg.DiagImagePullSecrets,
 	}, nil
 }
 
-// UpdateKubeconfig updates the kubeconfig file with EKS cluster info
-func UpdateKubeconfig(ctx context.Context, clusterName, region string) error {
+// defaultKubeconfigPath returns the kubeconfig path to use when none is given
+func defaultKubeconfigPath(path string) string {
+	if path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".kube", "config")
+}
+
+// buildRestConfig resolves the REST config from the in-cluster environment or kubeconfig,
+// along with the name of the kubeconfig context used and whether the in-cluster config was
+// used
+func buildRestConfig(cfg KubeClientConfig) (*rest.Config, string, bool, error) {
+	if cfg.InCluster {
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, "", false, fmt.Errorf("failed to load in-cluster config: %w", err)
+		}
+		return config, "", true, nil
+	}
+
+	if cfg.KubeConfig == "" && cfg.Context == "" {
+		if config, err := rest.InClusterConfig(); err == nil {
+			return config, "", true, nil
+		}
+	}
+
+	// Use the requested context, or the current context in kubeconfig if none is set
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: defaultKubeconfigPath(cfg.KubeConfig)}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: cfg.Context}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to build config from flags: %w", err)
+	}
+
+	contextName := cfg.Context
+	if contextName == "" {
+		if raw, err := clientConfig.RawConfig(); err == nil {
+			contextName = raw.CurrentContext
+		}
+	}
+	return config, contextName, false, nil
+}
+
+// KubeconfigOptions controls how UpdateKubeconfig writes the cluster entry
+type KubeconfigOptions struct {
+	// Path is the kubeconfig file to update, defaulting to ~/.kube/config
+	Path string
+	// Profile is the AWS profile passed to "aws eks get-token" via AWS_PROFILE
+	Profile string
+	// RoleARN, if set, is assumed to describe the cluster and passed to "aws eks get-token"
+	RoleARN string
+	// SetCurrent switches the kubeconfig current-context to the cluster
+	SetCurrent bool
+}
+
+// UpdateKubeconfig adds or updates the cluster, user and context entries for an EKS cluster
+// in the kubeconfig file. Authentication uses an exec plugin running "aws eks get-token",
+// matching "aws eks update-kubeconfig". The current context is left alone unless requested.
+func UpdateKubeconfig(ctx context.Context, clusterName, region string, opts KubeconfigOptions) error {
 	// Get the AWS config
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	loadOpts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if opts.Profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(opts.Profile))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
+	if opts.RoleARN != "" {
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), opts.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "ekspeek"
+		}))
+	}
 
 	// Create EKS client
 	svc := eks.NewFromConfig(cfg)
@@ -84,10 +200,32 @@ func UpdateKubeconfig(ctx context.Context, clusterName, region string) error {
 	if err != nil {
 		return fmt.Errorf("failed to describe cluster: %w", err)
 	}
+	// A cluster that was just created has no endpoint or certificate until it is ACTIVE
+	if result.Cluster.Endpoint == nil || result.Cluster.CertificateAuthority == nil || result.Cluster.CertificateAuthority.Data == nil {
+		return fmt.Errorf("cluster %s has no API endpoint or certificate yet (status %s): %w", clusterName, result.Cluster.Status, ErrClusterNotReady)
+	}
+
+	// Properly handle certificate data
+	certData := *result.Cluster.CertificateAuthority.Data
+	caData, err := base64.StdEncoding.DecodeString(certData)
+	if err != nil {
+		// If not base64 encoded, use as is
+		caData = []byte(certData)
+	}
+
+	return writeKubeconfigEntry(clusterName, region, *result.Cluster.Endpoint, caData, opts)
+}
 
+// writeKubeconfigEntry writes the cluster, exec-based user and context entries to the kubeconfig
+func writeKubeconfigEntry(clusterName, region, endpoint string, caData []byte, opts KubeconfigOptions) error {
 	// Get kubeconfig file path
-	kubeconfigPath := filepath.Join(os.Getenv("HOME"), ".kube", "config")
-	os.MkdirAll(filepath.Dir(kubeconfigPath), 0755)
+	kubeconfigPath := opts.Path
+	if kubeconfigPath == "" {
+		kubeconfigPath = filepath.Join(os.Getenv("HOME"), ".kube", "config")
+	}
+	if err := os.MkdirAll(filepath.Dir(kubeconfigPath), 0755); err != nil {
+		return fmt.Errorf("failed to create kubeconfig directory: %w", err)
+	}
 
 	// Load existing kubeconfig
 	kubeconfig, err := clientcmd.LoadFromFile(kubeconfigPath)
@@ -101,27 +239,23 @@ func UpdateKubeconfig(ctx context.Context, clusterName, region string) error {
 
 	// Create cluster entry
 	cluster := api.NewCluster()
-	cluster.Server = *result.Cluster.Endpoint
+	cluster.Server = endpoint
+	cluster.CertificateAuthorityData = caData
 
-	// Properly handle certificate data
-	certData := *result.Cluster.CertificateAuthority.Data
-	decodedCert, err := base64.StdEncoding.DecodeString(certData)
-	if err != nil {
-		// If not base64 encoded, use as is
-		cluster.CertificateAuthorityData = []byte(certData)
-	} else {
-		cluster.CertificateAuthorityData = decodedCert
-	}
-
-	// Create auth entry
+	// Create auth entry that fetches a fresh token on every request
 	authInfo := api.NewAuthInfo()
-	
-	// Set token for aws-iam-authenticator
-	v1Token, err := generateV1Token(clusterName, region)
-	if err != nil {
-		return fmt.Errorf("failed to generate token: %w", err)
+	authInfo.Exec = &api.ExecConfig{
+		APIVersion:      "client.authentication.k8s.io/v1beta1",
+		Command:         "aws",
+		Args:            []string{"eks", "get-token", "--cluster-name", clusterName, "--region", region},
+		InteractiveMode: api.NeverExecInteractiveMode,
+	}
+	if opts.RoleARN != "" {
+		authInfo.Exec.Args = append(authInfo.Exec.Args, "--role-arn", opts.RoleARN)
+	}
+	if opts.Profile != "" {
+		authInfo.Exec.Env = []api.ExecEnvVar{{Name: "AWS_PROFILE", Value: opts.Profile}}
 	}
-	authInfo.Token = v1Token
 
 	// Create context entry
 	context := api.NewContext()
@@ -133,7 +267,9 @@ func UpdateKubeconfig(ctx context.Context, clusterName, region string) error {
 	kubeconfig.Clusters[clusterName] = cluster
 	kubeconfig.AuthInfos[clusterName] = authInfo
 	kubeconfig.Contexts[clusterName] = context
-	kubeconfig.CurrentContext = clusterName
+	if opts.SetCurrent {
+		kubeconfig.CurrentContext = clusterName
+	}
 
 	// Write updated kubeconfig
 	err = clientcmd.WriteToFile(*kubeconfig, kubeconfigPath)
@@ -184,24 +320,131 @@ func (c *KubeClient) GetNetworkPolicies(ctx context.Context, namespace string) (
 	return c.Clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
 }
 
-// TestPodDNS tests DNS resolution from a pod
-func (c *KubeClient) TestPodDNS(ctx context.Context, namespace, podName, hostname string) (bool, error) {
+// PodIPForFamily returns the pod's address in the given IP family, falling back to its
+// primary address when the pod has none in that family
+func PodIPForFamily(pod *corev1.Pod, family corev1.IPFamily) string {
+	for _, podIP := range pod.Status.PodIPs {
+		ip := net.ParseIP(podIP.IP)
+		if ip == nil {
+			continue
+		}
+		if (ip.To4() != nil) == (family == corev1.IPv4Protocol) {
+			return podIP.IP
+		}
+	}
+	return pod.Status.PodIP
+}
+
+// TestPodDNS tests DNS resolution from a pod. In IPv6 clusters the AAAA record is queried.
+// The lookup is run with exec in the pod, or in another running pod of its namespace, so
+// nothing is scheduled; a throwaway pod is only created when none of them has a lookup
+// tool or exec is not possible.
+func (c *KubeClient) TestPodDNS(ctx context.Context, namespace, podName, hostname string, family corev1.IPFamily) (bool, error) {
+	if ok, ran := c.testDNSWithExec(ctx, namespace, podName, hostname, family); ran {
+		return ok, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return false, fmt.Errorf("DNS test canceled: %w", err)
+	}
+	return c.testDNSWithPod(ctx, namespace, hostname, family)
+}
+
+// testDNSWithExec runs a DNS lookup in existing running pods of the namespace, starting
+// with podName. ran is false if no pod could run a lookup tool, or if ctx was canceled
+// between attempts.
+func (c *KubeClient) testDNSWithExec(ctx context.Context, namespace, podName, hostname string, family corev1.IPFamily) (ok, ran bool) {
+	if c.Config == nil {
+		return false, false
+	}
+
+	pods, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return false, false
+	}
+	var candidates []string
+	for _, pod := range pods.Items {
+		if pod.Name == podName {
+			candidates = append([]string{pod.Name}, candidates...)
+		} else if len(candidates) < maxDNSExecCandidates {
+			candidates = append(candidates, pod.Name)
+		}
+	}
+
+	commands := [][]string{{"nslookup", hostname}, {"getent", "hosts", hostname}}
+	if family == corev1.IPv6Protocol {
+		commands = [][]string{{"nslookup", "-type=AAAA", hostname}, {"getent", "ahostsv6", hostname}}
+	}
+
+	for _, pod := range candidates {
+		for _, command := range commands {
+			for attempt := 1; attempt <= dnsLookupAttempts; attempt++ {
+				_, _, err := c.ExecInPod(ctx, namespace, pod, "", command)
+				if err == nil {
+					c.log().Debug("DNS lookup of %s succeeded in pod %s/%s", hostname, namespace, pod)
+					return true, true
+				}
+				code, exited := ExecExitCode(err)
+				if !exited || code == 126 || code == 127 {
+					// The tool is missing or exec failed: try the next command
+					c.log().Debug("Cannot run %s in pod %s/%s: %v", command[0], namespace, pod, err)
+					break
+				}
+				if attempt == dnsLookupAttempts {
+					c.log().Debug("DNS lookup of %s failed in pod %s/%s: %v", hostname, namespace, pod, err)
+					return false, true
+				}
+				select {
+				case <-ctx.Done():
+					return false, false
+				case <-time.After(time.Second):
+				}
+			}
+		}
+	}
+	return false, false
+}
+
+// diagnosticPodSpec returns the spec of a test pod running command in a single container.
+// The image is --diag-image when set, otherwise defaultImage.
+func (c *KubeClient) diagnosticPodSpec(name, defaultImage string, command []string) corev1.PodSpec {
+	image := c.DiagImage
+	if image == "" {
+		image = defaultImage
+	}
+	var pullSecrets []corev1.LocalObjectReference
+	for _, secret := range c.DiagImagePullSecrets {
+		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+	return corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:    name,
+				Image:   image,
+				Command: command,
+			},
+		},
+		ImagePullSecrets: pullSecrets,
+		RestartPolicy:    corev1.RestartPolicyNever,
+	}
+}
+
+// testDNSWithPod runs a DNS lookup in a temporary diagnostic pod
+func (c *KubeClient) testDNSWithPod(ctx context.Context, namespace, hostname string, family corev1.IPFamily) (bool, error) {
+	command := []string{"nslookup", hostname}
+	if family == corev1.IPv6Protocol {
+		command = []string{"nslookup", "-type=AAAA", hostname}
+	}
+
 	// Create a temporary pod to test DNS
 	testPod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "dns-test-",
 			Namespace:    namespace,
+			Labels:       diagnosticPodLabels(),
 		},
-		Spec: corev1.PodSpec{
-			Containers: []corev1.Container{
-				{
-					Name:    "dns-test",
-					Image:   "busybox",
-					Command: []string{"nslookup", hostname},
-				},
-			},
-			RestartPolicy: corev1.RestartPolicyNever,
-		},
+		Spec: c.diagnosticPodSpec("dns-test", DefaultDiagImage, command),
 	}
 
 	pod, err := c.Clientset.CoreV1().Pods(namespace).Create(ctx, testPod, metav1.CreateOptions{})
@@ -209,36 +452,29 @@ func (c *KubeClient) TestPodDNS(ctx context.Context, namespace, podName, hostnam
 		return false, fmt.Errorf("failed to create test pod: %w", err)
 	}
 
-	defer c.Clientset.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+	defer c.Clientset.CoreV1().Pods(namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
 
 	// Wait for pod completion
-	watch, err := c.Clientset.CoreV1().Pods(namespace).Watch(ctx, metav1.SingleObject(pod.ObjectMeta))
+	phase, err := c.waitForPodCompletion(ctx, namespace, pod.Name)
 	if err != nil {
-		return false, fmt.Errorf("failed to watch test pod: %w", err)
+		return false, err
 	}
-	defer watch.Stop()
-
-	for event := range watch.ResultChan() {
-		pod := event.Object.(*corev1.Pod)
-		if pod.Status.Phase == corev1.PodSucceeded {
-			return true, nil
-		} else if pod.Status.Phase == corev1.PodFailed {
-			return false, fmt.Errorf("DNS test failed")
-		}
+	if phase == corev1.PodFailed {
+		return false, fmt.Errorf("DNS test failed")
 	}
-
-	return false, fmt.Errorf("watch ended before pod completion")
+	return true, nil
 }
 
-// TestPodConnectivity tests network connectivity between pods
-func (c *KubeClient) TestPodConnectivity(ctx context.Context, sourceNS, sourcePod, targetNS, targetPod string) error {
+// TestPodConnectivity tests network connectivity between pods using the target's address
+// in the given IP family
+func (c *KubeClient) TestPodConnectivity(ctx context.Context, sourceNS, sourcePod, targetNS, targetPod string, family corev1.IPFamily) error {
 	// Get target pod IP
 	targetPodObj, err := c.GetPod(ctx, targetNS, targetPod)
 	if err != nil {
 		return fmt.Errorf("failed to get target pod: %w", err)
 	}
 
-	targetIP := targetPodObj.Status.PodIP
+	targetIP := PodIPForFamily(targetPodObj, family)
 	if targetIP == "" {
 		return fmt.Errorf("target pod has no IP address")
 	}
@@ -248,17 +484,10 @@ func (c *KubeClient) TestPodConnectivity(ctx context.Context, sourceNS, sourcePo
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "network-test-",
 			Namespace:    sourceNS,
+			Labels:       diagnosticPodLabels(),
 		},
-		Spec: corev1.PodSpec{
-			Containers: []corev1.Container{
-				{
-					Name:    "network-test",
-					Image:   "busybox",
-					Command: []string{"wget", "-T", "5", "-O-", fmt.Sprintf("http://%s:80", targetIP)},
-				},
-			},
-			RestartPolicy: corev1.RestartPolicyNever,
-		},
+		Spec: c.diagnosticPodSpec("network-test", DefaultDiagImage,
+			[]string{"wget", "-T", "5", "-O-", "http://" + net.JoinHostPort(targetIP, "80")}),
 	}
 
 	pod, err := c.Clientset.CoreV1().Pods(sourceNS).Create(ctx, testPod, metav1.CreateOptions{})
@@ -266,25 +495,69 @@ func (c *KubeClient) TestPodConnectivity(ctx context.Context, sourceNS, sourcePo
 		return fmt.Errorf("failed to create test pod: %w", err)
 	}
 
-	defer c.Clientset.CoreV1().Pods(sourceNS).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+	defer c.Clientset.CoreV1().Pods(sourceNS).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
 
 	// Wait for pod completion
-	watch, err := c.Clientset.CoreV1().Pods(sourceNS).Watch(ctx, metav1.SingleObject(pod.ObjectMeta))
+	phase, err := c.waitForPodCompletion(ctx, sourceNS, pod.Name)
 	if err != nil {
-		return fmt.Errorf("failed to watch test pod: %w", err)
+		return err
+	}
+	if phase == corev1.PodFailed {
+		return fmt.Errorf("connectivity test failed")
 	}
-	defer watch.Stop()
+	return nil
+}
 
-	for event := range watch.ResultChan() {
-		pod := event.Object.(*corev1.Pod)
-		if pod.Status.Phase == corev1.PodSucceeded {
-			return nil
-		} else if pod.Status.Phase == corev1.PodFailed {
-			return fmt.Errorf("connectivity test failed")
+// waitForPodCompletion watches a test pod until it succeeds or fails. Watches closed early by
+// the API server are re-established from the last seen resourceVersion with backoff, until
+// the pod finishes or the context (or testPodTimeout, if the context has no deadline) expires.
+func (c *KubeClient) waitForPodCompletion(ctx context.Context, namespace, name string) (corev1.PodPhase, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, testPodTimeout)
+		defer cancel()
+	}
+
+	resourceVersion := ""
+	backoff := time.Second
+	for {
+		w, err := c.Clientset.CoreV1().Pods(namespace).Watch(ctx, metav1.SingleObject(metav1.ObjectMeta{
+			Name:            name,
+			ResourceVersion: resourceVersion,
+		}))
+		if err == nil {
+			for event := range w.ResultChan() {
+				if event.Type == watch.Error {
+					// Most likely the resourceVersion is too old; start again from the current state
+					resourceVersion = ""
+					break
+				}
+				pod, ok := event.Object.(*corev1.Pod)
+				if !ok {
+					continue
+				}
+				if event.Type == watch.Deleted {
+					w.Stop()
+					return "", fmt.Errorf("test pod %s was deleted before completion", name)
+				}
+				resourceVersion = pod.ResourceVersion
+				if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+					w.Stop()
+					return pod.Status.Phase, nil
+				}
+			}
+			w.Stop()
 		}
-	}
 
-	return fmt.Errorf("watch ended before pod completion")
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("timed out waiting for test pod %s to complete: %w", name, ctx.Err())
+		case <-time.After(backoff):
+		}
+		if backoff < maxWatchBackoff {
+			backoff *= 2
+		}
+	}
 }
 
 // CheckMTU checks MTU settings on cluster nodes
@@ -302,31 +575,25 @@ func (c *KubeClient) CheckMTU(ctx context.Context) (map[string]int, error) {
 			ObjectMeta: metav1.ObjectMeta{
 				GenerateName: "mtu-test-",
 				Namespace:    "default",
+				Labels:       diagnosticPodLabels(),
 			},
-			Spec: corev1.PodSpec{
-				NodeName: node.Name,
-				Containers: []corev1.Container{
-					{
-						Name:    "mtu-test",
-						Image:   "busybox",
-						Command: []string{"cat", "/sys/class/net/eth0/mtu"},
-					},
-				},
-				RestartPolicy: corev1.RestartPolicyNever,
-			},
+			Spec: c.diagnosticPodSpec("mtu-test", DefaultDiagImage, []string{"cat", "/sys/class/net/eth0/mtu"}),
 		}
+		testPod.Spec.NodeName = node.Name
 
 		pod, err := c.Clientset.CoreV1().Pods("default").Create(ctx, testPod, metav1.CreateOptions{})
 		if err != nil {
 			continue
 		}
 
-		// Get pod logs
+		// Get pod logs once the pod has finished
 		var mtu int
-		logs, err := c.GetPodLogs(ctx, "default", pod.Name, "")
-		if err == nil {
-			fmt.Sscanf(logs, "%d", &mtu)
-			mtuByNode[node.Name] = mtu
+		if phase, err := c.waitForPodCompletion(ctx, "default", pod.Name); err == nil && phase == corev1.PodSucceeded {
+			logs, err := c.GetPodLogs(ctx, "default", pod.Name, "")
+			if err == nil {
+				fmt.Sscanf(logs, "%d", &mtu)
+				mtuByNode[node.Name] = mtu
+			}
 		}
 
 		c.Clientset.CoreV1().Pods("default").Delete(ctx, pod.Name, metav1.DeleteOptions{})
@@ -522,63 +789,3 @@ func parseCertificate(certBytes []byte) (*x509.Certificate, error) {
 
 	return cert, nil
 }
-
-// isCommandNotFound checks if the error is due to command not being found in PATH
-func isCommandNotFound(err error) bool {
-	if err == nil {
-		return false
-	}
-	return strings.Contains(err.Error(), "executable file not found") ||
-		strings.Contains(err.Error(), "not found in $PATH") ||
-		strings.Contains(err.Error(), "no such file or directory")
-}
-
-// generateV1Token generates a v1 token for authentication with EKS
-func generateV1Token(clusterName, region string) (string, error) {
-	// First try using aws-iam-authenticator
-	cmd := exec.Command("aws-iam-authenticator", "token", "-i", clusterName, "--region", region)
-	output, err := cmd.Output()
-	if err != nil {
-		// If command not found or execution error, try AWS CLI
-		if _, ok := err.(*exec.ExitError); ok || isCommandNotFound(err) {
-			return generateTokenWithAWSCLI(clusterName, region)
-		}
-		return "", fmt.Errorf("failed to generate token: %w", err)
-	}
-
-	var tokenData struct {
-		Status struct {
-			Token string `json:"token"`
-		} `json:"status"`
-	}
-
-	if err := json.Unmarshal(output, &tokenData); err != nil {
-		return "", fmt.Errorf("failed to parse token output: %w", err)
-	}
-
-	return tokenData.Status.Token, nil
-}
-
-// generateTokenWithAWSCLI generates a token using the AWS CLI as fallback
-func generateTokenWithAWSCLI(clusterName, region string) (string, error) {
-	cmd := exec.Command("aws", "eks", "get-token", "--cluster-name", clusterName, "--region", region)
-	output, err := cmd.Output()
-	if err != nil {
-		if isCommandNotFound(err) {
-			return "", fmt.Errorf("neither aws-iam-authenticator nor AWS CLI found in PATH. Please install one of them")
-		}
-		return "", fmt.Errorf("failed to generate token with AWS CLI: %w", err)
-	}
-
-	var tokenData struct {
-		Status struct {
-			Token string `json:"token"`
-		} `json:"status"`
-	}
-
-	if err := json.Unmarshal(output, &tokenData); err != nil {
-		return "", fmt.Errorf("failed to parse AWS CLI token output: %w", err)
-	}
-
-	return tokenData.Status.Token, nil
-}
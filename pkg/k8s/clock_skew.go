@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// nodeLeaseRenewInterval is how often kubelet renews its node lease, a quarter of the
+	// default 40s lease duration
+	nodeLeaseRenewInterval = 10 * time.Second
+	// maxClockSkew is the node clock offset beyond which TLS and token validation start
+	// failing intermittently
+	maxClockSkew = 30 * time.Second
+	// CriticalClockSkew is the offset at which AWS rejects SigV4 signed requests
+	CriticalClockSkew = 5 * time.Minute
+)
+
+// NodeClockSkew is the offset of a node's clock from the control plane's
+type NodeClockSkew struct {
+	Node string
+	// Offset is positive when the node's clock is ahead
+	Offset time.Duration
+}
+
+// checkClockSkew estimates each ready node's clock offset from the renew time kubelet
+// writes to its node lease, which comes from the node's clock, compared to the API
+// server's time. Leases are renewed every nodeLeaseRenewInterval, so a node may appear
+// up to that much behind without any skew.
+func (k *KubeClient) checkClockSkew(ctx context.Context, status *ClusterHealthStatus) error {
+	if k.Config == nil {
+		// Dumps carry no control plane time to compare with
+		return fmt.Errorf("reading the control plane time %w", ErrNeedsLiveCluster)
+	}
+	serverTime, err := k.apiServerTime(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the API server time: %w", err)
+	}
+
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	leases, err := k.Clientset.CoordinationV1().Leases(corev1.NamespaceNodeLease).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	renewed := make(map[string]time.Time, len(leases.Items))
+	for _, lease := range leases.Items {
+		if lease.Spec.RenewTime != nil {
+			renewed[lease.Name] = lease.Spec.RenewTime.Time
+		}
+	}
+
+	for _, node := range nodes.Items {
+		// A NotReady node stopped renewing its lease, which says nothing about its clock
+		if !nodeIsReady(&node) {
+			continue
+		}
+		renewTime, ok := renewed[node.Name]
+		if !ok {
+			continue
+		}
+		offset := renewTime.Sub(serverTime)
+		if offset > maxClockSkew || offset < -(maxClockSkew+nodeLeaseRenewInterval) {
+			status.ClockSkew = append(status.ClockSkew, NodeClockSkew{
+				Node:   node.Name,
+				Offset: offset.Round(time.Second),
+			})
+		}
+	}
+
+	sort.Slice(status.ClockSkew, func(i, j int) bool { return status.ClockSkew[i].Node < status.ClockSkew[j].Node })
+	return nil
+}
+
+// apiServerTime returns the API server's clock from the Date header of a /version request
+func (k *KubeClient) apiServerTime(ctx context.Context) (time.Time, error) {
+	client, err := rest.HTTPClientFor(k.Config)
+	if err != nil {
+		return time.Time{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(k.Config.Host, "/")+"/version", nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp.Body.Close()
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid Date header: %w", err)
+	}
+	return date, nil
+}
+
+// nodeIsReady returns true if the node's Ready condition is true
+func nodeIsReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// String describes the offset, e.g. "2m5s ahead"
+func (s NodeClockSkew) String() string {
+	if s.Offset < 0 {
+		return fmt.Sprintf("%s behind", -s.Offset)
+	}
+	return fmt.Sprintf("%s ahead", s.Offset)
+}
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ekspeek/pkg/findings"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestCheckClockSkew(t *testing.T) {
+	serverTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	node := func(name string, ready corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			},
+		}
+	}
+	lease := func(name string, offset time.Duration) *coordinationv1.Lease {
+		renewTime := metav1.NewMicroTime(serverTime.Add(offset))
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: corev1.NamespaceNodeLease},
+			Spec:       coordinationv1.LeaseSpec{RenewTime: &renewTime},
+		}
+	}
+
+	client := &KubeClient{
+		Clientset: fake.NewSimpleClientset(
+			node("in-sync", corev1.ConditionTrue), lease("in-sync", -8*time.Second),
+			node("ahead", corev1.ConditionTrue), lease("ahead", 2*time.Minute),
+			node("behind", corev1.ConditionTrue), lease("behind", -10*time.Minute),
+			node("not-ready", corev1.ConditionFalse), lease("not-ready", -time.Hour),
+		),
+		Config: &rest.Config{Host: server.URL},
+	}
+
+	status := &ClusterHealthStatus{}
+	if err := client.checkClockSkew(context.Background(), status); err != nil {
+		t.Fatalf("checkClockSkew() error = %v", err)
+	}
+
+	expected := []NodeClockSkew{
+		{Node: "ahead", Offset: 2 * time.Minute},
+		{Node: "behind", Offset: -10 * time.Minute},
+	}
+	if len(status.ClockSkew) != len(expected) {
+		t.Fatalf("ClockSkew = %v, want %v", status.ClockSkew, expected)
+	}
+	for i, skew := range status.ClockSkew {
+		if skew != expected[i] {
+			t.Errorf("ClockSkew[%d] = %v, want %v", i, skew, expected[i])
+		}
+	}
+
+	results := clockSkewFindings(status)
+	if len(results) != 2 || results[0].Severity != findings.SeverityWarning || results[1].Severity != findings.SeverityCritical {
+		t.Errorf("clockSkewFindings() = %v, want a warning for ahead and a critical finding for behind", results)
+	}
+}
+
+func TestCheckClockSkewFromDump(t *testing.T) {
+	client, err := NewKubeClientFromDump("testdata/dump")
+	if err != nil {
+		t.Fatalf("NewKubeClientFromDump() error = %v", err)
+	}
+
+	status, err := client.CheckClusterHealthWithOptions(context.Background(), HealthCheckOptions{Checks: []string{"clock-skew"}})
+	if err != nil {
+		t.Fatalf("CheckClusterHealthWithOptions() error = %v", err)
+	}
+	if status.RanCheck("clock-skew") || len(status.SkippedChecks) != 1 || status.SkippedChecks[0] != "clock-skew" {
+		t.Errorf("ChecksRun = %v, SkippedChecks = %v, want clock-skew skipped", status.ChecksRun, status.SkippedChecks)
+	}
+}
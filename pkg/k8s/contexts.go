@@ -0,0 +1,106 @@
+package k8s
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// KubeContext describes a kubeconfig context and the cluster it points at
+type KubeContext struct {
+	Name      string
+	Cluster   string // Name of the kubeconfig cluster entry
+	Server    string
+	Namespace string
+	Current   bool
+	// EKS is set when the server is an EKS API endpoint
+	EKS bool
+	// EKSCluster is the EKS cluster name from the cluster ARN or the "aws eks get-token"
+	// arguments, when known
+	EKSCluster string
+	Region     string
+}
+
+// IsEKSEndpoint returns true if the API server URL is an EKS cluster endpoint
+func IsEKSEndpoint(server string) bool {
+	u, err := url.Parse(server)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	return strings.HasSuffix(host, ".eks.amazonaws.com") || strings.HasSuffix(host, ".eks.amazonaws.com.cn")
+}
+
+// eksEndpointRegion returns the region of an EKS endpoint such as
+// https://ABC.gr7.us-east-1.eks.amazonaws.com
+func eksEndpointRegion(server string) string {
+	u, err := url.Parse(server)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(u.Hostname(), ".")
+	for i, part := range parts {
+		if part == "eks" && i > 0 {
+			return parts[i-1]
+		}
+	}
+	return ""
+}
+
+// eksClusterName returns the EKS cluster name from a cluster ARN or from the exec plugin
+// arguments of the context's user
+func eksClusterName(clusterEntry string, user *api.AuthInfo) string {
+	if strings.HasPrefix(clusterEntry, "arn:") {
+		if i := strings.LastIndex(clusterEntry, "cluster/"); i >= 0 {
+			return clusterEntry[i+len("cluster/"):]
+		}
+	}
+	if user == nil || user.Exec == nil {
+		return ""
+	}
+	args := user.Exec.Args
+	for i, arg := range args {
+		if (arg == "--cluster-name" || arg == "--cluster-id" || arg == "-i") && i+1 < len(args) {
+			return args[i+1]
+		}
+		if v, ok := strings.CutPrefix(arg, "--cluster-name="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// ListKubeContexts lists the contexts in the kubeconfig file, defaulting to
+// ~/.kube/config, sorted by name
+func ListKubeContexts(kubeconfig string) ([]KubeContext, error) {
+	path := defaultKubeconfigPath(kubeconfig)
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig %s: %w", path, err)
+	}
+
+	var contexts []KubeContext
+	for name, kctx := range config.Contexts {
+		c := KubeContext{
+			Name:      name,
+			Cluster:   kctx.Cluster,
+			Namespace: kctx.Namespace,
+			Current:   name == config.CurrentContext,
+		}
+		if cluster, ok := config.Clusters[kctx.Cluster]; ok {
+			c.Server = cluster.Server
+		}
+		c.EKS = IsEKSEndpoint(c.Server)
+		if c.EKS {
+			c.Region = eksEndpointRegion(c.Server)
+			c.EKSCluster = eksClusterName(kctx.Cluster, config.AuthInfos[kctx.AuthInfo])
+		}
+		contexts = append(contexts, c)
+	}
+	sort.Slice(contexts, func(i, j int) bool { return contexts[i].Name < contexts[j].Name })
+	return contexts, nil
+}
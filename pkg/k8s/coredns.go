@@ -0,0 +1,255 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// dnsBenchmarkImage provides dig, which reports the query time of each lookup
+	dnsBenchmarkImage = "registry.k8s.io/e2e-test-images/jessie-dnsutils:1.3"
+	// nodesPerCoreDNSReplica matches the cluster-proportional-autoscaler default for CoreDNS
+	nodesPerCoreDNSReplica = 16
+)
+
+var (
+	corefileCachePattern   = regexp.MustCompile(`(?m)^\s*cache\s+(\d+)`)
+	corefileForwardPattern = regexp.MustCompile(`(?m)^\s*forward\s+\.\s+([^{\n]+)`)
+	digQueryTimePattern    = regexp.MustCompile(`Query time: (\d+) msec`)
+	metricLinePattern      = regexp.MustCompile(`^(\w+)(?:\{(.*)\})?\s+([0-9.eE+-]+|\+Inf|NaN)$`)
+	metricLabelLePattern   = regexp.MustCompile(`le="([^"]+)"`)
+	metricLabelNXDomain    = regexp.MustCompile(`rcode="NXDOMAIN"`)
+)
+
+// CoreDNSReport contains CoreDNS scaling, configuration and latency information
+type CoreDNSReport struct {
+	Replicas            int32
+	Autoscaled          bool
+	Nodes               int
+	RecommendedReplicas int32
+	Underprovisioned    bool
+	CacheTTL            string
+	Forward             string
+	ForwardFailures     float64 // coredns_forward_healthcheck_failures_total across pods
+	Requests            float64 // coredns_dns_requests_total across pods
+	NXDomainResponses   float64 // NXDOMAIN responses, inflated by search domain expansion
+	Latency             *DNSLatency
+}
+
+// DNSLatency contains DNS resolution time percentiles in milliseconds
+type DNSLatency struct {
+	Source  string // "metrics" or "benchmark"
+	Samples int
+	P50     float64
+	P99     float64
+}
+
+// GetCoreDNSReport reports CoreDNS replica count relative to cluster size, whether it is
+// autoscaled, its cache TTL and forward configuration, and query latency from CoreDNS
+// Prometheus metrics when the metrics endpoint is reachable
+func (k *KubeClient) GetCoreDNSReport(ctx context.Context) (*CoreDNSReport, error) {
+	report := &CoreDNSReport{}
+
+	deployment, err := k.Clientset.AppsV1().Deployments("kube-system").Get(ctx, "coredns", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coredns deployment: %w", err)
+	}
+	if deployment.Spec.Replicas != nil {
+		report.Replicas = *deployment.Spec.Replicas
+	}
+
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	report.Nodes = len(nodes.Items)
+
+	report.Autoscaled = k.isCoreDNSAutoscaled(ctx)
+	report.RecommendedReplicas = int32(math.Max(2, math.Ceil(float64(report.Nodes)/nodesPerCoreDNSReplica)))
+	report.Underprovisioned = !report.Autoscaled && report.Replicas < report.RecommendedReplicas
+
+	if cm, err := k.Clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "coredns", metav1.GetOptions{}); err == nil {
+		corefile := cm.Data["Corefile"]
+		if match := corefileCachePattern.FindStringSubmatch(corefile); match != nil {
+			report.CacheTTL = match[1] + "s"
+		}
+		if match := corefileForwardPattern.FindStringSubmatch(corefile); match != nil {
+			report.Forward = strings.TrimSpace(match[1])
+		}
+	}
+
+	// Metrics are best effort, since the API server proxy may not be able to reach the pods
+	pods, err := k.Clientset.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{
+		LabelSelector: "k8s-app=kube-dns",
+	})
+	if err == nil {
+		buckets := make(map[float64]float64)
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+			metrics, err := k.Clientset.CoreV1().Pods(pod.Namespace).ProxyGet("http", pod.Name, "9153", "/metrics", nil).DoRaw(ctx)
+			if err != nil {
+				continue
+			}
+			parseCoreDNSMetrics(string(metrics), buckets, report)
+		}
+		if latency := latencyFromBuckets(buckets); latency != nil {
+			report.Latency = latency
+		}
+	}
+
+	return report, nil
+}
+
+// isCoreDNSAutoscaled returns true if CoreDNS is scaled by an HPA or a
+// cluster-proportional-autoscaler deployment
+func (k *KubeClient) isCoreDNSAutoscaled(ctx context.Context) bool {
+	hpas, err := k.Clientset.AutoscalingV2().HorizontalPodAutoscalers("kube-system").List(ctx, metav1.ListOptions{})
+	if err == nil {
+		for _, hpa := range hpas.Items {
+			if hpa.Spec.ScaleTargetRef.Name == "coredns" {
+				return true
+			}
+		}
+	}
+
+	deployments, err := k.Clientset.AppsV1().Deployments("kube-system").List(ctx, metav1.ListOptions{})
+	if err == nil {
+		for _, d := range deployments.Items {
+			if strings.Contains(d.Name, "dns") && strings.Contains(d.Name, "autoscaler") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseCoreDNSMetrics adds the request duration histogram buckets to buckets and the forward
+// health check failure, request and NXDOMAIN response counts to report
+func parseCoreDNSMetrics(metrics string, buckets map[float64]float64, report *CoreDNSReport) {
+	scanner := bufio.NewScanner(strings.NewReader(metrics))
+	for scanner.Scan() {
+		match := metricLinePattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[3], 64)
+		if err != nil {
+			continue
+		}
+
+		switch match[1] {
+		case "coredns_dns_request_duration_seconds_bucket":
+			le := metricLabelLePattern.FindStringSubmatch(match[2])
+			if le == nil {
+				continue
+			}
+			bound, err := strconv.ParseFloat(le[1], 64)
+			if err != nil {
+				continue
+			}
+			buckets[bound] += value
+		case "coredns_forward_healthcheck_failures_total":
+			report.ForwardFailures += value
+		case "coredns_dns_requests_total":
+			report.Requests += value
+		case "coredns_dns_responses_total":
+			if metricLabelNXDomain.MatchString(match[2]) {
+				report.NXDomainResponses += value
+			}
+		}
+	}
+}
+
+// latencyFromBuckets estimates p50/p99 from cumulative histogram buckets using each
+// bucket's upper bound
+func latencyFromBuckets(buckets map[float64]float64) *DNSLatency {
+	total := buckets[math.Inf(1)]
+	if total == 0 {
+		return nil
+	}
+
+	bounds := make([]float64, 0, len(buckets))
+	for bound := range buckets {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+
+	quantile := func(q float64) float64 {
+		for _, bound := range bounds {
+			if buckets[bound] >= q*total {
+				return bound * 1000
+			}
+		}
+		return math.Inf(1)
+	}
+
+	return &DNSLatency{Source: "metrics", Samples: int(total), P50: quantile(0.5), P99: quantile(0.99)}
+}
+
+// RunDNSBenchmark runs a short-lived pod that resolves hostname the given number of times
+// and returns the resolution time percentiles
+func (k *KubeClient) RunDNSBenchmark(ctx context.Context, namespace, hostname string, queries int) (*DNSLatency, error) {
+	script := fmt.Sprintf("for i in $(seq 1 %d); do dig +tries=1 +time=2 %s | grep 'Query time'; done", queries, hostname)
+	benchmarkPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "dns-benchmark-",
+			Namespace:    namespace,
+			Labels:       diagnosticPodLabels(),
+		},
+		Spec: k.diagnosticPodSpec("dns-benchmark", dnsBenchmarkImage, []string{"sh", "-c", script}),
+	}
+
+	pod, err := k.Clientset.CoreV1().Pods(namespace).Create(ctx, benchmarkPod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create benchmark pod: %w", err)
+	}
+	defer k.Clientset.CoreV1().Pods(namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+
+	// Wait for pod completion
+	phase, err := k.waitForPodCompletion(ctx, namespace, pod.Name)
+	if err != nil {
+		return nil, err
+	}
+	if phase == corev1.PodFailed {
+		return nil, fmt.Errorf("DNS benchmark pod failed")
+	}
+
+	matches, err := k.ScanPodLogs(ctx, namespace, pod.Name, "", []string{"Query time"})
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []float64
+	for _, match := range digQueryTimePattern.FindAllStringSubmatch(joinLogMatches(matches), -1) {
+		ms, err := strconv.ParseFloat(match[1], 64)
+		if err == nil {
+			samples = append(samples, ms)
+		}
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no successful DNS queries in benchmark")
+	}
+	sort.Float64s(samples)
+
+	percentile := func(q float64) float64 {
+		index := int(math.Ceil(q*float64(len(samples)))) - 1
+		if index < 0 {
+			index = 0
+		}
+		return samples[index]
+	}
+
+	return &DNSLatency{Source: "benchmark", Samples: len(samples), P50: percentile(0.5), P99: percentile(0.99)}, nil
+}
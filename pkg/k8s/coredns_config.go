@@ -0,0 +1,237 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// coreDNSLoopSignature is logged by the loop plugin before CoreDNS exits:
+// [FATAL] plugin/loop: Loop (127.0.0.1:55953 -> :53) detected for zone "."
+const coreDNSLoopSignature = "plugin/loop: Loop"
+
+// DNSConfigIssue is a CoreDNS misconfiguration and how to fix it
+type DNSConfigIssue struct {
+	Problem string
+	Fix     string
+}
+
+// DNSConfigReport holds the CoreDNS root zone configuration and the known misconfigurations
+// found in the Corefile, the deployment and the CoreDNS logs
+type DNSConfigReport struct {
+	Plugins   []string // Plugins of the root zone server block, in Corefile order
+	Forward   []string // Upstreams of the root zone forward plugin
+	DNSPolicy corev1.DNSPolicy
+	Issues    []DNSConfigIssue
+}
+
+// GetDNSConfigReport checks CoreDNS for the well-known failure signatures of a fresh
+// cluster: missing ready or health plugins that fail the probes, forward upstreams that
+// lead back to CoreDNS, and the loop plugin firing in the logs
+func (k *KubeClient) GetDNSConfigReport(ctx context.Context) (*DNSConfigReport, error) {
+	cm, err := k.Clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "coredns", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coredns configmap: %w", err)
+	}
+	deployment, err := k.Clientset.AppsV1().Deployments("kube-system").Get(ctx, "coredns", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coredns deployment: %w", err)
+	}
+
+	var serviceIPs []string
+	if svc, err := k.Clientset.CoreV1().Services("kube-system").Get(ctx, "kube-dns", metav1.GetOptions{}); err == nil {
+		serviceIPs = svc.Spec.ClusterIPs
+	}
+
+	report := &DNSConfigReport{DNSPolicy: deployment.Spec.Template.Spec.DNSPolicy}
+	if report.DNSPolicy == "" {
+		report.DNSPolicy = corev1.DNSClusterFirst
+	}
+	var probes []*corev1.Probe
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		if c.Name == "coredns" {
+			probes = []*corev1.Probe{c.ReadinessProbe, c.LivenessProbe}
+		}
+	}
+	analyzeCorefile(cm.Data["Corefile"], report, serviceIPs, probes)
+
+	// The loop plugin makes CoreDNS exit, so crashlooping pods have it in their previous logs
+	pods, err := k.Clientset.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{
+		LabelSelector: "k8s-app=kube-dns",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coredns pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != "coredns" {
+				continue
+			}
+			matches, err := k.scanPodLogs(ctx, pod.Namespace, pod.Name, cs.Name, cs.RestartCount > 0, []string{coreDNSLoopSignature})
+			if err != nil || len(matches) == 0 {
+				continue
+			}
+			report.Issues = append(report.Issues, DNSConfigIssue{
+				Problem: fmt.Sprintf("The loop plugin detected a forwarding loop in pod %s and stopped CoreDNS: %s", pod.Name, matches[len(matches)-1].Line),
+				Fix: "The upstream CoreDNS forwards to sends queries back to it. On nodes with systemd-resolved, /etc/resolv.conf points at 127.0.0.53: " +
+					"set the kubelet --resolv-conf to /run/systemd/resolve/resolv.conf, or forward to the VPC resolver (169.254.169.253) instead of /etc/resolv.conf",
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// analyzeCorefile checks the root zone server block for missing ready and health plugins
+// and for forward upstreams that lead back to CoreDNS
+func analyzeCorefile(corefile string, report *DNSConfigReport, serviceIPs []string, probes []*corev1.Probe) {
+	plugins, ok := rootZonePlugins(corefile)
+	if !ok {
+		report.Issues = append(report.Issues, DNSConfigIssue{
+			Problem: "The Corefile has no server block for the root zone (.:53), so CoreDNS does not resolve names outside the cluster",
+			Fix:     "Restore the .:53 server block of the EKS default Corefile",
+		})
+		return
+	}
+	for _, p := range plugins {
+		report.Plugins = append(report.Plugins, p[0])
+		if p[0] == "forward" && len(p) > 2 {
+			report.Forward = p[2:]
+		}
+	}
+
+	has := func(name string) bool {
+		for _, p := range report.Plugins {
+			if p == name {
+				return true
+			}
+		}
+		return false
+	}
+	probePath := func(path string) bool {
+		for _, probe := range probes {
+			if probe != nil && probe.HTTPGet != nil && probe.HTTPGet.Path == path {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !has("ready") {
+		problem := "The Corefile has no ready plugin, so nothing serves :8181/ready"
+		if probePath("/ready") {
+			problem += " and the readiness probe keeps CoreDNS pods out of the kube-dns Service"
+		}
+		report.Issues = append(report.Issues, DNSConfigIssue{Problem: problem, Fix: "Add \"ready\" to the .:53 server block"})
+	}
+	if !has("health") {
+		problem := "The Corefile has no health plugin, so nothing serves :8080/health"
+		if probePath("/health") {
+			problem += " and the liveness probe makes the kubelet restart CoreDNS in a loop"
+		}
+		report.Issues = append(report.Issues, DNSConfigIssue{Problem: problem, Fix: "Add \"health { lameduck 5s }\" to the .:53 server block"})
+	}
+	if !has("loop") {
+		report.Issues = append(report.Issues, DNSConfigIssue{
+			Problem: "The Corefile has no loop plugin, so a forwarding loop exhausts CoreDNS memory instead of being reported",
+			Fix:     "Add \"loop\" to the .:53 server block",
+		})
+	}
+
+	for _, upstream := range report.Forward {
+		switch {
+		case upstream == "/etc/resolv.conf" && report.DNSPolicy != corev1.DNSDefault:
+			report.Issues = append(report.Issues, DNSConfigIssue{
+				Problem: fmt.Sprintf("forward . /etc/resolv.conf with dnsPolicy %s: the pod's resolv.conf points at the kube-dns Service, so CoreDNS forwards to itself", report.DNSPolicy),
+				Fix:     "Set dnsPolicy: Default on the coredns deployment so it reads the node's resolv.conf",
+			})
+		case isLoopbackUpstream(upstream):
+			report.Issues = append(report.Issues, DNSConfigIssue{
+				Problem: fmt.Sprintf("forward . %s sends queries to the CoreDNS pod itself", upstream),
+				Fix:     "Forward to /etc/resolv.conf with dnsPolicy: Default, or to the VPC resolver (169.254.169.253)",
+			})
+		case containsUpstream(serviceIPs, upstream):
+			report.Issues = append(report.Issues, DNSConfigIssue{
+				Problem: fmt.Sprintf("forward . %s sends queries to the kube-dns Service, which is CoreDNS itself", upstream),
+				Fix:     "Forward to /etc/resolv.conf with dnsPolicy: Default, or to the VPC resolver (169.254.169.253)",
+			})
+		}
+	}
+}
+
+// rootZonePlugins returns the plugin lines, split into fields, of the server block serving
+// the root zone. Nested plugin blocks are skipped.
+func rootZonePlugins(corefile string) ([][]string, bool) {
+	var plugins [][]string
+	found, inRoot := false, false
+	depth := 0
+	scanner := bufio.NewScanner(strings.NewReader(corefile))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(strings.NewReplacer("{", " { ", "}", " } ").Replace(line))
+		if len(fields) == 0 {
+			continue
+		}
+
+		if depth == 0 && fields[len(fields)-1] == "{" {
+			inRoot = false
+			for _, key := range fields[:len(fields)-1] {
+				zone := strings.TrimPrefix(strings.TrimPrefix(key, "dns://"), "dns:/")
+				if zone == "." || strings.HasPrefix(zone, ".:") {
+					inRoot, found = true, true
+				}
+			}
+		} else if depth == 1 && inRoot && fields[0] != "}" {
+			plugin := fields
+			if plugin[len(plugin)-1] == "{" {
+				plugin = plugin[:len(plugin)-1]
+			}
+			plugins = append(plugins, plugin)
+		}
+
+		for _, f := range fields {
+			switch f {
+			case "{":
+				depth++
+			case "}":
+				depth--
+			}
+		}
+	}
+	return plugins, found
+}
+
+// isLoopbackUpstream returns true if a forward upstream is a loopback address
+func isLoopbackUpstream(upstream string) bool {
+	host := strings.TrimPrefix(upstream, "dns://")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// containsUpstream returns true if upstream, with or without a port, is one of the IPs
+func containsUpstream(ips []string, upstream string) bool {
+	host := strings.TrimPrefix(upstream, "dns://")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for _, ip := range ips {
+		if ip == host {
+			return true
+		}
+	}
+	return false
+}
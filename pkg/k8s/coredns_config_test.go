@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const eksCorefile = `.:53 {
+    errors
+    health {
+        lameduck 5s
+      }
+    ready
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+      pods insecure
+      fallthrough in-addr.arpa ip6.arpa
+    }
+    prometheus :9153
+    forward . /etc/resolv.conf
+    cache 30
+    loop
+    reload
+    loadbalance
+}
+`
+
+func TestAnalyzeCorefile(t *testing.T) {
+	readiness := &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready"}}}
+
+	tests := []struct {
+		name      string
+		corefile  string
+		dnsPolicy corev1.DNSPolicy
+		want      []string
+	}{
+		{
+			name:      "eks default",
+			corefile:  eksCorefile,
+			dnsPolicy: corev1.DNSDefault,
+		},
+		{
+			name:      "cluster first resolv.conf",
+			corefile:  eksCorefile,
+			dnsPolicy: corev1.DNSClusterFirst,
+			want:      []string{"CoreDNS forwards to itself"},
+		},
+		{
+			name:      "no ready plugin",
+			corefile:  strings.Replace(eksCorefile, "    ready\n", "", 1),
+			dnsPolicy: corev1.DNSDefault,
+			want:      []string{"no ready plugin, so nothing serves :8181/ready and the readiness probe"},
+		},
+		{
+			name:      "forward to kube-dns and no loop plugin",
+			corefile:  strings.NewReplacer("/etc/resolv.conf", "10.100.0.10", "    loop\n", "").Replace(eksCorefile),
+			dnsPolicy: corev1.DNSDefault,
+			want:      []string{"no loop plugin", "forward . 10.100.0.10 sends queries to the kube-dns Service"},
+		},
+		{
+			name:      "no root zone",
+			corefile:  "cluster.local:53 {\n    kubernetes\n}\n",
+			dnsPolicy: corev1.DNSDefault,
+			want:      []string{"no server block for the root zone"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &DNSConfigReport{DNSPolicy: tt.dnsPolicy}
+			analyzeCorefile(tt.corefile, report, []string{"10.100.0.10"}, []*corev1.Probe{readiness})
+
+			if len(report.Issues) != len(tt.want) {
+				t.Fatalf("Issues = %+v, want %d", report.Issues, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(report.Issues[i].Problem, want) {
+					t.Errorf("Issues[%d] = %q, want it to contain %q", i, report.Issues[i].Problem, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRootZonePlugins(t *testing.T) {
+	plugins, ok := rootZonePlugins(eksCorefile)
+	if !ok {
+		t.Fatal("rootZonePlugins() found no root zone")
+	}
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p[0])
+	}
+	want := "errors health ready kubernetes prometheus forward cache loop reload loadbalance"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("rootZonePlugins() = %s, want %s", got, want)
+	}
+}
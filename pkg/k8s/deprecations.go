@@ -0,0 +1,166 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// DeprecatedAPI describes a deprecated or removed API version of a resource
+type DeprecatedAPI struct {
+	GroupVersion string // deprecated group/version, e.g. "batch/v1beta1"
+	Resource     string // plural resource name, e.g. "cronjobs"
+	Kind         string
+	ReplacedBy   string // replacement group/version, empty if the API has no replacement
+	DeprecatedIn string // Kubernetes minor version the API was deprecated in, e.g. "1.21"
+	RemovedIn    string // Kubernetes minor version the API was removed in, e.g. "1.25"
+}
+
+// deprecatedAPIs is the list of deprecated APIs checked by the health check. New
+// deprecations only need an entry here.
+var deprecatedAPIs = []DeprecatedAPI{
+	{"extensions/v1beta1", "ingresses", "Ingress", "networking.k8s.io/v1", "1.14", "1.22"},
+	{"networking.k8s.io/v1beta1", "ingresses", "Ingress", "networking.k8s.io/v1", "1.19", "1.22"},
+	{"networking.k8s.io/v1beta1", "ingressclasses", "IngressClass", "networking.k8s.io/v1", "1.19", "1.22"},
+	{"apiextensions.k8s.io/v1beta1", "customresourcedefinitions", "CustomResourceDefinition", "apiextensions.k8s.io/v1", "1.16", "1.22"},
+	{"admissionregistration.k8s.io/v1beta1", "mutatingwebhookconfigurations", "MutatingWebhookConfiguration", "admissionregistration.k8s.io/v1", "1.16", "1.22"},
+	{"admissionregistration.k8s.io/v1beta1", "validatingwebhookconfigurations", "ValidatingWebhookConfiguration", "admissionregistration.k8s.io/v1", "1.16", "1.22"},
+	{"rbac.authorization.k8s.io/v1beta1", "clusterroles", "ClusterRole", "rbac.authorization.k8s.io/v1", "1.17", "1.22"},
+	{"rbac.authorization.k8s.io/v1beta1", "clusterrolebindings", "ClusterRoleBinding", "rbac.authorization.k8s.io/v1", "1.17", "1.22"},
+	{"rbac.authorization.k8s.io/v1beta1", "roles", "Role", "rbac.authorization.k8s.io/v1", "1.17", "1.22"},
+	{"rbac.authorization.k8s.io/v1beta1", "rolebindings", "RoleBinding", "rbac.authorization.k8s.io/v1", "1.17", "1.22"},
+	{"scheduling.k8s.io/v1beta1", "priorityclasses", "PriorityClass", "scheduling.k8s.io/v1", "1.14", "1.22"},
+	{"storage.k8s.io/v1beta1", "csidrivers", "CSIDriver", "storage.k8s.io/v1", "1.19", "1.22"},
+	{"storage.k8s.io/v1beta1", "storageclasses", "StorageClass", "storage.k8s.io/v1", "1.19", "1.22"},
+	{"batch/v1beta1", "cronjobs", "CronJob", "batch/v1", "1.21", "1.25"},
+	{"discovery.k8s.io/v1beta1", "endpointslices", "EndpointSlice", "discovery.k8s.io/v1", "1.21", "1.25"},
+	{"autoscaling/v2beta1", "horizontalpodautoscalers", "HorizontalPodAutoscaler", "autoscaling/v2", "1.22", "1.25"},
+	{"autoscaling/v2beta2", "horizontalpodautoscalers", "HorizontalPodAutoscaler", "autoscaling/v2", "1.23", "1.26"},
+	{"policy/v1beta1", "poddisruptionbudgets", "PodDisruptionBudget", "policy/v1", "1.21", "1.25"},
+	{"policy/v1beta1", "podsecuritypolicies", "PodSecurityPolicy", "", "1.21", "1.25"},
+	{"node.k8s.io/v1beta1", "runtimeclasses", "RuntimeClass", "node.k8s.io/v1", "1.20", "1.25"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "flowschemas", "FlowSchema", "flowcontrol.apiserver.k8s.io/v1", "1.23", "1.26"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "flowschemas", "FlowSchema", "flowcontrol.apiserver.k8s.io/v1", "1.26", "1.29"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "flowschemas", "FlowSchema", "flowcontrol.apiserver.k8s.io/v1", "1.29", "1.32"},
+}
+
+// checkDeprecatedAPIs finds objects that were last applied with a deprecated or removed API
+// version. The API server converts objects to whatever version they are read with, so the
+// version a manifest was written against is taken from its last-applied-configuration.
+func (k *KubeClient) checkDeprecatedAPIs(ctx context.Context, status *ClusterHealthStatus) error {
+	if k.Config == nil {
+		return nil
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(k.Config)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	clusterMinor := 0
+	if version, err := k.Clientset.Discovery().ServerVersion(); err == nil {
+		clusterMinor = parseMinorVersion(version.Major + "." + version.Minor)
+	}
+
+	for _, api := range deprecatedAPIs {
+		// List through the replacement version if served, since objects written with the old
+		// version are also visible there, and fall back to the old version otherwise
+		listVersion := ""
+		if api.ReplacedBy != "" && k.servesResource(api.ReplacedBy, api.Resource) {
+			listVersion = api.ReplacedBy
+		} else if k.servesResource(api.GroupVersion, api.Resource) {
+			listVersion = api.GroupVersion
+		}
+		if listVersion == "" {
+			continue
+		}
+
+		gv, err := schema.ParseGroupVersion(listVersion)
+		if err != nil {
+			continue
+		}
+		objects, err := dynamicClient.Resource(gv.WithResource(api.Resource)).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			continue
+		}
+
+		for _, obj := range objects.Items {
+			// APIs without a replacement are only listed through the deprecated version
+			if api.ReplacedBy != "" && lastAppliedAPIVersion(obj.GetAnnotations()) != api.GroupVersion {
+				continue
+			}
+
+			name := obj.GetName()
+			if obj.GetNamespace() != "" {
+				name = obj.GetNamespace() + "/" + name
+			}
+			status.DeprecatedAPIs = append(status.DeprecatedAPIs, describeDeprecatedAPI(api, name, clusterMinor))
+		}
+	}
+
+	return nil
+}
+
+// servesResource returns true if the API server serves the resource in the group/version
+func (k *KubeClient) servesResource(groupVersion, resource string) bool {
+	resources, err := k.Clientset.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return false
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// lastAppliedAPIVersion returns the apiVersion from the kubectl last-applied-configuration
+// annotation, or an empty string if it is not present
+func lastAppliedAPIVersion(annotations map[string]string) string {
+	lastApplied, ok := annotations["kubectl.kubernetes.io/last-applied-configuration"]
+	if !ok {
+		return ""
+	}
+	var manifest struct {
+		APIVersion string `json:"apiVersion"`
+	}
+	if err := json.Unmarshal([]byte(lastApplied), &manifest); err != nil {
+		return ""
+	}
+	return manifest.APIVersion
+}
+
+func describeDeprecatedAPI(api DeprecatedAPI, name string, clusterMinor int) string {
+	state := "deprecated in " + api.DeprecatedIn + ", removed in " + api.RemovedIn
+	if clusterMinor > 0 && clusterMinor >= parseMinorVersion(api.RemovedIn) {
+		state = "removed in " + api.RemovedIn
+	}
+
+	replacement := "no replacement"
+	if api.ReplacedBy != "" {
+		replacement = "use " + api.ReplacedBy
+	}
+
+	return fmt.Sprintf("%s %s uses %s (%s, %s)", api.Kind, name, api.GroupVersion, state, replacement)
+}
+
+// parseMinorVersion returns the minor version from a "1.x" version string, ignoring any
+// suffix such as the "+" EKS appends
+func parseMinorVersion(version string) int {
+	parts := strings.SplitN(version, ".", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+	minor, err := strconv.Atoi(strings.TrimRight(parts[1], "+"))
+	if err != nil {
+		return 0
+	}
+	return minor
+}
@@ -0,0 +1,28 @@
+package k8s
+
+import (
+	"testing"
+)
+
+func TestDiagnosticPodSpec(t *testing.T) {
+	client := &KubeClient{}
+	spec := client.diagnosticPodSpec("dns-test", DefaultDiagImage, []string{"nslookup", "kubernetes"})
+	if got := spec.Containers[0].Image; got != "busybox" {
+		t.Errorf("Image = %s, want busybox", got)
+	}
+	if len(spec.ImagePullSecrets) != 0 {
+		t.Errorf("ImagePullSecrets = %v, want none", spec.ImagePullSecrets)
+	}
+
+	client = &KubeClient{
+		DiagImage:            "111122223333.dkr.ecr.eu-west-1.amazonaws.com/busybox:1.36",
+		DiagImagePullSecrets: []string{"ecr-pull"},
+	}
+	spec = client.diagnosticPodSpec("dns-benchmark", dnsBenchmarkImage, []string{"sh", "-c", "dig kubernetes"})
+	if got := spec.Containers[0].Image; got != client.DiagImage {
+		t.Errorf("Image = %s, want %s", got, client.DiagImage)
+	}
+	if len(spec.ImagePullSecrets) != 1 || spec.ImagePullSecrets[0].Name != "ecr-pull" {
+		t.Errorf("ImagePullSecrets = %v, want ecr-pull", spec.ImagePullSecrets)
+	}
+}
@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// mirrorPodAnnotation marks static pods managed by the kubelet
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// DrainPod describes how draining a node affects one of its pods
+type DrainPod struct {
+	Namespace string
+	Name      string
+	Owner     string // Kind/name of the controlling workload, or empty for bare pods
+	// DaemonSet pods are ignored by drain and stay on the node
+	DaemonSet bool
+	// Static pods are managed by the kubelet and cannot be evicted
+	Static bool
+	// BlockingPDBs lists PodDisruptionBudgets covering the pod that allow no disruptions
+	BlockingPDBs []string
+	// EmptyDirVolumes lose their data on eviction
+	EmptyDirVolumes []string
+	// HostPathVolumes keep their data on the node the pod leaves
+	HostPathVolumes []string
+}
+
+// Bare returns true if the pod has no controller, so it is deleted by a drain and not
+// recreated elsewhere
+func (p DrainPod) Bare() bool {
+	return p.Owner == "" && !p.Static
+}
+
+// Blocking returns true if the pod prevents a plain drain from completing
+func (p DrainPod) Blocking() bool {
+	return len(p.BlockingPDBs) > 0 || p.Bare()
+}
+
+// DrainAssessment is a read-only assessment of what draining a node would affect
+type DrainAssessment struct {
+	Node     string
+	Cordoned bool
+	Pods     []DrainPod
+}
+
+// Ready returns true if no pod blocks the drain
+func (a *DrainAssessment) Ready() bool {
+	for _, p := range a.Pods {
+		if p.Blocking() {
+			return false
+		}
+	}
+	return true
+}
+
+// AssessNodeDrain lists the pods on a node and reports which would block a drain: pods
+// covered by a PodDisruptionBudget that allows no disruptions and bare pods without a
+// controller, along with pods using local storage and DaemonSet pods that stay. It does
+// not cordon or drain the node.
+func (k *KubeClient) AssessNodeDrain(ctx context.Context, nodeName string) (*DrainAssessment, error) {
+	node, err := k.Clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+
+	pods, err := k.Clientset.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", nodeName, err)
+	}
+
+	pdbs, err := k.Clientset.PolicyV1().PodDisruptionBudgets(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	assessment := &DrainAssessment{Node: node.Name, Cordoned: node.Spec.Unschedulable}
+	owners := make(map[string]string)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		p := DrainPod{Namespace: pod.Namespace, Name: pod.Name}
+		_, p.Static = pod.Annotations[mirrorPodAnnotation]
+		if ref := metav1.GetControllerOf(&pod); ref != nil {
+			p.DaemonSet = ref.Kind == "DaemonSet"
+			p.Owner = k.getPodOwner(ctx, &pod, owners)
+		}
+
+		for _, v := range pod.Spec.Volumes {
+			switch {
+			case v.EmptyDir != nil:
+				p.EmptyDirVolumes = append(p.EmptyDirVolumes, v.Name)
+			case v.HostPath != nil:
+				p.HostPathVolumes = append(p.HostPathVolumes, v.Name+" ("+v.HostPath.Path+")")
+			}
+		}
+
+		// DaemonSet and static pods are not evicted, so PDBs do not apply to them
+		if !p.DaemonSet && !p.Static {
+			for _, pdb := range pdbs.Items {
+				if pdb.Namespace != pod.Namespace || pdb.Status.DisruptionsAllowed > 0 {
+					continue
+				}
+				selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+				if err != nil {
+					continue
+				}
+				if selector.Matches(labels.Set(pod.Labels)) {
+					p.BlockingPDBs = append(p.BlockingPDBs, pdb.Name)
+				}
+			}
+		}
+
+		assessment.Pods = append(assessment.Pods, p)
+	}
+
+	sort.Slice(assessment.Pods, func(i, j int) bool {
+		if assessment.Pods[i].Namespace != assessment.Pods[j].Namespace {
+			return assessment.Pods[i].Namespace < assessment.Pods[j].Namespace
+		}
+		return assessment.Pods[i].Name < assessment.Pods[j].Name
+	})
+
+	return assessment, nil
+}
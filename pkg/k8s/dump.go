@@ -0,0 +1,226 @@
+package k8s
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ekspeek/pkg/common/logger"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// DumpClusterFile is the name of the cached "aws eks describe-cluster" output in a dump
+// directory
+const DumpClusterFile = "cluster.json"
+
+// ErrNeedsLiveCluster is returned by a client serving a dump for operations a dump cannot
+// answer: creating, changing or deleting objects, such as diagnostic pods, and reading
+// container logs
+var ErrNeedsLiveCluster = errors.New("needs a live cluster")
+
+// DumpCluster holds the cluster details read from DumpClusterFile
+type DumpCluster struct {
+	Name            string `json:"name"`
+	Arn             string `json:"arn"`
+	Version         string `json:"version"`
+	PlatformVersion string `json:"platformVersion"`
+	Status          string `json:"status"`
+	Endpoint        string `json:"endpoint"`
+}
+
+// LoadDumpCluster reads the cached DescribeCluster output from a dump directory. It returns
+// nil when the directory has no DumpClusterFile.
+func LoadDumpCluster(dir string) (*DumpCluster, error) {
+	data, err := os.ReadFile(filepath.Join(dir, DumpClusterFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", DumpClusterFile, err)
+	}
+
+	var output struct {
+		Cluster DumpCluster `json:"cluster"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", DumpClusterFile, err)
+	}
+	return &output.Cluster, nil
+}
+
+// NewKubeClientFromDump creates a Kubernetes client that serves the objects found in the
+// "kubectl get ... -o yaml" (or json) files under dir instead of a live cluster. Objects of
+// kinds unknown to client-go, such as custom resources, are skipped. The server version is
+// taken from DumpClusterFile when present. Writes and log reads fail with
+// ErrNeedsLiveCluster.
+func NewKubeClientFromDump(dir string) (*KubeClient, error) {
+	var objects []runtime.Object
+	skipped := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() == DumpClusterFile {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+
+		decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+		for {
+			var raw runtime.RawExtension
+			if err := decoder.Decode(&raw); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			if len(raw.Raw) == 0 {
+				continue
+			}
+			decoded, n := decodeDumpObjects(raw.Raw)
+			objects = append(objects, decoded...)
+			skipped += n
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dump %s: %w", dir, err)
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no Kubernetes objects found in dump %s", dir)
+	}
+	logger.Debug("Loaded %d objects from dump %s, skipped %d of unknown kinds", len(objects), dir, skipped)
+
+	clientset := fake.NewSimpleClientset(objects...)
+	// Without these the fake clientset would accept diagnostic pods that never run
+	for _, verb := range []string{"create", "update", "patch", "delete", "delete-collection"} {
+		clientset.PrependReactor(verb, "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, fmt.Errorf("%s %s %w; %s is a dump", action.GetVerb(), action.GetResource().Resource, ErrNeedsLiveCluster, dir)
+		})
+	}
+	// The fake clientset applies label selectors but ignores field selectors, which
+	// several checks rely on
+	clientset.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list, ok := action.(k8stesting.ListActionImpl)
+		if !ok || list.GetListRestrictions().Fields.Empty() {
+			return false, nil, nil
+		}
+		obj, err := clientset.Tracker().List(list.GetResource(), list.GetKind(), list.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		items, err := meta.ExtractList(obj)
+		if err != nil {
+			return true, nil, err
+		}
+		var matched []runtime.Object
+		for _, item := range items {
+			if list.GetListRestrictions().Fields.Matches(dumpObjectFields(item)) {
+				matched = append(matched, item)
+			}
+		}
+		if err := meta.SetList(obj, matched); err != nil {
+			return true, nil, err
+		}
+		return true, obj, nil
+	})
+
+	cluster, err := LoadDumpCluster(dir)
+	if err != nil {
+		return nil, err
+	}
+	if cluster != nil && cluster.Version != "" {
+		if discovery, ok := clientset.Discovery().(*fakediscovery.FakeDiscovery); ok {
+			info := &version.Info{GitVersion: "v" + cluster.Version + ".0-eks"}
+			if parts := strings.SplitN(cluster.Version, ".", 2); len(parts) == 2 {
+				info.Major, info.Minor = parts[0], parts[1]
+			}
+			discovery.FakedServerVersion = info
+		}
+	}
+
+	return &KubeClient{Clientset: clientset, DumpDir: dir}, nil
+}
+
+// needsLiveCluster returns true if err is ErrNeedsLiveCluster
+func needsLiveCluster(err error) bool {
+	return errors.Is(err, ErrNeedsLiveCluster)
+}
+
+// requireLive returns ErrNeedsLiveCluster for an operation of a client serving a dump
+func (k *KubeClient) requireLive(operation string) error {
+	if k.DumpDir == "" {
+		return nil
+	}
+	return fmt.Errorf("%s %w; %s is a dump", operation, ErrNeedsLiveCluster, k.DumpDir)
+}
+
+// decodeDumpObjects decodes a YAML or JSON document into typed objects, expanding List
+// documents, and returns the number of objects skipped because their kind is unknown
+func decodeDumpObjects(data []byte) ([]runtime.Object, int) {
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return nil, 1
+	}
+
+	list, ok := obj.(*corev1.List)
+	if !ok {
+		return []runtime.Object{obj}, 0
+	}
+	var objects []runtime.Object
+	skipped := 0
+	for _, item := range list.Items {
+		decoded, n := decodeDumpObjects(item.Raw)
+		objects = append(objects, decoded...)
+		skipped += n
+	}
+	return objects, skipped
+}
+
+// dumpObjectFields returns the fields the API server supports in field selectors for the
+// kinds ekspeek filters by field
+func dumpObjectFields(obj runtime.Object) fields.Set {
+	set := fields.Set{}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		set["metadata.name"] = accessor.GetName()
+		set["metadata.namespace"] = accessor.GetNamespace()
+	}
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		set["spec.nodeName"] = o.Spec.NodeName
+		set["status.phase"] = string(o.Status.Phase)
+	case *corev1.Event:
+		set["reason"] = o.Reason
+		set["type"] = o.Type
+		set["involvedObject.kind"] = o.InvolvedObject.Kind
+		set["involvedObject.name"] = o.InvolvedObject.Name
+		set["involvedObject.namespace"] = o.InvolvedObject.Namespace
+	case *corev1.Secret:
+		set["type"] = string(o.Type)
+	}
+	return set
+}
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewKubeClientFromDump(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewKubeClientFromDump("testdata/dump")
+	if err != nil {
+		t.Fatalf("NewKubeClientFromDump() error = %v", err)
+	}
+
+	// Pods come from a List document in a subdirectory
+	pods, err := client.Clientset.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing pods: %v", err)
+	}
+	if len(pods.Items) != 2 {
+		t.Errorf("pods = %d, want 2 from the List document", len(pods.Items))
+	}
+	onNode, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=ip-10-0-1-10.eu-west-1.compute.internal",
+	})
+	if err != nil {
+		t.Fatalf("listing pods by node: %v", err)
+	}
+	if len(onNode.Items) != 1 || onNode.Items[0].Name != "coredns-5d8f7b8c6-abcde" {
+		t.Errorf("pods on node = %+v, want coredns only", onNode.Items)
+	}
+
+	nodes, err := client.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing nodes: %v", err)
+	}
+	if len(nodes.Items) != 1 {
+		t.Errorf("nodes = %d, want 1 from the JSON object", len(nodes.Items))
+	}
+
+	info, err := client.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		t.Fatalf("ServerVersion() error = %v", err)
+	}
+	if info.Major != "1" || info.Minor != "29" {
+		t.Errorf("ServerVersion() = %s.%s, want 1.29 from %s", info.Major, info.Minor, DumpClusterFile)
+	}
+}
+
+func TestDumpNeedsLiveCluster(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewKubeClientFromDump("testdata/dump")
+	if err != nil {
+		t.Fatalf("NewKubeClientFromDump() error = %v", err)
+	}
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "dns-test", Namespace: "default"}}
+	if _, err := client.Clientset.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{}); !errors.Is(err, ErrNeedsLiveCluster) {
+		t.Errorf("creating a pod: error = %v, want ErrNeedsLiveCluster", err)
+	}
+	if err := client.Clientset.CoreV1().Pods("kube-system").Delete(ctx, "aws-node-fghij", metav1.DeleteOptions{}); !errors.Is(err, ErrNeedsLiveCluster) {
+		t.Errorf("deleting a pod: error = %v, want ErrNeedsLiveCluster", err)
+	}
+	if _, err := client.ScanPodLogs(ctx, "kube-system", "coredns-5d8f7b8c6-abcde", "", []string{"error"}); !errors.Is(err, ErrNeedsLiveCluster) {
+		t.Errorf("ScanPodLogs() error = %v, want ErrNeedsLiveCluster", err)
+	}
+	if _, err := client.GetPodLogs(ctx, "kube-system", "coredns-5d8f7b8c6-abcde", ""); !errors.Is(err, ErrNeedsLiveCluster) {
+		t.Errorf("GetPodLogs() error = %v, want ErrNeedsLiveCluster", err)
+	}
+}
+
+func TestDecodeDumpObjects(t *testing.T) {
+	crd := []byte(`{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition","metadata":{"name":"nodepools.karpenter.sh"}}`)
+	if objects, skipped := decodeDumpObjects(crd); len(objects) != 0 || skipped != 1 {
+		t.Errorf("decodeDumpObjects(CRD) = %d objects, %d skipped, want the CRD skipped", len(objects), skipped)
+	}
+
+	list := []byte(`{"apiVersion":"v1","kind":"List","items":[
+		{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"a","namespace":"default"}},
+		{"apiVersion":"karpenter.sh/v1","kind":"NodePool","metadata":{"name":"default"}}
+	]}`)
+	objects, skipped := decodeDumpObjects(list)
+	if len(objects) != 1 || skipped != 1 {
+		t.Errorf("decodeDumpObjects(List) = %d objects, %d skipped, want 1 and 1", len(objects), skipped)
+	}
+}
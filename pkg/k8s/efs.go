@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// efsCSIDriver is the name of the EFS CSI driver
+const efsCSIDriver = "efs.csi.aws.com"
+
+// EFSVolume is an EFS file system and the PersistentVolumes that mount it
+type EFSVolume struct {
+	FileSystemID      string
+	PersistentVolumes []string
+}
+
+// GetEFSVolumes returns the EFS file systems referenced by PersistentVolumes of the EFS
+// CSI driver, sorted by file system ID
+func (k *KubeClient) GetEFSVolumes(ctx context.Context) ([]EFSVolume, error) {
+	pvs, err := k.Clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volumes: %w", err)
+	}
+
+	byID := make(map[string]*EFSVolume)
+	for _, pv := range pvs.Items {
+		csi := pv.Spec.CSI
+		if csi == nil || csi.Driver != efsCSIDriver {
+			continue
+		}
+		// The volume handle is fs-id, fs-id:/path or fs-id:/path:fsap-id
+		fsID, _, _ := strings.Cut(csi.VolumeHandle, ":")
+		if fsID == "" {
+			continue
+		}
+		vol, ok := byID[fsID]
+		if !ok {
+			vol = &EFSVolume{FileSystemID: fsID}
+			byID[fsID] = vol
+		}
+		vol.PersistentVolumes = append(vol.PersistentVolumes, pv.Name)
+	}
+
+	volumes := make([]EFSVolume, 0, len(byID))
+	for _, vol := range byID {
+		volumes = append(volumes, *vol)
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].FileSystemID < volumes[j].FileSystemID
+	})
+	return volumes, nil
+}
+
+// GetNodeInstances returns the names of the nodes backed by EC2 instances, keyed by the
+// instance ID taken from the provider ID (aws:///us-east-1a/i-0123). Fargate and other
+// nodes are skipped.
+func (k *KubeClient) GetNodeInstances(ctx context.Context) (map[string]string, error) {
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	instances := make(map[string]string)
+	for _, node := range nodes.Items {
+		id := node.Spec.ProviderID[strings.LastIndex(node.Spec.ProviderID, "/")+1:]
+		if !strings.HasPrefix(id, "i-") {
+			continue
+		}
+		instances[id] = node.Name
+	}
+	return instances, nil
+}
@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetEFSVolumes(t *testing.T) {
+	pv := func(name, driver, handle string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: driver, VolumeHandle: handle},
+			}},
+		}
+	}
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		pv("shared", efsCSIDriver, "fs-222"),
+		pv("data", efsCSIDriver, "fs-111:/data"),
+		pv("logs", efsCSIDriver, "fs-111:/logs:fsap-333"),
+		pv("ebs", "ebs.csi.aws.com", "vol-444"),
+	)}
+
+	got, err := client.GetEFSVolumes(context.Background())
+	if err != nil {
+		t.Fatalf("GetEFSVolumes() error = %v", err)
+	}
+	if len(got) != 2 || got[0].FileSystemID != "fs-111" || got[1].FileSystemID != "fs-222" {
+		t.Fatalf("GetEFSVolumes() = %+v, want fs-111 and fs-222", got)
+	}
+	if len(got[0].PersistentVolumes) != 2 {
+		t.Errorf("fs-111 volumes = %v, want data and logs", got[0].PersistentVolumes)
+	}
+}
+
+func TestGetNodeInstances(t *testing.T) {
+	node := func(name, providerID string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: corev1.NodeSpec{ProviderID: providerID}}
+	}
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		node("ec2", "aws:///us-east-1a/i-0123"),
+		node("fargate", "aws:///us-east-1b/0a1b/fargate-ip-10-0-1-2.ec2.internal"),
+		node("kind", ""),
+	)}
+
+	got, err := client.GetNodeInstances(context.Background())
+	if err != nil {
+		t.Fatalf("GetNodeInstances() error = %v", err)
+	}
+	want := map[string]string{"i-0123": "ec2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetNodeInstances() = %+v, want %+v", got, want)
+	}
+}
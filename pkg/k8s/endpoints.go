@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ServiceEndpoints contains the EndpointSlices backing a service and their readiness
+type ServiceEndpoints struct {
+	Service          string
+	Namespace        string
+	Selector         string
+	EndpointSlices   []string
+	Ready            []EndpointInfo
+	NotReady         []EndpointInfo
+	MatchingPods     int
+	SelectorMismatch bool // the service has a selector but it matches no pods
+}
+
+// EndpointInfo describes a single endpoint address and the pod behind it
+type EndpointInfo struct {
+	Address  string
+	PodName  string
+	NodeName string
+}
+
+// GetServiceEndpoints returns the EndpointSlices of a service, split into ready and not ready
+// endpoints, and whether the service's selector matches any pods
+func (k *KubeClient) GetServiceEndpoints(ctx context.Context, namespace, service string) (*ServiceEndpoints, error) {
+	svc, err := k.Clientset.CoreV1().Services(namespace).Get(ctx, service, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s/%s: %w", namespace, service, err)
+	}
+
+	result := &ServiceEndpoints{
+		Service:   svc.Name,
+		Namespace: svc.Namespace,
+	}
+
+	if len(svc.Spec.Selector) > 0 {
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		result.Selector = selector.String()
+
+		pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: result.Selector,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods for service %s/%s: %w", namespace, service, err)
+		}
+		result.MatchingPods = len(pods.Items)
+		result.SelectorMismatch = len(pods.Items) == 0
+	}
+
+	slices, err := k.Clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint slices for service %s/%s: %w", namespace, service, err)
+	}
+
+	for _, slice := range slices.Items {
+		result.EndpointSlices = append(result.EndpointSlices, slice.Name)
+		for _, endpoint := range slice.Endpoints {
+			for _, address := range endpoint.Addresses {
+				info := EndpointInfo{Address: address}
+				if endpoint.TargetRef != nil {
+					info.PodName = endpoint.TargetRef.Name
+				}
+				if endpoint.NodeName != nil {
+					info.NodeName = *endpoint.NodeName
+				}
+
+				// A nil ready condition means the endpoint should be treated as ready
+				if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+					result.Ready = append(result.Ready, info)
+				} else {
+					result.NotReady = append(result.NotReady, info)
+				}
+			}
+		}
+	}
+	sort.Strings(result.EndpointSlices)
+
+	return result, nil
+}
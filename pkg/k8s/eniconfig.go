@@ -0,0 +1,142 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var eniConfigGVR = schema.GroupVersionResource{Group: "crd.k8s.amazonaws.com", Version: "v1alpha1", Resource: "eniconfigs"}
+
+const (
+	customNetworkEnv       = "AWS_VPC_K8S_CNI_CUSTOM_NETWORK_CFG"
+	eniConfigLabelEnv      = "ENI_CONFIG_LABEL_DEF"
+	eniConfigAnnotationEnv = "ENI_CONFIG_ANNOTATION_DEF"
+	// defaultENIConfigKey is the label and annotation VPC CNI reads when the env vars are unset
+	defaultENIConfigKey = "k8s.amazonaws.com/eniConfig"
+)
+
+// ENIConfig is a VPC CNI ENIConfig custom resource
+type ENIConfig struct {
+	Name           string
+	Subnet         string
+	SecurityGroups []string
+}
+
+// ENIConfigNode is a node and the ENIConfig VPC CNI selects for it
+type ENIConfigNode struct {
+	Name      string
+	Zone      string
+	ENIConfig string // Empty when neither the label nor the annotation is set
+}
+
+// CNICustomNetworking describes the VPC CNI custom networking setup
+type CNICustomNetworking struct {
+	Enabled        bool
+	LabelDef       string // Node label naming the ENIConfig
+	AnnotationDef  string // Node annotation naming the ENIConfig, takes precedence over the label
+	ENIConfigs     []ENIConfig
+	Nodes          []ENIConfigNode
+	ENIConfigError string // Set when the ENIConfig resources could not be listed
+}
+
+// ENIConfig returns the ENIConfig with the given name
+func (c *CNICustomNetworking) ENIConfig(name string) (ENIConfig, bool) {
+	for _, cfg := range c.ENIConfigs {
+		if cfg.Name == name {
+			return cfg, true
+		}
+	}
+	return ENIConfig{}, false
+}
+
+// GetCNICustomNetworking reads the custom networking settings from the aws-node DaemonSet,
+// the ENIConfig resources and the ENIConfig each node selects
+func (k *KubeClient) GetCNICustomNetworking(ctx context.Context) (*CNICustomNetworking, error) {
+	ds, err := k.Clientset.AppsV1().DaemonSets("kube-system").Get(ctx, "aws-node", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aws-node daemonset: %w", err)
+	}
+
+	result := &CNICustomNetworking{
+		LabelDef:      defaultENIConfigKey,
+		AnnotationDef: defaultENIConfigKey,
+	}
+	for _, container := range ds.Spec.Template.Spec.Containers {
+		if container.Name != "aws-node" {
+			continue
+		}
+		for _, env := range container.Env {
+			switch env.Name {
+			case customNetworkEnv:
+				result.Enabled = strings.EqualFold(env.Value, "true")
+			case eniConfigLabelEnv:
+				if env.Value != "" {
+					result.LabelDef = env.Value
+				}
+			case eniConfigAnnotationEnv:
+				if env.Value != "" {
+					result.AnnotationDef = env.Value
+				}
+			}
+		}
+	}
+
+	result.ENIConfigs, err = k.listENIConfigs(ctx)
+	if err != nil {
+		result.ENIConfigError = err.Error()
+	}
+
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		n := ENIConfigNode{Name: node.Name, Zone: node.Labels[zoneLabel]}
+		if name, ok := node.Annotations[result.AnnotationDef]; ok && name != "" {
+			n.ENIConfig = name
+		} else {
+			n.ENIConfig = node.Labels[result.LabelDef]
+		}
+		result.Nodes = append(result.Nodes, n)
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool { return result.Nodes[i].Name < result.Nodes[j].Name })
+
+	return result, nil
+}
+
+// listENIConfigs lists the ENIConfig resources. It returns none when the CRD is not installed.
+func (k *KubeClient) listENIConfigs(ctx context.Context) ([]ENIConfig, error) {
+	if k.Config == nil {
+		return nil, nil
+	}
+	dynamicClient, err := dynamic.NewForConfig(k.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	list, err := dynamicClient.Resource(eniConfigGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list eniconfigs: %w", err)
+	}
+
+	var configs []ENIConfig
+	for _, obj := range list.Items {
+		cfg := ENIConfig{Name: obj.GetName()}
+		cfg.Subnet, _, _ = unstructured.NestedString(obj.Object, "spec", "subnet")
+		cfg.SecurityGroups, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "securityGroups")
+		configs = append(configs, cfg)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Name < configs[j].Name })
+	return configs, nil
+}
@@ -0,0 +1,196 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// eventListPageSize is the number of events requested per List call
+const eventListPageSize = 500
+
+// EventOptions filters the events returned by GetRecentEvents and GetScalingEvents
+type EventOptions struct {
+	Namespace string
+	// Since only returns events last seen within this window; zero returns every event
+	Since time.Duration
+	// Type is "Warning" or "Normal"; empty returns both
+	Type string
+	// Reasons only returns events with one of these reasons; empty returns every reason
+	Reasons []string
+	// MaxEvents keeps only this many of the most recent matching events; zero keeps every
+	// event
+	MaxEvents int
+}
+
+// ListEvents lists events page by page and returns those matching opts, most recent first.
+// Events are filtered as each page arrives and, with opts.MaxEvents, cut back to the most
+// recent matches after each page, so at most MaxEvents plus one page are held in memory.
+func (k *KubeClient) ListEvents(ctx context.Context, opts EventOptions) ([]corev1.Event, error) {
+	listOpts := metav1.ListOptions{Limit: eventListPageSize}
+	var selectors []string
+	if opts.Type != "" {
+		selectors = append(selectors, "type="+opts.Type)
+	}
+	// Field selectors AND their terms, so only a single reason can be selected server-side
+	if len(opts.Reasons) == 1 {
+		selectors = append(selectors, "reason="+opts.Reasons[0])
+	}
+	listOpts.FieldSelector = strings.Join(selectors, ",")
+
+	var cutoff time.Time
+	if opts.Since > 0 {
+		cutoff = time.Now().Add(-opts.Since)
+	}
+
+	var events []corev1.Event
+	matched := 0
+	for {
+		page, err := k.Clientset.CoreV1().Events(opts.Namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events: %w", err)
+		}
+
+		for _, event := range page.Items {
+			if !cutoff.IsZero() && eventLastSeen(event).Before(cutoff) {
+				continue
+			}
+			if len(opts.Reasons) > 0 && !slices.Contains(opts.Reasons, event.Reason) {
+				continue
+			}
+			events = append(events, event)
+			matched++
+		}
+
+		// The API server does not return events in time order, so the most recent matches
+		// are only known once they are sorted
+		if opts.MaxEvents > 0 && len(events) > opts.MaxEvents {
+			sortEventsByLastSeen(events)
+			events = events[:opts.MaxEvents]
+		}
+
+		if page.Continue == "" {
+			break
+		}
+		listOpts.Continue = page.Continue
+	}
+
+	sortEventsByLastSeen(events)
+	if matched > len(events) {
+		k.log().Warning("Kept the %d most recent of %d matching events", len(events), matched)
+	}
+	return events, nil
+}
+
+// sortEventsByLastSeen sorts events by last seen, most recent first
+func sortEventsByLastSeen(events []corev1.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventLastSeen(events[i]).After(eventLastSeen(events[j]))
+	})
+}
+
+// EventSummary aggregates repeated events for the same object and reason
+type EventSummary struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Type      string
+	Reason    string
+	Message   string // message of the most recent occurrence
+	Count     int32
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// GetRecentEvents lists events cluster-wide (or in opts.Namespace) filtered by type, reason
+// and recency, and summarizes them with SummarizeEvents
+func (k *KubeClient) GetRecentEvents(ctx context.Context, opts EventOptions) ([]EventSummary, error) {
+	events, err := k.ListEvents(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return SummarizeEvents(events), nil
+}
+
+// SummarizeEvents merges repeated events for the same object and reason, summing their
+// counts. Results are sorted by last seen, most recent first.
+func SummarizeEvents(events []corev1.Event) []EventSummary {
+	summaries := make(map[string]*EventSummary)
+	for _, event := range events {
+		lastSeen := eventLastSeen(event)
+		obj := event.InvolvedObject
+		key := fmt.Sprintf("%s/%s/%s/%s/%s", obj.Namespace, obj.Kind, obj.Name, event.Type, event.Reason)
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &EventSummary{
+				Namespace: obj.Namespace,
+				Kind:      obj.Kind,
+				Name:      obj.Name,
+				Type:      event.Type,
+				Reason:    event.Reason,
+				FirstSeen: eventFirstSeen(event),
+			}
+			summaries[key] = summary
+		}
+
+		summary.Count += eventCount(event)
+		if first := eventFirstSeen(event); first.Before(summary.FirstSeen) {
+			summary.FirstSeen = first
+		}
+		if !lastSeen.Before(summary.LastSeen) {
+			summary.LastSeen = lastSeen
+			summary.Message = event.Message
+		}
+	}
+
+	result := make([]EventSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+	return result
+}
+
+// eventLastSeen returns when the event last occurred, falling back through the fields
+// set by older and newer event reporters
+func eventLastSeen(event corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// eventFirstSeen returns when the event first occurred
+func eventFirstSeen(event corev1.Event) time.Time {
+	switch {
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// eventCount returns how many times the event occurred
+func eventCount(event corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > 0 {
+		return event.Series.Count
+	}
+	if event.Count > 0 {
+		return event.Count
+	}
+	return 1
+}
@@ -0,0 +1,140 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// pagedEventsClient returns a fake clientset seeded with count events that serves event
+// lists in pages honoring Limit and Continue, like the API server, and records each page
+// request
+func pagedEventsClient(count int, event func(i int) corev1.Event) (*fake.Clientset, *[]metav1.ListOptions) {
+	var events []corev1.Event
+	for i := 0; i < count; i++ {
+		events = append(events, event(i))
+	}
+
+	clientset := fake.NewSimpleClientset()
+	var requests []metav1.ListOptions
+	clientset.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		opts := action.(k8stesting.ListActionImpl).ListOptions
+		requests = append(requests, opts)
+
+		start := 0
+		if opts.Continue != "" {
+			var err error
+			if start, err = strconv.Atoi(opts.Continue); err != nil {
+				return true, nil, fmt.Errorf("invalid continue token %q", opts.Continue)
+			}
+		}
+		end := len(events)
+		if opts.Limit > 0 && start+int(opts.Limit) < end {
+			end = start + int(opts.Limit)
+		}
+
+		list := &corev1.EventList{Items: events[start:end]}
+		if end < len(events) {
+			list.Continue = strconv.Itoa(end)
+		}
+		return true, list, nil
+	})
+	return clientset, &requests
+}
+
+func TestGetRecentEventsPagination(t *testing.T) {
+	now := time.Now()
+	event := func(i int) corev1.Event {
+		reason := "BackOff"
+		if i%10 == 0 {
+			reason = "FailedScheduling"
+		}
+		lastSeen := now.Add(-time.Minute)
+		if i%2 == 1 {
+			lastSeen = now.Add(-2 * time.Hour)
+		}
+		return corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: fmt.Sprintf("event-%d", i), Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: fmt.Sprintf("pod-%d", i)},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			LastTimestamp:  metav1.NewTime(lastSeen),
+		}
+	}
+
+	const total = 1234
+	testCases := []struct {
+		name             string
+		opts             EventOptions
+		expectedEvents   int
+		expectedRequests int
+		recentOnly       bool
+	}{
+		{
+			name:             "All pages are read",
+			opts:             EventOptions{},
+			expectedEvents:   total,
+			expectedRequests: 3,
+		},
+		{
+			name:             "Since filters across pages",
+			opts:             EventOptions{Since: time.Hour},
+			expectedEvents:   total / 2,
+			expectedRequests: 3,
+		},
+		{
+			name:             "Reasons filter across pages",
+			opts:             EventOptions{Reasons: []string{"FailedScheduling", "Evicted"}},
+			expectedEvents:   124,
+			expectedRequests: 3,
+		},
+		{
+			name:             "MaxEvents keeps the most recent",
+			opts:             EventOptions{MaxEvents: 600},
+			expectedEvents:   600,
+			expectedRequests: 3,
+			recentOnly:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset, requests := pagedEventsClient(total, event)
+			client := &KubeClient{Clientset: clientset}
+
+			events, err := client.GetRecentEvents(context.Background(), tc.opts)
+			if err != nil {
+				t.Fatalf("GetRecentEvents failed: %v", err)
+			}
+
+			if len(events) != tc.expectedEvents {
+				t.Errorf("Expected %d events, got %d", tc.expectedEvents, len(events))
+			}
+			for _, e := range events {
+				if tc.recentOnly && e.LastSeen.Before(now.Add(-time.Hour)) {
+					t.Errorf("Event for %s last seen %s kept over more recent events", e.Name, e.LastSeen)
+					break
+				}
+			}
+			if len(*requests) != tc.expectedRequests {
+				t.Errorf("Expected %d list requests, got %d", tc.expectedRequests, len(*requests))
+			}
+			for i, opts := range *requests {
+				if opts.Limit != eventListPageSize {
+					t.Errorf("Request %d: expected limit %d, got %d", i, eventListPageSize, opts.Limit)
+				}
+				if i > 0 && opts.Continue == "" {
+					t.Errorf("Request %d: expected a continue token", i)
+				}
+			}
+		})
+	}
+}
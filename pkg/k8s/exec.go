@@ -0,0 +1,85 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// ExecInPod runs a command in a container of a running pod through the pods/exec
+// subresource and returns its stdout and stderr. An empty container selects the pod's
+// default container. A command that exits non-zero returns an error carrying the exit
+// status, see ExecExitCode.
+func (c *KubeClient) ExecInPod(ctx context.Context, namespace, pod, container string, command []string) (stdout, stderr string, err error) {
+	var outBuf, errBuf bytes.Buffer
+	err = c.StreamInPod(ctx, namespace, pod, container, command, ExecStreams{Stdout: &outBuf, Stderr: &errBuf})
+	return outBuf.String(), errBuf.String(), err
+}
+
+// ExecStreams are the streams of a command run with StreamInPod
+type ExecStreams struct {
+	// Stdin, if set, is passed to the command
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// TTY allocates a terminal for the command, which merges its stderr into Stdout
+	TTY bool
+}
+
+// StreamInPod is ExecInPod for commands whose output is passed on as it is written, and
+// which can read stdin and run in a terminal, such as an interactive shell
+func (c *KubeClient) StreamInPod(ctx context.Context, namespace, pod, container string, command []string, streams ExecStreams) error {
+	if c.Config == nil {
+		return fmt.Errorf("exec requires a live cluster")
+	}
+
+	req := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     streams.Stdin != nil,
+			Stdout:    true,
+			Stderr:    !streams.TTY,
+			TTY:       streams.TTY,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.Config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	options := remotecommand.StreamOptions{
+		Stdin:  streams.Stdin,
+		Stdout: streams.Stdout,
+		Tty:    streams.TTY,
+	}
+	if !streams.TTY {
+		options.Stderr = streams.Stderr
+	}
+	if err := executor.StreamWithContext(ctx, options); err != nil {
+		return fmt.Errorf("failed to exec %q in pod %s/%s: %w", strings.Join(command, " "), namespace, pod, err)
+	}
+	return nil
+}
+
+// ExecExitCode returns the exit status of a command run by ExecInPod, or false if the
+// command did not run to completion
+func ExecExitCode(err error) (int, bool) {
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResourceShortage is a resource a pending pod requests more of than any node can allocate,
+// such as nvidia.com/gpu when the cluster has no GPU nodes
+type ResourceShortage struct {
+	Resource  string
+	Requested resource.Quantity
+	// MaxAllocatable is the largest allocatable amount of the resource on a single node
+	MaxAllocatable resource.Quantity
+	// Nodes is the number of nodes that advertise the resource
+	Nodes int
+}
+
+func (s ResourceShortage) String() string {
+	if s.Nodes == 0 {
+		return fmt.Sprintf("%s: requests %s but no node advertises it", s.Resource, s.Requested.String())
+	}
+	nodes := fmt.Sprintf("%d nodes have it", s.Nodes)
+	if s.Nodes == 1 {
+		nodes = "1 node has it"
+	}
+	return fmt.Sprintf("%s: requests %s but the largest allocatable on a node is %s (%s)",
+		s.Resource, s.Requested.String(), s.MaxAllocatable.String(), nodes)
+}
+
+// resourceShortages compares every resource the pod requests, extended resources and
+// hugepages included, with the allocatable resources of the nodes, and returns those no
+// single node has enough of. Such pods stay pending whatever else is freed up.
+func resourceShortages(pod *corev1.Pod, nodes []corev1.Node) []ResourceShortage {
+	var shortages []ResourceShortage
+	for name, requested := range podRequests(pod) {
+		if requested.IsZero() {
+			continue
+		}
+		shortage := ResourceShortage{Resource: string(name), Requested: requested}
+		for _, node := range nodes {
+			allocatable, ok := node.Status.Allocatable[name]
+			if !ok || allocatable.IsZero() {
+				continue
+			}
+			shortage.Nodes++
+			if allocatable.Cmp(shortage.MaxAllocatable) > 0 {
+				shortage.MaxAllocatable = allocatable
+			}
+		}
+		if shortage.MaxAllocatable.Cmp(requested) < 0 {
+			shortages = append(shortages, shortage)
+		}
+	}
+
+	sort.Slice(shortages, func(i, j int) bool {
+		return shortages[i].Resource < shortages[j].Resource
+	})
+	return shortages
+}
+
+// podRequests returns the resources the scheduler reserves for a pod: the sum of its
+// container requests or, where larger, the request of any init container, plus the pod
+// overhead
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		for name, q := range containerRequests(c) {
+			sum := requests[name]
+			sum.Add(q)
+			requests[name] = sum
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		for name, q := range containerRequests(c) {
+			if current, ok := requests[name]; !ok || q.Cmp(current) > 0 {
+				requests[name] = q
+			}
+		}
+	}
+	for name, q := range pod.Spec.Overhead {
+		sum := requests[name]
+		sum.Add(q)
+		requests[name] = sum
+	}
+	return requests
+}
+
+// containerRequests returns a container's requests, taking limits as the request for
+// resources that only have a limit, as the API server does
+func containerRequests(c corev1.Container) corev1.ResourceList {
+	requests := c.Resources.Requests.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	for name, q := range c.Resources.Limits {
+		if _, ok := requests[name]; !ok {
+			requests[name] = q
+		}
+	}
+	return requests
+}
@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResourceShortages(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:                   resource.MustParse("4"),
+			corev1.ResourceMemory:                resource.MustParse("16Gi"),
+			corev1.ResourceName("hugepages-2Mi"): resource.MustParse("512Mi"),
+		}},
+	}
+	pending := func(name string, limits corev1.ResourceList) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:      "app",
+				Resources: corev1.ResourceRequirements{Limits: limits},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		}
+	}
+
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		node,
+		pending("gpu", corev1.ResourceList{ResourceNvidiaGPU: resource.MustParse("1")}),
+		pending("hugepages", corev1.ResourceList{
+			corev1.ResourceName("hugepages-2Mi"): resource.MustParse("1Gi"),
+			corev1.ResourceMemory:                resource.MustParse("2Gi"),
+		}),
+		pending("fits", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}),
+	)}
+
+	status := &SchedulingStatus{}
+	if err := client.checkSchedulingStatus(context.Background(), []string{metav1.NamespaceAll}, false, status); err != nil {
+		t.Fatalf("checkSchedulingStatus() error = %v", err)
+	}
+
+	got := make(map[string][]ResourceShortage)
+	for _, pod := range status.PendingPods {
+		got[pod.Pod] = pod.ResourceShortages
+	}
+	if s := got["gpu"]; len(s) != 1 || s[0].Resource != "nvidia.com/gpu" || s[0].Nodes != 0 {
+		t.Errorf("gpu shortages = %v, want nvidia.com/gpu on no node", s)
+	}
+	if s := got["hugepages"]; len(s) != 1 || s[0].String() != "hugepages-2Mi: requests 1Gi but the largest allocatable on a node is 512Mi (1 node has it)" {
+		t.Errorf("hugepages shortages = %v, want hugepages-2Mi only", s)
+	}
+	if s := got["fits"]; len(s) != 0 {
+		t.Errorf("fits shortages = %v, want none", s)
+	}
+}
@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetFailedPodsSkipsCompletedJobs(t *testing.T) {
+	controller := true
+	jobPod := func(name, job string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "batch/v1", Kind: "Job", Name: job, Controller: &controller},
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodFailed},
+		}
+	}
+	job := func(name string, complete bool) *batchv1.Job {
+		j := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		if complete {
+			j.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			}
+		}
+		return j
+	}
+	standalone := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "default"},
+		Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+	}
+
+	clientset := fake.NewSimpleClientset(
+		job("migrate", true),
+		job("backfill", false),
+		jobPod("migrate-abc12", "migrate"),
+		jobPod("backfill-def34", "backfill"),
+		standalone,
+	)
+	client := &KubeClient{Clientset: clientset}
+
+	testCases := []struct {
+		name         string
+		includeJobs  bool
+		expectedPods []string
+	}{
+		{
+			name:         "Completed Job pods skipped by default",
+			includeJobs:  false,
+			expectedPods: []string{"backfill-def34", "standalone"},
+		},
+		{
+			name:         "Completed Job pods included on request",
+			includeJobs:  true,
+			expectedPods: []string{"backfill-def34", "migrate-abc12", "standalone"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pods, err := client.GetFailedPods(context.Background(), "default", tc.includeJobs)
+			if err != nil {
+				t.Fatalf("GetFailedPods failed: %v", err)
+			}
+
+			got := make(map[string]bool)
+			for _, pod := range pods {
+				got[pod.Name] = true
+			}
+			if len(got) != len(tc.expectedPods) {
+				t.Errorf("Expected %d failed pods, got %d", len(tc.expectedPods), len(got))
+			}
+			for _, name := range tc.expectedPods {
+				if !got[name] {
+					t.Errorf("Expected failed pod %s to be reported", name)
+				}
+			}
+		})
+	}
+}
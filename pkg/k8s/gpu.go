@@ -0,0 +1,157 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceNvidiaGPU is the extended resource advertised by the NVIDIA device plugin
+const ResourceNvidiaGPU corev1.ResourceName = "nvidia.com/gpu"
+
+// nvidiaInstanceFamilies are the EC2 instance families with NVIDIA GPUs, used to spot GPU
+// nodes that advertise no GPUs because the device plugin is not running on them
+var nvidiaInstanceFamilies = map[string]bool{
+	"p2": true, "p3": true, "p3dn": true, "p4d": true, "p4de": true, "p5": true, "p5e": true, "p5en": true,
+	"g3": true, "g3s": true, "g4dn": true, "g5": true, "g5g": true, "g6": true, "g6e": true, "gr6": true,
+}
+
+// GPUNode describes the GPUs of a node
+type GPUNode struct {
+	Name         string
+	InstanceType string
+	Allocatable  int64
+	Requested    int64 // GPUs requested by the pods scheduled on the node
+	// DevicePlugin is true if a ready device plugin pod runs on the node
+	DevicePlugin bool
+}
+
+// Overcommitted returns true if the node's pods request more GPUs than it advertises,
+// which happens when the device plugin restarts and loses devices
+func (n GPUNode) Overcommitted() bool {
+	return n.Requested > n.Allocatable
+}
+
+// Invisible returns true if the node's GPUs are hidden from the scheduler. A node without
+// a device plugin pod may still advertise GPUs, e.g. from a plugin the AMI runs.
+func (n GPUNode) Invisible() bool {
+	return n.Allocatable == 0
+}
+
+// GPUDevicePlugin is a NVIDIA device plugin DaemonSet
+type GPUDevicePlugin struct {
+	Namespace string
+	Name      string
+	Desired   int32
+	Ready     int32
+}
+
+// GPUStatus holds the GPU nodes, the device plugin DaemonSets and the pods waiting for GPUs
+type GPUStatus struct {
+	Nodes         []GPUNode
+	DevicePlugins []GPUDevicePlugin
+	PendingPods   []string // namespace/name of pending pods requesting GPUs
+	PendingGPUs   int64
+}
+
+// GetGPUStatus lists the nodes with NVIDIA GPUs, the GPUs requested on each, the health
+// of the nvidia-device-plugin DaemonSets and the pending pods that request GPUs
+func (k *KubeClient) GetGPUStatus(ctx context.Context) (*GPUStatus, error) {
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	daemonSets, err := k.Clientset.AppsV1().DaemonSets(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	pods, err := k.Clientset.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	status := &GPUStatus{}
+	plugins := make(map[string]bool)
+	for _, ds := range daemonSets.Items {
+		if !strings.Contains(ds.Name, "nvidia-device-plugin") {
+			continue
+		}
+		plugins[ds.Namespace+"/"+ds.Name] = true
+		status.DevicePlugins = append(status.DevicePlugins, GPUDevicePlugin{
+			Namespace: ds.Namespace,
+			Name:      ds.Name,
+			Desired:   ds.Status.DesiredNumberScheduled,
+			Ready:     ds.Status.NumberReady,
+		})
+	}
+
+	requested := make(map[string]int64)
+	pluginNodes := make(map[string]bool)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if isDevicePluginPod(&pod, plugins) {
+			if pod.Status.Phase == corev1.PodRunning && podIsReady(&pod) {
+				pluginNodes[pod.Spec.NodeName] = true
+			}
+			continue
+		}
+
+		// Extended resources cannot be overcommitted, so their requests equal their limits
+		gpus := podResourceTotal(pod.Spec, ResourceNvidiaGPU, true)
+		if gpus == 0 {
+			continue
+		}
+		if pod.Spec.NodeName == "" {
+			status.PendingPods = append(status.PendingPods, pod.Namespace+"/"+pod.Name)
+			status.PendingGPUs += gpus
+			continue
+		}
+		requested[pod.Spec.NodeName] += gpus
+	}
+
+	for _, node := range nodes.Items {
+		instanceType := node.Labels[corev1.LabelInstanceTypeStable]
+		allocatable := node.Status.Allocatable.Name(ResourceNvidiaGPU, "").Value()
+		family, _, _ := strings.Cut(instanceType, ".")
+		if allocatable == 0 && !nvidiaInstanceFamilies[family] {
+			continue
+		}
+		status.Nodes = append(status.Nodes, GPUNode{
+			Name:         node.Name,
+			InstanceType: instanceType,
+			Allocatable:  allocatable,
+			Requested:    requested[node.Name],
+			DevicePlugin: pluginNodes[node.Name],
+		})
+	}
+
+	sort.Slice(status.Nodes, func(i, j int) bool { return status.Nodes[i].Name < status.Nodes[j].Name })
+	sort.Strings(status.PendingPods)
+	return status, nil
+}
+
+// isDevicePluginPod returns true if the pod belongs to one of the device plugin DaemonSets
+func isDevicePluginPod(pod *corev1.Pod, plugins map[string]bool) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "DaemonSet" && plugins[pod.Namespace+"/"+ref.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// podIsReady returns true if the pod's Ready condition is true
+func podIsReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
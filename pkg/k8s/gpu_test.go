@@ -0,0 +1,112 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGPUNodeInvisible(t *testing.T) {
+	tests := []struct {
+		name string
+		node GPUNode
+		want bool
+	}{
+		{"plugin and GPUs", GPUNode{Allocatable: 4, DevicePlugin: true}, false},
+		{"GPUs without plugin pod", GPUNode{Allocatable: 4}, false},
+		{"plugin without GPUs", GPUNode{DevicePlugin: true}, true},
+		{"neither", GPUNode{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.Invisible(); got != tt.want {
+				t.Errorf("Invisible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGPUStatus(t *testing.T) {
+	node := func(name, instanceType string, gpus int64) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelInstanceTypeStable: instanceType}},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				ResourceNvidiaGPU: *resource.NewQuantity(gpus, resource.DecimalSI),
+			}},
+		}
+	}
+	pluginPod := func(name, nodeName string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "kube-system",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "nvidia-device-plugin-daemonset"}},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	gpuPod := func(name, nodeName string, gpus int64) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ml"},
+			Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{{
+				Name: "train",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{ResourceNvidiaGPU: *resource.NewQuantity(gpus, resource.DecimalSI)},
+				},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		}
+	}
+
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		node("gpu-a", "g5.xlarge", 1),
+		node("gpu-b", "g5.xlarge", 1),
+		node("gpu-c", "p4d.24xlarge", 0),
+		node("cpu", "m5.large", 0),
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-daemonset", Namespace: "kube-system"},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 1},
+		},
+		pluginPod("plugin-a", "gpu-a", corev1.ConditionTrue),
+		pluginPod("plugin-c", "gpu-c", corev1.ConditionFalse),
+		gpuPod("train-a", "gpu-a", 2),
+		gpuPod("train-pending", "", 1),
+	)}
+
+	status, err := client.GetGPUStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetGPUStatus() error = %v", err)
+	}
+
+	if len(status.DevicePlugins) != 1 || status.DevicePlugins[0].Ready != 1 {
+		t.Errorf("DevicePlugins = %+v, want the kube-system DaemonSet with 1 ready", status.DevicePlugins)
+	}
+	if len(status.Nodes) != 3 {
+		t.Fatalf("Nodes = %+v, want the 3 GPU nodes", status.Nodes)
+	}
+	want := map[string]struct {
+		plugin, invisible, overcommitted bool
+	}{
+		"gpu-a": {plugin: true, overcommitted: true},
+		"gpu-b": {},
+		"gpu-c": {invisible: true},
+	}
+	for _, n := range status.Nodes {
+		w := want[n.Name]
+		if n.DevicePlugin != w.plugin || n.Invisible() != w.invisible || n.Overcommitted() != w.overcommitted {
+			t.Errorf("node %s = %+v, want device plugin %v, invisible %v, overcommitted %v", n.Name, n, w.plugin, w.invisible, w.overcommitted)
+		}
+	}
+	if len(status.PendingPods) != 1 || status.PendingGPUs != 1 {
+		t.Errorf("PendingPods = %v with %d GPUs, want ml/train-pending with 1", status.PendingPods, status.PendingGPUs)
+	}
+}
@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"sort"
 	"strings"
 
-	"ekspeek/pkg/common/logger"
+	"ekspeek/pkg/findings"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -21,6 +24,9 @@ import (
 
 // GetPodLogs retrieves logs for a specific pod
 func (k *KubeClient) GetPodLogs(ctx context.Context, namespace, podName, containerName string) (string, error) {
+	if err := k.requireLive("reading pod logs"); err != nil {
+		return "", err
+	}
 	podLogOptions := corev1.PodLogOptions{
 		Container: containerName,
 	}
@@ -43,18 +49,36 @@ func (k *KubeClient) GetPodLogs(ctx context.Context, namespace, podName, contain
 
 // ClusterHealthStatus contains comprehensive health check results
 type ClusterHealthStatus struct {
-	NodeVersions        map[string][]string // Maps Kubernetes versions to node names
-	DeprecatedAPIs     []string
-	LoggingStatus      LoggingStatus
-	NetworkingStatus   NetworkingStatus
-	LoadBalancerStatus LoadBalancerStatus
-	SchedulingStatus   SchedulingStatus
-	AuthStatus         AuthStatus
-	NodeStatus         NodeStatus
-	StatefulSetStatus  []StatefulSetStatus
-	DaemonSetStatus    []DaemonSetStatus
-	PVCStatus          []*PVCStatus
-	StorageClasses     []StorageClass
+	NodeVersions            map[string][]string // Maps Kubernetes versions to node names
+	DeprecatedAPIs          []string
+	LoggingStatus           LoggingStatus
+	NetworkingStatus        NetworkingStatus
+	LoadBalancerStatus      LoadBalancerStatus
+	SchedulingStatus        SchedulingStatus
+	PortStatus              PortStatus
+	AuthStatus              AuthStatus
+	NodeStatus              NodeStatus
+	StatefulSetStatus       []StatefulSetStatus
+	DaemonSetStatus         []DaemonSetStatus
+	PVCStatus               []*PVCStatus
+	StatefulSetVolumeIssues []StatefulSetVolumeIssue
+	TolerationIssues        []TolerationIssue
+	SingleReplicaWorkloads  []SingleReplicaWorkload // Infrastructure workloads with one replica and no PDB
+	ProbeIssues             []ProbeIssue            // Probes with tight timings on restarting containers or wrong ports
+	ClockSkew               []NodeClockSkew         // Ready nodes whose clock is off from the control plane's
+	DefaultTokenNamespaces  []DefaultTokenNamespace // Pods auto-mounting the default service account token, by namespace
+	StorageClasses          []StorageClass
+	InTreeStorageClasses    []InTreeStorageClass // StorageClasses using deprecated or removed in-tree provisioners
+	Findings                []findings.Finding   // Issues derived from the checks above, most severe first
+	ChecksRun               []string             // Names of the health checks that ran
+	SkippedChecks           []string             // Checks skipped because HealthCheckOptions.Namespaces rules out cluster-wide access, or because they need a live cluster
+	Errors                  map[string]string    // Checks that failed to collect, by name, with their error
+
+	// criticalWorkloads holds the single-replicas check's infrastructure heuristics
+	criticalWorkloads *criticalWorkloadRules
+	// namespaceScoped is set when HealthCheckOptions.Namespaces limits the run, so checks
+	// can do without the cluster-scoped lists namespace-scoped RBAC denies
+	namespaceScoped bool
 }
 
 type LoggingStatus struct {
@@ -84,14 +108,22 @@ type IngressStatus struct {
 }
 
 type SchedulingStatus struct {
-	PendingPods    []PodSchedulingIssue
-	ResourceIssues []ResourceIssue
+	PendingPods       []PodSchedulingIssue
+	ResourceIssues    []ResourceIssue
+	PodCapacityIssues []PodCapacityIssue
+	CapacityError     string // Set when the pod capacity of the nodes could not be checked
 }
 
 type PodSchedulingIssue struct {
 	Pod       string
 	Namespace string
 	Reason    string
+	// SpreadConstraint describes the topology spread constraint that no schedulable node
+	// can satisfy, if that is why the pod is pending
+	SpreadConstraint string
+	// ResourceShortages are the resources the pod requests more of than any node can
+	// allocate
+	ResourceShortages []ResourceShortage
 }
 
 type ResourceIssue struct {
@@ -116,6 +148,10 @@ type NodeStatus struct {
 	NotReady        []string
 	ASGIssues       []string
 	BootstrapIssues []string
+	// Flapping lists nodes that switched between Ready and NotReady several times recently
+	Flapping []NodeFlap
+	// CertIssues lists NotReady nodes whose kubelet client certificate looks expired
+	CertIssues []NodeCertIssue
 }
 
 // PodStatus represents the status of a pod
@@ -128,6 +164,18 @@ type PodStatus struct {
 	Spec      corev1.PodSpec
 	Message   string
 	Requirements ResourceRequirements
+	Containers   []ContainerStatus
+}
+
+// ContainerStatus represents the status of a single container in a pod
+type ContainerStatus struct {
+	Name            string
+	Ready           bool
+	RestartCount    int32
+	State           string
+	Reason          string
+	LastTermination string
+	LastExitCode    int32
 }
 
 // ResourceRequirements represents the compute resources required by a pod
@@ -167,97 +215,214 @@ type StorageClass struct {
 	DefaultClass bool
 }
 
+// HealthCheckOptions scopes the cluster health check
+type HealthCheckOptions struct {
+	// NamespaceSelector limits workload, storage, networking and security checks to
+	// namespaces matching the label selector, e.g. "team=payments"
+	NamespaceSelector string
+	// Checks limits the run to the named health checks; empty runs every registered check
+	Checks []string
+	// SkipChecks names health checks to skip
+	SkipChecks []string
+	// Namespaces, if set, limits the run to these namespaces for users whose RBAC does not
+	// allow cluster-wide lists. Checks that need cluster-wide access are skipped and listed
+	// in ClusterHealthStatus.SkippedChecks.
+	Namespaces []string
+	// CriticalNamespaces and CriticalLabels decide which workloads the single-replicas
+	// check treats as cluster infrastructure: workloads in these namespaces, or whose
+	// labels match one of these label selectors. Empty lists use DefaultCriticalNamespaces
+	// and DefaultCriticalLabels.
+	CriticalNamespaces []string
+	CriticalLabels     []string
+	// Progress, if set, is called when each health check starts and finishes
+	Progress func(HealthCheckEvent)
+	// OnFindings, if set, is called with each check's findings as soon as the check
+	// finishes, so they can be streamed instead of waiting for the whole run
+	OnFindings func(check string, results []findings.Finding) error
+}
+
+// HealthCheckEvent reports the start or finish of a health check
+type HealthCheckEvent struct {
+	Check string
+	Index int // 1-based position of the check in the run
+	Total int
+	Done  bool
+}
+
 // CheckClusterHealth performs comprehensive health checks
 func (k *KubeClient) CheckClusterHealth(ctx context.Context) (*ClusterHealthStatus, error) {
+	return k.CheckClusterHealthWithOptions(ctx, HealthCheckOptions{})
+}
+
+// CheckClusterHealthWithOptions performs comprehensive health checks scoped by the options
+func (k *KubeClient) CheckClusterHealthWithOptions(ctx context.Context, opts HealthCheckOptions) (*ClusterHealthStatus, error) {
 	status := &ClusterHealthStatus{
 		NodeVersions: make(map[string][]string),
 	}
 
-	// Check node versions and control plane compatibility
-	if err := k.checkVersionMismatch(ctx, status); err != nil {
+	selected, err := SelectHealthChecks(opts.Checks, opts.SkipChecks)
+	if err != nil {
 		return nil, err
 	}
-
-	// Check for deprecated API usage
-	if err := k.checkDeprecatedAPIs(ctx, status); err != nil {
+	status.criticalWorkloads, err = newCriticalWorkloadRules(opts.CriticalNamespaces, opts.CriticalLabels)
+	if err != nil {
 		return nil, err
 	}
 
-	// Check logging components
-	if err := k.checkLoggingStatus(ctx, status); err != nil {
-		return nil, err
+	var namespaces []string
+	checks := selected
+	if len(opts.Namespaces) > 0 {
+		if opts.NamespaceSelector != "" {
+			return nil, fmt.Errorf("a namespace allowlist and a namespace selector cannot be combined")
+		}
+		namespaces = opts.Namespaces
+		status.namespaceScoped = true
+		checks = nil
+		for _, check := range selected {
+			if builtin, ok := check.(*builtinCheck); ok && builtin.namespaced {
+				checks = append(checks, check)
+			} else {
+				status.SkippedChecks = append(status.SkippedChecks, check.Name())
+			}
+		}
+	} else {
+		namespaces, err = k.resolveNamespaces(ctx, opts.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Check networking
-	if err := k.checkNetworkingStatus(ctx, &status.NetworkingStatus); err != nil {
-		return nil, err
+	progress := func(i int, check HealthCheck, done bool) {
+		if opts.Progress != nil {
+			opts.Progress(HealthCheckEvent{Check: check.Name(), Index: i + 1, Total: len(checks), Done: done})
+		}
 	}
 
-	// Check load balancers and ingress
-	if err := k.checkLoadBalancerStatus(ctx, &status.LoadBalancerStatus); err != nil {
-		return nil, err
-	}
+	for i, check := range checks {
+		progress(i, check, false)
+
+		// A failed check is recorded and the others still run, so one denied list does not
+		// cost the whole report
+		var results []findings.Finding
+		if builtin, ok := check.(*builtinCheck); ok {
+			// Built-in checks also fill in their section of the status for the detailed report
+			if err := builtin.populate(ctx, k, namespaces, status); needsLiveCluster(err) {
+				status.SkippedChecks = append(status.SkippedChecks, check.Name())
+				progress(i, check, true)
+				continue
+			} else if err != nil {
+				status.addError(check.Name(), namespaceScopedHint(check.Name(), err, opts))
+				progress(i, check, true)
+				continue
+			}
+			results = builtin.results(status)
+		} else {
+			results, err = check.Run(ctx, k)
+			if err != nil {
+				status.addError(check.Name(), namespaceScopedHint(check.Name(), err, opts))
+				progress(i, check, true)
+				continue
+			}
+		}
 
-	// Check scheduling and resources
-	if err := k.checkSchedulingStatus(ctx, &status.SchedulingStatus); err != nil {
-		return nil, err
+		status.ChecksRun = append(status.ChecksRun, check.Name())
+		status.Findings = append(status.Findings, results...)
+		if opts.OnFindings != nil {
+			if err := opts.OnFindings(check.Name(), results); err != nil {
+				return nil, err
+			}
+		}
+		progress(i, check, true)
 	}
 
-	// Check authentication and authorization
-	if err := k.checkAuthStatus(ctx, &status.AuthStatus); err != nil {
-		return nil, err
+	findings.Sort(status.Findings)
+	if len(status.Errors) > 0 {
+		return status, &HealthCheckError{Errors: status.Errors}
 	}
+	return status, nil
+}
 
-	// Check node health
-	if err := k.checkNodeStatus(ctx, &status.NodeStatus); err != nil {
-		return nil, err
+// HealthCheckError is returned along with the status when some health checks failed to
+// collect. The status holds the results of the other checks.
+type HealthCheckError struct {
+	Errors map[string]string // Error by check name
+}
+
+func (e *HealthCheckError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	// Check StatefulSets
-	if err := k.checkStatefulSetStatus(ctx, status); err != nil {
-		return nil, err
+	failed := make([]string, 0, len(names))
+	for _, name := range names {
+		failed = append(failed, fmt.Sprintf("%s: %s", name, e.Errors[name]))
 	}
+	return fmt.Sprintf("%d health checks failed: %s", len(names), strings.Join(failed, "; "))
+}
 
-	// Check DaemonSets
-	if err := k.checkDaemonSetStatus(ctx, status); err != nil {
-		return nil, err
+// addError records a health check that failed to collect
+func (s *ClusterHealthStatus) addError(check string, err error) {
+	if s.Errors == nil {
+		s.Errors = make(map[string]string)
 	}
+	s.Errors[check] = err.Error()
+}
 
-	// Check Storage
-	if err := k.checkStorageStatus(ctx, status); err != nil {
-		return nil, err
+// namespaceScopedHint points users denied a cluster-wide list at the namespace allowlist
+func namespaceScopedHint(check string, err error, opts HealthCheckOptions) error {
+	if !errors.IsForbidden(err) || len(opts.Namespaces) > 0 {
+		return err
 	}
+	return fmt.Errorf("health check %s was denied: %w (with namespace-scoped RBAC, pass --namespaces to check only the namespaces you can access)", check, err)
+}
 
-	return status, nil
+// RanCheck returns true if the named health check ran
+func (s *ClusterHealthStatus) RanCheck(name string) bool {
+	for _, ran := range s.ChecksRun {
+		if ran == name {
+			return true
+		}
+	}
+	return false
 }
 
-func (k *KubeClient) checkVersionMismatch(ctx context.Context, status *ClusterHealthStatus) error {
-	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return err
+// resolveNamespaces returns the namespaces matching the label selector, or a single entry
+// for all namespaces if the selector is empty. A selector matching no namespace is an
+// error.
+func (k *KubeClient) resolveNamespaces(ctx context.Context, selector string) ([]string, error) {
+	if selector == "" {
+		return []string{metav1.NamespaceAll}, nil
 	}
 
-	for _, node := range nodes.Items {
-		version := node.Status.NodeInfo.KubeletVersion
-		status.NodeVersions[version] = append(status.NodeVersions[version], node.Name)
+	list, err := k.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces matching %q: %w", selector, err)
+	}
+	// Checking no namespaces would report a mistyped selector as a healthy cluster
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no namespaces match selector %q", selector)
 	}
 
-	return nil
+	namespaces := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		namespaces = append(namespaces, ns.Name)
+	}
+	return namespaces, nil
 }
 
-func (k *KubeClient) checkDeprecatedAPIs(ctx context.Context, status *ClusterHealthStatus) error {
-	// Check deployments for deprecated API versions
-	deployments, err := k.Clientset.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+func (k *KubeClient) checkVersionMismatch(ctx context.Context, status *ClusterHealthStatus) error {
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return err
 	}
 
-	for _, deploy := range deployments.Items {
-		// Example check for deprecated API versions in annotations
-		if _, hasDeprecated := deploy.Annotations["deprecated.kubernetes.io"]; hasDeprecated {
-			status.DeprecatedAPIs = append(status.DeprecatedAPIs, 
-				fmt.Sprintf("Deployment %s/%s uses deprecated APIs", deploy.Namespace, deploy.Name))
-		}
+	for _, node := range nodes.Items {
+		version := node.Status.NodeInfo.KubeletVersion
+		status.NodeVersions[version] = append(status.NodeVersions[version], node.Name)
 	}
 
 	return nil
@@ -278,6 +443,7 @@ func (k *KubeClient) checkLoggingStatus(ctx context.Context, status *ClusterHeal
 			Namespace: pod.Namespace,
 			Status:    string(pod.Status.Phase),
 			Message:   pod.Status.Message,
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
 		})
 	}
 
@@ -295,6 +461,7 @@ func (k *KubeClient) checkLoggingStatus(ctx context.Context, status *ClusterHeal
 			Namespace: pod.Namespace,
 			Status:    string(pod.Status.Phase),
 			Message:   pod.Status.Message,
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
 		})
 	}
 
@@ -312,6 +479,7 @@ func (k *KubeClient) checkLoggingStatus(ctx context.Context, status *ClusterHeal
 			Namespace: pod.Namespace,
 			Status:    string(pod.Status.Phase),
 			Message:   pod.Status.Message,
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
 		})
 	}
 
@@ -329,6 +497,7 @@ func (k *KubeClient) checkLoggingStatus(ctx context.Context, status *ClusterHeal
 			Namespace: pod.Namespace,
 			Status:    string(pod.Status.Phase),
 			Message:   pod.Status.Message,
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
 		})
 	}
 
@@ -350,6 +519,7 @@ func (k *KubeClient) checkNetworkingStatus(ctx context.Context, status *Networki
 			Namespace: pod.Namespace,
 			Status:    string(pod.Status.Phase),
 			Message:   pod.Status.Message,
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
 		})
 	}
 
@@ -367,15 +537,25 @@ func (k *KubeClient) checkNetworkingStatus(ctx context.Context, status *Networki
 			Namespace: pod.Namespace,
 			Status:    string(pod.Status.Phase),
 			Message:   pod.Status.Message,
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
 		})
 	}
 
 	return nil
 }
 
-func (k *KubeClient) checkLoadBalancerStatus(ctx context.Context, status *LoadBalancerStatus) error {
+func (k *KubeClient) checkLoadBalancerStatus(ctx context.Context, namespaces []string, status *LoadBalancerStatus) error {
+	for _, namespace := range namespaces {
+		if err := k.checkNamespaceLoadBalancers(ctx, namespace, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *KubeClient) checkNamespaceLoadBalancers(ctx context.Context, namespace string, status *LoadBalancerStatus) error {
 	// Check services of type LoadBalancer
-	services, err := k.Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	services, err := k.Clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return err
 	}
@@ -388,7 +568,7 @@ func (k *KubeClient) checkLoadBalancerStatus(ctx context.Context, status *LoadBa
 	}
 
 	// Check ingress controllers
-	ingresses, err := k.Clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
+	ingresses, err := k.Clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return err
 	}
@@ -410,41 +590,91 @@ func (k *KubeClient) checkLoadBalancerStatus(ctx context.Context, status *LoadBa
 	return nil
 }
 
-func (k *KubeClient) checkSchedulingStatus(ctx context.Context, status *SchedulingStatus) error {
-	pods, err := k.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-		FieldSelector: "status.phase=Pending",
-	})
-	if err != nil {
-		return err
-	}
+func (k *KubeClient) checkSchedulingStatus(ctx context.Context, namespaces []string, namespaceScoped bool, status *SchedulingStatus) error {
+	// Nodes are only listed when there are unscheduled pods, and the pods of a namespace
+	// for pending pods with topology spread constraints
+	var nodes []corev1.Node
+	nodesListed := false
+	namespacePods := make(map[string][]corev1.Pod)
 
-	for _, pod := range pods.Items {
-		issue := PodSchedulingIssue{
-			Pod:       pod.Name,
-			Namespace: pod.Namespace,
+	for _, namespace := range namespaces {
+		pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: "status.phase=Pending",
+		})
+		if err != nil {
+			return err
 		}
 
-		for _, cond := range pod.Status.Conditions {
-			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
-				issue.Reason = cond.Message
-				break
+		for _, pod := range pods.Items {
+			issue := PodSchedulingIssue{
+				Pod:       pod.Name,
+				Namespace: pod.Namespace,
+			}
+
+			for _, cond := range pod.Status.Conditions {
+				if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+					issue.Reason = cond.Message
+					break
+				}
+			}
+
+			if pod.Spec.NodeName == "" {
+				if !nodesListed {
+					nodesListed = true
+					// With namespace-scoped RBAC requests and constraints are not analyzed
+					nodeList, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+					if err != nil && !(namespaceScoped && errors.IsForbidden(err)) {
+						return err
+					}
+					if err == nil {
+						nodes = nodeList.Items
+					}
+				}
+				if len(nodes) > 0 {
+					issue.ResourceShortages = resourceShortages(&pod, nodes)
+				}
+			}
+
+			if pod.Spec.NodeName == "" && len(pod.Spec.TopologySpreadConstraints) > 0 {
+				if len(nodes) > 0 {
+					peers, ok := namespacePods[pod.Namespace]
+					if !ok {
+						podList, err := k.Clientset.CoreV1().Pods(pod.Namespace).List(ctx, metav1.ListOptions{})
+						if err != nil {
+							return err
+						}
+						peers = podList.Items
+						namespacePods[pod.Namespace] = peers
+					}
+					issue.SpreadConstraint = spreadConstraintViolation(&pod, nodes, peers)
+				}
 			}
-		}
 
-		status.PendingPods = append(status.PendingPods, issue)
+			status.PendingPods = append(status.PendingPods, issue)
+		}
 	}
 
+	// Check pod (IP) capacity per node. Users with namespace-scoped RBAC cannot list nodes
+	// and all pods, so the capacity check is left out for them. Other failures are recorded
+	// so the pending pods are still reported.
+	if err := k.checkPodCapacity(ctx, status); err != nil && !(namespaceScoped && errors.IsForbidden(err)) {
+		status.CapacityError = err.Error()
+	}
 	return nil
 }
 
-func (k *KubeClient) checkAuthStatus(ctx context.Context, status *AuthStatus) error {
+func (k *KubeClient) checkAuthStatus(ctx context.Context, namespaces []string, status *AuthStatus) error {
 	// Check IRSA setup
-	sa, err := k.Clientset.CoreV1().ServiceAccounts("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return err
+	var accounts []corev1.ServiceAccount
+	for _, namespace := range namespaces {
+		sa, err := k.Clientset.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		accounts = append(accounts, sa.Items...)
 	}
 
-	for _, account := range sa.Items {
+	for _, account := range accounts {
 		if annotations := account.GetAnnotations(); annotations != nil {
 			if role, exists := annotations["eks.amazonaws.com/role-arn"]; exists {
 				// Verify if pods using this SA can access AWS resources
@@ -458,11 +688,11 @@ func (k *KubeClient) checkAuthStatus(ctx context.Context, status *AuthStatus) er
 				for _, pod := range pods.Items {
 					if pod.Status.Phase == corev1.PodRunning {
 						// Check pod logs for AWS API errors
-						logs, err := k.GetPodLogs(ctx, pod.Namespace, pod.Name, "")
+						matches, err := k.ScanPodLogs(ctx, pod.Namespace, pod.Name, "", []string{"AccessDenied", "UnauthorizedOperation"})
 						if err != nil {
 							continue
 						}
-						if strings.Contains(logs, "AccessDenied") || strings.Contains(logs, "UnauthorizedOperation") {
+						if len(matches) > 0 {
 							status.IRSAIssues = append(status.IRSAIssues,
 								fmt.Sprintf("Pod %s/%s using SA %s with role %s has AWS access issues",
 									pod.Namespace, pod.Name, account.Name, role))
@@ -482,6 +712,7 @@ func (k *KubeClient) checkNodeStatus(ctx context.Context, status *NodeStatus) er
 		return err
 	}
 
+	var notReady []corev1.Node
 	for _, node := range nodes.Items {
 		isReady := false
 		for _, condition := range node.Status.Conditions {
@@ -493,6 +724,7 @@ func (k *KubeClient) checkNodeStatus(ctx context.Context, status *NodeStatus) er
 
 		if !isReady {
 			status.NotReady = append(status.NotReady, node.Name)
+			notReady = append(notReady, node)
 			// Check node conditions for bootstrap issues
 			for _, condition := range node.Status.Conditions {
 				if condition.Status == corev1.ConditionFalse {
@@ -503,68 +735,83 @@ func (k *KubeClient) checkNodeStatus(ctx context.Context, status *NodeStatus) er
 		}
 	}
 
-	return nil
-}
-
-func (k *KubeClient) checkStatefulSetStatus(ctx context.Context, status *ClusterHealthStatus) error {
-	stsList, err := k.Clientset.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
-	if err != nil {
+	if err := k.checkKubeletCertificates(ctx, notReady, status); err != nil {
 		return err
 	}
+	return k.checkNodeFlapping(ctx, nodes.Items, status)
+}
 
-	for _, sts := range stsList.Items {
-		status.StatefulSetStatus = append(status.StatefulSetStatus, StatefulSetStatus{
-			Name:            sts.Name,
-			Namespace:       sts.Namespace,
-			ReadyReplicas:   sts.Status.ReadyReplicas,
-			DesiredReplicas: *sts.Spec.Replicas,
-		})
+func (k *KubeClient) checkStatefulSetStatus(ctx context.Context, namespaces []string, status *ClusterHealthStatus) error {
+	for _, namespace := range namespaces {
+		stsList, err := k.Clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		for _, sts := range stsList.Items {
+			status.StatefulSetStatus = append(status.StatefulSetStatus, StatefulSetStatus{
+				Name:            sts.Name,
+				Namespace:       sts.Namespace,
+				ReadyReplicas:   sts.Status.ReadyReplicas,
+				DesiredReplicas: *sts.Spec.Replicas,
+			})
+		}
 	}
 
 	return nil
 }
 
-func (k *KubeClient) checkDaemonSetStatus(ctx context.Context, status *ClusterHealthStatus) error {
-	dsList, err := k.Clientset.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return err
-	}
+func (k *KubeClient) checkDaemonSetStatus(ctx context.Context, namespaces []string, status *ClusterHealthStatus) error {
+	for _, namespace := range namespaces {
+		dsList, err := k.Clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
 
-	for _, ds := range dsList.Items {
-		status.DaemonSetStatus = append(status.DaemonSetStatus, DaemonSetStatus{
-			Name:              ds.Name,
-			Namespace:         ds.Namespace,
-			NumberUnavailable: ds.Status.NumberUnavailable,
-			NumberReady:       ds.Status.NumberReady,
-		})
+		for _, ds := range dsList.Items {
+			status.DaemonSetStatus = append(status.DaemonSetStatus, DaemonSetStatus{
+				Name:              ds.Name,
+				Namespace:         ds.Namespace,
+				NumberUnavailable: ds.Status.NumberUnavailable,
+				NumberReady:       ds.Status.NumberReady,
+			})
+		}
 	}
 
 	return nil
 }
 
-func (k *KubeClient) checkStorageStatus(ctx context.Context, status *ClusterHealthStatus) error {
+func (k *KubeClient) checkStorageStatus(ctx context.Context, namespaces []string, status *ClusterHealthStatus) error {
 	// Check PVCs
-	pvcList, err := k.Clientset.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return err
-	}
+	for _, namespace := range namespaces {
+		pvcList, err := k.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
 
-	for _, pvc := range pvcList.Items {
-		status.PVCStatus = append(status.PVCStatus, &PVCStatus{
-			Name:      pvc.Name,
-			Namespace: pvc.Namespace,
-			Status:    pvc.Status,
-			Spec:      pvc.Spec,
-		})
+		for _, pvc := range pvcList.Items {
+			status.PVCStatus = append(status.PVCStatus, &PVCStatus{
+				Name:      pvc.Name,
+				Namespace: pvc.Namespace,
+				Status:    pvc.Status,
+				Spec:      pvc.Spec,
+			})
+		}
 	}
 
-	// Check StorageClasses
+	// Check StorageClasses. They are cluster-scoped, so with namespace-scoped RBAC the
+	// volumes are checked without them.
 	scList, err := k.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if status.namespaceScoped && errors.IsForbidden(err) {
+		scList, err = &storagev1.StorageClassList{}, nil
+	}
 	if err != nil {
 		return err
 	}
 
+	classes := make(map[string]storagev1.StorageClass)
 	for _, sc := range scList.Items {
+		classes[sc.Name] = sc
 		isDefault := false
 		if annotations := sc.GetAnnotations(); annotations != nil {
 			_, isDefault = annotations["storageclass.kubernetes.io/is-default-class"]
@@ -576,7 +823,7 @@ func (k *KubeClient) checkStorageStatus(ctx context.Context, status *ClusterHeal
 		})
 	}
 
-	return nil
+	return k.checkStatefulSetVolumes(ctx, namespaces, classes, status)
 }
 
 // GetEFSCSIStatus checks the status of EFS CSI driver pods
@@ -594,6 +841,7 @@ func (k *KubeClient) GetEFSCSIStatus(ctx context.Context) ([]PodStatus, error) {
 			Name:      pod.Name,
 			Namespace: pod.Namespace,
 			Status:    string(pod.Status.Phase),
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
 		}
 		if pod.Status.Phase != corev1.PodRunning {
 			podStatus.Message = "Pod is not in Running state"
@@ -625,8 +873,9 @@ func (k *KubeClient) GetPVCStatus(ctx context.Context, namespace string) ([]*PVC
 	return pvcStatuses, nil
 }
 
-// GetFailedPods returns a list of failed pods
-func (k *KubeClient) GetFailedPods(ctx context.Context, namespace string) ([]PodStatus, error) {
+// GetFailedPods returns a list of failed pods. Failed pods owned by a Job that has since
+// completed are expected retries and are skipped unless includeJobs is set.
+func (k *KubeClient) GetFailedPods(ctx context.Context, namespace string, includeJobs bool) ([]PodStatus, error) {
 	pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 		FieldSelector: "status.phase=Failed",
 	})
@@ -634,13 +883,18 @@ func (k *KubeClient) GetFailedPods(ctx context.Context, namespace string) ([]Pod
 		return nil, fmt.Errorf("failed to list failed pods: %w", err)
 	}
 
+	completedJobs := make(map[string]bool)
 	var status []PodStatus
 	for _, pod := range pods.Items {
+		if !includeJobs && k.ownedByCompletedJob(ctx, &pod, completedJobs) {
+			continue
+		}
 		status = append(status, PodStatus{
 			Name:      pod.Name,
 			Namespace: pod.Namespace,
 			Status:    string(pod.Status.Phase),
 			Message:   pod.Status.Message,
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
 			Phase:     pod.Status.Phase,
 			NodeName:  pod.Spec.NodeName,
 			Spec:      pod.Spec,
@@ -650,6 +904,90 @@ func (k *KubeClient) GetFailedPods(ctx context.Context, namespace string) ([]Pod
 	return status, nil
 }
 
+// ownedByCompletedJob returns true if the pod is owned by a Job that has since completed,
+// caching Job lookups by namespace/name
+func (k *KubeClient) ownedByCompletedJob(ctx context.Context, pod *corev1.Pod, cache map[string]bool) bool {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "Job" {
+		return false
+	}
+
+	key := pod.Namespace + "/" + owner.Name
+	if completed, ok := cache[key]; ok {
+		return completed
+	}
+
+	completed := false
+	job, err := k.Clientset.BatchV1().Jobs(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	if err == nil {
+		for _, cond := range job.Status.Conditions {
+			if cond.Type == batchv1.JobComplete && cond.Status == corev1.ConditionTrue {
+				completed = true
+				break
+			}
+		}
+	}
+	cache[key] = completed
+	return completed
+}
+
+// GetPodStatuses returns the status of every pod in the specified namespace
+func (k *KubeClient) GetPodStatuses(ctx context.Context, namespace string) ([]PodStatus, error) {
+	pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var status []PodStatus
+	for _, pod := range pods.Items {
+		status = append(status, PodStatus{
+			Name:       pod.Name,
+			Namespace:  pod.Namespace,
+			Status:     string(pod.Status.Phase),
+			Message:    pod.Status.Message,
+			Phase:      pod.Status.Phase,
+			NodeName:   pod.Spec.NodeName,
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
+		})
+	}
+
+	return status, nil
+}
+
+// getContainerStatuses converts container statuses into their state, restart count and
+// last termination details
+func getContainerStatuses(statuses []corev1.ContainerStatus) []ContainerStatus {
+	var containers []ContainerStatus
+	for _, cs := range statuses {
+		container := ContainerStatus{
+			Name:         cs.Name,
+			Ready:        cs.Ready,
+			RestartCount: cs.RestartCount,
+		}
+
+		switch {
+		case cs.State.Running != nil:
+			container.State = "Running"
+		case cs.State.Waiting != nil:
+			container.State = "Waiting"
+			container.Reason = cs.State.Waiting.Reason
+		case cs.State.Terminated != nil:
+			container.State = "Terminated"
+			container.Reason = cs.State.Terminated.Reason
+		default:
+			container.State = "Unknown"
+		}
+
+		if terminated := cs.LastTerminationState.Terminated; terminated != nil {
+			container.LastTermination = terminated.Reason
+			container.LastExitCode = terminated.ExitCode
+		}
+
+		containers = append(containers, container)
+	}
+	return containers
+}
+
 // ClusterResources represents the resource usage in the cluster
 type ClusterResources struct {
 	TotalCPU        int64
@@ -658,7 +996,13 @@ type ClusterResources struct {
 	AllocatedMemory int64
 	CPUPercentage   float64
 	MemPercentage   float64
-}	// GetClusterResources returns the current resource usage in the cluster
+	LimitCPU        int64   // Sum of CPU limits in millicores
+	LimitMemory     int64   // Sum of memory limits in bytes
+	CPULimitRatio   float64 // CPU limits divided by capacity; above 1 means overcommitted
+	MemLimitRatio   float64 // Memory limits divided by capacity; above 1 means overcommitted
+}
+
+// GetClusterResources returns the current resource usage in the cluster
 func (k *KubeClient) GetClusterResources(ctx context.Context) (*ClusterResources, error) {
 	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -682,23 +1026,69 @@ func (k *KubeClient) GetClusterResources(ctx context.Context) (*ClusterResources
 		}
 
 		for _, pod := range pods.Items {
-			for _, container := range pod.Spec.Containers {
-				resources.AllocatedCPU += container.Resources.Requests.Cpu().MilliValue()
-				resources.AllocatedMemory += container.Resources.Requests.Memory().Value()
-			}
+			resources.AllocatedCPU += podResourceTotal(pod.Spec, corev1.ResourceCPU, false)
+			resources.AllocatedMemory += podResourceTotal(pod.Spec, corev1.ResourceMemory, false)
+			resources.LimitCPU += podResourceTotal(pod.Spec, corev1.ResourceCPU, true)
+			resources.LimitMemory += podResourceTotal(pod.Spec, corev1.ResourceMemory, true)
 		}
 	}
 
 	if resources.TotalCPU > 0 {
 		resources.CPUPercentage = float64(resources.AllocatedCPU) / float64(resources.TotalCPU) * 100
+		resources.CPULimitRatio = float64(resources.LimitCPU) / float64(resources.TotalCPU)
 	}
 	if resources.TotalMemory > 0 {
 		resources.MemPercentage = float64(resources.AllocatedMemory) / float64(resources.TotalMemory) * 100
+		resources.MemLimitRatio = float64(resources.LimitMemory) / float64(resources.TotalMemory)
 	}
 
 	return resources, nil
 }
 
+// podResourceTotal returns a pod's effective request (or limit) for a resource the way the
+// scheduler computes it: the larger of the regular containers' sum and the largest init
+// container, with sidecar init containers counted alongside both, plus pod overhead.
+// CPU is returned in millicores and everything else in base units.
+func podResourceTotal(spec corev1.PodSpec, name corev1.ResourceName, limits bool) int64 {
+	value := func(list corev1.ResourceList) int64 {
+		q, ok := list[name]
+		if !ok {
+			return 0
+		}
+		if name == corev1.ResourceCPU {
+			return q.MilliValue()
+		}
+		return q.Value()
+	}
+	containerValue := func(c corev1.Container) int64 {
+		if limits {
+			return value(c.Resources.Limits)
+		}
+		return value(c.Resources.Requests)
+	}
+
+	var sidecars, initMax int64
+	for _, c := range spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			sidecars += containerValue(c)
+			continue
+		}
+		if v := sidecars + containerValue(c); v > initMax {
+			initMax = v
+		}
+	}
+
+	total := sidecars
+	for _, c := range spec.Containers {
+		total += containerValue(c)
+	}
+	if initMax > total {
+		total = initMax
+	}
+
+	return total + value(spec.Overhead)
+}
+
 // GetPodServiceAccount gets the service account for a pod
 func (k *KubeClient) GetPodServiceAccount(ctx context.Context, namespace, podName string) (string, error) {
 	pod, err := k.Clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
@@ -787,16 +1177,18 @@ func (k *KubeClient) GetClusterAutoscalerPod(ctx context.Context) (*corev1.Pod,
 	return &pods.Items[0], nil
 }
 
-// GetScalingEvents returns scaling-related events
-func (k *KubeClient) GetScalingEvents(ctx context.Context) ([]corev1.Event, error) {
-	events, err := k.Clientset.CoreV1().Events("").List(ctx, metav1.ListOptions{
-		FieldSelector: "reason=TriggeredScaleUp,reason=ScalingReplicaSet",
-	})
+// GetScalingEvents returns scaling-related events matching opts. opts.Reasons defaults to
+// the cluster-autoscaler and deployment scaling reasons.
+func (k *KubeClient) GetScalingEvents(ctx context.Context, opts EventOptions) ([]corev1.Event, error) {
+	if len(opts.Reasons) == 0 {
+		opts.Reasons = []string{"TriggeredScaleUp", "NotTriggerScaleUp", "ScaleDown", "ScalingReplicaSet"}
+	}
+	events, err := k.ListEvents(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list scaling events: %w", err)
 	}
 
-	return events.Items, nil
+	return events, nil
 }
 
 // KarpenterStatus represents the status of Karpenter
@@ -873,6 +1265,7 @@ func (k *KubeClient) GetKarpenterPendingPods(ctx context.Context) ([]PodStatus,
 			Namespace: pod.Namespace,
 			Status:    string(pod.Status.Phase),
 			Message:   pod.Status.Message,
+			Containers: getContainerStatuses(pod.Status.ContainerStatuses),
 		})
 	}
 
@@ -891,7 +1284,7 @@ func (k *KubeClient) AnalyzeUnschedulablePods(ctx context.Context) error {
 	for _, pod := range pods.Items {
 		for _, cond := range pod.Status.Conditions {
 			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
-				logger.Warning("Pod %s/%s is unschedulable: %s", pod.Namespace, pod.Name, cond.Message)
+				k.log().Warning("Pod %s/%s is unschedulable: %s", pod.Namespace, pod.Name, cond.Message)
 			}
 		}
 	}
@@ -0,0 +1,113 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckClusterHealthPartial(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", errors.New("denied"))
+	})
+	client := &KubeClient{Clientset: clientset}
+
+	status, err := client.CheckClusterHealthWithOptions(context.Background(), HealthCheckOptions{
+		Checks: []string{"nodes", "probes"},
+	})
+
+	var partial *HealthCheckError
+	if !errors.As(err, &partial) {
+		t.Fatalf("CheckClusterHealthWithOptions() error = %v, want a *HealthCheckError", err)
+	}
+	if status == nil {
+		t.Fatal("CheckClusterHealthWithOptions() status = nil, want the partial status")
+	}
+	if _, ok := partial.Errors["nodes"]; !ok || len(partial.Errors) != 1 {
+		t.Errorf("Errors = %v, want the nodes check only", partial.Errors)
+	}
+	if !status.RanCheck("probes") || status.RanCheck("nodes") {
+		t.Errorf("ChecksRun = %v, want probes only", status.ChecksRun)
+	}
+}
+
+func TestCheckClusterHealthNamespaceAllowlist(t *testing.T) {
+	newClient := func() *KubeClient {
+		clientset := fake.NewSimpleClientset(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		})
+		for _, resource := range []string{"nodes", "storageclasses"} {
+			resource := resource
+			clientset.PrependReactor("list", resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New("denied"))
+			})
+		}
+		return &KubeClient{Clientset: clientset}
+	}
+
+	// With an allowlist, cluster-wide checks are skipped and namespaced checks do without
+	// the cluster-scoped lists they are denied
+	status, err := newClient().CheckClusterHealthWithOptions(context.Background(), HealthCheckOptions{
+		Checks:     []string{"nodes", "scheduling", "storage"},
+		Namespaces: []string{"shop"},
+	})
+	if err != nil {
+		t.Fatalf("CheckClusterHealthWithOptions() with allowlist error = %v", err)
+	}
+	if len(status.SkippedChecks) != 1 || status.SkippedChecks[0] != "nodes" {
+		t.Errorf("SkippedChecks = %v, want nodes", status.SkippedChecks)
+	}
+	if !status.RanCheck("scheduling") || !status.RanCheck("storage") {
+		t.Errorf("ChecksRun = %v, want scheduling and storage", status.ChecksRun)
+	}
+	if len(status.SchedulingStatus.PendingPods) != 1 {
+		t.Errorf("PendingPods = %+v, want shop/web", status.SchedulingStatus.PendingPods)
+	}
+
+	// Without one, a denied list is an error of the check
+	status, err = newClient().CheckClusterHealthWithOptions(context.Background(), HealthCheckOptions{
+		Checks: []string{"scheduling", "storage"},
+	})
+	var partial *HealthCheckError
+	if !errors.As(err, &partial) {
+		t.Fatalf("CheckClusterHealthWithOptions() error = %v, want a *HealthCheckError", err)
+	}
+	if _, ok := partial.Errors["scheduling"]; !ok {
+		t.Errorf("Errors = %v, want scheduling", partial.Errors)
+	}
+	if _, ok := partial.Errors["storage"]; !ok {
+		t.Errorf("Errors = %v, want storage", partial.Errors)
+	}
+}
+
+func TestCheckClusterHealthNamespaceSelectorNoMatch(t *testing.T) {
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments"}},
+	})}
+
+	// A mistyped selector must not look like a healthy cluster
+	_, err := client.CheckClusterHealthWithOptions(context.Background(), HealthCheckOptions{
+		Checks:            []string{"scheduling"},
+		NamespaceSelector: "team=paymnets",
+	})
+	if err == nil {
+		t.Error("CheckClusterHealthWithOptions() error = nil for a selector matching no namespace")
+	}
+
+	if _, err := client.CheckClusterHealthWithOptions(context.Background(), HealthCheckOptions{
+		Checks:            []string{"scheduling"},
+		NamespaceSelector: "team=payments",
+	}); err != nil {
+		t.Errorf("CheckClusterHealthWithOptions() with a matching selector error = %v", err)
+	}
+}
@@ -0,0 +1,354 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"ekspeek/pkg/findings"
+)
+
+// versionMismatchFindings reports nodes running different Kubernetes versions
+func versionMismatchFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	if len(status.NodeVersions) > 1 {
+		versions := make([]string, 0, len(status.NodeVersions))
+		for version := range status.NodeVersions {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+		results = append(results, findings.Finding{
+			ID:          "node_version_mismatch",
+			Severity:    findings.SeverityCritical,
+			Category:    "nodes",
+			Message:     fmt.Sprintf("Nodes are running multiple Kubernetes versions: %s", strings.Join(versions, ", ")),
+			Remediation: "Upgrade nodes to match control plane version",
+		})
+	}
+	return results
+}
+
+// deprecatedAPIFindings reports resources using deprecated API versions
+func deprecatedAPIFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, api := range status.DeprecatedAPIs {
+		results = append(results, findings.Finding{
+			ID:          "deprecated_api",
+			Severity:    findings.SeverityWarning,
+			Category:    "workloads",
+			Message:     api,
+			Remediation: "Update applications using deprecated APIs",
+		})
+	}
+	return results
+}
+
+// authFindings reports IRSA and RBAC issues
+func authFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, issue := range status.AuthStatus.IRSAIssues {
+		results = append(results, findings.Finding{
+			ID:          "irsa",
+			Severity:    findings.SeverityWarning,
+			Category:    "security",
+			Message:     issue,
+			Remediation: "Fix IRSA configuration issues",
+		})
+	}
+
+	for _, issue := range status.AuthStatus.RBACIssues {
+		results = append(results, findings.Finding{
+			ID:          "rbac",
+			Severity:    findings.SeverityWarning,
+			Category:    "security",
+			Message:     issue,
+			Remediation: "Review and fix RBAC issues",
+		})
+	}
+	return results
+}
+
+// nodeFindings reports nodes that are not ready, NotReady nodes with kubelet certificate
+// problems and nodes flapping between Ready and NotReady
+func nodeFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, node := range status.NodeStatus.NotReady {
+		results = append(results, findings.Finding{
+			ID:          "node_not_ready",
+			Severity:    findings.SeverityWarning,
+			Category:    "nodes",
+			Resource:    node,
+			Message:     "Node is not ready",
+			Remediation: "Investigate nodes in NotReady state",
+		})
+	}
+	for _, issue := range status.NodeStatus.CertIssues {
+		results = append(results, findings.Finding{
+			ID:          "kubelet_certificate",
+			Severity:    findings.SeverityCritical,
+			Category:    "nodes",
+			Resource:    issue.Node,
+			Message:     issue.Reason,
+			Remediation: "Approve the node's pending kubelet CSR (kubectl certificate approve), check the node role mapping in aws-auth or the access entries, or replace the node",
+		})
+	}
+	for _, flap := range status.NodeStatus.Flapping {
+		results = append(results, findings.Finding{
+			ID:          "node_flapping",
+			Severity:    findings.SeverityWarning,
+			Category:    "nodes",
+			Resource:    flap.Node,
+			Message:     fmt.Sprintf("Node transitioned between Ready and NotReady %d times in the last %s", flap.Transitions, nodeFlapWindow),
+			Remediation: "Check kubelet and container runtime logs, node memory and disk pressure, and network connectivity to the API server",
+		})
+	}
+	return results
+}
+
+// schedulingFindings reports pending pods and nodes at pod capacity
+func schedulingFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, pod := range status.SchedulingStatus.PendingPods {
+		if len(pod.ResourceShortages) > 0 {
+			shortages := make([]string, 0, len(pod.ResourceShortages))
+			for _, s := range pod.ResourceShortages {
+				shortages = append(shortages, s.String())
+			}
+			results = append(results, findings.Finding{
+				ID:          "pod_pending_insufficient_resource",
+				Severity:    findings.SeverityWarning,
+				Category:    "scheduling",
+				Resource:    fmt.Sprintf("%s/%s", pod.Namespace, pod.Pod),
+				Message:     fmt.Sprintf("Pod is pending because no node can allocate its requests: %s", strings.Join(shortages, "; ")),
+				Remediation: "Add nodes that provide the resource, such as GPU instances with the device plugin running or nodes with hugepages configured, or lower the pod's requests",
+			})
+			continue
+		}
+		if pod.SpreadConstraint != "" {
+			results = append(results, findings.Finding{
+				ID:          "pod_pending_topology_spread",
+				Severity:    findings.SeverityWarning,
+				Category:    "scheduling",
+				Resource:    fmt.Sprintf("%s/%s", pod.Namespace, pod.Pod),
+				Message:     fmt.Sprintf("Pod is pending on a topology spread constraint: %s", pod.SpreadConstraint),
+				Remediation: "Add schedulable nodes in the missing topology domains, raise maxSkew, or use whenUnsatisfiable: ScheduleAnyway",
+			})
+			continue
+		}
+		results = append(results, findings.Finding{
+			ID:          "pod_pending",
+			Severity:    findings.SeverityWarning,
+			Category:    "scheduling",
+			Resource:    fmt.Sprintf("%s/%s", pod.Namespace, pod.Pod),
+			Message:     fmt.Sprintf("Pod is pending: %s", pod.Reason),
+			Remediation: "Address pod scheduling issues",
+		})
+	}
+
+	for _, issue := range status.SchedulingStatus.PodCapacityIssues {
+		results = append(results, findings.Finding{
+			ID:          "node_pod_capacity",
+			Severity:    findings.SeverityWarning,
+			Category:    "scheduling",
+			Resource:    issue.NodeName,
+			Message:     fmt.Sprintf("Node is at pod capacity (%d/%d pods)", issue.PodCount, issue.AllocatablePods),
+			Remediation: "Use larger instance types or enable VPC CNI prefix delegation for nodes at pod capacity",
+		})
+	}
+	return results
+}
+
+// clockSkewFindings reports nodes whose clock is off from the control plane's
+func clockSkewFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, skew := range status.ClockSkew {
+		severity := findings.SeverityWarning
+		if skew.Offset >= CriticalClockSkew || skew.Offset <= -CriticalClockSkew {
+			severity = findings.SeverityCritical
+		}
+		results = append(results, findings.Finding{
+			ID:          "node_clock_skew",
+			Severity:    severity,
+			Category:    "security",
+			Resource:    skew.Node,
+			Message:     fmt.Sprintf("Node clock is %s compared to the control plane", skew),
+			Remediation: "Check that chronyd is running and can reach the Amazon Time Sync Service (169.254.169.123); skewed clocks break certificate and IRSA token validation",
+		})
+	}
+	return results
+}
+
+// defaultTokenFindings reports, per namespace, the pods that mount the default service
+// account token. Namespaces with such pods behind a load balancer are warnings.
+func defaultTokenFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, ns := range status.DefaultTokenNamespaces {
+		severity := findings.SeverityInfo
+		message := fmt.Sprintf("%d pods use the default service account with its token auto-mounted", ns.Pods)
+		if len(ns.Exposed) > 0 {
+			severity = findings.SeverityWarning
+			message += fmt.Sprintf(", %d of them behind a LoadBalancer or Ingress: %s", len(ns.Exposed), strings.Join(ns.Exposed, ", "))
+		}
+		results = append(results, findings.Finding{
+			ID:          "default_sa_token",
+			Severity:    severity,
+			Category:    "security",
+			Resource:    ns.Namespace,
+			Message:     message,
+			Remediation: "Set automountServiceAccountToken: false on the namespace's default service account, and give pods that call the Kubernetes API their own service account",
+		})
+	}
+	return results
+}
+
+// tolerationFindings reports workload pods that landed on restricted nodes through an
+// over-broad toleration
+func tolerationFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, issue := range status.TolerationIssues {
+		results = append(results, findings.Finding{
+			ID:          "broad_toleration",
+			Severity:    findings.SeverityWarning,
+			Category:    "scheduling",
+			Resource:    fmt.Sprintf("%s/%s", issue.Namespace, issue.Pod),
+			Message:     fmt.Sprintf("Pod runs on tainted node %s (%s) via toleration %s", issue.Node, issue.Taint, issue.Toleration),
+			Remediation: "Narrow the pod's tolerations to the specific taints it needs",
+		})
+	}
+	return results
+}
+
+// portFindings reports host port conflicts, pending pods blocked by a taken host port and
+// NodePort allocation problems
+func portFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, c := range status.PortStatus.HostPortConflicts {
+		results = append(results, findings.Finding{
+			ID:          "host_port_conflict",
+			Severity:    findings.SeverityWarning,
+			Category:    "scheduling",
+			Resource:    fmt.Sprintf("%s:%d/%s", c.NodeName, c.Port, c.Protocol),
+			Message:     fmt.Sprintf("Pods bind the same host port: %s", strings.Join(c.Pods, ", ")),
+			Remediation: "Use a Service instead of hostPort, or spread the pods with pod anti-affinity",
+		})
+	}
+
+	for _, p := range status.PortStatus.PendingHostPortPods {
+		results = append(results, findings.Finding{
+			ID:          "host_port_unavailable",
+			Severity:    findings.SeverityWarning,
+			Category:    "scheduling",
+			Resource:    p.Pod,
+			Message:     fmt.Sprintf("Pending pod requests host port %d/%s, which is in use on %d of %d nodes", p.Port, p.Protocol, p.NodesInUse, p.TotalNodes),
+			Remediation: "Free the host port, add nodes, or drop the hostPort from the pod spec",
+		})
+	}
+
+	for _, issue := range status.PortStatus.NodePortIssues {
+		results = append(results, findings.Finding{
+			ID:       "nodeport_allocation",
+			Severity: findings.SeverityWarning,
+			Category: "networking",
+			Message:  issue,
+		})
+	}
+	return results
+}
+
+// storageFindings reports Pending StatefulSet pods whose volumeClaimTemplate PVCs are not bound
+func storageFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, issue := range status.StatefulSetVolumeIssues {
+		results = append(results, findings.Finding{
+			ID:       "statefulset_volume_pending",
+			Severity: findings.SeverityWarning,
+			Category: "storage",
+			Resource: fmt.Sprintf("%s/%s", issue.Namespace, issue.PVC),
+			Message:  fmt.Sprintf("StatefulSet pod %s is Pending with PVC %s: %s", issue.Pod, strings.ToLower(issue.Phase), issue.Diagnosis),
+		})
+	}
+	return results
+}
+
+// loadBalancerFindings reports LoadBalancer services that have not been provisioned
+func loadBalancerFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, svc := range status.LoadBalancerStatus.PendingServices {
+		results = append(results, findings.Finding{
+			ID:          "loadbalancer_pending",
+			Severity:    findings.SeverityWarning,
+			Category:    "networking",
+			Resource:    svc,
+			Message:     "LoadBalancer service has no external address",
+			Remediation: "Check LoadBalancer provisioning issues",
+		})
+	}
+	return results
+}
+
+// singleReplicaFindings reports infrastructure workloads that a single node drain or crash
+// takes down
+func singleReplicaFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, w := range status.SingleReplicaWorkloads {
+		results = append(results, findings.Finding{
+			ID:          "single_replica_critical",
+			Severity:    findings.SeverityWarning,
+			Category:    "workloads",
+			Resource:    fmt.Sprintf("%s/%s", w.Namespace, w.Name),
+			Message:     fmt.Sprintf("%s runs a single replica without a PodDisruptionBudget (infrastructure by %s)", w.Kind, w.Reason),
+			Remediation: "Run at least 2 replicas spread across nodes and add a PodDisruptionBudget with minAvailable: 1",
+		})
+	}
+	return results
+}
+
+// inTreeStorageFindings reports StorageClasses using in-tree provisioners. Without the
+// replacement CSI driver, provisioning is broken once CSI migration is on and breaks on
+// upgrade before that.
+func inTreeStorageFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, sc := range status.InTreeStorageClasses {
+		finding := findings.Finding{
+			ID:          "intree_storage_provisioner",
+			Severity:    findings.SeverityInfo,
+			Category:    "storage",
+			Resource:    sc.Name,
+			Remediation: fmt.Sprintf("Create a StorageClass with provisioner %s and move workloads to it", sc.CSIDriver),
+		}
+		switch {
+		case !sc.CSIDriverInstalled && sc.Removed:
+			finding.Severity = findings.SeverityCritical
+			finding.Message = fmt.Sprintf("StorageClass uses in-tree provisioner %s, removed in %s, and CSI driver %s is not installed; dynamic provisioning fails", sc.Provisioner, sc.RemovedIn, sc.CSIDriver)
+			finding.Remediation = fmt.Sprintf("Install the %s CSI driver, e.g. as an EKS add-on, or create a StorageClass for another installed driver", sc.CSIDriver)
+		case !sc.CSIDriverInstalled && sc.Migrated:
+			finding.Severity = findings.SeverityCritical
+			finding.Message = fmt.Sprintf("StorageClass uses in-tree provisioner %s, migrated to CSI in %s, and CSI driver %s is not installed; dynamic provisioning fails", sc.Provisioner, sc.MigratedIn, sc.CSIDriver)
+			finding.Remediation = fmt.Sprintf("Install the %s CSI driver, e.g. as an EKS add-on, or create a StorageClass for another installed driver", sc.CSIDriver)
+		case !sc.CSIDriverInstalled:
+			finding.Severity = findings.SeverityWarning
+			finding.Message = fmt.Sprintf("StorageClass uses in-tree provisioner %s, migrated to CSI in %s, and CSI driver %s is not installed; provisioning breaks on upgrade", sc.Provisioner, sc.MigratedIn, sc.CSIDriver)
+			finding.Remediation = fmt.Sprintf("Install the %s CSI driver before upgrading to %s", sc.CSIDriver, sc.MigratedIn)
+		default:
+			finding.Message = fmt.Sprintf("StorageClass uses in-tree provisioner %s; volumes are provisioned by %s through CSI migration", sc.Provisioner, sc.CSIDriver)
+		}
+		results = append(results, finding)
+	}
+	return results
+}
+
+// probeFindings reports probes that restart containers needlessly or probe the wrong port
+func probeFindings(status *ClusterHealthStatus) []findings.Finding {
+	var results []findings.Finding
+	for _, issue := range status.ProbeIssues {
+		results = append(results, findings.Finding{
+			ID:          "probe_misconfigured",
+			Severity:    findings.SeverityWarning,
+			Category:    "workloads",
+			Resource:    fmt.Sprintf("%s/%s/%s", issue.Namespace, issue.Pod, issue.Container),
+			Message:     fmt.Sprintf("%s probe (%s) on a container with %d restarts: %s", issue.Probe, issue.Config, issue.RestartCount, strings.Join(issue.Problems, "; ")),
+			Remediation: "Point the probe at a port the container serves, and give it a timeout of a few seconds, a failureThreshold of 3 or more and a startupProbe for slow starts",
+		})
+	}
+	return results
+}
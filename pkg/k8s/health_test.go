@@ -27,10 +27,7 @@ func TestGetEFSCSIStatus(t *testing.T) {
 		},
 	)
 
-	client, err := NewKubeClient(clientset)
-	if err != nil {
-		t.Fatalf("Failed to create client: %v", err)
-	}
+	client := &KubeClient{Clientset: clientset}
 
 	status, err := client.GetEFSCSIStatus(context.Background())
 	if err != nil {
@@ -80,11 +77,7 @@ func TestGetClusterResources(t *testing.T) {
 		},
 	}
 
-	clientset := fake.NewSimpleClientset(node, pod)
-	client, err := NewKubeClient(clientset)
-	if err != nil {
-		t.Fatalf("Failed to create client: %v", err)
-	}
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(node, pod)}
 
 	resources, err := client.GetClusterResources(context.Background())
 	if err != nil {
@@ -106,6 +99,7 @@ func TestValidatePodWebIdentityToken(t *testing.T) {
 		{
 			name: "Valid IRSA configuration",
 			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
 				Spec: corev1.PodSpec{
 					Volumes: []corev1.Volume{
 						{
@@ -136,6 +130,7 @@ func TestValidatePodWebIdentityToken(t *testing.T) {
 		{
 			name: "Missing token volume",
 			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
 						{
@@ -153,7 +148,8 @@ func TestValidatePodWebIdentityToken(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := ValidatePodWebIdentityToken(tc.pod)
+			client := &KubeClient{Clientset: fake.NewSimpleClientset(tc.pod)}
+			err := client.ValidatePodWebIdentityToken(context.Background(), "default", "app")
 			if tc.expectError && err == nil {
 				t.Error("Expected error but got nil")
 			}
@@ -0,0 +1,164 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// hpaPinnedThreshold is how long an HPA can sit at its min or max replicas before it is
+// considered mis-tuned
+const hpaPinnedThreshold = 6 * time.Hour
+
+// HPAStatus represents the status of a HorizontalPodAutoscaler
+type HPAStatus struct {
+	Name            string
+	Namespace       string
+	Target          string
+	MinReplicas     int32
+	MaxReplicas     int32
+	CurrentReplicas int32
+	DesiredReplicas int32
+	Metrics         []string // target vs current value for each metric
+	Conditions      []string // ScalingActive/AbleToScale conditions that are False
+	PinnedAt        string   // "min" or "max" if the HPA has been stuck at a replica bound
+}
+
+// GetHPAStatus lists HorizontalPodAutoscalers in the specified namespace with their replica
+// counts, metric values, failing conditions and whether they are pinned at min or max
+func (k *KubeClient) GetHPAStatus(ctx context.Context, namespace string) ([]HPAStatus, error) {
+	hpas, err := k.Clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontal pod autoscalers: %w", err)
+	}
+
+	var statuses []HPAStatus
+	for _, hpa := range hpas.Items {
+		status := HPAStatus{
+			Name:            hpa.Name,
+			Namespace:       hpa.Namespace,
+			Target:          fmt.Sprintf("%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name),
+			MinReplicas:     1,
+			MaxReplicas:     hpa.Spec.MaxReplicas,
+			CurrentReplicas: hpa.Status.CurrentReplicas,
+			DesiredReplicas: hpa.Status.DesiredReplicas,
+		}
+		if hpa.Spec.MinReplicas != nil {
+			status.MinReplicas = *hpa.Spec.MinReplicas
+		}
+
+		for i, metric := range hpa.Spec.Metrics {
+			current := "<unknown>"
+			if i < len(hpa.Status.CurrentMetrics) {
+				current = describeMetricStatus(hpa.Status.CurrentMetrics[i])
+			}
+			status.Metrics = append(status.Metrics, fmt.Sprintf("%s: %s / %s",
+				describeMetricName(metric), current, describeMetricTarget(metric)))
+		}
+
+		for _, condition := range hpa.Status.Conditions {
+			if (condition.Type == autoscalingv2.ScalingActive || condition.Type == autoscalingv2.AbleToScale) &&
+				condition.Status == corev1.ConditionFalse {
+				status.Conditions = append(status.Conditions, fmt.Sprintf("%s=False (%s): %s",
+					condition.Type, condition.Reason, condition.Message))
+			}
+		}
+
+		// An HPA that hasn't scaled for a long time while at a bound is likely mis-tuned
+		stale := hpa.Status.LastScaleTime == nil || time.Since(hpa.Status.LastScaleTime.Time) > hpaPinnedThreshold
+		if stale && hpa.CreationTimestamp.Time.Before(time.Now().Add(-hpaPinnedThreshold)) {
+			if status.CurrentReplicas == status.MaxReplicas {
+				status.PinnedAt = "max"
+			} else if status.CurrentReplicas == status.MinReplicas {
+				status.PinnedAt = "min"
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func describeMetricName(metric autoscalingv2.MetricSpec) string {
+	switch metric.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if metric.Resource != nil {
+			return string(metric.Resource.Name)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if metric.ContainerResource != nil {
+			return fmt.Sprintf("%s (container %s)", metric.ContainerResource.Name, metric.ContainerResource.Container)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if metric.Pods != nil {
+			return metric.Pods.Metric.Name
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if metric.Object != nil {
+			return metric.Object.Metric.Name
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if metric.External != nil {
+			return metric.External.Metric.Name
+		}
+	}
+	return strings.ToLower(string(metric.Type))
+}
+
+func describeMetricTarget(metric autoscalingv2.MetricSpec) string {
+	var target autoscalingv2.MetricTarget
+	switch {
+	case metric.Resource != nil:
+		target = metric.Resource.Target
+	case metric.ContainerResource != nil:
+		target = metric.ContainerResource.Target
+	case metric.Pods != nil:
+		target = metric.Pods.Target
+	case metric.Object != nil:
+		target = metric.Object.Target
+	case metric.External != nil:
+		target = metric.External.Target
+	}
+
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return target.AverageValue.String()
+	case target.Value != nil:
+		return target.Value.String()
+	}
+	return "<unset>"
+}
+
+func describeMetricStatus(metric autoscalingv2.MetricStatus) string {
+	var current autoscalingv2.MetricValueStatus
+	switch {
+	case metric.Resource != nil:
+		current = metric.Resource.Current
+	case metric.ContainerResource != nil:
+		current = metric.ContainerResource.Current
+	case metric.Pods != nil:
+		current = metric.Pods.Current
+	case metric.Object != nil:
+		current = metric.Object.Current
+	case metric.External != nil:
+		current = metric.External.Current
+	}
+
+	switch {
+	case current.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *current.AverageUtilization)
+	case current.AverageValue != nil:
+		return current.AverageValue.String()
+	case current.Value != nil:
+		return current.Value.String()
+	}
+	return "<unknown>"
+}
@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// imagePullReasons are container waiting reasons caused by failing image pulls
+var imagePullReasons = map[string]bool{
+	"ImagePullBackOff": true,
+	"ErrImagePull":     true,
+}
+
+// ImagePullFailure describes a container that cannot pull its image
+type ImagePullFailure struct {
+	Namespace string
+	Pod       string
+	Container string
+	Image     string
+	Reason    string
+	Message   string
+	NodeName  string
+	Nodegroup string // EKS managed nodegroup of the node, if any
+}
+
+// GetImagePullFailures lists containers in the specified namespace stuck pulling their image
+func (k *KubeClient) GetImagePullFailures(ctx context.Context, namespace string) ([]ImagePullFailure, error) {
+	pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	nodegroups := make(map[string]string)
+	var failures []ImagePullFailure
+	for _, pod := range pods.Items {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			if cs.State.Waiting == nil || !imagePullReasons[cs.State.Waiting.Reason] {
+				continue
+			}
+
+			failures = append(failures, ImagePullFailure{
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Container: cs.Name,
+				Image:     cs.Image,
+				Reason:    cs.State.Waiting.Reason,
+				Message:   cs.State.Waiting.Message,
+				NodeName:  pod.Spec.NodeName,
+				Nodegroup: k.getNodegroup(ctx, pod.Spec.NodeName, nodegroups),
+			})
+		}
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Namespace != failures[j].Namespace {
+			return failures[i].Namespace < failures[j].Namespace
+		}
+		return failures[i].Pod < failures[j].Pod
+	})
+	return failures, nil
+}
+
+// getNodegroup returns the EKS managed nodegroup label of a node, caching lookups
+func (k *KubeClient) getNodegroup(ctx context.Context, nodeName string, cache map[string]string) string {
+	if nodeName == "" {
+		return ""
+	}
+	if nodegroup, ok := cache[nodeName]; ok {
+		return nodegroup
+	}
+
+	nodegroup := ""
+	if node, err := k.Clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{}); err == nil {
+		nodegroup = node.Labels["eks.amazonaws.com/nodegroup"]
+	}
+	cache[nodeName] = nodegroup
+	return nodegroup
+}
@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContainerImage is a distinct image running in the cluster. The same reference pulled
+// at different digests, e.g. a moving tag, is listed once per digest.
+type ContainerImage struct {
+	Image string
+	// Digest is the repository digest the image resolved to, empty until a container
+	// using it has started
+	Digest     string
+	Containers int
+	Namespaces []string
+}
+
+// GetImageInventory lists the distinct images of all containers, init containers and
+// ephemeral containers across all pods, with the digest each resolved to from the
+// container statuses
+func (k *KubeClient) GetImageInventory(ctx context.Context) ([]ContainerImage, error) {
+	pods, err := k.Clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	type imageKey struct{ image, digest string }
+	images := make(map[imageKey]*ContainerImage)
+	namespaces := make(map[imageKey]map[string]bool)
+	for _, pod := range pods.Items {
+		digests := make(map[string]string)
+		statuses := append(append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...),
+			pod.Status.ContainerStatuses...), pod.Status.EphemeralContainerStatuses...)
+		for _, status := range statuses {
+			digests[status.Name] = imageDigest(status.ImageID)
+		}
+
+		containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, c := range pod.Spec.EphemeralContainers {
+			containers = append(containers, corev1.Container(c.EphemeralContainerCommon))
+		}
+		for _, c := range containers {
+			key := imageKey{c.Image, digests[c.Name]}
+			image, ok := images[key]
+			if !ok {
+				image = &ContainerImage{Image: key.image, Digest: key.digest}
+				images[key] = image
+				namespaces[key] = make(map[string]bool)
+			}
+			image.Containers++
+			namespaces[key][pod.Namespace] = true
+		}
+	}
+
+	result := make([]ContainerImage, 0, len(images))
+	for key, image := range images {
+		for ns := range namespaces[key] {
+			image.Namespaces = append(image.Namespaces, ns)
+		}
+		sort.Strings(image.Namespaces)
+		result = append(result, *image)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Image != result[j].Image {
+			return result[i].Image < result[j].Image
+		}
+		return result[i].Digest < result[j].Digest
+	})
+	return result, nil
+}
+
+// imageDigest returns the repository digest of a container status imageID, such as
+// "docker-pullable://repo@sha256:..." or "repo@sha256:...". Bare image IDs are local
+// config digests that registries do not know, so they yield "".
+func imageDigest(imageID string) string {
+	if i := strings.LastIndex(imageID, "@"); i >= 0 {
+		return imageID[i+1:]
+	}
+	return ""
+}
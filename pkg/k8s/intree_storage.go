@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// inTreeProvisioner is an in-tree volume plugin that was removed in favour of a CSI driver
+type inTreeProvisioner struct {
+	CSIDriver string
+	// MigratedIn is the version from which CSI migration is on, on EKS for aws-ebs
+	MigratedIn string
+	RemovedIn  string
+}
+
+// inTreeProvisioners maps the StorageClass provisioner names of removed in-tree volume
+// plugins to their CSI replacement. With CSI migration, StorageClasses that still name the
+// in-tree provisioner are served by the CSI driver, so they only work if it is installed.
+var inTreeProvisioners = map[string]inTreeProvisioner{
+	"kubernetes.io/aws-ebs":    {"ebs.csi.aws.com", "1.23", "1.27"},
+	"kubernetes.io/azure-disk": {"disk.csi.azure.com", "1.23", "1.27"},
+	"kubernetes.io/cinder":     {"cinder.csi.openstack.org", "1.21", "1.26"},
+	"kubernetes.io/gce-pd":     {"pd.csi.storage.gke.io", "1.23", "1.28"},
+}
+
+// InTreeStorageClass is a StorageClass that uses a deprecated or removed in-tree provisioner
+type InTreeStorageClass struct {
+	Name         string
+	Provisioner  string
+	DefaultClass bool
+	// CSIDriver is the CSI driver that replaces the provisioner
+	CSIDriver  string
+	MigratedIn string
+	RemovedIn  string
+	// Migrated is true if the cluster runs a version that serves the provisioner through
+	// the CSI driver
+	Migrated bool
+	// Removed is true if the cluster runs a version without the in-tree plugin
+	Removed bool
+	// CSIDriverInstalled is true if a CSIDriver object for the replacement driver exists
+	CSIDriverInstalled bool
+}
+
+// checkInTreeStorageClasses finds StorageClasses using in-tree provisioners and reports
+// whether the cluster version still has the plugin and the replacement CSI driver is
+// installed
+func (k *KubeClient) checkInTreeStorageClasses(ctx context.Context, status *ClusterHealthStatus) error {
+	scList, err := k.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list storageclasses: %w", err)
+	}
+
+	var classes []InTreeStorageClass
+	for _, sc := range scList.Items {
+		provisioner, ok := inTreeProvisioners[sc.Provisioner]
+		if !ok {
+			continue
+		}
+		_, isDefault := sc.GetAnnotations()["storageclass.kubernetes.io/is-default-class"]
+		classes = append(classes, InTreeStorageClass{
+			Name:         sc.Name,
+			Provisioner:  sc.Provisioner,
+			DefaultClass: isDefault,
+			CSIDriver:    provisioner.CSIDriver,
+			MigratedIn:   provisioner.MigratedIn,
+			RemovedIn:    provisioner.RemovedIn,
+		})
+	}
+	if len(classes) == 0 {
+		return nil
+	}
+
+	clusterMinor := 0
+	if version, err := k.Clientset.Discovery().ServerVersion(); err == nil {
+		clusterMinor = parseMinorVersion(version.Major + "." + version.Minor)
+	}
+
+	driverList, err := k.Clientset.StorageV1().CSIDrivers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list csidrivers: %w", err)
+	}
+	drivers := make(map[string]bool)
+	for _, driver := range driverList.Items {
+		drivers[driver.Name] = true
+	}
+
+	for i := range classes {
+		classes[i].Migrated = clusterMinor > 0 && clusterMinor >= parseMinorVersion(classes[i].MigratedIn)
+		classes[i].Removed = clusterMinor > 0 && clusterMinor >= parseMinorVersion(classes[i].RemovedIn)
+		classes[i].CSIDriverInstalled = drivers[classes[i].CSIDriver]
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i].Name < classes[j].Name })
+	status.InTreeStorageClasses = classes
+	return nil
+}
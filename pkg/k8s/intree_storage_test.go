@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"ekspeek/pkg/findings"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckInTreeStorageClasses(t *testing.T) {
+	storageClass := func(name, provisioner string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}, Provisioner: provisioner}
+	}
+
+	tests := []struct {
+		name         string
+		minor        string
+		drivers      []string
+		wantMigrated bool
+		wantRemoved  bool
+		wantSeverity findings.Severity
+	}{
+		{"removed without driver", "29", nil, true, true, findings.SeverityCritical},
+		{"removed with driver", "29", []string{"ebs.csi.aws.com"}, true, true, findings.SeverityInfo},
+		{"migrated without driver", "23", nil, true, false, findings.SeverityCritical},
+		{"migrated with driver", "26", []string{"ebs.csi.aws.com"}, true, false, findings.SeverityInfo},
+		{"not yet migrated without driver", "22", nil, false, false, findings.SeverityWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(
+				storageClass("gp2", "kubernetes.io/aws-ebs"),
+				storageClass("gp3", "ebs.csi.aws.com"),
+			)
+			for _, driver := range tt.drivers {
+				clientset.Tracker().Add(&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: driver}})
+			}
+			clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{Major: "1", Minor: tt.minor}
+			client := &KubeClient{Clientset: clientset}
+
+			status := &ClusterHealthStatus{}
+			if err := client.checkInTreeStorageClasses(context.Background(), status); err != nil {
+				t.Fatalf("checkInTreeStorageClasses() error = %v", err)
+			}
+			if len(status.InTreeStorageClasses) != 1 || status.InTreeStorageClasses[0].Name != "gp2" {
+				t.Fatalf("InTreeStorageClasses = %v, want only gp2", status.InTreeStorageClasses)
+			}
+			if sc := status.InTreeStorageClasses[0]; sc.Migrated != tt.wantMigrated || sc.Removed != tt.wantRemoved || sc.CSIDriver != "ebs.csi.aws.com" {
+				t.Errorf("InTreeStorageClasses[0] = %+v, want Migrated %v and Removed %v with CSI driver ebs.csi.aws.com", sc, tt.wantMigrated, tt.wantRemoved)
+			}
+
+			results := inTreeStorageFindings(status)
+			if len(results) != 1 || results[0].Severity != tt.wantSeverity {
+				t.Errorf("inTreeStorageFindings() = %v, want one finding with severity %v", results, tt.wantSeverity)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	karpenterNodePoolLabel    = "karpenter.sh/nodepool"
+	karpenterDoNotDisrupt     = "karpenter.sh/do-not-disrupt"
+	karpenterControllerLabels = "app.kubernetes.io/name=karpenter"
+)
+
+var nodeClaimGVR = schema.GroupVersionResource{Group: "karpenter.sh", Version: "v1", Resource: "nodeclaims"}
+
+// KarpenterNodeClaim describes a Karpenter v1 NodeClaim and its launch progress
+type KarpenterNodeClaim struct {
+	Name         string
+	NodePool     string
+	NodeName     string
+	InstanceType string
+	CapacityType string
+	Zone         string
+	Launched     bool
+	Registered   bool
+	Initialized  bool
+	// FailedConditions lists conditions with status False as "Type: reason: message"
+	FailedConditions []string
+	CreatedAt        metav1.Time
+}
+
+// KarpenterDisruptionDecision is a disruption (consolidation, drift, expiration) decision
+// logged by the Karpenter controller
+type KarpenterDisruptionDecision struct {
+	Time     string
+	Reason   string
+	Decision string
+	Message  string
+}
+
+// KarpenterDisruption summarizes Karpenter's disruption decisions and what blocks them
+type KarpenterDisruption struct {
+	Decisions []KarpenterDisruptionDecision
+	// Blocked lists DisruptionBlocked and Unconsolidatable events as "Kind/name: message"
+	Blocked []string
+	// DoNotDisruptPods lists pods annotated karpenter.sh/do-not-disrupt on Karpenter nodes
+	// as "namespace/name (node)"
+	DoNotDisruptPods []string
+	// LogError is set when the controller logs could not be read
+	LogError string
+}
+
+// GetKarpenterNodeClaims lists Karpenter v1 NodeClaims. It returns no NodeClaims when the
+// NodeClaim CRD is not installed.
+func (k *KubeClient) GetKarpenterNodeClaims(ctx context.Context) ([]KarpenterNodeClaim, error) {
+	if k.Config == nil {
+		return nil, nil
+	}
+	dynamicClient, err := dynamic.NewForConfig(k.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	list, err := dynamicClient.Resource(nodeClaimGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list nodeclaims: %w", err)
+	}
+
+	var claims []KarpenterNodeClaim
+	for _, obj := range list.Items {
+		labels := obj.GetLabels()
+		claim := KarpenterNodeClaim{
+			Name:         obj.GetName(),
+			NodePool:     labels[karpenterNodePoolLabel],
+			InstanceType: labels[corev1.LabelInstanceTypeStable],
+			CapacityType: labels["karpenter.sh/capacity-type"],
+			Zone:         labels[zoneLabel],
+			CreatedAt:    obj.GetCreationTimestamp(),
+		}
+		claim.NodeName, _, _ = unstructured.NestedString(obj.Object, "status", "nodeName")
+
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, c := range conditions {
+			cond, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			condType, _ := cond["type"].(string)
+			condStatus, _ := cond["status"].(string)
+			switch condType {
+			case "Launched":
+				claim.Launched = condStatus == "True"
+			case "Registered":
+				claim.Registered = condStatus == "True"
+			case "Initialized":
+				claim.Initialized = condStatus == "True"
+			}
+			if condStatus == "False" {
+				reason, _ := cond["reason"].(string)
+				message, _ := cond["message"].(string)
+				claim.FailedConditions = append(claim.FailedConditions, fmt.Sprintf("%s: %s: %s", condType, reason, message))
+			}
+		}
+
+		claims = append(claims, claim)
+	}
+
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].Name < claims[j].Name
+	})
+	return claims, nil
+}
+
+// GetKarpenterDisruption reads disruption decisions from the Karpenter controller logs and
+// reports what blocks disruption: DisruptionBlocked and Unconsolidatable events, and pods
+// annotated karpenter.sh/do-not-disrupt on Karpenter nodes
+func (k *KubeClient) GetKarpenterDisruption(ctx context.Context) (*KarpenterDisruption, error) {
+	result := &KarpenterDisruption{}
+
+	controllers, err := k.Clientset.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: karpenterControllerLabels,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list karpenter pods: %w", err)
+	}
+	for _, pod := range controllers.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		matches, err := k.ScanPodLogs(ctx, pod.Namespace, pod.Name, "controller", []string{"disrupting"})
+		if err != nil {
+			result.LogError = err.Error()
+			continue
+		}
+		result.Decisions = append(result.Decisions, parseDisruptionDecisions(joinLogMatches(matches))...)
+	}
+
+	events, err := k.Clientset.CoreV1().Events(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, e := range events.Items {
+		if e.Reason != "DisruptionBlocked" && e.Reason != "Unconsolidatable" {
+			continue
+		}
+		line := fmt.Sprintf("%s/%s: %s", e.InvolvedObject.Kind, e.InvolvedObject.Name, e.Message)
+		if !seen[line] {
+			seen[line] = true
+			result.Blocked = append(result.Blocked, line)
+		}
+	}
+	sort.Strings(result.Blocked)
+
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: karpenterNodePoolLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	karpenterNodes := make(map[string]bool)
+	for _, node := range nodes.Items {
+		karpenterNodes[node.Name] = true
+	}
+
+	pods, err := k.Clientset.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Annotations[karpenterDoNotDisrupt] == "true" && karpenterNodes[pod.Spec.NodeName] {
+			result.DoNotDisruptPods = append(result.DoNotDisruptPods, fmt.Sprintf("%s/%s (%s)", pod.Namespace, pod.Name, pod.Spec.NodeName))
+		}
+	}
+	sort.Strings(result.DoNotDisruptPods)
+
+	return result, nil
+}
+
+// parseDisruptionDecisions extracts "disrupting ..." decisions from Karpenter controller
+// logs, which are JSON lines in Karpenter v1
+func parseDisruptionDecisions(logs string) []KarpenterDisruptionDecision {
+	var decisions []KarpenterDisruptionDecision
+	for _, line := range strings.Split(logs, "\n") {
+		if !strings.Contains(line, "disrupting") {
+			continue
+		}
+
+		var entry struct {
+			Time     string `json:"time"`
+			Message  string `json:"message"`
+			Reason   string `json:"reason"`
+			Decision string `json:"decision"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			decisions = append(decisions, KarpenterDisruptionDecision{Message: strings.TrimSpace(line)})
+			continue
+		}
+		if !strings.HasPrefix(entry.Message, "disrupting") {
+			continue
+		}
+		decisions = append(decisions, KarpenterDisruptionDecision{
+			Time:     entry.Time,
+			Reason:   entry.Reason,
+			Decision: entry.Decision,
+			Message:  entry.Message,
+		})
+	}
+	return decisions
+}
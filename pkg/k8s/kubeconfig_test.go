@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestWriteKubeconfigEntry(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          KubeconfigOptions
+		expectedEnv   []api.ExecEnvVar
+		expectCurrent bool
+	}{
+		{
+			name:          "Default profile keeps current context",
+			opts:          KubeconfigOptions{},
+			expectedEnv:   nil,
+			expectCurrent: false,
+		},
+		{
+			name:          "Profile and set current",
+			opts:          KubeconfigOptions{Profile: "prod", SetCurrent: true},
+			expectedEnv:   []api.ExecEnvVar{{Name: "AWS_PROFILE", Value: "prod"}},
+			expectCurrent: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+
+			existing := api.NewConfig()
+			existing.Clusters["other"] = api.NewCluster()
+			existing.Contexts["other"] = api.NewContext()
+			existing.CurrentContext = "other"
+			if err := clientcmd.WriteToFile(*existing, path); err != nil {
+				t.Fatalf("Failed to write kubeconfig: %v", err)
+			}
+
+			tc.opts.Path = path
+			err := writeKubeconfigEntry("my-cluster", "us-east-1", "https://example.eks.amazonaws.com", []byte("ca"), tc.opts)
+			if err != nil {
+				t.Fatalf("writeKubeconfigEntry failed: %v", err)
+			}
+
+			kubeconfig, err := clientcmd.LoadFromFile(path)
+			if err != nil {
+				t.Fatalf("Failed to load kubeconfig: %v", err)
+			}
+
+			authInfo, ok := kubeconfig.AuthInfos["my-cluster"]
+			if !ok || authInfo.Exec == nil {
+				t.Fatalf("Expected exec auth for my-cluster, got %+v", authInfo)
+			}
+
+			exec := authInfo.Exec
+			if exec.APIVersion != "client.authentication.k8s.io/v1beta1" {
+				t.Errorf("Expected exec apiVersion client.authentication.k8s.io/v1beta1, got %s", exec.APIVersion)
+			}
+			if exec.Command != "aws" {
+				t.Errorf("Expected exec command aws, got %s", exec.Command)
+			}
+			expectedArgs := []string{"eks", "get-token", "--cluster-name", "my-cluster", "--region", "us-east-1"}
+			if !reflect.DeepEqual(exec.Args, expectedArgs) {
+				t.Errorf("Expected exec args %v, got %v", expectedArgs, exec.Args)
+			}
+			if len(exec.Env) != len(tc.expectedEnv) || (len(tc.expectedEnv) > 0 && !reflect.DeepEqual(exec.Env, tc.expectedEnv)) {
+				t.Errorf("Expected exec env %v, got %v", tc.expectedEnv, exec.Env)
+			}
+			if authInfo.Token != "" {
+				t.Errorf("Expected no static token, got %s", authInfo.Token)
+			}
+
+			if server := kubeconfig.Clusters["my-cluster"].Server; server != "https://example.eks.amazonaws.com" {
+				t.Errorf("Expected server https://example.eks.amazonaws.com, got %s", server)
+			}
+			if _, ok := kubeconfig.Clusters["other"]; !ok {
+				t.Error("Expected existing cluster entry to be preserved")
+			}
+
+			expectedCurrent := "other"
+			if tc.expectCurrent {
+				expectedCurrent = "my-cluster"
+			}
+			if kubeconfig.CurrentContext != expectedCurrent {
+				t.Errorf("Expected current context %s, got %s", expectedCurrent, kubeconfig.CurrentContext)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeCertIssue is a NotReady node whose kubelet client certificate looks expired or
+// not renewed
+type NodeCertIssue struct {
+	Node   string
+	Reason string
+}
+
+// checkKubeletCertificates looks for signs of an expired kubelet client certificate on
+// NotReady nodes: x509 errors in the node conditions, or a latest kubelet client CSR that
+// is still pending or was denied. A kubelet that cannot renew its certificate stops
+// posting node status, so the node goes NotReady without any other symptom.
+func (k *KubeClient) checkKubeletCertificates(ctx context.Context, notReady []corev1.Node, status *NodeStatus) error {
+	if len(notReady) == 0 {
+		return nil
+	}
+
+	reported := make(map[string]bool)
+	for _, node := range notReady {
+		for _, condition := range node.Status.Conditions {
+			if certificateError(condition.Message) {
+				status.CertIssues = append(status.CertIssues, NodeCertIssue{
+					Node:   node.Name,
+					Reason: fmt.Sprintf("%s condition: %s", condition.Type, condition.Message),
+				})
+				reported[node.Name] = true
+				break
+			}
+		}
+	}
+
+	csrs, err := k.Clientset.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+	if errors.IsForbidden(err) {
+		k.log().Debug("Cannot list certificate signing requests, skipping the kubelet CSR check: %v", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list certificate signing requests: %w", err)
+	}
+
+	// The newest kubelet client CSR of each NotReady node
+	latest := make(map[string]*certificatesv1.CertificateSigningRequest)
+	for _, node := range notReady {
+		latest["system:node:"+node.Name] = nil
+	}
+	for i := range csrs.Items {
+		csr := &csrs.Items[i]
+		if csr.Spec.SignerName != certificatesv1.KubeAPIServerClientKubeletSignerName {
+			continue
+		}
+		current, ok := latest[csr.Spec.Username]
+		if !ok {
+			continue
+		}
+		if current == nil || csr.CreationTimestamp.After(current.CreationTimestamp.Time) {
+			latest[csr.Spec.Username] = csr
+		}
+	}
+
+	for _, node := range notReady {
+		csr := latest["system:node:"+node.Name]
+		if csr == nil || reported[node.Name] {
+			continue
+		}
+		if reason := csrProblem(csr); reason != "" {
+			status.CertIssues = append(status.CertIssues, NodeCertIssue{Node: node.Name, Reason: reason})
+		}
+	}
+	return nil
+}
+
+// certificateError returns true if a node condition message reports a certificate failure
+func certificateError(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "x509") || strings.Contains(message, "certificate has expired")
+}
+
+// csrProblem describes why a kubelet client CSR has not given the node a certificate, or
+// returns "" if it was issued
+func csrProblem(csr *certificatesv1.CertificateSigningRequest) string {
+	for _, condition := range csr.Status.Conditions {
+		if condition.Type == certificatesv1.CertificateDenied || condition.Type == certificatesv1.CertificateFailed {
+			return fmt.Sprintf("kubelet client CSR %s %s: %s", csr.Name, strings.ToLower(string(condition.Type)), condition.Message)
+		}
+	}
+	if len(csr.Status.Certificate) > 0 {
+		return ""
+	}
+	return fmt.Sprintf("kubelet client CSR %s pending since %s", csr.Name, csr.CreationTimestamp.UTC().Format("2006-01-02 15:04 MST"))
+}
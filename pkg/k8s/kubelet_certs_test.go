@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckKubeletCertificates(t *testing.T) {
+	node := func(name string, ready corev1.ConditionStatus, message string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: ready, Message: message},
+			}},
+		}
+	}
+	csr := func(name, node string, age time.Duration, issued bool, conditions ...certificatesv1.CertificateSigningRequestCondition) *certificatesv1.CertificateSigningRequest {
+		csr := &certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(time.Now().Add(-age))},
+			Spec: certificatesv1.CertificateSigningRequestSpec{
+				SignerName: certificatesv1.KubeAPIServerClientKubeletSignerName,
+				Username:   "system:node:" + node,
+			},
+			Status: certificatesv1.CertificateSigningRequestStatus{Conditions: conditions},
+		}
+		if issued {
+			csr.Status.Certificate = []byte("cert")
+		}
+		return csr
+	}
+	approved := certificatesv1.CertificateSigningRequestCondition{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue}
+	denied := certificatesv1.CertificateSigningRequestCondition{Type: certificatesv1.CertificateDenied, Status: corev1.ConditionTrue, Message: "not allowed"}
+
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		node("ready", corev1.ConditionTrue, ""),
+		node("x509", corev1.ConditionUnknown, "Kubelet stopped posting node status: x509: certificate has expired or is not yet valid"),
+		node("pending", corev1.ConditionUnknown, "Kubelet stopped posting node status."),
+		node("denied", corev1.ConditionUnknown, "Kubelet stopped posting node status."),
+		node("renewed", corev1.ConditionUnknown, "Kubelet stopped posting node status."),
+		csr("csr-ready", "ready", time.Minute, false),
+		csr("csr-pending", "pending", time.Hour, false),
+		csr("csr-denied", "denied", time.Hour, false, denied),
+		csr("csr-renewed-old", "renewed", 2*time.Hour, false),
+		csr("csr-renewed", "renewed", time.Hour, true, approved),
+	)}
+
+	status := &NodeStatus{}
+	if err := client.checkNodeStatus(context.Background(), status); err != nil {
+		t.Fatalf("checkNodeStatus() error = %v", err)
+	}
+
+	want := map[string]string{
+		"x509":    "Ready condition: Kubelet stopped posting node status: x509",
+		"pending": "kubelet client CSR csr-pending pending since",
+		"denied":  "kubelet client CSR csr-denied denied: not allowed",
+	}
+	if len(status.CertIssues) != len(want) {
+		t.Fatalf("CertIssues = %+v, want x509, pending and denied", status.CertIssues)
+	}
+	for _, issue := range status.CertIssues {
+		if !strings.HasPrefix(issue.Reason, want[issue.Node]) {
+			t.Errorf("CertIssues[%s] = %q, want it to start with %q", issue.Node, issue.Reason, want[issue.Node])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// logContextLines is the number of lines kept before each matched log line
+	logContextLines = 2
+	// maxLogMatches bounds the matches ScanPodLogs keeps per matcher; older ones are dropped
+	maxLogMatches = 1000
+	// maxLogLineBytes truncates longer log lines
+	maxLogLineBytes = 64 * 1024
+)
+
+// LogMatch is a log line containing one of the ScanPodLogs matchers
+type LogMatch struct {
+	Line    string
+	Matcher string   // First matcher found in the line
+	Before  []string // Up to logContextLines lines preceding the match
+}
+
+// ScanPodLogs streams a container's logs line by line and returns the lines containing
+// any of the matchers, each with the lines just before it. Unlike GetPodLogs it never
+// holds the whole log in memory, so it is safe on pods that log gigabytes. Only the
+// most recent maxLogMatches matches of each matcher are kept.
+func (k *KubeClient) ScanPodLogs(ctx context.Context, namespace, podName, containerName string, matchers []string) ([]LogMatch, error) {
+	return k.scanPodLogs(ctx, namespace, podName, containerName, false, matchers)
+}
+
+// scanPodLogs is ScanPodLogs on the current or, for crashlooping containers, the previous
+// container's logs
+func (k *KubeClient) scanPodLogs(ctx context.Context, namespace, podName, containerName string, previous bool, matchers []string) ([]LogMatch, error) {
+	if err := k.requireLive("reading pod logs"); err != nil {
+		return nil, err
+	}
+	req := k.Clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: containerName,
+		Previous:  previous,
+	})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod logs: %w", err)
+	}
+	defer stream.Close()
+
+	matches, err := scanLogLines(stream, matchers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pod logs: %w", err)
+	}
+	return matches, nil
+}
+
+// scanLogLines returns the lines of r that contain any of the matchers
+func scanLogLines(r io.Reader, matchers []string) ([]LogMatch, error) {
+	reader := bufio.NewReader(r)
+	var (
+		matches []LogMatch
+		recent  []string
+		counts  = make(map[string]int)
+	)
+	for {
+		line, err := readLogLine(reader)
+		if err == io.EOF {
+			return matches, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, matcher := range matchers {
+			if !strings.Contains(line, matcher) {
+				continue
+			}
+			if counts[matcher] == maxLogMatches {
+				matches = dropOldestMatch(matches, matcher)
+			} else {
+				counts[matcher]++
+			}
+			matches = append(matches, LogMatch{
+				Line:    line,
+				Matcher: matcher,
+				Before:  append([]string(nil), recent...),
+			})
+			break
+		}
+
+		recent = append(recent, line)
+		if len(recent) > logContextLines {
+			recent = recent[1:]
+		}
+	}
+}
+
+// dropOldestMatch removes the first match of the given matcher
+func dropOldestMatch(matches []LogMatch, matcher string) []LogMatch {
+	for i, m := range matches {
+		if m.Matcher == matcher {
+			return append(matches[:i], matches[i+1:]...)
+		}
+	}
+	return matches
+}
+
+// readLogLine reads one line without its line ending, truncated to maxLogLineBytes
+func readLogLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		fragment, isPrefix, err := r.ReadLine()
+		if room := maxLogLineBytes - len(line); room > 0 {
+			line = append(line, fragment[:min(len(fragment), room)]...)
+		}
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				return string(line), nil
+			}
+			return "", err
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
+
+// joinLogMatches joins the matched lines into a log excerpt
+func joinLogMatches(matches []LogMatch) string {
+	lines := make([]string, len(matches))
+	for i, m := range matches {
+		lines[i] = m.Line
+	}
+	return strings.Join(lines, "\n")
+}
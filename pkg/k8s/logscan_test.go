@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanLogLines(t *testing.T) {
+	testCases := []struct {
+		name       string
+		logs       string
+		matchers   []string
+		wantLines  []string
+		wantBefore []string // Context of the first match
+	}{
+		{
+			name:       "matches with context",
+			logs:       "a\nb\nc\nAccessDenied here\nd\n",
+			matchers:   []string{"AccessDenied"},
+			wantLines:  []string{"AccessDenied here"},
+			wantBefore: []string{"b", "c"},
+		},
+		{
+			name:       "last line without newline",
+			logs:       "x\nStarted CA",
+			matchers:   []string{"NoScaleUpGroups", "Started CA"},
+			wantLines:  []string{"Started CA"},
+			wantBefore: []string{"x"},
+		},
+		{
+			name:      "no match",
+			logs:      "x\ny\n",
+			matchers:  []string{"disrupting"},
+			wantLines: nil,
+		},
+		{
+			name:       "long line is truncated",
+			logs:       "disrupting " + strings.Repeat("x", maxLogLineBytes) + "\nnext\n",
+			matchers:   []string{"disrupting"},
+			wantLines:  []string{"disrupting " + strings.Repeat("x", maxLogLineBytes-len("disrupting "))},
+			wantBefore: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			matches, err := scanLogLines(strings.NewReader(tc.logs), tc.matchers)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(matches) != len(tc.wantLines) {
+				t.Fatalf("expected %d matches, got %d", len(tc.wantLines), len(matches))
+			}
+			for i, m := range matches {
+				if m.Line != tc.wantLines[i] {
+					t.Errorf("match %d: expected line of %d bytes, got %d bytes", i, len(tc.wantLines[i]), len(m.Line))
+				}
+			}
+			if len(matches) > 0 && strings.Join(matches[0].Before, ",") != strings.Join(tc.wantBefore, ",") {
+				t.Errorf("expected context %v, got %v", tc.wantBefore, matches[0].Before)
+			}
+		})
+	}
+}
+
+func TestScanLogLinesKeepsRecentMatchesPerMatcher(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("Started CA\n")
+	for i := 0; i < maxLogMatches+10; i++ {
+		b.WriteString("FailedToUpdateNodeGroupSize\n")
+	}
+
+	matches, err := scanLogLines(strings.NewReader(b.String()), []string{"Started CA", "FailedToUpdateNodeGroupSize"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != maxLogMatches+1 {
+		t.Fatalf("expected %d matches, got %d", maxLogMatches+1, len(matches))
+	}
+	if matches[0].Matcher != "Started CA" {
+		t.Errorf("expected the Started CA match to be kept, got %q", matches[0].Matcher)
+	}
+}
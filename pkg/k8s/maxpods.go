@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// eniMaxPods is the maximum number of pods per instance type with the default VPC CNI
+// (ENIs * (IPv4 addresses per ENI - 1) + 2), as published in the EKS AMI eni-max-pods.txt
+var eniMaxPods = map[string]int64{
+	"t3.micro": 4, "t3.small": 11, "t3.medium": 17, "t3.large": 35, "t3.xlarge": 58, "t3.2xlarge": 58,
+	"t3a.micro": 4, "t3a.small": 8, "t3a.medium": 17, "t3a.large": 35, "t3a.xlarge": 58, "t3a.2xlarge": 58,
+	"m5.large": 29, "m5.xlarge": 58, "m5.2xlarge": 58, "m5.4xlarge": 234, "m5.8xlarge": 234, "m5.12xlarge": 234, "m5.16xlarge": 737, "m5.24xlarge": 737,
+	"m6i.large": 29, "m6i.xlarge": 58, "m6i.2xlarge": 58, "m6i.4xlarge": 234, "m6i.8xlarge": 234, "m6i.12xlarge": 234, "m6i.16xlarge": 737, "m6i.24xlarge": 737,
+	"m6g.medium": 8, "m6g.large": 29, "m6g.xlarge": 58, "m6g.2xlarge": 58, "m6g.4xlarge": 234, "m6g.8xlarge": 234, "m6g.12xlarge": 234, "m6g.16xlarge": 737,
+	"m7i.large": 29, "m7i.xlarge": 58, "m7i.2xlarge": 58, "m7i.4xlarge": 234, "m7i.8xlarge": 234, "m7i.12xlarge": 234, "m7i.16xlarge": 737, "m7i.24xlarge": 737,
+	"m7g.medium": 8, "m7g.large": 29, "m7g.xlarge": 58, "m7g.2xlarge": 58, "m7g.4xlarge": 234, "m7g.8xlarge": 234, "m7g.12xlarge": 234, "m7g.16xlarge": 737,
+	"c5.large": 29, "c5.xlarge": 58, "c5.2xlarge": 58, "c5.4xlarge": 234, "c5.9xlarge": 234, "c5.12xlarge": 234, "c5.18xlarge": 737, "c5.24xlarge": 737,
+	"c6i.large": 29, "c6i.xlarge": 58, "c6i.2xlarge": 58, "c6i.4xlarge": 234, "c6i.8xlarge": 234, "c6i.12xlarge": 234, "c6i.16xlarge": 737, "c6i.24xlarge": 737,
+	"c6g.medium": 8, "c6g.large": 29, "c6g.xlarge": 58, "c6g.2xlarge": 58, "c6g.4xlarge": 234, "c6g.8xlarge": 234, "c6g.12xlarge": 234, "c6g.16xlarge": 737,
+	"c7g.medium": 8, "c7g.large": 29, "c7g.xlarge": 58, "c7g.2xlarge": 58, "c7g.4xlarge": 234, "c7g.8xlarge": 234, "c7g.12xlarge": 234, "c7g.16xlarge": 737,
+	"r5.large": 29, "r5.xlarge": 58, "r5.2xlarge": 58, "r5.4xlarge": 234, "r5.8xlarge": 234, "r5.12xlarge": 234, "r5.16xlarge": 737, "r5.24xlarge": 737,
+	"r6i.large": 29, "r6i.xlarge": 58, "r6i.2xlarge": 58, "r6i.4xlarge": 234, "r6i.8xlarge": 234, "r6i.12xlarge": 234, "r6i.16xlarge": 737, "r6i.24xlarge": 737,
+	"r6g.medium": 8, "r6g.large": 29, "r6g.xlarge": 58, "r6g.2xlarge": 58, "r6g.4xlarge": 234, "r6g.8xlarge": 234, "r6g.12xlarge": 234, "r6g.16xlarge": 737,
+	"g4dn.xlarge": 29, "g4dn.2xlarge": 29, "g4dn.4xlarge": 29, "g4dn.8xlarge": 58, "g4dn.12xlarge": 234, "g4dn.16xlarge": 58,
+	"g5.xlarge": 58, "g5.2xlarge": 58, "g5.4xlarge": 234, "g5.8xlarge": 234, "g5.12xlarge": 737, "g5.16xlarge": 234, "g5.24xlarge": 737, "g5.48xlarge": 345,
+	"p3.2xlarge": 58, "p3.8xlarge": 234, "p3.16xlarge": 234,
+}
+
+// PodCapacityIssue describes a node that has run out of (or is close to) pod capacity
+type PodCapacityIssue struct {
+	NodeName         string
+	InstanceType     string
+	PodCount         int64
+	AllocatablePods  int64
+	TheoreticalMax   int64
+	PrefixDelegation bool
+}
+
+// MaxPodsForInstanceType returns the ENI-based maximum pods for an instance type, if known
+func MaxPodsForInstanceType(instanceType string) (int64, bool) {
+	maxPods, ok := eniMaxPods[instanceType]
+	return maxPods, ok
+}
+
+// checkPodCapacity compares each node's running pod count against its allocatable pods and
+// the instance type's theoretical maximum with the default VPC CNI
+func (k *KubeClient) checkPodCapacity(ctx context.Context, status *SchedulingStatus) error {
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	pods, err := k.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	podsPerNode := make(map[string]int64)
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podsPerNode[pod.Spec.NodeName]++
+	}
+
+	lookedUp := make(map[string]int64)
+	for _, node := range nodes.Items {
+		allocatable := node.Status.Allocatable.Pods().Value()
+		instanceType := node.Labels["node.kubernetes.io/instance-type"]
+		theoreticalMax, known := MaxPodsForInstanceType(instanceType)
+		if !known && instanceType != "" && k.MaxPodsLookup != nil {
+			theoreticalMax, known = k.lookupMaxPods(ctx, instanceType, lookedUp)
+		}
+
+		issue := PodCapacityIssue{
+			NodeName:        node.Name,
+			InstanceType:    instanceType,
+			PodCount:        podsPerNode[node.Name],
+			AllocatablePods: allocatable,
+			TheoreticalMax:  theoreticalMax,
+		}
+
+		// Allocatable pods above the ENI limit means prefix delegation (or a custom max-pods) is in use
+		limit := allocatable
+		if known {
+			if allocatable > theoreticalMax {
+				issue.PrefixDelegation = true
+			} else if theoreticalMax < limit || limit == 0 {
+				limit = theoreticalMax
+			}
+		}
+
+		if limit > 0 && issue.PodCount >= limit {
+			status.PodCapacityIssues = append(status.PodCapacityIssues, issue)
+		}
+	}
+
+	return nil
+}
+
+// lookupMaxPods calls MaxPodsLookup once per instance type, caching the result, including
+// failed lookups, in cache
+func (k *KubeClient) lookupMaxPods(ctx context.Context, instanceType string, cache map[string]int64) (int64, bool) {
+	maxPods, ok := cache[instanceType]
+	if !ok {
+		var err error
+		maxPods, err = k.MaxPodsLookup(ctx, instanceType)
+		if err != nil {
+			k.log().Debug("Cannot look up the maximum pods of instance type %s: %v", instanceType, err)
+			maxPods = 0
+		}
+		cache[instanceType] = maxPods
+	}
+	return maxPods, maxPods > 0
+}
+
+// String returns a human readable description of the pod capacity issue
+func (i PodCapacityIssue) String() string {
+	description := fmt.Sprintf("Node %s (%s): %d/%d pods", i.NodeName, i.InstanceType, i.PodCount, i.AllocatablePods)
+	if i.TheoreticalMax > 0 {
+		if i.PrefixDelegation {
+			description += fmt.Sprintf(", prefix delegation in use (ENI limit %d)", i.TheoreticalMax)
+		} else {
+			description += fmt.Sprintf(", ENI/IP limit %d", i.TheoreticalMax)
+		}
+	}
+	return description
+}
@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckPodCapacityLookup(t *testing.T) {
+	node := func(name, instanceType string, allocatable int64) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"node.kubernetes.io/instance-type": instanceType}},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourcePods: *resource.NewQuantity(allocatable, resource.DecimalSI),
+			}},
+		}
+	}
+	objects := []runtime.Object{
+		node("new-1", "m8g.large", 29),
+		node("new-2", "m8g.large", 29),
+		node("unknown", "x9.large", 110),
+	}
+	for i := 0; i < 29; i++ {
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: "new-1"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		})
+	}
+
+	lookups := make(map[string]int)
+	client := &KubeClient{
+		Clientset: fake.NewSimpleClientset(objects...),
+		MaxPodsLookup: func(_ context.Context, instanceType string) (int64, error) {
+			lookups[instanceType]++
+			if instanceType == "m8g.large" {
+				return 29, nil
+			}
+			return 0, errors.New("instance type not found")
+		},
+	}
+
+	status := &SchedulingStatus{}
+	if err := client.checkPodCapacity(context.Background(), status); err != nil {
+		t.Fatalf("checkPodCapacity() error = %v", err)
+	}
+
+	if lookups["m8g.large"] != 1 || lookups["x9.large"] != 1 {
+		t.Errorf("lookups = %v, want one per instance type", lookups)
+	}
+	if len(status.PodCapacityIssues) != 1 {
+		t.Fatalf("PodCapacityIssues = %+v, want new-1 only", status.PodCapacityIssues)
+	}
+	if issue := status.PodCapacityIssues[0]; issue.NodeName != "new-1" || issue.TheoreticalMax != 29 {
+		t.Errorf("issue = %+v, want new-1 at the EC2 limit of 29", issue)
+	}
+}
+
+func TestCheckSchedulingStatusCapacityError(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	})
+	clientset.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection reset by peer")
+	})
+	client := &KubeClient{Clientset: clientset}
+
+	status := &SchedulingStatus{}
+	if err := client.checkSchedulingStatus(context.Background(), []string{metav1.NamespaceAll}, false, status); err != nil {
+		t.Fatalf("checkSchedulingStatus() error = %v", err)
+	}
+
+	if len(status.PendingPods) != 1 || status.PendingPods[0].Pod != "web" {
+		t.Errorf("PendingPods = %+v, want web", status.PendingPods)
+	}
+	if status.CapacityError != "connection reset by peer" {
+		t.Errorf("CapacityError = %q, want the node list error", status.CapacityError)
+	}
+}
@@ -0,0 +1,190 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clusterFirstNdots is the ndots value kubelet writes for ClusterFirst pods
+const clusterFirstNdots = 5
+
+// DNSSearchIssue is a workload whose external hostnames are expanded through the cluster
+// search domains before being resolved, because they have fewer dots than ndots
+type DNSSearchIssue struct {
+	Namespace     string
+	Owner         string // Kind/name of the controlling workload
+	Pods          int
+	DNSPolicy     corev1.DNSPolicy
+	Ndots         int
+	ExternalHosts []string
+}
+
+// DNSSearchReport summarizes the ndots and dnsConfig settings of running pods
+type DNSSearchReport struct {
+	Pods int
+	// DefaultNdotsPods use the ClusterFirst default of ndots:5
+	DefaultNdotsPods int
+	// CustomDNSConfigPods set spec.dnsConfig options or use dnsPolicy None
+	CustomDNSConfigPods int
+	Issues              []DNSSearchIssue
+}
+
+// podNdots returns the ndots value in effect for a pod
+func podNdots(pod *corev1.Pod) int {
+	ndots := 1 // resolv.conf default, used by the Default and None policies
+	policy := pod.Spec.DNSPolicy
+	if policy == "" || policy == corev1.DNSClusterFirst && !pod.Spec.HostNetwork || policy == corev1.DNSClusterFirstWithHostNet {
+		ndots = clusterFirstNdots
+	}
+	if pod.Spec.DNSConfig != nil {
+		for _, opt := range pod.Spec.DNSConfig.Options {
+			if opt.Name == "ndots" && opt.Value != nil {
+				if n, err := strconv.Atoi(*opt.Value); err == nil {
+					ndots = n
+				}
+			}
+		}
+	}
+	return ndots
+}
+
+// externalHosts returns the external hostnames a pod's containers are configured to call,
+// taken from URLs and *_HOST environment variables. Service names qualified by one of the
+// cluster's namespaces, such as redis.cache, are in-cluster names and left out.
+func externalHosts(pod *corev1.Pod, namespaces map[string]bool) []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	add := func(host string) {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" || strings.HasSuffix(host, ".") || !strings.Contains(host, ".") || net.ParseIP(host) != nil {
+			return
+		}
+		for _, suffix := range []string{".local", ".svc", ".internal"} {
+			if strings.HasSuffix(host, suffix) {
+				return
+			}
+		}
+		if namespaces[host[strings.LastIndex(host, ".")+1:]] {
+			return
+		}
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+
+	for _, c := range pod.Spec.Containers {
+		values := append([]string{}, c.Args...)
+		for _, env := range c.Env {
+			if strings.HasSuffix(env.Name, "_HOST") && !strings.Contains(env.Value, "/") {
+				h, _, err := net.SplitHostPort(env.Value)
+				if err != nil {
+					h = env.Value
+				}
+				add(h)
+				continue
+			}
+			values = append(values, env.Value)
+		}
+		for _, v := range values {
+			for _, field := range strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' || r == ';' }) {
+				if !strings.Contains(field, "://") {
+					continue
+				}
+				if u, err := url.Parse(field); err == nil {
+					add(u.Hostname())
+				}
+			}
+		}
+	}
+	return hosts
+}
+
+// GetDNSSearchReport inspects the dnsPolicy and dnsConfig of running pods and reports
+// workloads calling external hostnames with fewer dots than their ndots setting. Each such
+// lookup first tries every search domain, multiplying DNS queries and NXDOMAIN responses.
+func (k *KubeClient) GetDNSSearchReport(ctx context.Context, namespace string) (*DNSSearchReport, error) {
+	pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	// The namespaces qualifying in-cluster service names, listed best effort; those of the
+	// running pods are always known
+	namespaces := make(map[string]bool)
+	if list, err := k.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{}); err == nil {
+		for _, ns := range list.Items {
+			namespaces[ns.Name] = true
+		}
+	}
+	for _, pod := range pods.Items {
+		namespaces[pod.Namespace] = true
+	}
+
+	report := &DNSSearchReport{}
+	owners := make(map[string]string)
+	issues := make(map[string]*DNSSearchIssue)
+	for _, pod := range pods.Items {
+		report.Pods++
+		ndots := podNdots(&pod)
+		if ndots >= clusterFirstNdots {
+			report.DefaultNdotsPods++
+		}
+		if pod.Spec.DNSPolicy == corev1.DNSNone || pod.Spec.DNSConfig != nil && len(pod.Spec.DNSConfig.Options) > 0 {
+			report.CustomDNSConfigPods++
+		}
+
+		var expanded []string
+		for _, host := range externalHosts(&pod, namespaces) {
+			if strings.Count(host, ".") < ndots {
+				expanded = append(expanded, host)
+			}
+		}
+		if len(expanded) == 0 {
+			continue
+		}
+
+		owner := k.getPodOwner(ctx, &pod, owners)
+		key := pod.Namespace + "/" + owner
+		issue := issues[key]
+		if issue == nil {
+			issue = &DNSSearchIssue{
+				Namespace: pod.Namespace,
+				Owner:     owner,
+				DNSPolicy: pod.Spec.DNSPolicy,
+				Ndots:     ndots,
+			}
+			issues[key] = issue
+		}
+		issue.Pods++
+		for _, host := range expanded {
+			if !slices.Contains(issue.ExternalHosts, host) {
+				issue.ExternalHosts = append(issue.ExternalHosts, host)
+			}
+		}
+	}
+
+	for _, issue := range issues {
+		sort.Strings(issue.ExternalHosts)
+		report.Issues = append(report.Issues, *issue)
+	}
+	sort.Slice(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Namespace != report.Issues[j].Namespace {
+			return report.Issues[i].Namespace < report.Issues[j].Namespace
+		}
+		return report.Issues[i].Owner < report.Issues[j].Owner
+	})
+
+	return report, nil
+}
@@ -0,0 +1,26 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestExternalHosts(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Args: []string{"https://api.stripe.com/v1", "http://redis.cache:6379"},
+		Env: []corev1.EnvVar{
+			{Name: "DB_HOST", Value: "db.payments:5432"},
+			{Name: "QUEUE_HOST", Value: "sqs.eu-west-1.amazonaws.com"},
+			{Name: "METRICS_URL", Value: "http://prometheus.monitoring.svc:9090"},
+		},
+	}}}}
+	namespaces := map[string]bool{"cache": true, "payments": true, "monitoring": true}
+
+	got := externalHosts(pod, namespaces)
+	want := []string{"sqs.eu-west-1.amazonaws.com", "api.stripe.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("externalHosts() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NetworkPolicyAnalysis contains the NetworkPolicy coverage results for a set of namespaces
+type NetworkPolicyAnalysis struct {
+	ProtectedPods        []string // namespace/pod selected by at least one policy
+	UnprotectedPods      []string // namespace/pod not selected by any policy (default-allow)
+	LockedDownNamespaces []string // "namespace (direction)" with a default-deny policy but no allow rules in that direction
+	DeadPolicies         []string // namespace/policy whose podSelector matches zero pods
+}
+
+// AnalyzeNetworkPolicies reports NetworkPolicy coverage and conflicts in the specified namespace
+func (k *KubeClient) AnalyzeNetworkPolicies(ctx context.Context, namespace string) (*NetworkPolicyAnalysis, error) {
+	pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	policies, err := k.GetNetworkPolicies(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network policies: %w", err)
+	}
+
+	// Group policies by namespace, since a policy only ever selects pods in its own namespace
+	policiesByNamespace := make(map[string][]networkingv1.NetworkPolicy)
+	for _, policy := range policies.Items {
+		policiesByNamespace[policy.Namespace] = append(policiesByNamespace[policy.Namespace], policy)
+	}
+
+	analysis := &NetworkPolicyAnalysis{}
+	matchCount := make(map[string]int)
+
+	for _, pod := range pods.Items {
+		podKey := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		selected := false
+
+		for _, policy := range policiesByNamespace[pod.Namespace] {
+			selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+			if err != nil {
+				continue
+			}
+			if selector.Matches(labels.Set(pod.Labels)) {
+				selected = true
+				matchCount[fmt.Sprintf("%s/%s", policy.Namespace, policy.Name)]++
+			}
+		}
+
+		if selected {
+			analysis.ProtectedPods = append(analysis.ProtectedPods, podKey)
+		} else {
+			analysis.UnprotectedPods = append(analysis.UnprotectedPods, podKey)
+		}
+	}
+
+	for ns, nsPolicies := range policiesByNamespace {
+		// Default-deny and allow rules only cancel out within the same direction
+		denied := make(map[networkingv1.PolicyType]bool)
+		allowed := make(map[networkingv1.PolicyType]bool)
+
+		for _, policy := range nsPolicies {
+			policyKey := fmt.Sprintf("%s/%s", policy.Namespace, policy.Name)
+			if matchCount[policyKey] == 0 {
+				analysis.DeadPolicies = append(analysis.DeadPolicies, policyKey)
+			}
+
+			for _, direction := range policyDirections(policy) {
+				rules := len(policy.Spec.Ingress)
+				if direction == networkingv1.PolicyTypeEgress {
+					rules = len(policy.Spec.Egress)
+				}
+				if rules > 0 {
+					allowed[direction] = true
+				} else if selectsAllPods(policy) {
+					denied[direction] = true
+				}
+			}
+		}
+
+		for _, direction := range []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress} {
+			if denied[direction] && !allowed[direction] {
+				analysis.LockedDownNamespaces = append(analysis.LockedDownNamespaces, fmt.Sprintf("%s (%s)", ns, strings.ToLower(string(direction))))
+			}
+		}
+	}
+
+	sort.Strings(analysis.LockedDownNamespaces)
+	sort.Strings(analysis.DeadPolicies)
+
+	return analysis, nil
+}
+
+// selectsAllPods returns true if the policy selects every pod in its namespace
+func selectsAllPods(policy networkingv1.NetworkPolicy) bool {
+	return len(policy.Spec.PodSelector.MatchLabels) == 0 && len(policy.Spec.PodSelector.MatchExpressions) == 0
+}
+
+// policyDirections returns the directions a policy applies to. Policies without explicit
+// policyTypes apply to Ingress, and to Egress if they have egress rules.
+func policyDirections(policy networkingv1.NetworkPolicy) []networkingv1.PolicyType {
+	if len(policy.Spec.PolicyTypes) > 0 {
+		return policy.Spec.PolicyTypes
+	}
+	directions := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	if len(policy.Spec.Egress) > 0 {
+		directions = append(directions, networkingv1.PolicyTypeEgress)
+	}
+	return directions
+}
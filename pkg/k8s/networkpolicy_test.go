@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAnalyzeNetworkPoliciesLockedDown(t *testing.T) {
+	policy := func(namespace, name string, spec networkingv1.NetworkPolicySpec) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Spec: spec}
+	}
+	allowIngress := []networkingv1.NetworkPolicyIngressRule{{}}
+	allowEgress := []networkingv1.NetworkPolicyEgressRule{{}}
+
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		// Default-deny ingress with only an egress allow policy
+		policy("shop", "deny-ingress", networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		}),
+		policy("shop", "allow-egress", networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      allowEgress,
+		}),
+		// One policy allowing ingress and denying egress
+		policy("billing", "ingress-only", networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress:     allowIngress,
+		}),
+		// Default-deny ingress with an ingress allow policy
+		policy("web", "deny-all", networkingv1.NetworkPolicySpec{}),
+		policy("web", "allow-http", networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Ingress:     allowIngress,
+		}),
+	)}
+
+	analysis, err := client.AnalyzeNetworkPolicies(context.Background(), "")
+	if err != nil {
+		t.Fatalf("AnalyzeNetworkPolicies() error = %v", err)
+	}
+	want := []string{"billing (egress)", "shop (ingress)"}
+	if !reflect.DeepEqual(analysis.LockedDownNamespaces, want) {
+		t.Errorf("LockedDownNamespaces = %v, want %v", analysis.LockedDownNamespaces, want)
+	}
+}
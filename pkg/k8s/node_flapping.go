@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// nodeFlapWindow is how far back node readiness transitions are counted
+	nodeFlapWindow = 30 * time.Minute
+	// nodeFlapThreshold is the number of Ready/NotReady transitions within nodeFlapWindow
+	// that marks a node as flapping
+	nodeFlapThreshold = 3
+)
+
+// NodeFlap describes a node that transitioned between Ready and NotReady several times
+// within nodeFlapWindow
+type NodeFlap struct {
+	Node           string
+	Transitions    int
+	Ready          bool
+	LastTransition time.Time
+}
+
+// checkNodeFlapping counts each node's Ready/NotReady transitions in the last
+// nodeFlapWindow from NodeReady and NodeNotReady events and the Ready condition's
+// lastTransitionTime. A node that flaps is reported even when it is Ready right now.
+func (k *KubeClient) checkNodeFlapping(ctx context.Context, nodes []corev1.Node, status *NodeStatus) error {
+	events, err := k.ListEvents(ctx, EventOptions{
+		Since:   nodeFlapWindow,
+		Reasons: []string{"NodeReady", "NodeNotReady"},
+	})
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-nodeFlapWindow)
+	transitions := make(map[string]int)
+	for _, event := range events {
+		if event.InvolvedObject.Kind != "Node" {
+			continue
+		}
+		// Repeated events are aggregated; only count occurrences inside the window
+		count := int(eventCount(event))
+		if eventFirstSeen(event).Before(cutoff) {
+			count = 1
+		}
+		transitions[event.InvolvedObject.Name] += count
+	}
+
+	for _, node := range nodes {
+		flap := NodeFlap{Node: node.Name, Transitions: transitions[node.Name]}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type != corev1.NodeReady {
+				continue
+			}
+			flap.Ready = condition.Status == corev1.ConditionTrue
+			flap.LastTransition = condition.LastTransitionTime.Time
+			// Events may have expired or been dropped; the condition records at least the
+			// latest transition
+			if flap.Transitions == 0 && flap.LastTransition.After(cutoff) {
+				flap.Transitions = 1
+			}
+		}
+		if flap.Transitions >= nodeFlapThreshold {
+			status.Flapping = append(status.Flapping, flap)
+		}
+	}
+
+	sort.Slice(status.Flapping, func(i, j int) bool {
+		return status.Flapping[i].Transitions > status.Flapping[j].Transitions
+	})
+	return nil
+}
@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// unremovableNodePattern matches cluster-autoscaler log lines for nodes it refused to scale down
+var unremovableNodePattern = regexp.MustCompile(`[Nn]ode ([A-Za-z0-9.-]+) (?:is not suitable for removal|cannot be removed)`)
+
+// PDBStatus represents a PodDisruptionBudget that currently allows no disruptions
+type PDBStatus struct {
+	Name               string
+	Namespace          string
+	DisruptionsAllowed int32
+	CurrentHealthy     int32
+	DesiredHealthy     int32
+	ExpectedPods       int32
+	Nodes              []string // nodes running pods selected by the PDB
+	BlockedNodes       []string // nodes in Nodes that the cluster-autoscaler flagged as unremovable
+}
+
+// GetPDBStatus lists PodDisruptionBudgets in the specified namespace that allow zero
+// disruptions, along with the nodes their pods run on. Nodes the cluster-autoscaler has
+// flagged as unremovable are reported as blocked by the PDB. PDBs that select no pods
+// cannot block anything and are left out.
+func (k *KubeClient) GetPDBStatus(ctx context.Context, namespace string) ([]PDBStatus, error) {
+	pdbs, err := k.Clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	// Correlation with the autoscaler is best effort, since it may not be installed
+	unremovable := k.getUnremovableNodes(ctx)
+
+	var statuses []PDBStatus
+	for _, pdb := range pdbs.Items {
+		if pdb.Status.DisruptionsAllowed > 0 || pdb.Status.ExpectedPods == 0 || pdb.Spec.Selector == nil {
+			continue
+		}
+
+		status := PDBStatus{
+			Name:               pdb.Name,
+			Namespace:          pdb.Namespace,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			CurrentHealthy:     pdb.Status.CurrentHealthy,
+			DesiredHealthy:     pdb.Status.DesiredHealthy,
+			ExpectedPods:       pdb.Status.ExpectedPods,
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err == nil {
+			pods, err := k.Clientset.CoreV1().Pods(pdb.Namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: selector.String(),
+			})
+			if err == nil {
+				seen := make(map[string]bool)
+				for _, pod := range pods.Items {
+					if pod.Spec.NodeName == "" || seen[pod.Spec.NodeName] {
+						continue
+					}
+					seen[pod.Spec.NodeName] = true
+					status.Nodes = append(status.Nodes, pod.Spec.NodeName)
+					if unremovable[pod.Spec.NodeName] {
+						status.BlockedNodes = append(status.BlockedNodes, pod.Spec.NodeName)
+					}
+				}
+				sort.Strings(status.Nodes)
+				sort.Strings(status.BlockedNodes)
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// getUnremovableNodes returns the nodes the cluster-autoscaler logged as unremovable during
+// scale-down, or an empty set if the autoscaler or its logs are unavailable
+func (k *KubeClient) getUnremovableNodes(ctx context.Context) map[string]bool {
+	nodes := make(map[string]bool)
+
+	caPod, err := k.GetClusterAutoscalerPod(ctx)
+	if err != nil {
+		return nodes
+	}
+
+	matches, err := k.ScanPodLogs(ctx, caPod.Namespace, caPod.Name, "", []string{"is not suitable for removal", "cannot be removed"})
+	if err != nil {
+		return nodes
+	}
+
+	for _, match := range unremovableNodePattern.FindAllStringSubmatch(joinLogMatches(matches), -1) {
+		nodes[match[1]] = true
+	}
+
+	return nodes
+}
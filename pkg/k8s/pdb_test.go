@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetPDBStatus(t *testing.T) {
+	pdb := func(name string, selector *metav1.LabelSelector, expected int32) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: selector},
+			Status:     policyv1.PodDisruptionBudgetStatus{ExpectedPods: expected},
+		}
+	}
+	pod := func(name, app, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": app}},
+			Spec:       corev1.PodSpec{NodeName: node},
+		}
+	}
+
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		pod("web-0", "web", "node-a"),
+		pod("db-0", "db", "node-b"),
+		pdb("web", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}, 1),
+		// A nil selector matches no pods, not every pod in the namespace
+		pdb("no-selector", nil, 0),
+		pdb("no-pods", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gone"}}, 0),
+	)}
+
+	statuses, err := client.GetPDBStatus(context.Background(), "shop")
+	if err != nil {
+		t.Fatalf("GetPDBStatus() error = %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "web" {
+		t.Fatalf("GetPDBStatus() = %+v, want only the web PDB", statuses)
+	}
+	if len(statuses[0].Nodes) != 1 || statuses[0].Nodes[0] != "node-a" {
+		t.Errorf("Nodes = %v, want [node-a]", statuses[0].Nodes)
+	}
+}
@@ -0,0 +1,213 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Default kube-apiserver --service-node-port-range, which EKS does not allow changing
+const (
+	nodePortMin = 30000
+	nodePortMax = 32767
+)
+
+// PortStatus holds host port and NodePort usage across the cluster
+type PortStatus struct {
+	HostPortConflicts []HostPortConflict
+	// PendingHostPortPods are pending pods requesting a host port already taken on nodes
+	PendingHostPortPods []PendingHostPortPod
+	NodePortServices    []NodePortService
+	NodePortIssues      []string
+}
+
+// HostPortConflict describes pods on the same node binding the same host port
+type HostPortConflict struct {
+	NodeName string
+	Port     int32
+	Protocol corev1.Protocol
+	Pods     []string // namespace/name
+}
+
+// PendingHostPortPod is a pending pod whose host port is in use on some nodes
+type PendingHostPortPod struct {
+	Pod        string // namespace/name
+	Port       int32
+	Protocol   corev1.Protocol
+	NodesInUse int
+	TotalNodes int
+}
+
+// NodePortService is a service with NodePort allocations
+type NodePortService struct {
+	Namespace string
+	Name      string
+	Type      corev1.ServiceType
+	NodePorts []int32
+}
+
+type hostPortKey struct {
+	port     int32
+	protocol corev1.Protocol
+}
+
+type hostPortUse struct {
+	pod    string
+	hostIP string
+}
+
+// podHostPorts returns the host ports a pod binds. Pods using the host network bind their
+// container ports on the node.
+func podHostPorts(pod *corev1.Pod) []corev1.ContainerPort {
+	var ports []corev1.ContainerPort
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.HostPort == 0 && pod.Spec.HostNetwork {
+				p.HostPort = p.ContainerPort
+			}
+			if p.HostPort == 0 {
+				continue
+			}
+			if p.Protocol == "" {
+				p.Protocol = corev1.ProtocolTCP
+			}
+			ports = append(ports, p)
+		}
+	}
+	return ports
+}
+
+// hostIPsOverlap returns true if two bindings of the same port collide: either binds all
+// addresses or both bind the same address
+func hostIPsOverlap(a, b string) bool {
+	wildcard := func(ip string) bool { return ip == "" || ip == "0.0.0.0" || ip == "::" }
+	return wildcard(a) || wildcard(b) || a == b
+}
+
+// checkPortStatus reports pods binding the same host port on a node, pending pods whose host
+// port is taken, and NodePort allocations that are duplicated or out of range
+func (k *KubeClient) checkPortStatus(ctx context.Context, status *PortStatus) error {
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := k.Clientset.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	usage := make(map[string]map[hostPortKey][]hostPortUse)
+	var pending []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if pod.Spec.NodeName == "" {
+			if len(podHostPorts(&pod)) > 0 {
+				pending = append(pending, pod)
+			}
+			continue
+		}
+
+		for _, p := range podHostPorts(&pod) {
+			if usage[pod.Spec.NodeName] == nil {
+				usage[pod.Spec.NodeName] = make(map[hostPortKey][]hostPortUse)
+			}
+			key := hostPortKey{port: p.HostPort, protocol: p.Protocol}
+			usage[pod.Spec.NodeName][key] = append(usage[pod.Spec.NodeName][key], hostPortUse{
+				pod:    pod.Namespace + "/" + pod.Name,
+				hostIP: p.HostIP,
+			})
+		}
+	}
+
+	for node, ports := range usage {
+		for key, uses := range ports {
+			conflicting := make(map[string]bool)
+			for i := range uses {
+				for j := i + 1; j < len(uses); j++ {
+					if uses[i].pod != uses[j].pod && hostIPsOverlap(uses[i].hostIP, uses[j].hostIP) {
+						conflicting[uses[i].pod] = true
+						conflicting[uses[j].pod] = true
+					}
+				}
+			}
+			if len(conflicting) == 0 {
+				continue
+			}
+
+			conflict := HostPortConflict{NodeName: node, Port: key.port, Protocol: key.protocol}
+			for pod := range conflicting {
+				conflict.Pods = append(conflict.Pods, pod)
+			}
+			sort.Strings(conflict.Pods)
+			status.HostPortConflicts = append(status.HostPortConflicts, conflict)
+		}
+	}
+	sort.Slice(status.HostPortConflicts, func(i, j int) bool {
+		a, b := status.HostPortConflicts[i], status.HostPortConflicts[j]
+		if a.NodeName != b.NodeName {
+			return a.NodeName < b.NodeName
+		}
+		return a.Port < b.Port
+	})
+
+	for _, pod := range pending {
+		for _, p := range podHostPorts(&pod) {
+			inUse := 0
+			for _, node := range nodes.Items {
+				if len(usage[node.Name][hostPortKey{port: p.HostPort, protocol: p.Protocol}]) > 0 {
+					inUse++
+				}
+			}
+			if inUse > 0 {
+				status.PendingHostPortPods = append(status.PendingHostPortPods, PendingHostPortPod{
+					Pod:        pod.Namespace + "/" + pod.Name,
+					Port:       p.HostPort,
+					Protocol:   p.Protocol,
+					NodesInUse: inUse,
+					TotalNodes: len(nodes.Items),
+				})
+			}
+		}
+	}
+
+	services, err := k.Clientset.CoreV1().Services(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+	owners := make(map[string][]string)
+	for _, svc := range services.Items {
+		name := svc.Namespace + "/" + svc.Name
+		entry := NodePortService{Namespace: svc.Namespace, Name: svc.Name, Type: svc.Spec.Type}
+		for _, p := range svc.Spec.Ports {
+			if p.NodePort == 0 {
+				continue
+			}
+			entry.NodePorts = append(entry.NodePorts, p.NodePort)
+			key := fmt.Sprintf("%d/%s", p.NodePort, p.Protocol)
+			owners[key] = append(owners[key], name)
+			if p.NodePort < nodePortMin || p.NodePort > nodePortMax {
+				status.NodePortIssues = append(status.NodePortIssues,
+					fmt.Sprintf("%s: NodePort %d is outside the range %d-%d", name, p.NodePort, nodePortMin, nodePortMax))
+			}
+		}
+		if len(entry.NodePorts) > 0 {
+			status.NodePortServices = append(status.NodePortServices, entry)
+		}
+	}
+	for port, names := range owners {
+		if len(names) > 1 {
+			sort.Strings(names)
+			status.NodePortIssues = append(status.NodePortIssues,
+				fmt.Sprintf("NodePort %s is allocated to several services: %s", port, strings.Join(names, ", ")))
+		}
+	}
+	sort.Strings(status.NodePortIssues)
+
+	return nil
+}
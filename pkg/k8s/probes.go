@@ -0,0 +1,190 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// probeRestartThreshold is the restart count from which a container's probe timings
+	// are examined
+	probeRestartThreshold = 5
+	// minProbeFailureWindow is the shortest time, periodSeconds x failureThreshold, a
+	// liveness or startup probe should keep failing before the container is restarted
+	minProbeFailureWindow = 10
+)
+
+// ProbeIssue is a container probe that is likely misconfigured: tight timings on a
+// container that keeps restarting, or a port the container does not expose
+type ProbeIssue struct {
+	Namespace    string
+	Pod          string
+	Container    string
+	Probe        string // liveness, readiness or startup
+	RestartCount int32
+	// Config summarizes the probe, e.g. "httpGet :8080/healthz timeout=1s period=5s failureThreshold=1"
+	Config   string
+	Problems []string
+}
+
+// checkProbes inspects the liveness, readiness and startup probes of every container.
+// Probes targeting a port the container does not expose are always reported; liveness
+// and startup probes with tight timings only for containers that restarted at least
+// probeRestartThreshold times and were not OOM killed.
+func (k *KubeClient) checkProbes(ctx context.Context, namespaces []string, status *ClusterHealthStatus) error {
+	for _, namespace := range namespaces {
+		pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		for _, pod := range pods.Items {
+			statuses := make(map[string]corev1.ContainerStatus)
+			for _, cs := range pod.Status.ContainerStatuses {
+				statuses[cs.Name] = cs
+			}
+
+			for _, c := range pod.Spec.Containers {
+				cs := statuses[c.Name]
+				restarting := cs.RestartCount >= probeRestartThreshold &&
+					(cs.LastTerminationState.Terminated == nil || cs.LastTerminationState.Terminated.Reason != "OOMKilled")
+
+				for _, p := range []struct {
+					name  string
+					probe *corev1.Probe
+				}{
+					{"liveness", c.LivenessProbe},
+					{"readiness", c.ReadinessProbe},
+					{"startup", c.StartupProbe},
+				} {
+					if p.probe == nil {
+						continue
+					}
+					problems := probePortProblems(p.probe, c)
+					if restarting && p.name != "readiness" {
+						problems = append(problems, probeTimingProblems(p.name, p.probe, c.StartupProbe != nil)...)
+					}
+					if len(problems) == 0 {
+						continue
+					}
+					status.ProbeIssues = append(status.ProbeIssues, ProbeIssue{
+						Namespace:    pod.Namespace,
+						Pod:          pod.Name,
+						Container:    c.Name,
+						Probe:        p.name,
+						RestartCount: cs.RestartCount,
+						Config:       describeProbe(p.probe),
+						Problems:     problems,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(status.ProbeIssues, func(i, j int) bool {
+		return status.ProbeIssues[i].RestartCount > status.ProbeIssues[j].RestartCount
+	})
+	return nil
+}
+
+// probeTimingProblems flags liveness and startup probe settings that restart a container
+// on brief slowness
+func probeTimingProblems(name string, probe *corev1.Probe, hasStartupProbe bool) []string {
+	var problems []string
+	if probe.TimeoutSeconds <= 1 {
+		problems = append(problems, fmt.Sprintf("timeoutSeconds=%d fails the probe on any response slower than a second", probeTimeout(probe)))
+	}
+	if probe.FailureThreshold == 1 {
+		problems = append(problems, "failureThreshold=1 restarts the container on a single failed probe")
+	} else if window := probePeriod(probe) * probeFailureThreshold(probe); window < minProbeFailureWindow {
+		problems = append(problems, fmt.Sprintf("restarts the container after %ds of failures (periodSeconds x failureThreshold)", window))
+	}
+	if name == "liveness" && !hasStartupProbe && probe.InitialDelaySeconds == 0 {
+		problems = append(problems, "no startupProbe or initialDelaySeconds, so a slow start counts as failures")
+	}
+	return problems
+}
+
+// probePortProblems flags probes whose port is a name the container does not define, or
+// a number missing from the container's declared ports
+func probePortProblems(probe *corev1.Probe, c corev1.Container) []string {
+	var port *intstr.IntOrString
+	switch {
+	case probe.HTTPGet != nil:
+		port = &probe.HTTPGet.Port
+	case probe.TCPSocket != nil:
+		port = &probe.TCPSocket.Port
+	case probe.GRPC != nil:
+		p := intstr.FromInt32(probe.GRPC.Port)
+		port = &p
+	default:
+		return nil
+	}
+
+	if port.Type == intstr.String {
+		for _, cp := range c.Ports {
+			if cp.Name == port.StrVal {
+				return nil
+			}
+		}
+		return []string{fmt.Sprintf("port %q is not a named port of the container", port.StrVal)}
+	}
+	// Declaring ports is optional, so numbers are only checked against a declared list
+	if len(c.Ports) == 0 {
+		return nil
+	}
+	var declared []string
+	for _, cp := range c.Ports {
+		if cp.ContainerPort == port.IntVal {
+			return nil
+		}
+		declared = append(declared, fmt.Sprint(cp.ContainerPort))
+	}
+	return []string{fmt.Sprintf("port %d is not among the container's ports (%s)", port.IntVal, strings.Join(declared, ", "))}
+}
+
+// describeProbe summarizes a probe's handler and timings, with the API defaults filled in
+func describeProbe(probe *corev1.Probe) string {
+	var handler string
+	switch {
+	case probe.HTTPGet != nil:
+		handler = fmt.Sprintf("httpGet :%s%s", probe.HTTPGet.Port.String(), probe.HTTPGet.Path)
+	case probe.TCPSocket != nil:
+		handler = "tcpSocket :" + probe.TCPSocket.Port.String()
+	case probe.GRPC != nil:
+		handler = fmt.Sprintf("grpc :%d", probe.GRPC.Port)
+	case probe.Exec != nil:
+		handler = "exec " + strings.Join(probe.Exec.Command, " ")
+	}
+	return fmt.Sprintf("%s initialDelay=%ds timeout=%ds period=%ds failureThreshold=%d",
+		handler, probe.InitialDelaySeconds, probeTimeout(probe), probePeriod(probe), probeFailureThreshold(probe))
+}
+
+// probeTimeout, probePeriod and probeFailureThreshold return the probe settings, with
+// the API defaults for fields left unset
+func probeTimeout(probe *corev1.Probe) int32 {
+	if probe.TimeoutSeconds == 0 {
+		return 1
+	}
+	return probe.TimeoutSeconds
+}
+
+func probePeriod(probe *corev1.Probe) int32 {
+	if probe.PeriodSeconds == 0 {
+		return 10
+	}
+	return probe.PeriodSeconds
+}
+
+func probeFailureThreshold(probe *corev1.Probe) int32 {
+	if probe.FailureThreshold == 0 {
+		return 3
+	}
+	return probe.FailureThreshold
+}
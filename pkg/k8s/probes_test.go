@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckProbes(t *testing.T) {
+	pod := func(name string, restarts int32, lastReason string, probe *corev1.Probe) *corev1.Pod {
+		status := corev1.ContainerStatus{Name: "app", RestartCount: restarts}
+		if lastReason != "" {
+			status.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{Reason: lastReason}
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:          "app",
+				Ports:         []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				LivenessProbe: probe,
+			}}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}},
+		}
+	}
+	httpProbe := func(port intstr.IntOrString, timeout, failureThreshold int32) *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: port}},
+			InitialDelaySeconds: 10,
+			TimeoutSeconds:      timeout,
+			FailureThreshold:    failureThreshold,
+		}
+	}
+
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		pod("tight", 12, "Error", httpProbe(intstr.FromString("http"), 1, 1)),
+		pod("tight-oom", 12, "OOMKilled", httpProbe(intstr.FromString("http"), 1, 1)),
+		pod("tight-stable", 0, "", httpProbe(intstr.FromString("http"), 1, 1)),
+		pod("wrong-port", 0, "", httpProbe(intstr.FromInt32(9090), 5, 3)),
+		pod("healthy", 12, "Error", httpProbe(intstr.FromInt32(8080), 5, 3)),
+	)}
+
+	status := &ClusterHealthStatus{}
+	if err := client.checkProbes(context.Background(), []string{metav1.NamespaceAll}, status); err != nil {
+		t.Fatalf("checkProbes() error = %v", err)
+	}
+
+	got := make(map[string]ProbeIssue)
+	for _, issue := range status.ProbeIssues {
+		got[issue.Pod] = issue
+	}
+	if len(got) != 2 {
+		t.Fatalf("ProbeIssues = %+v, want tight and wrong-port", status.ProbeIssues)
+	}
+	if issue := got["tight"]; len(issue.Problems) != 2 || issue.RestartCount != 12 {
+		t.Errorf("tight = %+v, want the timeout and failureThreshold problems with 12 restarts", issue)
+	}
+	if issue := got["wrong-port"]; len(issue.Problems) != 1 {
+		t.Errorf("wrong-port = %+v, want the port problem", issue)
+	}
+	if got := probeFindings(status); len(got) != 2 {
+		t.Errorf("probeFindings() = %v, want 2 findings", got)
+	}
+}
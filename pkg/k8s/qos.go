@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodResourceIssue describes a pod whose containers omit resource requests or limits
+type PodResourceIssue struct {
+	Namespace       string
+	Pod             string
+	Owner           string // Kind/name of the controlling workload, e.g. Deployment/web
+	QOSClass        corev1.PodQOSClass
+	MissingRequests []string // containers without CPU or memory requests
+	MissingLimits   []string // containers without CPU or memory limits
+}
+
+// GetPodsWithoutRequests lists pods in the specified namespace whose containers omit CPU or
+// memory requests or limits, sorted by namespace and owning workload
+func (k *KubeClient) GetPodsWithoutRequests(ctx context.Context, namespace string) ([]PodResourceIssue, error) {
+	pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	owners := make(map[string]string)
+	var issues []PodResourceIssue
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		issue := PodResourceIssue{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			QOSClass:  pod.Status.QOSClass,
+		}
+		for _, container := range pod.Spec.Containers {
+			if !hasCPUAndMemory(container.Resources.Requests) {
+				issue.MissingRequests = append(issue.MissingRequests, container.Name)
+			}
+			if !hasCPUAndMemory(container.Resources.Limits) {
+				issue.MissingLimits = append(issue.MissingLimits, container.Name)
+			}
+		}
+		if len(issue.MissingRequests) == 0 && len(issue.MissingLimits) == 0 {
+			continue
+		}
+
+		issue.Owner = k.getPodOwner(ctx, &pod, owners)
+		issues = append(issues, issue)
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Namespace != issues[j].Namespace {
+			return issues[i].Namespace < issues[j].Namespace
+		}
+		if issues[i].Owner != issues[j].Owner {
+			return issues[i].Owner < issues[j].Owner
+		}
+		return issues[i].Pod < issues[j].Pod
+	})
+
+	return issues, nil
+}
+
+// GetQoSDistribution returns the number of pods in each QoS class in the specified namespace
+func (k *KubeClient) GetQoSDistribution(ctx context.Context, namespace string) (map[corev1.PodQOSClass]int, error) {
+	pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	distribution := make(map[corev1.PodQOSClass]int)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		distribution[pod.Status.QOSClass]++
+	}
+
+	return distribution, nil
+}
+
+// getPodOwner returns the Kind/name of the workload controlling a pod, following
+// ReplicaSets up to their Deployment. Resolved ReplicaSets are cached in owners.
+func (k *KubeClient) getPodOwner(ctx context.Context, pod *corev1.Pod, owners map[string]string) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "Pod/" + pod.Name
+	}
+	if ref.Kind != "ReplicaSet" {
+		return ref.Kind + "/" + ref.Name
+	}
+
+	key := pod.Namespace + "/" + ref.Name
+	if owner, ok := owners[key]; ok {
+		return owner
+	}
+
+	owner := "ReplicaSet/" + ref.Name
+	rs, err := k.Clientset.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err == nil {
+		if rsRef := metav1.GetControllerOf(rs); rsRef != nil {
+			owner = rsRef.Kind + "/" + rsRef.Name
+		}
+	}
+	owners[key] = owner
+	return owner
+}
+
+func hasCPUAndMemory(resources corev1.ResourceList) bool {
+	_, hasCPU := resources[corev1.ResourceCPU]
+	_, hasMemory := resources[corev1.ResourceMemory]
+	return hasCPU && hasMemory
+}
@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetClusterResourcesInitContainers(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+
+	container := func(name, cpuRequest, cpuLimit string) corev1.Container {
+		c := corev1.Container{
+			Name: name,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuRequest)},
+			},
+		}
+		if cpuLimit != "" {
+			c.Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuLimit)}
+		}
+		return c
+	}
+	sidecar := func(name, cpuRequest string) corev1.Container {
+		always := corev1.ContainerRestartPolicyAlways
+		c := container(name, cpuRequest, "")
+		c.RestartPolicy = &always
+		return c
+	}
+
+	testCases := []struct {
+		name          string
+		spec          corev1.PodSpec
+		expectedCPU   int64
+		expectedLimit int64
+	}{
+		{
+			name: "Large init container dominates",
+			spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{container("migrate", "2", "3")},
+				Containers:     []corev1.Container{container("app", "500m", "1"), container("proxy", "500m", "")},
+			},
+			expectedCPU:   2000,
+			expectedLimit: 3000,
+		},
+		{
+			name: "Regular containers dominate",
+			spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{container("init", "250m", "")},
+				Containers:     []corev1.Container{container("app", "1", "2"), container("proxy", "500m", "")},
+			},
+			expectedCPU:   1500,
+			expectedLimit: 2000,
+		},
+		{
+			name: "Sidecar init container runs alongside",
+			spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{sidecar("mesh", "500m"), container("init", "1", "")},
+				Containers:     []corev1.Container{container("app", "1", "")},
+			},
+			expectedCPU:   1500,
+			expectedLimit: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+				Spec:       tc.spec,
+			}
+			pod.Spec.NodeName = "test-node"
+
+			client := &KubeClient{Clientset: fake.NewSimpleClientset(node, pod)}
+			resources, err := client.GetClusterResources(context.Background())
+			if err != nil {
+				t.Fatalf("GetClusterResources failed: %v", err)
+			}
+
+			if resources.AllocatedCPU != tc.expectedCPU {
+				t.Errorf("Expected allocated CPU %dm, got %dm", tc.expectedCPU, resources.AllocatedCPU)
+			}
+			if resources.LimitCPU != tc.expectedLimit {
+				t.Errorf("Expected CPU limits %dm, got %dm", tc.expectedLimit, resources.LimitCPU)
+			}
+
+			expectedRatio := float64(tc.expectedLimit) / 4000
+			if resources.CPULimitRatio != expectedRatio {
+				t.Errorf("Expected CPU limit ratio %f, got %f", expectedRatio, resources.CPULimitRatio)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/aws/smithy-go"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ErrClusterNotReady is returned for a cluster that has no API endpoint or certificate
+// yet, as happens while it is being created
+var ErrClusterNotReady = errors.New("cluster is not ready")
+
+// throttlingCodes are the AWS API error codes of throttled requests
+var throttlingCodes = map[string]bool{
+	"Throttling":               true,
+	"ThrottlingException":      true,
+	"TooManyRequestsException": true,
+	"RequestLimitExceeded":     true,
+}
+
+// RetryOptions bounds the retries of calls that can fail briefly while a new cluster or a
+// freshly written kubeconfig context is not ready yet
+type RetryOptions struct {
+	// Retries is the number of attempts after the first one
+	Retries int
+	// Backoff is the wait before the first retry; it doubles with every retry
+	Backoff time.Duration
+	// OnRetry, if set, is called before each retry with the attempt that failed
+	OnRetry func(attempt int, err error, wait time.Duration)
+}
+
+// Retry calls fn until it succeeds, fails with an error that is not transient, the retries
+// are used up or the context is done, and returns the last error. Errors such as Forbidden,
+// Unauthorized, NotFound or AccessDenied are returned at once, since waiting does not fix
+// them.
+func Retry(ctx context.Context, opts RetryOptions, fn func() error) error {
+	wait := opts.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > opts.Retries || !transient(err) {
+			return err
+		}
+		if opts.OnRetry != nil {
+			opts.OnRetry(attempt, err, wait)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// transient returns true if an error can go away on its own: timeouts, throttling, server
+// errors, refused or reset connections, endpoint names that do not resolve yet and clusters
+// that are still being created
+func transient(err error) bool {
+	if errors.Is(err, ErrClusterNotReady) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	if apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) {
+		return true
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return retryableStatus(int(status.Status().Code))
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && throttlingCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	var responseErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseErr) {
+		return retryableStatus(responseErr.HTTPStatusCode())
+	}
+	return false
+}
+
+// retryableStatus returns true for the HTTP status codes of throttled requests and server
+// errors
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// WaitReady makes the client's first API call, a server version request, with retries, so
+// an endpoint that is not serving yet fails here rather than in the middle of the checks
+func (k *KubeClient) WaitReady(ctx context.Context, opts RetryOptions) error {
+	if k.Config == nil {
+		return nil
+	}
+	err := Retry(ctx, opts, func() error {
+		_, err := k.Clientset.Discovery().ServerVersion()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reach the API server: %w", err)
+	}
+	return nil
+}
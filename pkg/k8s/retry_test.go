@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTransient(t *testing.T) {
+	awsResponse := func(code int, err error) error {
+		return &smithy.OperationError{ServiceID: "EKS", OperationName: "DescribeCluster", Err: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: code}},
+			Err:      err,
+		}}
+	}
+	pods := schema.GroupResource{Resource: "pods"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"cluster not ready", fmt.Errorf("cluster dev has no API endpoint or certificate yet (status CREATING): %w", ErrClusterNotReady), true},
+		{"deadline exceeded", fmt.Errorf("get version: %w", context.DeadlineExceeded), true},
+		{"connection refused", &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, true},
+		{"connection reset", &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		{"endpoint not resolving", &net.OpError{Op: "dial", Err: &net.DNSError{Name: "abc.eks.amazonaws.com", IsNotFound: true}}, true},
+		{"kubernetes service unavailable", apierrors.NewServiceUnavailable("etcd is not ready"), true},
+		{"kubernetes too many requests", apierrors.NewTooManyRequests("slow down", 1), true},
+		{"kubernetes server timeout", apierrors.NewServerTimeout(pods, "list", 1), true},
+		{"kubernetes bad gateway", apierrors.NewGenericServerResponse(http.StatusBadGateway, "get", pods, "", "", 0, false), true},
+		{"aws throttling", awsResponse(http.StatusBadRequest, &smithy.GenericAPIError{Code: "ThrottlingException"}), true},
+		{"aws server error", awsResponse(http.StatusServiceUnavailable, &smithy.GenericAPIError{Code: "ServiceUnavailableException"}), true},
+		{"kubernetes forbidden", apierrors.NewForbidden(pods, "", errors.New("no RBAC")), false},
+		{"kubernetes unauthorized", apierrors.NewUnauthorized("token expired"), false},
+		{"kubernetes not found", apierrors.NewNotFound(pods, "web"), false},
+		{"aws access denied", awsResponse(http.StatusForbidden, &smithy.GenericAPIError{Code: "AccessDeniedException"}), false},
+		{"aws cluster not found", awsResponse(http.StatusNotFound, &smithy.GenericAPIError{Code: "ResourceNotFoundException"}), false},
+		{"context canceled", context.Canceled, false},
+		{"unknown error", errors.New("x509: certificate signed by unknown authority"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transient(tt.err); got != tt.want {
+				t.Errorf("transient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	unavailable := apierrors.NewServiceUnavailable("starting")
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("no RBAC"))
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{"success", []error{nil}, 1, nil},
+		{"transient then success", []error{unavailable, unavailable, nil}, 3, nil},
+		{"transient until retries are used up", []error{unavailable, unavailable, unavailable, unavailable, nil}, 3, unavailable},
+		{"permanent error", []error{forbidden, nil}, 1, forbidden},
+		{"transient then permanent", []error{unavailable, forbidden, nil}, 2, forbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls, retries int
+			opts := RetryOptions{
+				Retries: 2,
+				Backoff: time.Millisecond,
+				OnRetry: func(int, error, time.Duration) { retries++ },
+			}
+			err := Retry(context.Background(), opts, func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			if err != tt.wantErr {
+				t.Errorf("Retry() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls || retries != calls-1 {
+				t.Errorf("Retry() made %d calls and %d retries, want %d calls", calls, retries, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	unavailable := apierrors.NewServiceUnavailable("starting")
+
+	var calls int
+	opts := RetryOptions{
+		Retries: 5,
+		Backoff: time.Hour,
+		OnRetry: func(int, error, time.Duration) { cancel() },
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- Retry(ctx, opts, func() error {
+			calls++
+			return unavailable
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != unavailable {
+			t.Errorf("Retry() error = %v, want the last error %v", err, unavailable)
+		}
+		if calls != 1 {
+			t.Errorf("Retry() made %d calls after the context was canceled, want 1", calls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Retry() kept waiting after the context was canceled")
+	}
+}
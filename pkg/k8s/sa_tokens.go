@@ -0,0 +1,209 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// internalLoadBalancerAnnotations mark in-tree LoadBalancer Services that are only
+// reachable from inside the VPC
+var internalLoadBalancerAnnotations = map[string]string{
+	"service.beta.kubernetes.io/aws-load-balancer-scheme":   "internal",
+	"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+}
+
+const (
+	// albSchemeAnnotation sets the scheme of the ALB the AWS Load Balancer Controller
+	// creates for an Ingress, internal by default
+	albSchemeAnnotation = "alb.ingress.kubernetes.io/scheme"
+	// nlbSchemeAnnotation sets the scheme of a Service load balancer, internal by default
+	// for NLBs the AWS Load Balancer Controller creates
+	nlbSchemeAnnotation = "service.beta.kubernetes.io/aws-load-balancer-scheme"
+	// internetFacing is the scheme of a load balancer reachable from the internet
+	internetFacing = "internet-facing"
+)
+
+// DefaultTokenNamespace counts the pods of a namespace that run as the default service
+// account with its token auto-mounted
+type DefaultTokenNamespace struct {
+	Namespace string
+	Pods      int
+	// Exposed lists the pods behind a LoadBalancer Service or an Ingress, as
+	// "pod (via service/name)"
+	Exposed []string
+}
+
+// checkDefaultServiceAccountTokens finds running pods that use the default service account
+// without disabling automountServiceAccountToken, on the pod or the service account. Most
+// workloads never call the API server, so the mounted token only helps an attacker who
+// gets into the pod, above all in pods reachable through a load balancer.
+func (k *KubeClient) checkDefaultServiceAccountTokens(ctx context.Context, namespaces []string, status *ClusterHealthStatus) error {
+	for _, namespace := range namespaces {
+		serviceAccounts, err := k.Clientset.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: "metadata.name=default",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list service accounts: %w", err)
+		}
+		automountDisabled := make(map[string]bool)
+		for _, sa := range serviceAccounts.Items {
+			if sa.Name == "default" && sa.AutomountServiceAccountToken != nil && !*sa.AutomountServiceAccountToken {
+				automountDisabled[sa.Namespace] = true
+			}
+		}
+
+		pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+		exposure, err := k.loadBalancerExposure(ctx, namespace)
+		if err != nil {
+			return err
+		}
+
+		byNamespace := make(map[string]*DefaultTokenNamespace)
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if systemNamespaces[pod.Namespace] || pod.Labels[DiagnosticPodLabel] == DiagnosticPodLabelValue {
+				continue
+			}
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			if !mountsDefaultToken(pod, automountDisabled[pod.Namespace]) {
+				continue
+			}
+
+			ns, ok := byNamespace[pod.Namespace]
+			if !ok {
+				ns = &DefaultTokenNamespace{Namespace: pod.Namespace}
+				byNamespace[pod.Namespace] = ns
+			}
+			ns.Pods++
+			if via := exposure(pod); via != "" {
+				ns.Exposed = append(ns.Exposed, fmt.Sprintf("%s (via %s)", pod.Name, via))
+			}
+		}
+		for _, ns := range byNamespace {
+			status.DefaultTokenNamespaces = append(status.DefaultTokenNamespaces, *ns)
+		}
+	}
+
+	sort.Slice(status.DefaultTokenNamespaces, func(i, j int) bool {
+		return status.DefaultTokenNamespaces[i].Namespace < status.DefaultTokenNamespaces[j].Namespace
+	})
+	return nil
+}
+
+// mountsDefaultToken returns true if the pod runs as the default service account and gets
+// its token mounted. The pod's automountServiceAccountToken overrides the service account's.
+func mountsDefaultToken(pod *corev1.Pod, saAutomountDisabled bool) bool {
+	if pod.Spec.ServiceAccountName != "" && pod.Spec.ServiceAccountName != "default" {
+		return false
+	}
+	if pod.Spec.AutomountServiceAccountToken != nil {
+		return *pod.Spec.AutomountServiceAccountToken
+	}
+	return !saAutomountDisabled
+}
+
+// loadBalancerExposure returns a function naming the internet-facing LoadBalancer Service
+// or Ingress a pod of the namespace is reachable through, or "" if there is none
+func (k *KubeClient) loadBalancerExposure(ctx context.Context, namespace string) (func(*corev1.Pod) string, error) {
+	services, err := k.Clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	ingresses, err := k.Clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+
+	// Services exposed by an Ingress, keyed by namespace/name, with the Ingress
+	ingressBackends := make(map[string]string)
+	for _, ing := range ingresses.Items {
+		if ing.Annotations[albSchemeAnnotation] != internetFacing {
+			continue
+		}
+		var backends []string
+		if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil {
+			backends = append(backends, ing.Spec.DefaultBackend.Service.Name)
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil {
+					backends = append(backends, path.Backend.Service.Name)
+				}
+			}
+		}
+		for _, backend := range backends {
+			ingressBackends[ing.Namespace+"/"+backend] = "ingress/" + ing.Name
+		}
+	}
+
+	type exposedService struct {
+		namespace string
+		selector  labels.Selector
+		via       string
+	}
+	var exposed []exposedService
+	for _, svc := range services.Items {
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		via := ingressBackends[svc.Namespace+"/"+svc.Name]
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && internetFacingService(&svc) {
+			via = "service/" + svc.Name
+		}
+		if via == "" {
+			continue
+		}
+		exposed = append(exposed, exposedService{
+			namespace: svc.Namespace,
+			selector:  labels.SelectorFromSet(svc.Spec.Selector),
+			via:       via,
+		})
+	}
+
+	return func(pod *corev1.Pod) string {
+		for _, svc := range exposed {
+			if svc.namespace == pod.Namespace && svc.selector.Matches(labels.Set(pod.Labels)) {
+				return svc.via
+			}
+		}
+		return ""
+	}, nil
+}
+
+// internetFacingService returns true if the load balancer of a LoadBalancer Service is
+// reachable from the internet. NLBs the AWS Load Balancer Controller manages are internal
+// unless their scheme is internet-facing; the in-tree controller's load balancers are
+// internet-facing unless annotated as internal.
+func internetFacingService(svc *corev1.Service) bool {
+	if controllerManagedNLB(svc) {
+		return svc.Annotations[nlbSchemeAnnotation] == internetFacing
+	}
+	for key, value := range internalLoadBalancerAnnotations {
+		if svc.Annotations[key] == value {
+			return false
+		}
+	}
+	return true
+}
+
+// controllerManagedNLB returns true if the AWS Load Balancer Controller, not the in-tree
+// cloud provider, creates the load balancer of a Service
+func controllerManagedNLB(svc *corev1.Service) bool {
+	if svc.Spec.LoadBalancerClass != nil && *svc.Spec.LoadBalancerClass == "service.k8s.aws/nlb" {
+		return true
+	}
+	return svc.Annotations["service.beta.kubernetes.io/aws-load-balancer-type"] == "external"
+}
@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckDefaultServiceAccountTokens(t *testing.T) {
+	disabled := false
+	nlbClass := "service.k8s.aws/nlb"
+	pod := func(namespace, name, serviceAccount string, automount *bool) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": name}},
+			Spec: corev1.PodSpec{
+				ServiceAccountName:           serviceAccount,
+				AutomountServiceAccountToken: automount,
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	service := func(namespace, name string, serviceType corev1.ServiceType, annotations map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations},
+			Spec:       corev1.ServiceSpec{Type: serviceType, Selector: map[string]string{"app": name}},
+		}
+	}
+
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "locked"}, AutomountServiceAccountToken: &disabled},
+		pod("shop", "web", "", nil),
+		pod("shop", "api", "default", nil),
+		pod("shop", "worker", "default", nil),
+		pod("shop", "internal", "", nil),
+		pod("shop", "nlb-private", "", nil),
+		pod("shop", "nlb-public", "", nil),
+		pod("shop", "nlb-class", "", nil),
+		pod("shop", "opted-out", "", &disabled),
+		pod("shop", "operator", "operator", nil),
+		pod("locked", "app", "", nil),
+		pod("kube-system", "aws-node", "", nil),
+		service("shop", "web", corev1.ServiceTypeLoadBalancer, nil),
+		service("shop", "api", corev1.ServiceTypeClusterIP, nil),
+		service("shop", "worker", corev1.ServiceTypeClusterIP, nil),
+		service("shop", "internal", corev1.ServiceTypeLoadBalancer, map[string]string{"service.beta.kubernetes.io/aws-load-balancer-scheme": "internal"}),
+		service("shop", "nlb-private", corev1.ServiceTypeLoadBalancer, map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "external"}),
+		service("shop", "nlb-public", corev1.ServiceTypeLoadBalancer, map[string]string{
+			"service.beta.kubernetes.io/aws-load-balancer-type":   "external",
+			"service.beta.kubernetes.io/aws-load-balancer-scheme": "internet-facing",
+		}),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "nlb-class", Namespace: "shop"},
+			Spec: corev1.ServiceSpec{
+				Type:              corev1.ServiceTypeLoadBalancer,
+				LoadBalancerClass: &nlbClass,
+				Selector:          map[string]string{"app": "nlb-class"},
+			},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop", Annotations: map[string]string{"alb.ingress.kubernetes.io/scheme": "internet-facing"}},
+			Spec: networkingv1.IngressSpec{DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "api"},
+			}},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "jobs", Namespace: "shop"},
+			Spec: networkingv1.IngressSpec{DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "worker"},
+			}},
+		},
+	)}
+
+	status := &ClusterHealthStatus{}
+	if err := client.checkDefaultServiceAccountTokens(context.Background(), []string{metav1.NamespaceAll}, status); err != nil {
+		t.Fatalf("checkDefaultServiceAccountTokens() error = %v", err)
+	}
+
+	if len(status.DefaultTokenNamespaces) != 1 {
+		t.Fatalf("DefaultTokenNamespaces = %+v, want shop only", status.DefaultTokenNamespaces)
+	}
+	shop := status.DefaultTokenNamespaces[0]
+	if shop.Namespace != "shop" || shop.Pods != 7 {
+		t.Errorf("shop = %+v, want 7 pods", shop)
+	}
+	want := map[string]bool{
+		"web (via service/web)":               true,
+		"api (via ingress/shop)":              true,
+		"nlb-public (via service/nlb-public)": true,
+	}
+	if len(shop.Exposed) != len(want) {
+		t.Fatalf("Exposed = %v, want web, api and nlb-public", shop.Exposed)
+	}
+	for _, exposed := range shop.Exposed {
+		if !want[exposed] {
+			t.Errorf("unexpected exposed pod %q", exposed)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var nodePoolGVR = schema.GroupVersionResource{Group: "karpenter.sh", Version: "v1", Resource: "nodepools"}
+
+const (
+	// clusterAutoscalerLabels select the Cluster Autoscaler pods of the upstream manifests
+	// and of the Helm chart
+	clusterAutoscalerLabels     = "app=cluster-autoscaler"
+	clusterAutoscalerHelmLabels = "app.kubernetes.io/name=aws-cluster-autoscaler"
+	// maxScalingLogLines bounds the controller log lines kept per pending pod
+	maxScalingLogLines = 3
+)
+
+// scalingEventReasons are the pod events the autoscalers record about scale-up decisions
+var scalingEventReasons = map[string]bool{
+	"NotTriggerScaleUp": true, // Cluster Autoscaler
+	"TriggeredScaleUp":  true, // Cluster Autoscaler
+	"Nominated":         true, // Karpenter
+}
+
+// wellKnownNodeLabels are set on every node from the instance it runs on, so a node group
+// or NodePool that does not pin them can still satisfy a pod selecting them
+var wellKnownNodeLabels = map[string]bool{
+	corev1.LabelArchStable:         true,
+	corev1.LabelOSStable:           true,
+	corev1.LabelInstanceTypeStable: true,
+	corev1.LabelTopologyZone:       true,
+	corev1.LabelTopologyRegion:     true,
+}
+
+// ScalingPod is a pending pod with its resource requests, placement constraints and what
+// the autoscalers reported about it
+type ScalingPod struct {
+	Namespace        string
+	Name             string
+	CPU              int64 // Millicores requested
+	Memory           int64 // Bytes requested
+	GPU              int64
+	NodeSelector     map[string]string
+	Tolerations      []corev1.Toleration
+	SchedulerMessage string
+	// Events are the autoscaler events on the pod as "Reason: message"
+	Events []string
+	// LogLines are the latest autoscaler controller log lines naming the pod
+	LogLines []string
+}
+
+// KarpenterNodePool is the part of a Karpenter v1 NodePool that decides which pods it can
+// provision nodes for
+type KarpenterNodePool struct {
+	Name         string
+	Labels       map[string]string
+	Requirements []corev1.NodeSelectorRequirement
+	Taints       []corev1.Taint
+}
+
+// ScalingStatus ties pending pods to the autoscaler that should add nodes for them
+type ScalingStatus struct {
+	// ClusterAutoscaler and Karpenter are the namespace/name of a running controller pod,
+	// empty when the autoscaler is not installed
+	ClusterAutoscaler string
+	Karpenter         string
+	Pods              []ScalingPod
+	NodePools         []KarpenterNodePool
+	LogError          string // Set when controller logs could not be read
+}
+
+// GetScalingStatus lists the pending pods that are not bound to a node, the autoscaler
+// events about them and the Cluster Autoscaler or Karpenter log lines naming them, along
+// with the Karpenter NodePools
+func (k *KubeClient) GetScalingStatus(ctx context.Context) (*ScalingStatus, error) {
+	status := &ScalingStatus{}
+
+	var caPods []corev1.Pod
+	for _, selector := range []string{clusterAutoscalerLabels, clusterAutoscalerHelmLabels} {
+		pods, err := k.Clientset.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cluster-autoscaler pods: %w", err)
+		}
+		caPods = append(caPods, runningPods(pods.Items)...)
+	}
+	karpenterPods, err := k.Clientset.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: karpenterControllerLabels})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list karpenter pods: %w", err)
+	}
+	controllers := runningPods(karpenterPods.Items)
+	if len(caPods) > 0 {
+		status.ClusterAutoscaler = caPods[0].Namespace + "/" + caPods[0].Name
+	}
+	if len(controllers) > 0 {
+		status.Karpenter = controllers[0].Namespace + "/" + controllers[0].Name
+	}
+
+	pods, err := k.Clientset.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase=Pending,spec.nodeName=",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending pods: %w", err)
+	}
+	// Index of each pod in status.Pods by namespace/name
+	byKey := make(map[string]int)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+			continue
+		}
+		p := ScalingPod{
+			Namespace:    pod.Namespace,
+			Name:         pod.Name,
+			CPU:          podResourceTotal(pod.Spec, corev1.ResourceCPU, false),
+			Memory:       podResourceTotal(pod.Spec, corev1.ResourceMemory, false),
+			GPU:          podResourceTotal(pod.Spec, ResourceNvidiaGPU, true),
+			NodeSelector: pod.Spec.NodeSelector,
+			Tolerations:  pod.Spec.Tolerations,
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+				p.SchedulerMessage = cond.Message
+			}
+		}
+		byKey[pod.Namespace+"/"+pod.Name] = len(status.Pods)
+		status.Pods = append(status.Pods, p)
+	}
+	if len(status.Pods) == 0 {
+		return status, nil
+	}
+
+	reasons := []string{"FailedScheduling"}
+	for reason := range scalingEventReasons {
+		reasons = append(reasons, reason)
+	}
+	events, err := k.ListEvents(ctx, EventOptions{Reasons: reasons})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if e.InvolvedObject.Kind != "Pod" {
+			continue
+		}
+		if e.Reason == "FailedScheduling" && e.Source.Component != "karpenter" {
+			continue
+		}
+		if i, ok := byKey[e.InvolvedObject.Namespace+"/"+e.InvolvedObject.Name]; ok {
+			status.Pods[i].Events = append(status.Pods[i].Events, fmt.Sprintf("%s: %s", e.Reason, e.Message))
+		}
+	}
+
+	refs := make(map[string]int)
+	var matchers []string
+	for i, p := range status.Pods {
+		for _, ref := range podLogRefs(p.Namespace, p.Name) {
+			refs[ref] = i
+			matchers = append(matchers, ref)
+		}
+	}
+	scan := func(pod corev1.Pod, container string) {
+		matches, err := k.ScanPodLogs(ctx, pod.Namespace, pod.Name, container, matchers)
+		if err != nil {
+			status.LogError = err.Error()
+			return
+		}
+		for _, m := range matches {
+			// A line may name several pods, and a matcher also hits pods whose name it
+			// prefixes, so check every reference for a whole-name match
+			named := make(map[int]bool)
+			for _, ref := range matchers {
+				if i := refs[ref]; !named[i] && mentionsRef(m.Line, ref) {
+					named[i] = true
+					lines := append(status.Pods[i].LogLines, m.Line)
+					if len(lines) > maxScalingLogLines {
+						lines = lines[1:]
+					}
+					status.Pods[i].LogLines = lines
+				}
+			}
+		}
+	}
+	if len(caPods) > 0 {
+		scan(caPods[0], "")
+	}
+	if len(controllers) > 0 {
+		scan(controllers[0], "controller")
+	}
+
+	status.NodePools, err = k.listKarpenterNodePools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// podLogRefs are the ways controller logs name a pod: namespace/name in Cluster Autoscaler
+// and Karpenter messages, and the object reference Karpenter logs as JSON
+func podLogRefs(namespace, name string) []string {
+	return []string{
+		namespace + "/" + name,
+		fmt.Sprintf(`{"name":%q,"namespace":%q}`, name, namespace),
+	}
+}
+
+// mentionsRef returns true if the line contains ref as a whole, not as part of a longer
+// namespace or pod name
+func mentionsRef(line, ref string) bool {
+	for start := 0; ; start++ {
+		i := strings.Index(line[start:], ref)
+		if i < 0 {
+			return false
+		}
+		start += i
+		end := start + len(ref)
+		if (start == 0 || !isNameChar(line[start-1])) && (end == len(line) || !isNameChar(line[end])) {
+			return true
+		}
+	}
+}
+
+// isNameChar returns true for the characters of Kubernetes object names
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '.'
+}
+
+// runningPods returns the pods in the Running phase
+func runningPods(pods []corev1.Pod) []corev1.Pod {
+	var running []corev1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			running = append(running, pod)
+		}
+	}
+	return running
+}
+
+// listKarpenterNodePools lists the Karpenter v1 NodePools. It returns none when the
+// NodePool CRD is not installed.
+func (k *KubeClient) listKarpenterNodePools(ctx context.Context) ([]KarpenterNodePool, error) {
+	if k.Config == nil {
+		return nil, nil
+	}
+	dynamicClient, err := dynamic.NewForConfig(k.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	list, err := dynamicClient.Resource(nodePoolGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list nodepools: %w", err)
+	}
+
+	var pools []KarpenterNodePool
+	for _, obj := range list.Items {
+		var spec struct {
+			Template struct {
+				Metadata struct {
+					Labels map[string]string `json:"labels"`
+				} `json:"metadata"`
+				Spec struct {
+					Requirements []corev1.NodeSelectorRequirement `json:"requirements"`
+					Taints       []corev1.Taint                   `json:"taints"`
+				} `json:"spec"`
+			} `json:"template"`
+		}
+		raw, _ := obj.Object["spec"].(map[string]interface{})
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &spec); err != nil {
+			return nil, fmt.Errorf("failed to parse nodepool %s: %w", obj.GetName(), err)
+		}
+		pools = append(pools, KarpenterNodePool{
+			Name:         obj.GetName(),
+			Labels:       spec.Template.Metadata.Labels,
+			Requirements: spec.Template.Spec.Requirements,
+			Taints:       spec.Template.Spec.Taints,
+		})
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	return pools, nil
+}
+
+// MatchNodeGroup returns true if nodes with the given labels and taints would accept the
+// pod, judged by its nodeSelector and tolerations. Otherwise it returns the reason.
+func (p ScalingPod) MatchNodeGroup(labels map[string]string, taints []corev1.Taint) (bool, string) {
+	return p.matchNodes(labels, nil, taints, func(key string) bool { return wellKnownNodeLabels[key] })
+}
+
+// MatchNodePool returns true if the Karpenter NodePool can launch a node for the pod,
+// judged by its nodeSelector and tolerations. Otherwise it returns the reason.
+func (p ScalingPod) MatchNodePool(pool KarpenterNodePool) (bool, string) {
+	labels := map[string]string{karpenterNodePoolLabel: pool.Name}
+	for key, value := range pool.Labels {
+		labels[key] = value
+	}
+	// Karpenter also sets the capacity type and the instance details it picked
+	wellKnown := func(key string) bool {
+		return wellKnownNodeLabels[key] || key == "karpenter.sh/capacity-type" || strings.HasPrefix(key, "karpenter.k8s.aws/")
+	}
+	return p.matchNodes(labels, pool.Requirements, pool.Taints, wellKnown)
+}
+
+// matchNodes checks the pod's tolerations against the taints and its nodeSelector against
+// the labels and requirements of a node template. Selected labels the template leaves
+// open only match if wellKnown reports that every node carries them.
+func (p ScalingPod) matchNodes(labels map[string]string, requirements []corev1.NodeSelectorRequirement, taints []corev1.Taint, wellKnown func(string) bool) (bool, string) {
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range p.Tolerations {
+			if toleration.ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false, fmt.Sprintf("taint %s is not tolerated", taint.ToString())
+		}
+	}
+
+	keys := make([]string, 0, len(p.NodeSelector))
+	for key := range p.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		want := p.NodeSelector[key]
+		if value, ok := labels[key]; ok {
+			if value != want {
+				return false, fmt.Sprintf("sets %s=%s, the pod selects %s", key, value, want)
+			}
+			continue
+		}
+		if req, ok := findRequirement(requirements, key); ok {
+			if !requirementAllows(req, want) {
+				return false, fmt.Sprintf("requires %s %s [%s], the pod selects %s", key, req.Operator, strings.Join(req.Values, ", "), want)
+			}
+			continue
+		}
+		if !wellKnown(key) {
+			return false, fmt.Sprintf("does not set label %s", key)
+		}
+	}
+	return true, ""
+}
+
+// findRequirement returns the NodePool requirement on a label
+func findRequirement(requirements []corev1.NodeSelectorRequirement, key string) (corev1.NodeSelectorRequirement, bool) {
+	for _, req := range requirements {
+		if req.Key == key {
+			return req, true
+		}
+	}
+	return corev1.NodeSelectorRequirement{}, false
+}
+
+// requirementAllows returns true if a node satisfying the requirement can carry the label
+// value. Gt and Lt are not evaluated.
+func requirementAllows(req corev1.NodeSelectorRequirement, value string) bool {
+	contains := false
+	for _, v := range req.Values {
+		if v == value {
+			contains = true
+		}
+	}
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return contains
+	case corev1.NodeSelectorOpNotIn:
+		return !contains
+	case corev1.NodeSelectorOpDoesNotExist:
+		return false
+	}
+	return true
+}
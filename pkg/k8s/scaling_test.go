@@ -0,0 +1,228 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMatchNodeGroup(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	labels := map[string]string{"team": "ml", "eks.amazonaws.com/nodegroup": "gpu"}
+
+	tests := []struct {
+		name   string
+		pod    ScalingPod
+		taints []corev1.Taint
+		want   bool
+		reason string
+	}{
+		{
+			name: "no constraints",
+			pod:  ScalingPod{},
+			want: true,
+		},
+		{
+			name: "matching selector and toleration",
+			pod: ScalingPod{
+				NodeSelector: map[string]string{"team": "ml"},
+				Tolerations:  []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+			},
+			taints: []corev1.Taint{gpuTaint},
+			want:   true,
+		},
+		{
+			name:   "untolerated taint",
+			pod:    ScalingPod{},
+			taints: []corev1.Taint{gpuTaint},
+			reason: "taint nvidia.com/gpu=true:NoSchedule is not tolerated",
+		},
+		{
+			name:   "prefer no schedule taint",
+			pod:    ScalingPod{},
+			taints: []corev1.Taint{{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}},
+			want:   true,
+		},
+		{
+			name:   "label value mismatch",
+			pod:    ScalingPod{NodeSelector: map[string]string{"team": "web"}},
+			reason: "sets team=ml, the pod selects web",
+		},
+		{
+			name:   "missing label",
+			pod:    ScalingPod{NodeSelector: map[string]string{"disk": "ssd"}},
+			reason: "does not set label disk",
+		},
+		{
+			name: "well-known label",
+			pod:  ScalingPod{NodeSelector: map[string]string{corev1.LabelArchStable: "arm64"}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := tt.pod.MatchNodeGroup(labels, tt.taints)
+			if got != tt.want || reason != tt.reason {
+				t.Errorf("MatchNodeGroup() = %v, %q, want %v, %q", got, reason, tt.want, tt.reason)
+			}
+		})
+	}
+}
+
+func TestMatchNodePool(t *testing.T) {
+	pool := KarpenterNodePool{
+		Name:   "default",
+		Labels: map[string]string{"team": "web"},
+		Requirements: []corev1.NodeSelectorRequirement{
+			{Key: "karpenter.sh/capacity-type", Operator: corev1.NodeSelectorOpIn, Values: []string{"spot"}},
+			{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"us-east-1c"}},
+		},
+		Taints: []corev1.Taint{{Key: "dedicated", Value: "web", Effect: corev1.TaintEffectNoSchedule}},
+	}
+	tolerate := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "web", Effect: corev1.TaintEffectNoSchedule}}
+
+	tests := []struct {
+		name     string
+		selector map[string]string
+		want     bool
+		reason   string
+	}{
+		{
+			name:     "nodepool label",
+			selector: map[string]string{karpenterNodePoolLabel: "default", "team": "web"},
+			want:     true,
+		},
+		{
+			name:     "other nodepool",
+			selector: map[string]string{karpenterNodePoolLabel: "gpu"},
+			reason:   "sets " + karpenterNodePoolLabel + "=default, the pod selects gpu",
+		},
+		{
+			name:     "allowed by requirement",
+			selector: map[string]string{"karpenter.sh/capacity-type": "spot", corev1.LabelTopologyZone: "us-east-1a"},
+			want:     true,
+		},
+		{
+			name:     "excluded by In requirement",
+			selector: map[string]string{"karpenter.sh/capacity-type": "on-demand"},
+			reason:   "requires karpenter.sh/capacity-type In [spot], the pod selects on-demand",
+		},
+		{
+			name:     "excluded by NotIn requirement",
+			selector: map[string]string{corev1.LabelTopologyZone: "us-east-1c"},
+			reason:   "requires " + corev1.LabelTopologyZone + " NotIn [us-east-1c], the pod selects us-east-1c",
+		},
+		{
+			name:     "label karpenter sets",
+			selector: map[string]string{"karpenter.k8s.aws/instance-family": "m7g"},
+			want:     true,
+		},
+		{
+			name:     "label nobody sets",
+			selector: map[string]string{"disk": "ssd"},
+			reason:   "does not set label disk",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := ScalingPod{NodeSelector: tt.selector, Tolerations: tolerate}
+			got, reason := pod.MatchNodePool(pool)
+			if got != tt.want || reason != tt.reason {
+				t.Errorf("MatchNodePool() = %v, %q, want %v, %q", got, reason, tt.want, tt.reason)
+			}
+		})
+	}
+
+	if ok, reason := (ScalingPod{}).MatchNodePool(pool); ok || !strings.Contains(reason, "dedicated=web:NoSchedule") {
+		t.Errorf("MatchNodePool() without toleration = %v, %q, want the taint reported", ok, reason)
+	}
+}
+
+func TestRequirementAllows(t *testing.T) {
+	tests := []struct {
+		operator corev1.NodeSelectorOperator
+		values   []string
+		value    string
+		want     bool
+	}{
+		{corev1.NodeSelectorOpIn, []string{"a", "b"}, "b", true},
+		{corev1.NodeSelectorOpIn, []string{"a", "b"}, "c", false},
+		{corev1.NodeSelectorOpNotIn, []string{"a", "b"}, "c", true},
+		{corev1.NodeSelectorOpNotIn, []string{"a", "b"}, "a", false},
+		{corev1.NodeSelectorOpExists, nil, "a", true},
+		{corev1.NodeSelectorOpDoesNotExist, nil, "a", false},
+		{corev1.NodeSelectorOpGt, []string{"4"}, "2", true},
+	}
+	for _, tt := range tests {
+		req := corev1.NodeSelectorRequirement{Key: "k", Operator: tt.operator, Values: tt.values}
+		if got := requirementAllows(req, tt.value); got != tt.want {
+			t.Errorf("requirementAllows(%s %v, %q) = %v, want %v", tt.operator, tt.values, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMentionsRef(t *testing.T) {
+	tests := []struct {
+		line string
+		ref  string
+		want bool
+	}{
+		{"Pod shop/web is unschedulable", "shop/web", true},
+		{"Pod shop/web-2 is unschedulable", "shop/web", false},
+		{"Pod shop/web-2 is unschedulable, Pod shop/web is too", "shop/web", true},
+		{"Pod workshop/web is unschedulable", "shop/web", false},
+		{`{"message":"could not schedule pod","Pod":{"name":"web","namespace":"shop"}}`, `{"name":"web","namespace":"shop"}`, true},
+		{"Pod web is unschedulable", "shop/web", false},
+	}
+	for _, tt := range tests {
+		if got := mentionsRef(tt.line, tt.ref); got != tt.want {
+			t.Errorf("mentionsRef(%q, %q) = %v, want %v", tt.line, tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestGetScalingStatusEvents(t *testing.T) {
+	pending := func(namespace, name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		}
+	}
+	event := func(namespace, pod, reason, component, message string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: pod + "." + component, Namespace: namespace},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: pod},
+			Reason:         reason,
+			Source:         corev1.EventSource{Component: component},
+			Message:        message,
+		}
+	}
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		pending("shop", "web"),
+		pending("blog", "web"),
+		event("shop", "web", "NotTriggerScaleUp", "cluster-autoscaler", "pod didn't trigger scale-up"),
+		event("blog", "web", "FailedScheduling", "karpenter", "incompatible with nodepool default"),
+		event("blog", "web", "FailedScheduling", "default-scheduler", "0/3 nodes are available"),
+	)}
+
+	status, err := client.GetScalingStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetScalingStatus() error = %v", err)
+	}
+	got := make(map[string][]string)
+	for _, p := range status.Pods {
+		got[p.Namespace+"/"+p.Name] = p.Events
+	}
+	want := map[string][]string{
+		"shop/web": {"NotTriggerScaleUp: pod didn't trigger scale-up"},
+		"blog/web": {"FailedScheduling: incompatible with nodepool default"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pod events = %v, want %v", got, want)
+	}
+}
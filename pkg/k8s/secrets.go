@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SecretSizeLimit is the maximum size of a Secret accepted by the API server
+	SecretSizeLimit = 1 << 20
+
+	helmReleaseSecretType = "helm.sh/release.v1"
+	secretListPageSize    = 500
+)
+
+// SecretNamespaceStats counts the Secrets in a namespace
+type SecretNamespaceStats struct {
+	Namespace    string
+	Count        int
+	HelmReleases int // Secrets of type helm.sh/release.v1
+	TotalBytes   int
+}
+
+// LargeSecret is a Secret whose stored size approaches SecretSizeLimit
+type LargeSecret struct {
+	Namespace string
+	Name      string
+	Type      corev1.SecretType
+	Bytes     int
+}
+
+// HelmReleaseHistory is the number of revisions Helm keeps as Secrets for a release
+type HelmReleaseHistory struct {
+	Namespace string
+	Release   string
+	Revisions int
+}
+
+// SecretReport summarizes Secret counts and sizes. It holds metadata only, never Secret data.
+type SecretReport struct {
+	Total        int
+	Namespaces   []SecretNamespaceStats // sorted by count, largest first
+	LargeSecrets []LargeSecret          // sorted by size, largest first
+	HelmReleases []HelmReleaseHistory   // sorted by revisions, most first
+}
+
+// GetSecretReport counts Secrets per namespace and Helm release and lists Secrets whose size
+// is at least largeBytes. Secrets are listed in pages to bound memory on large clusters.
+func (k *KubeClient) GetSecretReport(ctx context.Context, namespace string, largeBytes int) (*SecretReport, error) {
+	report := &SecretReport{}
+	stats := make(map[string]*SecretNamespaceStats)
+	releases := make(map[string]*HelmReleaseHistory)
+
+	opts := metav1.ListOptions{Limit: secretListPageSize}
+	for {
+		secrets, err := k.Clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+
+		for i := range secrets.Items {
+			secret := &secrets.Items[i]
+			// Size is the protobuf-encoded size, which is how the object is stored in etcd
+			size := secret.Size()
+
+			ns := stats[secret.Namespace]
+			if ns == nil {
+				ns = &SecretNamespaceStats{Namespace: secret.Namespace}
+				stats[secret.Namespace] = ns
+			}
+			ns.Count++
+			ns.TotalBytes += size
+			report.Total++
+
+			if secret.Type == helmReleaseSecretType {
+				ns.HelmReleases++
+				key := secret.Namespace + "/" + secret.Labels["name"]
+				if releases[key] == nil {
+					releases[key] = &HelmReleaseHistory{Namespace: secret.Namespace, Release: secret.Labels["name"]}
+				}
+				releases[key].Revisions++
+			}
+
+			if size >= largeBytes {
+				report.LargeSecrets = append(report.LargeSecrets, LargeSecret{
+					Namespace: secret.Namespace,
+					Name:      secret.Name,
+					Type:      secret.Type,
+					Bytes:     size,
+				})
+			}
+		}
+
+		if secrets.Continue == "" {
+			break
+		}
+		opts.Continue = secrets.Continue
+	}
+
+	for _, ns := range stats {
+		report.Namespaces = append(report.Namespaces, *ns)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		if report.Namespaces[i].Count != report.Namespaces[j].Count {
+			return report.Namespaces[i].Count > report.Namespaces[j].Count
+		}
+		return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace
+	})
+
+	sort.Slice(report.LargeSecrets, func(i, j int) bool {
+		return report.LargeSecrets[i].Bytes > report.LargeSecrets[j].Bytes
+	})
+
+	for _, r := range releases {
+		report.HelmReleases = append(report.HelmReleases, *r)
+	}
+	sort.Slice(report.HelmReleases, func(i, j int) bool {
+		if report.HelmReleases[i].Revisions != report.HelmReleases[j].Revisions {
+			return report.HelmReleases[i].Revisions > report.HelmReleases[j].Revisions
+		}
+		return report.HelmReleases[i].Namespace+"/"+report.HelmReleases[i].Release <
+			report.HelmReleases[j].Namespace+"/"+report.HelmReleases[j].Release
+	})
+
+	return report, nil
+}
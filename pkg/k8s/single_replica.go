@@ -0,0 +1,168 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var (
+	// DefaultCriticalNamespaces hold cluster infrastructure whose outage affects every
+	// workload: ingress, certificates, DNS, autoscaling, admission and secrets
+	DefaultCriticalNamespaces = []string{
+		metav1.NamespaceSystem,
+		"ingress-nginx",
+		"cert-manager",
+		"karpenter",
+		"istio-system",
+		"external-dns",
+		"external-secrets",
+		"kyverno",
+		"gatekeeper-system",
+	}
+	// DefaultCriticalLabels are label selectors marking controllers and webhooks in any
+	// namespace as cluster infrastructure
+	DefaultCriticalLabels = []string{
+		"app.kubernetes.io/component=controller",
+		"app.kubernetes.io/component=webhook",
+		"k8s-app=kube-dns",
+	}
+)
+
+// SingleReplicaWorkload is a cluster infrastructure Deployment or StatefulSet running a
+// single replica without a PodDisruptionBudget, so a node drain or crash takes it down
+type SingleReplicaWorkload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Reason is why the workload is considered infrastructure, e.g. "namespace kube-system"
+	Reason string
+}
+
+// criticalWorkloadRules decide which workloads count as cluster infrastructure
+type criticalWorkloadRules struct {
+	namespaces map[string]bool
+	selectors  []labels.Selector
+	labels     []string
+}
+
+// newCriticalWorkloadRules parses the namespace and label selector lists, falling back
+// to the defaults for an empty list
+func newCriticalWorkloadRules(namespaces, selectors []string) (*criticalWorkloadRules, error) {
+	if len(namespaces) == 0 {
+		namespaces = DefaultCriticalNamespaces
+	}
+	if len(selectors) == 0 {
+		selectors = DefaultCriticalLabels
+	}
+
+	rules := &criticalWorkloadRules{namespaces: make(map[string]bool), labels: selectors}
+	for _, ns := range namespaces {
+		rules.namespaces[ns] = true
+	}
+	for _, s := range selectors {
+		selector, err := labels.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid critical workload label selector %q: %w", s, err)
+		}
+		rules.selectors = append(rules.selectors, selector)
+	}
+	return rules, nil
+}
+
+// match returns why a workload with the namespace and labels is critical, or "" if it is not
+func (r *criticalWorkloadRules) match(namespace string, workloadLabels map[string]string) string {
+	if r.namespaces[namespace] {
+		return "namespace " + namespace
+	}
+	for i, selector := range r.selectors {
+		if selector.Matches(labels.Set(workloadLabels)) {
+			return "label " + r.labels[i]
+		}
+	}
+	return ""
+}
+
+// checkSingleReplicaWorkloads finds infrastructure Deployments and StatefulSets with one
+// replica that no PodDisruptionBudget covers. Workloads scaled to zero are ignored.
+func (k *KubeClient) checkSingleReplicaWorkloads(ctx context.Context, namespaces []string, status *ClusterHealthStatus) error {
+	rules := status.criticalWorkloads
+	if rules == nil {
+		var err error
+		if rules, err = newCriticalWorkloadRules(nil, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, namespace := range namespaces {
+		pdbs, err := k.Clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		check := func(kind, ns, name string, replicas *int32, workloadLabels, podLabels map[string]string) {
+			if replicas != nil && *replicas != 1 {
+				return
+			}
+			reason := rules.match(ns, workloadLabels)
+			if reason == "" {
+				reason = rules.match(ns, podLabels)
+			}
+			if reason == "" || coveredByPDB(pdbs.Items, ns, podLabels) {
+				return
+			}
+			status.SingleReplicaWorkloads = append(status.SingleReplicaWorkloads, SingleReplicaWorkload{
+				Kind:      kind,
+				Namespace: ns,
+				Name:      name,
+				Reason:    reason,
+			})
+		}
+
+		deployments, err := k.Clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, d := range deployments.Items {
+			check("Deployment", d.Namespace, d.Name, d.Spec.Replicas, d.Labels, d.Spec.Template.Labels)
+		}
+
+		statefulSets, err := k.Clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, sts := range statefulSets.Items {
+			check("StatefulSet", sts.Namespace, sts.Name, sts.Spec.Replicas, sts.Labels, sts.Spec.Template.Labels)
+		}
+	}
+
+	sort.Slice(status.SingleReplicaWorkloads, func(i, j int) bool {
+		a, b := status.SingleReplicaWorkloads[i], status.SingleReplicaWorkloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return nil
+}
+
+// coveredByPDB returns true if a PodDisruptionBudget in the namespace selects the pod
+// labels. In policy/v1 an empty selector selects every pod in the namespace.
+func coveredByPDB(pdbs []policyv1.PodDisruptionBudget, namespace string, podLabels map[string]string) bool {
+	for _, pdb := range pdbs {
+		if pdb.Namespace != namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(podLabels)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckSingleReplicaWorkloads(t *testing.T) {
+	replicas := func(n int32) *int32 { return &n }
+	deployment := func(namespace, name string, n int32, workloadLabels map[string]string) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: workloadLabels},
+			Spec:       appsv1.DeploymentSpec{Replicas: replicas(n)},
+		}
+		d.Spec.Template.Labels = map[string]string{"app": name}
+		return d
+	}
+
+	client := &KubeClient{Clientset: fake.NewSimpleClientset(
+		// Covered by a PodDisruptionBudget
+		deployment("kube-system", "coredns", 1, nil),
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "coredns"}}},
+		},
+		// One replica in a critical namespace
+		deployment("kube-system", "metrics-server", 1, nil),
+		// One replica with a matching label outside the critical namespaces
+		deployment("shop", "ingress-controller", 1, map[string]string{"app.kubernetes.io/component": "controller"}),
+		// Not infrastructure
+		deployment("shop", "web", 1, nil),
+		// Several replicas
+		deployment("shop", "admission", 3, map[string]string{"app.kubernetes.io/component": "webhook"}),
+		// Scaled to zero
+		deployment("kube-system", "paused", 0, nil),
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "kube-system"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: replicas(1)},
+		},
+	)}
+
+	status := &ClusterHealthStatus{}
+	if err := client.checkSingleReplicaWorkloads(context.Background(), []string{metav1.NamespaceAll}, status); err != nil {
+		t.Fatalf("checkSingleReplicaWorkloads() error = %v", err)
+	}
+
+	want := []SingleReplicaWorkload{
+		{Kind: "Deployment", Namespace: "kube-system", Name: "metrics-server", Reason: "namespace kube-system"},
+		{Kind: "StatefulSet", Namespace: "kube-system", Name: "vault", Reason: "namespace kube-system"},
+		{Kind: "Deployment", Namespace: "shop", Name: "ingress-controller", Reason: "label app.kubernetes.io/component=controller"},
+	}
+	if !reflect.DeepEqual(status.SingleReplicaWorkloads, want) {
+		t.Errorf("SingleReplicaWorkloads = %+v, want %+v", status.SingleReplicaWorkloads, want)
+	}
+}
@@ -0,0 +1,180 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	volumeSnapshotGVR      = schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshots"}
+	volumeSnapshotClassGVR = schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshotclasses"}
+)
+
+// snapshotDrivers are the CSI drivers on EKS that support volume snapshots
+var snapshotDrivers = map[string]bool{"ebs.csi.aws.com": true}
+
+// defaultSnapshotClassAnnotation marks the VolumeSnapshotClass used when a snapshot names none
+const defaultSnapshotClassAnnotation = "snapshot.storage.kubernetes.io/is-default-class"
+
+// SnapshotController is a deployment of the CSI external-snapshotter controller
+type SnapshotController struct {
+	Namespace string
+	Name      string
+	Ready     int32
+	Desired   int32
+}
+
+// VolumeSnapshotClass is a snapshot.storage.k8s.io VolumeSnapshotClass
+type VolumeSnapshotClass struct {
+	Name           string
+	Driver         string
+	DeletionPolicy string
+	Default        bool
+}
+
+// VolumeSnapshot is a snapshot.storage.k8s.io VolumeSnapshot
+type VolumeSnapshot struct {
+	Namespace  string
+	Name       string
+	Class      string // Empty when the default class is used
+	SourcePVC  string
+	ReadyToUse bool
+	Created    time.Time
+	Error      string // status.error.message, set when snapshotting failed
+}
+
+// SnapshotStatus describes the volume snapshot setup of a cluster
+type SnapshotStatus struct {
+	// CRDsInstalled is false when the snapshot.storage.k8s.io CRDs are missing
+	CRDsInstalled bool
+	Controllers   []SnapshotController
+	Classes       []VolumeSnapshotClass
+	Snapshots     []VolumeSnapshot
+	// DriversWithoutClass lists snapshot-capable CSI drivers used by StorageClasses that
+	// have no VolumeSnapshotClass
+	DriversWithoutClass []string
+}
+
+// Class returns the VolumeSnapshotClass with the given name, or the default class for an
+// empty name
+func (s *SnapshotStatus) Class(name string) (VolumeSnapshotClass, bool) {
+	for _, c := range s.Classes {
+		if c.Name == name || (name == "" && c.Default) {
+			return c, true
+		}
+	}
+	return VolumeSnapshotClass{}, false
+}
+
+// hasDriverClass returns true if a VolumeSnapshotClass uses the CSI driver
+func (s *SnapshotStatus) hasDriverClass(driver string) bool {
+	for _, c := range s.Classes {
+		if c.Driver == driver {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSnapshotStatus reads the snapshot-controller deployments, the VolumeSnapshotClasses
+// and the VolumeSnapshots of a namespace (all namespaces when empty). The snapshot
+// resources are custom resources read with the dynamic client.
+func (k *KubeClient) GetSnapshotStatus(ctx context.Context, namespace string) (*SnapshotStatus, error) {
+	if k.Config == nil {
+		return nil, fmt.Errorf("volume snapshots cannot be read from a dump")
+	}
+
+	status := &SnapshotStatus{}
+	deployments, err := k.Clientset.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		if !strings.Contains(d.Name, "snapshot-controller") {
+			continue
+		}
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		status.Controllers = append(status.Controllers, SnapshotController{
+			Namespace: d.Namespace,
+			Name:      d.Name,
+			Ready:     d.Status.ReadyReplicas,
+			Desired:   desired,
+		})
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(k.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	classes, err := dynamicClient.Resource(volumeSnapshotClassGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return status, nil
+		}
+		return nil, fmt.Errorf("failed to list volumesnapshotclasses: %w", err)
+	}
+	status.CRDsInstalled = true
+	for _, obj := range classes.Items {
+		c := VolumeSnapshotClass{
+			Name:    obj.GetName(),
+			Default: obj.GetAnnotations()[defaultSnapshotClassAnnotation] == "true",
+		}
+		c.Driver, _, _ = unstructured.NestedString(obj.Object, "driver")
+		c.DeletionPolicy, _, _ = unstructured.NestedString(obj.Object, "deletionPolicy")
+		status.Classes = append(status.Classes, c)
+	}
+	sort.Slice(status.Classes, func(i, j int) bool { return status.Classes[i].Name < status.Classes[j].Name })
+
+	storageClasses, err := k.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storageclasses: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, sc := range storageClasses.Items {
+		if !snapshotDrivers[sc.Provisioner] || seen[sc.Provisioner] {
+			continue
+		}
+		seen[sc.Provisioner] = true
+		if !status.hasDriverClass(sc.Provisioner) {
+			status.DriversWithoutClass = append(status.DriversWithoutClass, sc.Provisioner)
+		}
+	}
+
+	snapshots, err := dynamicClient.Resource(volumeSnapshotGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumesnapshots: %w", err)
+	}
+	for _, obj := range snapshots.Items {
+		s := VolumeSnapshot{
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Created:   obj.GetCreationTimestamp().Time,
+		}
+		s.Class, _, _ = unstructured.NestedString(obj.Object, "spec", "volumeSnapshotClassName")
+		s.SourcePVC, _, _ = unstructured.NestedString(obj.Object, "spec", "source", "persistentVolumeClaimName")
+		s.ReadyToUse, _, _ = unstructured.NestedBool(obj.Object, "status", "readyToUse")
+		s.Error, _, _ = unstructured.NestedString(obj.Object, "status", "error", "message")
+		status.Snapshots = append(status.Snapshots, s)
+	}
+	sort.Slice(status.Snapshots, func(i, j int) bool {
+		if status.Snapshots[i].Namespace != status.Snapshots[j].Namespace {
+			return status.Snapshots[i].Namespace < status.Snapshots[j].Namespace
+		}
+		return status.Snapshots[i].Name < status.Snapshots[j].Name
+	})
+
+	return status, nil
+}
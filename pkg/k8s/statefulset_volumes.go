@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StatefulSetVolumeIssue correlates a Pending StatefulSet pod with the state of one of its
+// volumeClaimTemplate PVCs
+type StatefulSetVolumeIssue struct {
+	Namespace    string
+	StatefulSet  string
+	Pod          string
+	PVC          string
+	Phase        string // PVC phase, or Missing if the PVC does not exist
+	StorageClass string
+	Provisioner  string
+	BindingMode  storagev1.VolumeBindingMode
+	// Unschedulable is set when the pod's PodScheduled condition is False
+	Unschedulable bool
+	Diagnosis     string
+}
+
+// checkStatefulSetVolumes inspects the volumeClaimTemplate PVCs of Pending StatefulSet pods.
+// It flags the chicken-and-egg case where a WaitForFirstConsumer PVC stays Pending because
+// its pod cannot be scheduled.
+func (k *KubeClient) checkStatefulSetVolumes(ctx context.Context, namespaces []string, classes map[string]storagev1.StorageClass, status *ClusterHealthStatus) error {
+	defaultClass := ""
+	for name, sc := range classes {
+		if sc.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+			defaultClass = name
+		}
+	}
+
+	pvcs := make(map[string]*PVCStatus)
+	for _, pvc := range status.PVCStatus {
+		pvcs[pvc.Namespace+"/"+pvc.Name] = pvc
+	}
+
+	for _, namespace := range namespaces {
+		stsList, err := k.Clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list statefulsets: %w", err)
+		}
+		templates := make(map[string][]corev1.PersistentVolumeClaim)
+		for _, sts := range stsList.Items {
+			if len(sts.Spec.VolumeClaimTemplates) > 0 {
+				templates[sts.Namespace+"/"+sts.Name] = sts.Spec.VolumeClaimTemplates
+			}
+		}
+		if len(templates) == 0 {
+			continue
+		}
+
+		pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: "status.phase=Pending",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+
+		for _, pod := range pods.Items {
+			owner := metav1.GetControllerOf(&pod)
+			if owner == nil || owner.Kind != "StatefulSet" {
+				continue
+			}
+
+			unschedulable, schedulingMessage := false, ""
+			for _, cond := range pod.Status.Conditions {
+				if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+					unschedulable, schedulingMessage = true, cond.Message
+				}
+			}
+
+			for _, tmpl := range templates[pod.Namespace+"/"+owner.Name] {
+				issue := StatefulSetVolumeIssue{
+					Namespace:     pod.Namespace,
+					StatefulSet:   owner.Name,
+					Pod:           pod.Name,
+					PVC:           tmpl.Name + "-" + pod.Name,
+					Unschedulable: unschedulable,
+				}
+
+				pvc, ok := pvcs[pod.Namespace+"/"+issue.PVC]
+				spec := tmpl.Spec
+				if ok {
+					if pvc.Status.Phase == corev1.ClaimBound {
+						continue
+					}
+					issue.Phase = string(pvc.Status.Phase)
+					spec = pvc.Spec
+				} else {
+					issue.Phase = "Missing"
+				}
+
+				issue.StorageClass = defaultClass
+				if spec.StorageClassName != nil {
+					issue.StorageClass = *spec.StorageClassName
+				}
+				sc, scFound := classes[issue.StorageClass]
+				if scFound {
+					issue.Provisioner = sc.Provisioner
+					issue.BindingMode = storagev1.VolumeBindingImmediate
+					if sc.VolumeBindingMode != nil {
+						issue.BindingMode = *sc.VolumeBindingMode
+					}
+				}
+
+				switch {
+				case issue.StorageClass == "":
+					issue.Diagnosis = "PVC has no StorageClass and there is no default StorageClass"
+				case !scFound:
+					issue.Diagnosis = fmt.Sprintf("StorageClass %s does not exist", issue.StorageClass)
+				case !ok:
+					issue.Diagnosis = "PVC has not been created from the volumeClaimTemplate"
+				case issue.BindingMode == storagev1.VolumeBindingWaitForFirstConsumer && unschedulable:
+					issue.Diagnosis = fmt.Sprintf("PVC waits for its pod to be scheduled, but the pod cannot be scheduled: %s. "+
+						"Fix the pod's scheduling (node capacity, selectors, taints, or nodes in the zone of existing volumes)", schedulingMessage)
+				case issue.BindingMode == storagev1.VolumeBindingWaitForFirstConsumer:
+					issue.Diagnosis = "PVC waits for its pod to be scheduled"
+				default:
+					issue.Diagnosis = fmt.Sprintf("PVC is not bound; check that the %s provisioner is running and the PVC's events", issue.Provisioner)
+				}
+
+				status.StatefulSetVolumeIssues = append(status.StatefulSetVolumeIssues, issue)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DiagnosticPodLabel marks the short-lived pods ekspeek creates for its own tests
+	DiagnosticPodLabel      = "app.kubernetes.io/managed-by"
+	DiagnosticPodLabelValue = "ekspeek"
+
+	nodeRoleTaintPrefix = "node-role.kubernetes.io/"
+)
+
+// systemNamespaces hold cluster components that are expected to tolerate restricted nodes
+var systemNamespaces = map[string]bool{
+	metav1.NamespaceSystem:    true,
+	metav1.NamespacePublic:    true,
+	corev1.NamespaceNodeLease: true,
+}
+
+// TolerationIssue is a workload pod running on a tainted node only because of an
+// over-broad or node-role toleration
+type TolerationIssue struct {
+	Namespace  string
+	Pod        string
+	Node       string
+	Taint      string // Taint on the node, as key=value:effect
+	Toleration string // Toleration that matched it
+}
+
+// diagnosticPodLabels returns the labels applied to pods ekspeek creates
+func diagnosticPodLabels() map[string]string {
+	return map[string]string{DiagnosticPodLabel: DiagnosticPodLabelValue}
+}
+
+// broadToleration returns true if the toleration matches every taint or targets node-role taints
+func broadToleration(t corev1.Toleration) bool {
+	if t.Key == "" && t.Operator == corev1.TolerationOpExists {
+		return true
+	}
+	return strings.HasPrefix(t.Key, nodeRoleTaintPrefix)
+}
+
+func formatToleration(t corev1.Toleration) string {
+	if t.Key == "" {
+		return "operator: Exists (all taints)"
+	}
+	s := t.Key
+	if t.Operator == corev1.TolerationOpExists {
+		s += " (Exists)"
+	} else if t.Value != "" {
+		s += "=" + t.Value
+	}
+	if t.Effect != "" {
+		s += ":" + string(t.Effect)
+	}
+	return s
+}
+
+// exemptFromTolerationCheck returns true for pods that legitimately run everywhere:
+// DaemonSet pods, static pods and ekspeek's own diagnostic pods
+func exemptFromTolerationCheck(pod *corev1.Pod) bool {
+	if pod.Labels[DiagnosticPodLabel] == DiagnosticPodLabelValue {
+		return true
+	}
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return true
+	}
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTolerations finds non-system pods scheduled onto tainted nodes through a
+// wildcard or node-role toleration
+func (k *KubeClient) checkTolerations(ctx context.Context, namespaces []string, status *ClusterHealthStatus) error {
+	nodes, err := k.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	taints := make(map[string][]corev1.Taint, len(nodes.Items))
+	for _, node := range nodes.Items {
+		for _, taint := range node.Spec.Taints {
+			if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+				taints[node.Name] = append(taints[node.Name], taint)
+			}
+		}
+	}
+	if len(taints) == 0 {
+		return nil
+	}
+
+	for _, namespace := range namespaces {
+		pods, err := k.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if systemNamespaces[pod.Namespace] || pod.Spec.NodeName == "" || exemptFromTolerationCheck(pod) {
+				continue
+			}
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+
+			for _, taint := range taints[pod.Spec.NodeName] {
+				for _, toleration := range pod.Spec.Tolerations {
+					if !broadToleration(toleration) || !toleration.ToleratesTaint(&taint) {
+						continue
+					}
+					status.TolerationIssues = append(status.TolerationIssues, TolerationIssue{
+						Namespace:  pod.Namespace,
+						Pod:        pod.Name,
+						Node:       pod.Spec.NodeName,
+						Taint:      taint.ToString(),
+						Toleration: formatToleration(toleration),
+					})
+					break
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// spreadConstraintViolation explains which DoNotSchedule topology spread constraint of a
+// pending pod cannot be met on the current nodes, following the scheduler's
+// PodTopologySpread filter: domains are the topologyKey values of the nodes matching the
+// pod's node selector and affinity, and the pod fits a domain with a schedulable node if
+// adding it keeps the domain within maxSkew of the least populated domain. namespacePods
+// are the pods in the pod's namespace. It returns "" if every constraint can be met.
+func spreadConstraintViolation(pod *corev1.Pod, nodes []corev1.Node, namespacePods []corev1.Pod) string {
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable != corev1.DoNotSchedule {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
+		if err != nil {
+			continue
+		}
+		// matchLabelKeys narrow the selector to pods sharing the pod's values, e.g. its revision
+		for _, key := range c.MatchLabelKeys {
+			if value, ok := pod.Labels[key]; ok {
+				if req, err := labels.NewRequirement(key, selection.Equals, []string{value}); err == nil {
+					selector = selector.Add(*req)
+				}
+			}
+		}
+
+		counts := make(map[string]int)
+		schedulable := make(map[string]bool)
+		nodeDomains := make(map[string]string)
+		for i := range nodes {
+			node := &nodes[i]
+			domain, ok := node.Labels[c.TopologyKey]
+			if !ok {
+				continue
+			}
+			if (c.NodeAffinityPolicy == nil || *c.NodeAffinityPolicy == corev1.NodeInclusionPolicyHonor) && !podSelectsNode(pod, node) {
+				continue
+			}
+			if c.NodeTaintsPolicy != nil && *c.NodeTaintsPolicy == corev1.NodeInclusionPolicyHonor && !toleratesNode(pod, node) {
+				continue
+			}
+			counts[domain] += 0
+			nodeDomains[node.Name] = domain
+			if nodeAcceptsPods(node) && toleratesNode(pod, node) && podSelectsNode(pod, node) {
+				schedulable[domain] = true
+			}
+		}
+		if len(counts) == 0 {
+			return fmt.Sprintf("topologyKey %s: no node the pod can select has this label", c.TopologyKey)
+		}
+
+		for _, p := range namespacePods {
+			if p.Spec.NodeName == "" || p.DeletionTimestamp != nil || !selector.Matches(labels.Set(p.Labels)) {
+				continue
+			}
+			if domain, ok := nodeDomains[p.Spec.NodeName]; ok {
+				counts[domain]++
+			}
+		}
+
+		globalMin := -1
+		for _, count := range counts {
+			if globalMin < 0 || count < globalMin {
+				globalMin = count
+			}
+		}
+		belowMinDomains := c.MinDomains != nil && int32(len(counts)) < *c.MinDomains
+		if belowMinDomains {
+			globalMin = 0
+		}
+		self := 0
+		if selector.Matches(labels.Set(pod.Labels)) {
+			self = 1
+		}
+
+		fits := false
+		var open []string
+		for domain := range schedulable {
+			open = append(open, domain)
+			if int32(counts[domain]+self-globalMin) <= c.MaxSkew {
+				fits = true
+			}
+		}
+		if fits {
+			continue
+		}
+		return describeSpreadViolation(c, counts, open, globalMin, self, belowMinDomains)
+	}
+	return ""
+}
+
+// describeSpreadViolation formats why no domain can take the pod, such as "maxSkew=1 on
+// topology.kubernetes.io/zone across 2 domains (a: 3, b: 1) but only a has schedulable
+// nodes, where the skew would be 3"
+func describeSpreadViolation(c corev1.TopologySpreadConstraint, counts map[string]int, open []string, globalMin, self int, belowMinDomains bool) string {
+	domains := make([]string, 0, len(counts))
+	for domain := range counts {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	sort.Strings(open)
+
+	var distribution []string
+	for _, domain := range domains {
+		distribution = append(distribution, fmt.Sprintf("%s: %d", domain, counts[domain]))
+	}
+	msg := fmt.Sprintf("maxSkew=%d on %s across %d domains (%s)", c.MaxSkew, c.TopologyKey, len(domains), strings.Join(distribution, ", "))
+
+	switch {
+	case len(open) == 0:
+		return msg + " but none has schedulable nodes"
+	case belowMinDomains:
+		return msg + fmt.Sprintf(" but minDomains=%d, so the skew is counted from 0 until more domains exist", *c.MinDomains)
+	case len(open) < len(domains):
+		minSkew := -1
+		for _, domain := range open {
+			if skew := counts[domain] + self - globalMin; minSkew < 0 || skew < minSkew {
+				minSkew = skew
+			}
+		}
+		verb := "has"
+		if len(open) > 1 {
+			verb = "have"
+		}
+		return msg + fmt.Sprintf(" but only %s %s schedulable nodes, where the skew would be %d",
+			strings.Join(open, ", "), verb, minSkew)
+	}
+	return msg + " and adding the pod to any of them exceeds it"
+}
+
+// podSelectsNode returns true if the node matches the pod's nodeSelector and required
+// node affinity
+func podSelectsNode(pod *corev1.Pod, node *corev1.Node) bool {
+	nodeLabels := labels.Set(node.Labels)
+	for key, value := range pod.Spec.NodeSelector {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// Terms are ORed, the expressions of a term ANDed
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		matched := len(term.MatchExpressions) > 0
+		for _, expr := range term.MatchExpressions {
+			req, err := labels.NewRequirement(expr.Key, selection.Operator(expr.Operator), expr.Values)
+			if err != nil || !req.Matches(nodeLabels) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// toleratesNode returns true if the pod tolerates the node's NoSchedule and NoExecute taints
+func toleratesNode(pod *corev1.Pod, node *corev1.Node) bool {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range pod.Spec.Tolerations {
+			if toleration.ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeAcceptsPods returns true if the node is Ready and not cordoned
+func nodeAcceptsPods(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
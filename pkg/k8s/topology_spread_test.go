@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSpreadConstraintViolation(t *testing.T) {
+	node := func(name, zone string, ready bool, cordoned bool) corev1.Node {
+		status := corev1.ConditionTrue
+		if !ready {
+			status = corev1.ConditionFalse
+		}
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone}},
+			Spec:       corev1.NodeSpec{Unschedulable: cordoned},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}},
+		}
+	}
+	running := func(name, nodeName string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app": "web"}},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-4", Labels: map[string]string{"app": "web"}},
+		Spec: corev1.PodSpec{
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       corev1.LabelTopologyZone,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			}},
+		},
+	}
+	pods := []corev1.Pod{running("web-1", "a-1"), running("web-2", "a-1"), running("web-3", "b-1")}
+
+	tests := []struct {
+		name  string
+		nodes []corev1.Node
+		want  string
+	}{
+		{
+			name:  "zone b cordoned",
+			nodes: []corev1.Node{node("a-1", "a", true, false), node("b-1", "b", true, true)},
+			want:  "maxSkew=1 on topology.kubernetes.io/zone across 2 domains (a: 2, b: 1) but only a has schedulable nodes, where the skew would be 2",
+		},
+		{
+			name:  "no schedulable nodes",
+			nodes: []corev1.Node{node("a-1", "a", false, false), node("b-1", "b", true, true)},
+			want:  "but none has schedulable nodes",
+		},
+		{
+			name:  "zone b has room",
+			nodes: []corev1.Node{node("a-1", "a", true, false), node("b-1", "b", true, false)},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := spreadConstraintViolation(pending, tt.nodes, pods)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("spreadConstraintViolation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
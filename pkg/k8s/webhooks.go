@@ -0,0 +1,149 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// criticalNamespaces hold the components a cluster needs to recover; a webhook that fails
+// closed on them can keep the cluster from healing itself
+var criticalNamespaces = []string{metav1.NamespaceSystem, corev1.NamespaceNodeLease}
+
+// WebhookStatus describes an admission webhook and the health of its backend
+type WebhookStatus struct {
+	Configuration string
+	Kind          string // Validating or Mutating
+	Name          string
+	FailurePolicy admissionregistrationv1.FailurePolicyType
+	// Service is the backend service as namespace/name, empty for URL webhooks
+	Service        string
+	URL            string
+	ReadyEndpoints int
+	BackendError   string // Set when the backend service cannot be resolved
+	// CriticalNamespaces lists the critical namespaces the webhook's selectors match
+	CriticalNamespaces []string
+}
+
+// FailsClosed returns true if API requests fail when the webhook cannot be called
+func (w WebhookStatus) FailsClosed() bool {
+	// failurePolicy defaults to Fail in admissionregistration/v1
+	return w.FailurePolicy == "" || w.FailurePolicy == admissionregistrationv1.Fail
+}
+
+// BackendDown returns true if the webhook's service has no ready endpoints
+func (w WebhookStatus) BackendDown() bool {
+	return w.Service != "" && (w.BackendError != "" || w.ReadyEndpoints == 0)
+}
+
+// webhookSpec holds the fields shared by validating and mutating webhooks
+type webhookSpec struct {
+	name              string
+	clientConfig      admissionregistrationv1.WebhookClientConfig
+	failurePolicy     *admissionregistrationv1.FailurePolicyType
+	namespaceSelector *metav1.LabelSelector
+	objectSelector    *metav1.LabelSelector
+}
+
+// GetWebhookStatus lists the validating and mutating webhooks, the ready endpoints of
+// their backend services and the critical namespaces they intercept
+func (k *KubeClient) GetWebhookStatus(ctx context.Context) ([]WebhookStatus, error) {
+	validating, err := k.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list validating webhook configurations: %w", err)
+	}
+	mutating, err := k.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mutating webhook configurations: %w", err)
+	}
+
+	namespaceLabels := make(map[string]labels.Set, len(criticalNamespaces))
+	for _, name := range criticalNamespaces {
+		ns, err := k.Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			// Namespaces carry their name as a label since Kubernetes 1.21
+			namespaceLabels[name] = labels.Set{corev1.LabelMetadataName: name}
+			continue
+		}
+		namespaceLabels[name] = labels.Set(ns.Labels)
+	}
+
+	var results []WebhookStatus
+	add := func(configuration, kind string, spec webhookSpec) {
+		status := WebhookStatus{
+			Configuration: configuration,
+			Kind:          kind,
+			Name:          spec.name,
+		}
+		if spec.failurePolicy != nil {
+			status.FailurePolicy = *spec.failurePolicy
+		}
+		if svc := spec.clientConfig.Service; svc != nil {
+			status.Service = svc.Namespace + "/" + svc.Name
+			endpoints, err := k.GetServiceEndpoints(ctx, svc.Namespace, svc.Name)
+			if err != nil {
+				status.BackendError = err.Error()
+			} else {
+				status.ReadyEndpoints = len(endpoints.Ready)
+			}
+		} else if spec.clientConfig.URL != nil {
+			status.URL = *spec.clientConfig.URL
+		}
+		status.CriticalNamespaces = k.webhookCriticalNamespaces(ctx, spec, namespaceLabels)
+		results = append(results, status)
+	}
+
+	for _, cfg := range validating.Items {
+		for _, w := range cfg.Webhooks {
+			add(cfg.Name, "Validating", webhookSpec{w.Name, w.ClientConfig, w.FailurePolicy, w.NamespaceSelector, w.ObjectSelector})
+		}
+	}
+	for _, cfg := range mutating.Items {
+		for _, w := range cfg.Webhooks {
+			add(cfg.Name, "Mutating", webhookSpec{w.Name, w.ClientConfig, w.FailurePolicy, w.NamespaceSelector, w.ObjectSelector})
+		}
+	}
+
+	return results, nil
+}
+
+// webhookCriticalNamespaces returns the critical namespaces matched by the webhook's
+// namespaceSelector in which its objectSelector, if any, matches at least one pod
+func (k *KubeClient) webhookCriticalNamespaces(ctx context.Context, spec webhookSpec, namespaceLabels map[string]labels.Set) []string {
+	nsSelector := labels.Everything()
+	if spec.namespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.namespaceSelector)
+		if err != nil {
+			return nil
+		}
+		nsSelector = selector
+	}
+
+	var objSelector labels.Selector
+	if spec.objectSelector != nil && (len(spec.objectSelector.MatchLabels) > 0 || len(spec.objectSelector.MatchExpressions) > 0) {
+		selector, err := metav1.LabelSelectorAsSelector(spec.objectSelector)
+		if err != nil {
+			return nil
+		}
+		objSelector = selector
+	}
+
+	var matched []string
+	for _, name := range criticalNamespaces {
+		if !nsSelector.Matches(namespaceLabels[name]) {
+			continue
+		}
+		if objSelector != nil {
+			pods, err := k.Clientset.CoreV1().Pods(name).List(ctx, metav1.ListOptions{LabelSelector: objSelector.String(), Limit: 1})
+			if err != nil || len(pods.Items) == 0 {
+				continue
+			}
+		}
+		matched = append(matched, name)
+	}
+	return matched
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"ekspeek/pkg/findings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Format is a machine-readable or human-readable output format
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatSARIF Format = "sarif"
+	// FormatJSONL writes one compact JSON document per line, so results can be streamed
+	// and processed incrementally
+	FormatJSONL Format = "jsonl"
+)
+
+// ParseFormat returns the format with the given name. An empty name means text.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatJSON, FormatYAML, FormatSARIF, FormatJSONL:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (must be text, json, jsonl, yaml or sarif)", name)
+}
+
+// Write serializes v as JSON or YAML. SARIF is supported when v is a slice of findings.
+// For JSON Lines each element of a slice is written on its own line.
+func Write(w io.Writer, format Format, v interface{}) error {
+	switch format {
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return enc.Encode(v)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case FormatYAML:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	case FormatSARIF:
+		fs, ok := v.([]findings.Finding)
+		if !ok {
+			return fmt.Errorf("sarif output is only supported for findings")
+		}
+		return WriteSARIF(w, fs)
+	}
+	return fmt.Errorf("output format %q is not supported here", format)
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSONL(t *testing.T) {
+	type item struct {
+		Name  string
+		Items []string
+	}
+
+	tests := []struct {
+		name string
+		v    interface{}
+		want []string
+	}{
+		{
+			name: "slice",
+			v:    []item{{Name: "a", Items: []string{"x", "y"}}, {Name: "b"}},
+			want: []string{`{"Name":"a","Items":["x","y"]}`, `{"Name":"b","Items":null}`},
+		},
+		{
+			name: "non-slice",
+			v:    item{Name: "a", Items: []string{"x"}},
+			want: []string{`{"Name":"a","Items":["x"]}`},
+		},
+		{
+			name: "map",
+			v:    map[string]int{"issues": 2},
+			want: []string{`{"issues":2}`},
+		},
+		{
+			name: "empty slice",
+			v:    []item{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, FormatJSONL, tt.v); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			out := buf.String()
+			if len(tt.want) == 0 {
+				if out != "" {
+					t.Errorf("Write() = %q, want no output", out)
+				}
+				return
+			}
+			if !strings.HasSuffix(out, "\n") {
+				t.Errorf("Write() = %q, want a trailing newline", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("Write() wrote %d lines, want %d:\n%s", len(lines), len(tt.want), out)
+			}
+			for i, line := range lines {
+				if !json.Valid([]byte(line)) {
+					t.Errorf("line %d is not a JSON document: %s", i, line)
+				}
+				if line != tt.want[i] {
+					t.Errorf("line %d = %s, want %s", i, line, tt.want[i])
+				}
+			}
+		})
+	}
+}
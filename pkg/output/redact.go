@@ -0,0 +1,122 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// accountPattern matches 12-digit AWS account IDs, including the account field of ARNs
+	accountPattern = regexp.MustCompile(`\b\d{12}\b`)
+	// ipPattern matches IPv4 addresses and the dashed form used in EC2 hostnames (ip-10-0-1-23)
+	ipPattern = regexp.MustCompile(`\b\d{1,3}[.-]\d{1,3}[.-]\d{1,3}[.-]\d{1,3}\b`)
+	// tokenPattern matches bearer tokens and JSON token/secret fields
+	tokenPattern = regexp.MustCompile(`(?i)(bearer\s+|"(?:token|secret|password|certificateData|data)"\s*:\s*")[^"\s]+`)
+)
+
+// Redactor replaces AWS account IDs and private IP addresses with placeholders such as
+// ACCOUNT_A and IP_1, and masks tokens as REDACTED. The same value always gets the same
+// placeholder, so output stays correlatable within a run.
+type Redactor struct {
+	mu       sync.Mutex
+	accounts map[string]string
+	ips      map[string]string
+}
+
+// NewRedactor creates a redactor with an empty mapping
+func NewRedactor() *Redactor {
+	return &Redactor{
+		accounts: make(map[string]string),
+		ips:      make(map[string]string),
+	}
+}
+
+// Redact returns s with account IDs, private IP addresses and tokens replaced
+func (r *Redactor) Redact(s string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s = tokenPattern.ReplaceAllString(s, "${1}REDACTED")
+	s = accountPattern.ReplaceAllStringFunc(s, func(account string) string {
+		placeholder, ok := r.accounts[account]
+		if !ok {
+			placeholder = "ACCOUNT_" + letterIndex(len(r.accounts))
+			r.accounts[account] = placeholder
+		}
+		return placeholder
+	})
+
+	return ipPattern.ReplaceAllStringFunc(s, func(match string) string {
+		ip := net.ParseIP(strings.ReplaceAll(match, "-", "."))
+		if ip == nil || !ip.IsPrivate() {
+			return match
+		}
+		placeholder, ok := r.ips[ip.String()]
+		if !ok {
+			placeholder = "IP_" + strconv.Itoa(len(r.ips)+1)
+			r.ips[ip.String()] = placeholder
+		}
+		return placeholder
+	})
+}
+
+// Writer returns a writer that redacts everything written to it before passing it to w.
+// Output is redacted a whole line at a time, so values split across writes are still
+// found: a line is passed on once it ends with a newline, or a carriage return as
+// progress lines do, and Close passes on the rest. Close does not close w.
+func (r *Redactor) Writer(w io.Writer) io.WriteCloser {
+	return &redactingWriter{r: r, w: w}
+}
+
+type redactingWriter struct {
+	r   *Redactor
+	w   io.Writer
+	mu  sync.Mutex
+	buf []byte // Output after the last line end
+}
+
+func (rw *redactingWriter) Write(p []byte) (int, error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	rw.buf = append(rw.buf, p...)
+	end := bytes.LastIndexAny(rw.buf, "\n\r")
+	if end < 0 {
+		return len(p), nil
+	}
+	lines := rw.r.Redact(string(rw.buf[:end+1]))
+	rw.buf = rw.buf[:copy(rw.buf, rw.buf[end+1:])]
+	if _, err := io.WriteString(rw.w, lines); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes the output buffered after the last line end
+func (rw *redactingWriter) Close() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if len(rw.buf) == 0 {
+		return nil
+	}
+	rest := rw.r.Redact(string(rw.buf))
+	rw.buf = rw.buf[:0]
+	_, err := io.WriteString(rw.w, rest)
+	return err
+}
+
+// letterIndex returns A, B, ..., Z, AA, AB, ... for 0, 1, ...
+func letterIndex(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
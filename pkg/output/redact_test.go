@@ -0,0 +1,69 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRedactorRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "stable placeholders",
+			in:   "111122223333 444455556666 111122223333",
+			want: "ACCOUNT_A ACCOUNT_B ACCOUNT_A",
+		},
+		{
+			name: "ARN account field",
+			in:   "arn:aws:iam::111122223333:role/ekspeek",
+			want: "arn:aws:iam::ACCOUNT_A:role/ekspeek",
+		},
+		{
+			name: "private IPs and EC2 host names",
+			in:   "10.0.1.23 ip-10-0-1-23.eu-west-1.compute.internal 192.168.0.1",
+			want: "IP_1 ip-IP_1.eu-west-1.compute.internal IP_2",
+		},
+		{
+			name: "tokens",
+			in:   `Authorization: Bearer k8s-aws-v1.abc {"token": "s3cr3t"}`,
+			want: `Authorization: Bearer REDACTED {"token": "REDACTED"}`,
+		},
+		{
+			name: "public IPs are kept",
+			in:   "52.95.110.1",
+			want: "52.95.110.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRedactor().Redact(tt.in); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewRedactor().Writer(&buf)
+
+	// An account ID split across writes is redacted once its line is complete
+	for _, chunk := range []string{"account 1111222", "23333 in ", "10.0.", "1.23\n", "progress\r", "last 111122223333"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write(%q) error = %v", chunk, err)
+		}
+	}
+	if got, want := buf.String(), "account ACCOUNT_A in IP_1\nprogress\r"; got != want {
+		t.Errorf("before Close: %q, want %q", got, want)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got, want := buf.String(), "account ACCOUNT_A in IP_1\nprogress\rlast ACCOUNT_A"; got != want {
+		t.Errorf("after Close: %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,184 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+
+	"ekspeek/pkg/findings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolURI      = "https://github.com/akhilthomas236/ekspeek"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	Help                 *sarifMessage      `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifRuleDescriptions describes the rule behind each security finding ID. Finding
+// messages name a resource and vary between results, so they cannot describe the rule.
+var sarifRuleDescriptions = map[string]string{
+	"cluster_encryption":      "Kubernetes secrets should be envelope encrypted with a KMS key",
+	"default_sa_token":        "Pods should not mount the default service account token",
+	"endpoint_access":         "The cluster API server endpoint should not be publicly accessible",
+	"irsa":                    "Pods using IAM roles for service accounts should be able to assume their role",
+	"logging":                 "Control plane logging should be enabled",
+	"node_clock_skew":         "Node clocks should be in sync with the control plane",
+	"node_imds":               "Nodes should require IMDSv2 with a hop limit of 1",
+	"node_imds_hop_limit":     "Nodes should set an IMDS hop limit of 1 so pods cannot read node credentials",
+	"node_imdsv1":             "Nodes should not allow IMDSv1",
+	"nodegroup_iam":           "Nodegroups should have an IAM role",
+	"nodegroup_public_ip":     "Nodes should not get public IP addresses in subnets routed to the internet",
+	"nodegroup_remote_access": "Nodegroup remote access should be restricted by security groups",
+	"nodegroups":              "Nodegroups should be checked for security issues",
+	"rbac":                    "RBAC bindings should not grant excessive permissions",
+	"security_group_egress":   "Security groups should not allow all egress to the internet",
+	"security_group_ingress":  "Security groups should not allow ingress from the internet on sensitive ports",
+	"security_groups":         "Security groups should not be open to the internet on sensitive ports",
+}
+
+// sarifRuleDescription returns the description of a rule, or a generic one for finding IDs
+// without a description
+func sarifRuleDescription(id string) string {
+	if description, ok := sarifRuleDescriptions[id]; ok {
+		return description
+	}
+	return "ekspeek security check " + id
+}
+
+// sarifLevel maps a finding severity to a SARIF result level
+func sarifLevel(s findings.Severity) string {
+	switch s {
+	case findings.SeverityCritical:
+		return "error"
+	case findings.SeverityWarning:
+		return "warning"
+	case findings.SeverityInfo:
+		return "note"
+	}
+	return "none"
+}
+
+// WriteSARIF serializes the security findings that are issues as a SARIF 2.1.0 log. Each
+// finding ID becomes a rule with a fixed description, and the finding's resource becomes
+// the result location.
+func WriteSARIF(w io.Writer, fs []findings.Finding) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "ekspeek",
+			InformationURI: toolURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	// Index of each finding ID's rule in the driver's rules
+	rules := make(map[string]int)
+	for _, f := range fs {
+		if f.Category != "security" || f.Severity == findings.SeverityPass {
+			continue
+		}
+
+		i, ok := rules[f.ID]
+		if !ok {
+			i = len(run.Tool.Driver.Rules)
+			rules[f.ID] = i
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:                   f.ID,
+				ShortDescription:     sarifMessage{Text: sarifRuleDescription(f.ID)},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(f.Severity)},
+			})
+		}
+		// Findings that failed to collect have no remediation; take it from the first that does
+		if rule := &run.Tool.Driver.Rules[i]; rule.Help == nil && f.Remediation != "" {
+			rule.Help = &sarifMessage{Text: f.Remediation}
+		}
+
+		result := sarifResult{
+			RuleID:  f.ID,
+			Level:   sarifLevel(f.Severity),
+			Message: sarifMessage{Text: f.Message},
+		}
+		if f.Resource != "" {
+			result.Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: "ekspeek/" + f.Category + "/" + f.Resource},
+				},
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               f.Resource,
+					FullyQualifiedName: f.Category + "/" + f.Resource,
+					Kind:               "resource",
+				}},
+			}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"ekspeek/pkg/findings"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	fs := []findings.Finding{
+		{ID: "security_group_ingress", Severity: findings.SeverityCritical, Category: "security", Resource: "sg-1",
+			Message: "Ingress from the internet allowed on SSH port(s) 22", Remediation: "Restrict the rule"},
+		{ID: "security_group_ingress", Severity: findings.SeverityCritical, Category: "security", Resource: "sg-2",
+			Message: "Ingress from the internet allowed on RDP port(s) 3389", Remediation: "Restrict the rule"},
+		{ID: "node_imds", Severity: findings.SeverityWarning, Category: "security", Resource: "ng-1",
+			Message: "Failed to check instance metadata options: denied"},
+		{ID: "node_imds", Severity: findings.SeverityWarning, Category: "security", Resource: "ng-2",
+			Message: "Instances allow IMDSv1", Remediation: "Require IMDSv2"},
+		{ID: "custom_check", Severity: findings.SeverityInfo, Category: "security", Message: "Something to know"},
+		{ID: "logging", Severity: findings.SeverityPass, Category: "security", Message: "Cluster logging is configured"},
+		{ID: "pod_pending", Severity: findings.SeverityWarning, Category: "scheduling", Resource: "default/web", Message: "Pod is pending"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, fs); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if log.Version != sarifVersion || len(log.Runs) != 1 {
+		t.Fatalf("log = version %q with %d runs, want %s with one run", log.Version, len(log.Runs), sarifVersion)
+	}
+	run := log.Runs[0]
+
+	wantRules := []struct {
+		id, description, level, help string
+	}{
+		{"security_group_ingress", sarifRuleDescriptions["security_group_ingress"], "error", "Restrict the rule"},
+		{"node_imds", sarifRuleDescriptions["node_imds"], "warning", "Require IMDSv2"},
+		{"custom_check", "ekspeek security check custom_check", "note", ""},
+	}
+	if len(run.Tool.Driver.Rules) != len(wantRules) {
+		t.Fatalf("rules = %+v, want %d rules", run.Tool.Driver.Rules, len(wantRules))
+	}
+	for i, want := range wantRules {
+		rule := run.Tool.Driver.Rules[i]
+		help := ""
+		if rule.Help != nil {
+			help = rule.Help.Text
+		}
+		if rule.ID != want.id || rule.ShortDescription.Text != want.description || rule.DefaultConfiguration.Level != want.level || help != want.help {
+			t.Errorf("rule %d = %+v (help %q), want %+v", i, rule, help, want)
+		}
+	}
+
+	if len(run.Results) != 5 {
+		t.Fatalf("results = %+v, want the 5 security issues", run.Results)
+	}
+	first := run.Results[0]
+	if first.RuleID != "security_group_ingress" || first.Level != "error" || first.Message.Text != fs[0].Message {
+		t.Errorf("first result = %+v, want the sg-1 finding", first)
+	}
+	if len(first.Locations) != 1 || first.Locations[0].LogicalLocations[0].FullyQualifiedName != "security/sg-1" ||
+		first.Locations[0].PhysicalLocation.ArtifactLocation.URI != "ekspeek/security/sg-1" {
+		t.Errorf("first result locations = %+v, want security/sg-1", first.Locations)
+	}
+	if last := run.Results[4]; last.RuleID != "custom_check" || len(last.Locations) != 0 {
+		t.Errorf("last result = %+v, want custom_check without a location", last)
+	}
+}
+
+func TestWriteSARIFNoFindings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, nil); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+
+	// Empty rules and results must be arrays, not null, for SARIF consumers
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules json.RawMessage `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if len(log.Runs) != 1 || string(log.Runs[0].Tool.Driver.Rules) != "[]" || string(log.Runs[0].Results) != "[]" {
+		t.Errorf("output = %s, want one run with empty rules and results", buf.String())
+	}
+}